@@ -0,0 +1,296 @@
+// Package apikey 提供了面向机器间调用的 API 密钥管理
+// 支持密钥生成（前缀 + 哈希存储的密文）、可插拔存储、权限范围、调用配额、吊销，
+// 以及将密钥身份写入请求上下文的校验中间件
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/xzl-go/easygo/core"
+	errs "github.com/xzl-go/easygo/errors"
+	"github.com/xzl-go/easygo/id"
+)
+
+// 上下文键
+const (
+	ContextKey     = "api_key"      // 通过校验的 *Key
+	CurrentUserKey = "current_user" // 密钥所有者
+)
+
+// HeaderName 是默认读取密钥的请求头
+const HeaderName = "X-API-Key"
+
+// 预定义错误
+var (
+	ErrInvalidKey        = errs.New(40110, "error.apikey.invalid", http.StatusUnauthorized, "Invalid API key")
+	ErrKeyRevoked        = errs.New(40111, "error.apikey.revoked", http.StatusUnauthorized, "API key has been revoked")
+	ErrKeyExpired        = errs.New(40112, "error.apikey.expired", http.StatusUnauthorized, "API key has expired")
+	ErrInsufficientScope = errs.New(40310, "error.apikey.scope", http.StatusForbidden, "API key lacks the required scope")
+	ErrQuotaExceeded     = errs.New(42910, "error.apikey.quota", http.StatusTooManyRequests, "API key quota exceeded")
+)
+
+// Key 是一个 API 密钥，明文密钥只在创建时返回一次，存储中仅保存其哈希
+type Key struct {
+	ID          string        `json:"id"`
+	Prefix      string        `json:"prefix"` // 明文密钥的前缀，便于识别，例如 "ek_live"
+	Hash        string        `json:"-"`      // 密文的 SHA-256 哈希
+	Name        string        `json:"name"`
+	Owner       string        `json:"owner"`
+	Scopes      []string      `json:"scopes"`       // 权限范围，包含 "*" 表示全部
+	Quota       int64         `json:"quota"`        // 每个配额周期内允许的调用次数，0 表示不限制
+	QuotaPeriod time.Duration `json:"quota_period"` // 配额周期，默认 1 小时
+	ExpiresAt   time.Time     `json:"expires_at,omitempty"`
+	RevokedAt   time.Time     `json:"revoked_at,omitempty"`
+	LastUsedAt  time.Time     `json:"last_used_at,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// HasScope 判断密钥是否拥有指定权限范围
+func (k *Key) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == "*" || s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Revoked 判断密钥是否已被吊销
+func (k *Key) Revoked() bool {
+	return !k.RevokedAt.IsZero()
+}
+
+// Expired 判断密钥是否已过期
+func (k *Key) Expired() bool {
+	return !k.ExpiresAt.IsZero() && time.Now().After(k.ExpiresAt)
+}
+
+// CreateOptions 定义了创建密钥的参数
+type CreateOptions struct {
+	Name        string
+	Owner       string
+	Scopes      []string
+	Quota       int64
+	QuotaPeriod time.Duration
+	TTL         time.Duration // 有效期，0 表示永不过期
+}
+
+// Options 定义了密钥管理器配置
+type Options struct {
+	// Prefix 明文密钥前缀，默认 "ek"
+	Prefix string
+	// TouchInterval 更新最后使用时间的最小间隔，默认 1 分钟，避免每次请求都写存储
+	TouchInterval time.Duration
+}
+
+// Manager 是 API 密钥管理器
+type Manager struct {
+	store   Store
+	options Options
+}
+
+// NewManager 创建密钥管理器
+// store: 密钥存储，为 nil 时使用内存存储
+// options: 管理器配置
+func NewManager(store Store, options Options) *Manager {
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	if options.Prefix == "" {
+		options.Prefix = "ek"
+	}
+	if options.TouchInterval <= 0 {
+		options.TouchInterval = time.Minute
+	}
+	return &Manager{store: store, options: options}
+}
+
+// Create 创建密钥
+// 返回明文密钥（格式为 "前缀_密钥ID_密文"，仅此一次返回，需提示调用方妥善保存）和密钥信息
+func (m *Manager) Create(opts CreateOptions) (string, *Key, error) {
+	keyID, err := id.NewULID()
+	if err != nil {
+		return "", nil, err
+	}
+	keyID = strings.ToLower(keyID)
+	secret, err := randomSecret()
+	if err != nil {
+		return "", nil, err
+	}
+	if opts.QuotaPeriod <= 0 {
+		opts.QuotaPeriod = time.Hour
+	}
+
+	now := time.Now()
+	key := &Key{
+		ID:          keyID,
+		Prefix:      m.options.Prefix,
+		Hash:        hashSecret(secret),
+		Name:        opts.Name,
+		Owner:       opts.Owner,
+		Scopes:      opts.Scopes,
+		Quota:       opts.Quota,
+		QuotaPeriod: opts.QuotaPeriod,
+		CreatedAt:   now,
+	}
+	if opts.TTL > 0 {
+		key.ExpiresAt = now.Add(opts.TTL)
+	}
+	if err := m.store.Save(key); err != nil {
+		return "", nil, err
+	}
+	return m.options.Prefix + "_" + keyID + "_" + secret, key, nil
+}
+
+// Verify 校验明文密钥，返回对应的密钥信息
+// 不检查权限范围和配额，由 Middleware 或调用方自行处理
+func (m *Manager) Verify(raw string) (*Key, error) {
+	keyID, secret, ok := m.parse(raw)
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+	key, err := m.store.Get(keyID)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(hashSecret(secret))) != 1 {
+		return nil, ErrInvalidKey
+	}
+	if key.Revoked() {
+		return nil, ErrKeyRevoked
+	}
+	if key.Expired() {
+		return nil, ErrKeyExpired
+	}
+	return key, nil
+}
+
+// Revoke 吊销密钥，吊销后立即失效
+func (m *Manager) Revoke(keyID string) error {
+	key, err := m.store.Get(keyID)
+	if err != nil {
+		return err
+	}
+	if key.Revoked() {
+		return nil
+	}
+	key.RevokedAt = time.Now()
+	return m.store.Save(key)
+}
+
+// Get 获取密钥信息
+func (m *Manager) Get(keyID string) (*Key, error) {
+	return m.store.Get(keyID)
+}
+
+// List 获取指定所有者的全部密钥
+func (m *Manager) List(owner string) ([]*Key, error) {
+	return m.store.List(owner)
+}
+
+// Consume 消耗一次调用配额，超出配额时返回 ErrQuotaExceeded
+func (m *Manager) Consume(key *Key) error {
+	if key.Quota <= 0 {
+		return nil
+	}
+	window := time.Now().Truncate(key.QuotaPeriod)
+	count, err := m.store.Incr(key.ID, window)
+	if err != nil {
+		return err
+	}
+	if count > key.Quota {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
+
+// Middleware 返回密钥校验中间件
+// 从请求头 X-API-Key 或 "Authorization: ApiKey <key>" 读取密钥，校验通过后
+// 将 *Key 写入上下文键 api_key，将所有者写入 current_user
+// scopes: 访问接口需要具备的全部权限范围
+func (m *Manager) Middleware(scopes ...string) core.HandlerFunc {
+	return func(c *core.Context) {
+		key, err := m.Verify(extractKey(c))
+		if err == nil {
+			for _, scope := range scopes {
+				if !key.HasScope(scope) {
+					err = ErrInsufficientScope
+					break
+				}
+			}
+		}
+		if err == nil {
+			err = m.Consume(key)
+		}
+		if err != nil {
+			c.Fail(err)
+			c.Abort()
+			return
+		}
+
+		m.touch(key)
+		c.Set(ContextKey, key)
+		c.Set(CurrentUserKey, key.Owner)
+		c.Next()
+	}
+}
+
+// FromContext 获取中间件写入上下文的密钥，未通过密钥认证时返回 nil
+func FromContext(c *core.Context) *Key {
+	key, _ := c.Get(ContextKey).(*Key)
+	return key
+}
+
+// touch 更新密钥的最后使用时间
+// 只写入 LastUsedAt，不保存请求开始时读取的整个密钥，避免覆盖期间发生的吊销或权限变更
+func (m *Manager) touch(key *Key) {
+	now := time.Now()
+	if now.Sub(key.LastUsedAt) < m.options.TouchInterval {
+		return
+	}
+	key.LastUsedAt = now
+	_ = m.store.Touch(key.ID, now)
+}
+
+// parse 解析明文密钥，返回密钥ID和密文
+func (m *Manager) parse(raw string) (keyID, secret string, ok bool) {
+	rest, found := strings.CutPrefix(raw, m.options.Prefix+"_")
+	if !found {
+		return "", "", false
+	}
+	keyID, secret, ok = strings.Cut(rest, "_")
+	return keyID, secret, ok && keyID != "" && secret != ""
+}
+
+// extractKey 从请求中读取密钥
+func extractKey(c *core.Context) string {
+	if key := c.GetHeader(HeaderName); key != "" {
+		return key
+	}
+	if auth := c.GetHeader("Authorization"); len(auth) > 7 && strings.EqualFold(auth[:7], "ApiKey ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return ""
+}
+
+// randomSecret 生成随机密文，使用不含下划线的小写 base32 编码
+func randomSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b)), nil
+}
+
+// hashSecret 计算密文哈希
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,38 @@
+package apikey
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTouchKeepsRevocation(t *testing.T) {
+	m := NewManager(NewMemoryStore(), Options{Prefix: "ek_test"})
+	raw, key, err := m.Create(CreateOptions{Name: "test", Owner: "partner", Scopes: []string{"*"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 请求开始时读取密钥，处理期间密钥被吊销
+	snapshot, err := m.Verify(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Revoke(key.ID); err != nil {
+		t.Fatal(err)
+	}
+	m.touch(snapshot)
+
+	stored, err := m.Get(key.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stored.Revoked() {
+		t.Fatal("更新最后使用时间后吊销失效")
+	}
+	if stored.LastUsedAt.IsZero() || time.Since(stored.LastUsedAt) > time.Minute {
+		t.Errorf("最后使用时间没有更新: %v", stored.LastUsedAt)
+	}
+	if _, err := m.Verify(raw); err != ErrKeyRevoked {
+		t.Errorf("校验已吊销的密钥返回 %v，期望 ErrKeyRevoked", err)
+	}
+}
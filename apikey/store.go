@@ -0,0 +1,110 @@
+package apikey
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNotFound 密钥不存在
+var ErrNotFound = errors.New("apikey: 密钥不存在")
+
+// Store 定义了 API 密钥存储，可基于数据库或 Redis 实现
+type Store interface {
+	// Save 新增或更新密钥
+	Save(key *Key) error
+	// Get 根据密钥ID获取密钥
+	Get(id string) (*Key, error)
+	// List 获取指定所有者的密钥，owner 为空时返回全部
+	List(owner string) ([]*Key, error)
+	// Incr 将密钥在指定时间窗口内的调用次数加一，返回加一后的次数
+	Incr(id string, window time.Time) (int64, error)
+	// Touch 只更新密钥的最后使用时间，不能覆盖吊销时间、权限范围等其他字段；密钥不存在时返回 ErrNotFound
+	Touch(id string, t time.Time) error
+}
+
+// MemoryStore 是基于内存的存储，适用于单实例和测试
+type MemoryStore struct {
+	mu     sync.RWMutex
+	keys   map[string]*Key
+	usages map[string]usage
+}
+
+// usage 是某个时间窗口内的调用次数
+type usage struct {
+	window time.Time
+	count  int64
+}
+
+// NewMemoryStore 创建内存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		keys:   make(map[string]*Key),
+		usages: make(map[string]usage),
+	}
+}
+
+// Save 新增或更新密钥
+func (s *MemoryStore) Save(key *Key) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := *key
+	k.Scopes = append([]string(nil), key.Scopes...)
+	s.keys[k.ID] = &k
+	return nil
+}
+
+// Get 根据密钥ID获取密钥
+func (s *MemoryStore) Get(id string) (*Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	k, ok := s.keys[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *k
+	return &copied, nil
+}
+
+// List 获取指定所有者的密钥，按创建时间排序
+func (s *MemoryStore) List(owner string) ([]*Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]*Key, 0)
+	for _, k := range s.keys {
+		if owner == "" || k.Owner == owner {
+			copied := *k
+			list = append(list, &copied)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+	return list, nil
+}
+
+// Touch 只更新密钥的最后使用时间
+func (s *MemoryStore) Touch(id string, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k, ok := s.keys[id]
+	if !ok {
+		return ErrNotFound
+	}
+	copied := *k
+	copied.LastUsedAt = t
+	s.keys[id] = &copied
+	return nil
+}
+
+// Incr 将密钥在指定时间窗口内的调用次数加一，进入新窗口时重新计数
+func (s *MemoryStore) Incr(id string, window time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.usages[id]
+	if !u.window.Equal(window) {
+		u = usage{window: window}
+	}
+	u.count++
+	s.usages[id] = u
+	return u.count, nil
+}
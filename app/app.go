@@ -0,0 +1,252 @@
+// Package app 提供了应用容器
+// 通过构造函数注册依赖（日志、配置、数据库、Redis、链路追踪、Web 引擎等），按类型自动注入，
+// 按依赖顺序启动各模块，并在关闭时逆序停止，避免手动组装时遗漏资源清理
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/xzl-go/easygo/core"
+	"github.com/xzl-go/easygo/logger"
+)
+
+// Hook 是模块的生命周期钩子
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Module 是具有启动和停止过程的模块
+type Module interface {
+	Name() string
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+// Lifecycle 管理生命周期钩子，构造函数可以声明 *Lifecycle 参数来注册钩子
+// 由于依赖总是先于使用方构造，钩子的注册顺序即为依赖顺序
+type Lifecycle struct {
+	mu    sync.Mutex
+	hooks []Hook
+}
+
+// Append 注册生命周期钩子
+func (l *Lifecycle) Append(hook Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, hook)
+}
+
+// snapshot 返回当前已注册钩子的副本
+func (l *Lifecycle) snapshot() []Hook {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Hook(nil), l.hooks...)
+}
+
+// Options 定义了应用配置
+type Options struct {
+	// StartTimeout 启动全部模块的超时时间，默认 15 秒
+	StartTimeout time.Duration
+	// StopTimeout 停止全部模块的超时时间，默认 30 秒
+	StopTimeout time.Duration
+	// Signals 触发关闭的信号，默认为 SIGINT 和 SIGTERM
+	Signals []os.Signal
+	// Report 启动完成后将启动诊断报告写入日志
+	Report bool
+	// Version 报告中的版本号，默认读取二进制的模块版本
+	Version string
+	// Config 报告中展示的已解析配置，密码、密钥等敏感字段会被脱敏
+	Config interface{}
+}
+
+// App 是应用容器
+type App struct {
+	options   Options
+	container *container
+	lifecycle *Lifecycle
+
+	mu        sync.Mutex
+	started   []Hook
+	startedAt time.Time
+	servers   []server
+}
+
+// New 创建应用容器
+// 容器中默认提供 *App 和 *Lifecycle
+// options: 应用配置
+func New(options Options) *App {
+	if options.StartTimeout <= 0 {
+		options.StartTimeout = 15 * time.Second
+	}
+	if options.StopTimeout <= 0 {
+		options.StopTimeout = 30 * time.Second
+	}
+	if len(options.Signals) == 0 {
+		options.Signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	a := &App{
+		options:   options,
+		container: newContainer(),
+		lifecycle: &Lifecycle{},
+	}
+	a.container.supply(reflect.TypeOf(a), reflect.ValueOf(a))
+	a.container.supply(reflect.TypeOf(a.lifecycle), reflect.ValueOf(a.lifecycle))
+	return a
+}
+
+// Provide 注册构造函数
+// 构造函数的参数会从容器中按类型注入，返回值注册为可注入的类型，最后一个返回值可以是 error。
+// 构造函数在首次被依赖时调用，结果为单例。
+// 例如：func NewUserService(db *gorm.DB, lc *app.Lifecycle) (*UserService, error)
+func (a *App) Provide(constructors ...interface{}) error {
+	for _, constructor := range constructors {
+		if err := a.container.provide(constructor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Supply 直接注册已创建的值，按值的实际类型注入
+func (a *App) Supply(values ...interface{}) {
+	for _, v := range values {
+		a.container.supply(reflect.TypeOf(v), reflect.ValueOf(v))
+	}
+}
+
+// Invoke 调用函数并注入其参数，通常用于注册路由、触发模块构造
+// 函数的最后一个返回值为 error 时返回该错误
+func (a *App) Invoke(fn interface{}) error {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return fmt.Errorf("app: Invoke 的参数必须是函数，实际为 %T", fn)
+	}
+	_, err := a.container.call(v, nil)
+	return err
+}
+
+// Resolve 从容器中取出值并写入指针
+// ptr: 目标指针，例如 var db *gorm.DB; app.Resolve(&db)
+func (a *App) Resolve(ptr interface{}) error {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("app: Resolve 的参数必须是非 nil 指针，实际为 %T", ptr)
+	}
+	resolved, err := a.container.resolve(v.Elem().Type(), nil)
+	if err != nil {
+		return err
+	}
+	v.Elem().Set(resolved)
+	return nil
+}
+
+// Register 将模块注册到生命周期中，按注册顺序启动
+func (a *App) Register(modules ...Module) {
+	for _, m := range modules {
+		a.lifecycle.Append(Hook{Name: m.Name(), OnStart: m.Start, OnStop: m.Stop})
+	}
+}
+
+// Start 按注册顺序启动各模块
+// 某个模块启动失败时，逆序停止已启动的模块并返回错误
+func (a *App) Start(ctx context.Context) error {
+	for _, hook := range a.lifecycle.snapshot() {
+		if hook.OnStart != nil {
+			if err := hook.OnStart(ctx); err != nil {
+				startErr := fmt.Errorf("app: 启动 %s 失败: %w", hook.Name, err)
+				return errors.Join(startErr, a.Stop(ctx))
+			}
+		}
+		a.mu.Lock()
+		a.started = append(a.started, hook)
+		a.mu.Unlock()
+		logger.Info("模块 %s 已启动", hook.Name)
+	}
+	a.mu.Lock()
+	a.startedAt = time.Now()
+	a.mu.Unlock()
+	if a.options.Report {
+		a.logReport()
+	}
+	return nil
+}
+
+// Stop 逆序停止已启动的模块，某个模块停止失败不影响其他模块
+func (a *App) Stop(ctx context.Context) error {
+	a.mu.Lock()
+	started := a.started
+	a.started = nil
+	a.servers = nil
+	a.mu.Unlock()
+
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		hook := started[i]
+		if hook.OnStop == nil {
+			continue
+		}
+		if err := hook.OnStop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("app: 停止 %s 失败: %w", hook.Name, err))
+			continue
+		}
+		logger.Info("模块 %s 已停止", hook.Name)
+	}
+	return errors.Join(errs...)
+}
+
+// Run 启动应用并阻塞，收到退出信号后停止全部模块
+func (a *App) Run() error {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, a.options.Signals...)
+	defer signal.Stop(sigCh)
+
+	startCtx, cancel := context.WithTimeout(context.Background(), a.options.StartTimeout)
+	err := a.Start(startCtx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	sig := <-sigCh
+	logger.Info("收到信号 %v，开始停止应用", sig)
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), a.options.StopTimeout)
+	defer cancel()
+	return a.Stop(stopCtx)
+}
+
+// Serve 将 Web 引擎注册为模块：启动时同步监听地址并在后台提供服务，停止时优雅关闭
+// engine: Web 引擎
+// addr: 监听地址
+func (a *App) Serve(engine *core.Engine, addr string) {
+	a.lifecycle.Append(Hook{
+		Name: "http " + addr,
+		OnStart: func(ctx context.Context) error {
+			ln, err := net.Listen("tcp", addr)
+			if err != nil {
+				return err
+			}
+			a.mu.Lock()
+			a.servers = append(a.servers, server{engine: engine, addr: ln.Addr().String()})
+			a.mu.Unlock()
+			go func() {
+				if err := engine.RunListener(ln); err != nil {
+					logger.Error("HTTP 服务异常退出：%v", err)
+				}
+			}()
+			return nil
+		},
+		OnStop: engine.Shutdown,
+	})
+}
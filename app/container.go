@@ -0,0 +1,156 @@
+package app
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// provider 是已注册的构造函数
+type provider struct {
+	fn      reflect.Value
+	outputs []reflect.Type
+}
+
+// container 是基于类型的依赖注入容器，构造函数在首次被依赖时调用，结果以单例缓存
+type container struct {
+	mu        sync.Mutex
+	providers map[reflect.Type]*provider
+	values    map[reflect.Type]reflect.Value
+	resolving map[reflect.Type]bool
+}
+
+func newContainer() *container {
+	return &container{
+		providers: make(map[reflect.Type]*provider),
+		values:    make(map[reflect.Type]reflect.Value),
+		resolving: make(map[reflect.Type]bool),
+	}
+}
+
+// provide 注册构造函数
+// 构造函数的参数为依赖，返回值为提供的类型，最后一个返回值可以是 error
+func (c *container) provide(constructor interface{}) error {
+	fn := reflect.ValueOf(constructor)
+	if fn.Kind() != reflect.Func {
+		return fmt.Errorf("app: 构造函数必须是函数，实际为 %T", constructor)
+	}
+	t := fn.Type()
+
+	p := &provider{fn: fn}
+	for i := 0; i < t.NumOut(); i++ {
+		out := t.Out(i)
+		if out == errorType {
+			if i != t.NumOut()-1 {
+				return fmt.Errorf("app: %s 的 error 返回值必须在最后", t)
+			}
+			continue
+		}
+		p.outputs = append(p.outputs, out)
+	}
+	if len(p.outputs) == 0 {
+		return fmt.Errorf("app: %s 没有提供任何类型", t)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, out := range p.outputs {
+		if _, exists := c.providers[out]; exists {
+			return fmt.Errorf("app: 类型 %s 已注册", out)
+		}
+		if _, exists := c.values[out]; exists {
+			return fmt.Errorf("app: 类型 %s 已注册", out)
+		}
+	}
+	for _, out := range p.outputs {
+		c.providers[out] = p
+	}
+	return nil
+}
+
+// supply 直接注册已创建的值
+func (c *container) supply(t reflect.Type, v reflect.Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[t] = v
+}
+
+// resolve 获取指定类型的值，必要时调用构造函数
+func (c *container) resolve(t reflect.Type, path []reflect.Type) (reflect.Value, error) {
+	c.mu.Lock()
+	if v, ok := c.values[t]; ok {
+		c.mu.Unlock()
+		return v, nil
+	}
+	p, ok := c.providers[t]
+	if !ok {
+		c.mu.Unlock()
+		return reflect.Value{}, fmt.Errorf("app: 类型 %s 未注册%s", t, formatPath(path))
+	}
+	if c.resolving[t] {
+		c.mu.Unlock()
+		return reflect.Value{}, fmt.Errorf("app: 存在循环依赖 %s", formatPath(append(path, t)))
+	}
+	for _, out := range p.outputs {
+		c.resolving[out] = true
+	}
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		for _, out := range p.outputs {
+			delete(c.resolving, out)
+		}
+		c.mu.Unlock()
+	}()
+
+	results, err := c.call(p.fn, append(path, t))
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	c.mu.Lock()
+	for i, r := range results {
+		c.values[p.outputs[i]] = r
+	}
+	v := c.values[t]
+	c.mu.Unlock()
+	return v, nil
+}
+
+// call 解析函数参数并调用，最后一个返回值为非 nil 的 error 时返回该错误
+func (c *container) call(fn reflect.Value, path []reflect.Type) ([]reflect.Value, error) {
+	t := fn.Type()
+	args := make([]reflect.Value, t.NumIn())
+	for i := range args {
+		v, err := c.resolve(t.In(i), path)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+
+	results := fn.Call(args)
+	if n := len(results); n > 0 && t.Out(n-1) == errorType {
+		if err, _ := results[n-1].Interface().(error); err != nil {
+			return nil, err
+		}
+		results = results[:n-1]
+	}
+	return results, nil
+}
+
+// formatPath 格式化依赖路径，便于定位错误
+func formatPath(path []reflect.Type) string {
+	if len(path) == 0 {
+		return ""
+	}
+	names := make([]string, len(path))
+	for i, t := range path {
+		names[i] = t.String()
+	}
+	return "（依赖路径：" + strings.Join(names, " -> ") + "）"
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/xzl-go/easygo/core"
+	"github.com/xzl-go/easygo/logger"
+)
+
+// Report 是启动诊断报告，回答“这个实例实际运行的是什么”
+type Report struct {
+	StartedAt time.Time      `json:"started_at"`
+	Hostname  string         `json:"hostname"`
+	PID       int            `json:"pid"`
+	Mode      string         `json:"mode"`
+	Build     BuildInfo      `json:"build"`
+	Modules   []string       `json:"modules"` // 按启动顺序排列
+	Servers   []ServerReport `json:"servers"`
+	Config    interface{}    `json:"config,omitempty"` // 敏感字段已脱敏
+}
+
+// BuildInfo 是构建信息
+type BuildInfo struct {
+	Module    string `json:"module"`
+	Version   string `json:"version"`
+	GoVersion string `json:"go_version"`
+	Revision  string `json:"revision,omitempty"`
+	Time      string `json:"time,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+}
+
+// ServerReport 是 Web 服务的信息
+type ServerReport struct {
+	Addr        string           `json:"addr"`
+	Middlewares []string         `json:"middlewares"` // 按执行顺序排列
+	Routes      []core.RouteInfo `json:"routes"`
+}
+
+// server 是通过 Serve 注册的 Web 服务
+type server struct {
+	engine *core.Engine
+	addr   string
+}
+
+// Report 生成启动诊断报告
+func (a *App) Report() Report {
+	hostname, _ := os.Hostname()
+	report := Report{
+		Hostname: hostname,
+		PID:      os.Getpid(),
+		Mode:     core.Mode(),
+		Build:    buildInfo(a.options.Version),
+		Config:   maskConfig(a.options.Config),
+	}
+
+	a.mu.Lock()
+	report.StartedAt = a.startedAt
+	for _, hook := range a.started {
+		report.Modules = append(report.Modules, hook.Name)
+	}
+	servers := append([]server(nil), a.servers...)
+	a.mu.Unlock()
+
+	for _, s := range servers {
+		report.Servers = append(report.Servers, ServerReport{
+			Addr:        s.addr,
+			Middlewares: s.engine.Middlewares(),
+			Routes:      s.engine.Routes(),
+		})
+	}
+	return report
+}
+
+// ReportHandler 返回输出启动诊断报告的处理函数，应注册在受保护的管理接口下
+// 例如：admin.GET("/debug/report", application.ReportHandler())
+func (a *App) ReportHandler() core.HandlerFunc {
+	return func(c *core.Context) {
+		c.Success(a.Report())
+	}
+}
+
+// logReport 将启动诊断报告写入日志
+func (a *App) logReport() {
+	data, err := json.MarshalIndent(a.Report(), "", "  ")
+	if err != nil {
+		logger.Error("生成启动报告失败：%v", err)
+		return
+	}
+	logger.Info("启动报告：\n%s", data)
+}
+
+// buildInfo 读取二进制中嵌入的构建信息
+// version: 指定的版本号，为空时使用模块版本
+func buildInfo(version string) BuildInfo {
+	info := BuildInfo{Version: version, GoVersion: runtime.Version()}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.Module = bi.Main.Path
+	if info.Version == "" {
+		info.Version = bi.Main.Version
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			info.Revision = s.Value
+		case "vcs.time":
+			info.Time = s.Value
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		}
+	}
+	return info
+}
+
+var (
+	// sensitiveKey 匹配需要脱敏的配置键
+	sensitiveKey = regexp.MustCompile(`(?i)(password|passwd|pwd|secret|token|api_?key|private_?key|credential|access_?key)`)
+	// dsnPassword 匹配连接串中的密码，例如 "user:pass@tcp(...)"、"redis://:pass@host"
+	dsnPassword = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.-]*://)?([^:/@\s]*):([^@\s]+)@`)
+)
+
+// maskConfig 将配置转换为通用结构并脱敏
+func maskConfig(config interface{}) interface{} {
+	if config == nil {
+		return nil
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil
+	}
+	return mask(value)
+}
+
+// mask 递归脱敏：敏感键的值替换为 "******"，连接串中的密码替换为 "******"
+func mask(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if sensitiveKey.MatchString(k) && child != nil && child != "" {
+				v[k] = "******"
+				continue
+			}
+			v[k] = mask(child)
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = mask(v[i])
+		}
+		return v
+	case string:
+		return dsnPassword.ReplaceAllString(v, "${1}${2}:******@")
+	default:
+		return value
+	}
+}
@@ -0,0 +1,218 @@
+// Package audit 提供了审计日志功能
+// 记录操作人、操作、资源、变更前后差异、IP 和追踪ID，并写入可插拔的存储（数据库、文件、消息队列）
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/xzl-go/easygo/core"
+	"github.com/xzl-go/easygo/jwt"
+	"github.com/xzl-go/easygo/logger"
+)
+
+// Change 是单个字段的变更
+type Change struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// Entry 是一条审计记录
+type Entry struct {
+	Time      time.Time         `json:"time"`
+	Actor     string            `json:"actor"`              // 操作人
+	Action    string            `json:"action"`             // 操作
+	Resource  string            `json:"resource"`           // 资源
+	Before    json.RawMessage   `json:"before,omitempty"`   // 变更前的数据
+	After     json.RawMessage   `json:"after,omitempty"`    // 变更后的数据
+	Changes   map[string]Change `json:"changes,omitempty"`  // 字段级差异
+	IP        string            `json:"ip"`                 // 客户端IP
+	TraceID   string            `json:"trace_id,omitempty"` // 追踪ID
+	Method    string            `json:"method"`             // 请求方法
+	Path      string            `json:"path"`               // 请求路径
+	Status    int               `json:"status"`             // 响应状态码
+	UserAgent string            `json:"user_agent,omitempty"`
+}
+
+// Sink 定义了审计记录的存储
+type Sink interface {
+	Write(ctx context.Context, entry *Entry) error
+}
+
+// ActorFunc 从请求上下文中解析操作人
+type ActorFunc func(c *core.Context) string
+
+// Options 定义了审计记录器配置
+type Options struct {
+	// Actor 解析操作人，默认读取 JWT 中间件写入的 claims，其次读取 current_user
+	Actor ActorFunc
+	// AutoMethods 未显式调用 ctx.Audit 时自动记录的请求方法，默认为 POST、PUT、PATCH、DELETE
+	AutoMethods []string
+	// BufferSize 异步写入队列长度，默认 1024，队列满时丢弃并记录错误日志
+	BufferSize int
+}
+
+// Recorder 是审计记录器
+// 审计记录通过后台协程异步写入存储，避免拖慢请求
+type Recorder struct {
+	sinks   []Sink
+	options Options
+	auto    map[string]bool
+	queue   chan *Entry
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+// New 创建审计记录器
+// options: 记录器配置
+// sinks: 审计记录存储，同一条记录会写入全部存储
+func New(options Options, sinks ...Sink) *Recorder {
+	if options.Actor == nil {
+		options.Actor = DefaultActor
+	}
+	if options.AutoMethods == nil {
+		options.AutoMethods = []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	}
+	if options.BufferSize <= 0 {
+		options.BufferSize = 1024
+	}
+	r := &Recorder{
+		sinks:   sinks,
+		options: options,
+		auto:    make(map[string]bool),
+		queue:   make(chan *Entry, options.BufferSize),
+	}
+	for _, m := range options.AutoMethods {
+		r.auto[m] = true
+	}
+	r.wg.Add(1)
+	go r.loop()
+	return r
+}
+
+// DefaultActor 默认的操作人解析函数
+func DefaultActor(c *core.Context) string {
+	if claims, ok := c.Get("claims").(*jwt.Claims); ok && claims != nil {
+		if claims.Username != "" {
+			return claims.Username
+		}
+		return claims.UserID
+	}
+	if user := c.Get("current_user"); user != nil {
+		if s, ok := user.(string); ok {
+			return s
+		}
+	}
+	return "anonymous"
+}
+
+// Middleware 返回审计中间件
+// 请求结束后将 ctx.Audit 记录的事件补充上下文信息后写入存储；
+// 未显式记录且请求方法属于 AutoMethods 时，以 "方法 路径" 作为操作自动记录。
+func (r *Recorder) Middleware() core.HandlerFunc {
+	return func(c *core.Context) {
+		c.Next()
+
+		entries := c.AuditEntries()
+		if len(entries) == 0 {
+			if !r.auto[c.Request.Method] {
+				return
+			}
+			entries = []core.AuditEntry{{
+				Action:   c.Request.Method + " " + c.Request.URL.Path,
+				Resource: c.Request.URL.Path,
+			}}
+		}
+
+		actor := r.options.Actor(c)
+		for _, e := range entries {
+			entry := &Entry{
+				Time:      time.Now(),
+				Actor:     actor,
+				Action:    e.Action,
+				Resource:  e.Resource,
+				IP:        c.ClientIP(),
+				TraceID:   c.TraceID(),
+				Method:    c.Request.Method,
+				Path:      c.Request.URL.Path,
+				Status:    c.Writer.Status(),
+				UserAgent: c.Request.UserAgent(),
+			}
+			entry.Before, entry.After, entry.Changes = Diff(e.Before, e.After)
+			r.Record(entry)
+		}
+	}
+}
+
+// Record 提交一条审计记录，可用于记录非 HTTP 请求触发的操作（如定时任务）
+func (r *Recorder) Record(entry *Entry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	select {
+	case r.queue <- entry:
+	default:
+		logger.Error("审计队列已满，丢弃记录：%s %s %s", entry.Actor, entry.Action, entry.Resource)
+	}
+}
+
+// Close 停止接收新记录，并等待队列中的记录全部写入
+func (r *Recorder) Close() {
+	r.once.Do(func() {
+		close(r.queue)
+	})
+	r.wg.Wait()
+}
+
+// loop 后台写入协程
+func (r *Recorder) loop() {
+	defer r.wg.Done()
+	for entry := range r.queue {
+		for _, sink := range r.sinks {
+			if err := sink.Write(context.Background(), entry); err != nil {
+				logger.Error("写入审计记录失败：%v", err)
+			}
+		}
+	}
+}
+
+// Diff 计算变更前后数据的字段级差异
+// before、after 会被序列化为 JSON，并按顶层字段比较
+func Diff(before, after interface{}) (beforeJSON, afterJSON json.RawMessage, changes map[string]Change) {
+	if before != nil {
+		beforeJSON, _ = json.Marshal(before)
+	}
+	if after != nil {
+		afterJSON, _ = json.Marshal(after)
+	}
+	if before == nil && after == nil {
+		return
+	}
+
+	var b, a map[string]interface{}
+	_ = json.Unmarshal(beforeJSON, &b)
+	_ = json.Unmarshal(afterJSON, &a)
+	if b == nil && a == nil {
+		return
+	}
+
+	changes = make(map[string]Change)
+	for k, bv := range b {
+		if av, ok := a[k]; !ok || !reflect.DeepEqual(av, bv) {
+			changes[k] = Change{From: bv, To: a[k]}
+		}
+	}
+	for k, av := range a {
+		if _, ok := b[k]; !ok {
+			changes[k] = Change{From: nil, To: av}
+		}
+	}
+	if len(changes) == 0 {
+		changes = nil
+	}
+	return
+}
@@ -0,0 +1,147 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FileSink 将审计记录以 JSON Lines 格式追加写入文件
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink 创建文件存储
+// path: 审计日志文件路径，目录不存在时自动创建
+func NewFileSink(path string) (*FileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{file: f}, nil
+}
+
+// Write 写入一条审计记录
+func (s *FileSink) Write(ctx context.Context, entry *Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(append(data, '\n'))
+	return err
+}
+
+// Close 关闭文件
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// Record 是审计记录的数据库模型
+type Record struct {
+	ID        uint      `gorm:"primaryKey"`
+	Time      time.Time `gorm:"index"`
+	Actor     string    `gorm:"size:128;index"`
+	Action    string    `gorm:"size:128;index"`
+	Resource  string    `gorm:"size:255;index"`
+	Before    string    `gorm:"type:text"`
+	After     string    `gorm:"type:text"`
+	Changes   string    `gorm:"type:text"`
+	IP        string    `gorm:"size:64"`
+	TraceID   string    `gorm:"size:64;index"`
+	Method    string    `gorm:"size:16"`
+	Path      string    `gorm:"size:255"`
+	Status    int
+	UserAgent string `gorm:"size:255"`
+}
+
+// TableName 返回审计记录表名
+func (Record) TableName() string {
+	return "audit_logs"
+}
+
+// GormSink 将审计记录写入数据库表 audit_logs
+type GormSink struct {
+	db *gorm.DB
+}
+
+// NewGormSink 创建数据库存储，并自动迁移审计表
+// db: GORM 数据库连接
+func NewGormSink(db *gorm.DB) (*GormSink, error) {
+	if err := db.AutoMigrate(&Record{}); err != nil {
+		return nil, err
+	}
+	return &GormSink{db: db}, nil
+}
+
+// Write 写入一条审计记录
+func (s *GormSink) Write(ctx context.Context, entry *Entry) error {
+	changes := ""
+	if entry.Changes != nil {
+		data, err := json.Marshal(entry.Changes)
+		if err != nil {
+			return err
+		}
+		changes = string(data)
+	}
+	return s.db.WithContext(ctx).Create(&Record{
+		Time:      entry.Time,
+		Actor:     entry.Actor,
+		Action:    entry.Action,
+		Resource:  entry.Resource,
+		Before:    string(entry.Before),
+		After:     string(entry.After),
+		Changes:   changes,
+		IP:        entry.IP,
+		TraceID:   entry.TraceID,
+		Method:    entry.Method,
+		Path:      entry.Path,
+		Status:    entry.Status,
+		UserAgent: entry.UserAgent,
+	}).Error
+}
+
+// Publisher 定义了消息队列的发布接口，可基于 Kafka、RabbitMQ、Redis Stream 等实现
+type Publisher interface {
+	Publish(ctx context.Context, topic string, payload []byte) error
+}
+
+// MQSink 将审计记录以 JSON 格式发布到消息队列
+type MQSink struct {
+	publisher Publisher
+	topic     string
+}
+
+// NewMQSink 创建消息队列存储
+// publisher: 消息发布者
+// topic: 主题
+func NewMQSink(publisher Publisher, topic string) *MQSink {
+	return &MQSink{publisher: publisher, topic: topic}
+}
+
+// Write 发布一条审计记录
+func (s *MQSink) Write(ctx context.Context, entry *Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return s.publisher.Publish(ctx, s.topic, data)
+}
+
+// SinkFunc 是函数形式的存储，便于自定义
+type SinkFunc func(ctx context.Context, entry *Entry) error
+
+// Write 调用函数写入审计记录
+func (f SinkFunc) Write(ctx context.Context, entry *Entry) error {
+	return f(ctx, entry)
+}
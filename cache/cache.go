@@ -0,0 +1,192 @@
+// Package cache 提供了统一的键值缓存接口
+// 内置基于内存的实现，支持按键设置过期时间
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache 定义了缓存的基本行为
+type Cache interface {
+	// Get 获取缓存值，不存在或已过期时返回 false
+	Get(key string) (string, bool)
+	// Set 设置缓存值，ttl 为 0 表示永不过期
+	Set(key, value string, ttl time.Duration) error
+	// Delete 删除缓存值
+	Delete(key string) error
+}
+
+// Adder 是支持原子地在键不存在时写入的缓存，用于一次性令牌、防重放随机串等只能使用一次的标记
+type Adder interface {
+	// SetNX 键不存在或已过期时设置缓存值，返回是否写入；并发写入同一个键时只有一次成功
+	SetNX(key, value string, ttl time.Duration) (bool, error)
+}
+
+// SetNX 键不存在时设置缓存值，返回是否写入
+// 缓存实现了 Adder 时为原子操作；否则先 Get 再 Set，并发写入同一个键时可能都返回 true
+// c: 缓存
+// key: 键
+// value: 值
+// ttl: 过期时间，为 0 表示永不过期
+func SetNX(c Cache, key, value string, ttl time.Duration) (bool, error) {
+	if a, ok := c.(Adder); ok {
+		return a.SetNX(key, value, ttl)
+	}
+	if _, ok := c.Get(key); ok {
+		return false, nil
+	}
+	return true, c.Set(key, value, ttl)
+}
+
+// Taker 是支持原子地取出并删除缓存值的缓存，用于验证码、OAuth state 等只能使用一次的值
+type Taker interface {
+	// Take 取出并删除缓存值，不存在或已过期时返回 false；并发取出同一个键时只有一次成功
+	Take(key string) (string, bool)
+}
+
+// Take 取出并删除缓存值，不存在或已过期时返回 false
+// 缓存实现了 Taker 时为原子操作；否则先 Get 再 Delete，并发取出同一个键时可能都返回 true
+// c: 缓存
+// key: 键
+func Take(c Cache, key string) (string, bool) {
+	if t, ok := c.(Taker); ok {
+		return t.Take(key)
+	}
+	value, ok := c.Get(key)
+	if !ok {
+		return "", false
+	}
+	if err := c.Delete(key); err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+// item 是缓存条目
+type item struct {
+	value    string
+	expireAt time.Time // 零值表示永不过期
+}
+
+// expired 判断条目是否过期
+func (i item) expired(now time.Time) bool {
+	return !i.expireAt.IsZero() && now.After(i.expireAt)
+}
+
+// MemoryCache 是基于内存的缓存实现
+// 过期条目在读取时惰性删除，并由后台协程定期清理
+type MemoryCache struct {
+	mu    sync.RWMutex
+	items map[string]item
+	stop  chan struct{}
+}
+
+// NewMemory 创建一个内存缓存
+// cleanupInterval: 过期条目清理间隔，为 0 时不启动后台清理
+func NewMemory(cleanupInterval time.Duration) *MemoryCache {
+	c := &MemoryCache{
+		items: make(map[string]item),
+		stop:  make(chan struct{}),
+	}
+	if cleanupInterval > 0 {
+		go c.janitor(cleanupInterval)
+	}
+	return c
+}
+
+// Get 获取缓存值
+func (c *MemoryCache) Get(key string) (string, bool) {
+	c.mu.RLock()
+	it, ok := c.items[key]
+	c.mu.RUnlock()
+	if !ok {
+		return "", false
+	}
+	if it.expired(time.Now()) {
+		c.Delete(key)
+		return "", false
+	}
+	return it.value, true
+}
+
+// Set 设置缓存值
+func (c *MemoryCache) Set(key, value string, ttl time.Duration) error {
+	it := item{value: value}
+	if ttl > 0 {
+		it.expireAt = time.Now().Add(ttl)
+	}
+	c.mu.Lock()
+	c.items[key] = it
+	c.mu.Unlock()
+	return nil
+}
+
+// SetNX 键不存在或已过期时设置缓存值，返回是否写入
+func (c *MemoryCache) SetNX(key, value string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	it := item{value: value}
+	if ttl > 0 {
+		it.expireAt = now.Add(ttl)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if old, ok := c.items[key]; ok && !old.expired(now) {
+		return false, nil
+	}
+	c.items[key] = it
+	return true, nil
+}
+
+// Take 取出并删除缓存值
+func (c *MemoryCache) Take(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	it, ok := c.items[key]
+	if !ok {
+		return "", false
+	}
+	delete(c.items, key)
+	if it.expired(time.Now()) {
+		return "", false
+	}
+	return it.value, true
+}
+
+// Delete 删除缓存值
+func (c *MemoryCache) Delete(key string) error {
+	c.mu.Lock()
+	delete(c.items, key)
+	c.mu.Unlock()
+	return nil
+}
+
+// Close 停止后台清理协程
+func (c *MemoryCache) Close() {
+	select {
+	case <-c.stop:
+	default:
+		close(c.stop)
+	}
+}
+
+// janitor 定期清理过期条目
+func (c *MemoryCache) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			now := time.Now()
+			c.mu.Lock()
+			for k, it := range c.items {
+				if it.expired(now) {
+					delete(c.items, k)
+				}
+			}
+			c.mu.Unlock()
+		case <-c.stop:
+			return
+		}
+	}
+}
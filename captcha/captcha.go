@@ -0,0 +1,222 @@
+// Package captcha 提供了图形验证码功能
+// 支持数字验证码和算术验证码，答案存储在 cache 模块中并自动过期
+package captcha
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xzl-go/easygo/cache"
+	"github.com/xzl-go/easygo/core"
+	errs "github.com/xzl-go/easygo/errors"
+)
+
+// ErrInvalidCaptcha 表示验证码错误或已过期
+var ErrInvalidCaptcha = errs.New(40010, "error.captcha", http.StatusBadRequest, "Invalid or expired captcha")
+
+// Mode 定义了验证码类型
+type Mode int
+
+// 验证码类型常量
+const (
+	ModeDigit Mode = iota // 数字验证码
+	ModeMath              // 算术验证码
+)
+
+// Config 定义了验证码配置
+type Config struct {
+	Mode      Mode          // 验证码类型
+	Length    int           // 数字验证码长度
+	Width     int           // 图片宽度
+	Height    int           // 图片高度
+	NoiseDots int           // 干扰点数量
+	TTL       time.Duration // 答案有效期
+	KeyPrefix string        // 缓存键前缀
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	return Config{
+		Mode:      ModeDigit,
+		Length:    4,
+		Width:     120,
+		Height:    40,
+		NoiseDots: 60,
+		TTL:       5 * time.Minute,
+		KeyPrefix: "captcha:",
+	}
+}
+
+// Captcha 是验证码管理器
+type Captcha struct {
+	config Config
+	store  cache.Cache
+}
+
+// New 创建一个验证码管理器
+// store: 用于保存答案的缓存
+// config: 验证码配置
+func New(store cache.Cache, config Config) *Captcha {
+	def := DefaultConfig()
+	if config.Length <= 0 {
+		config.Length = def.Length
+	}
+	if config.Width <= 0 {
+		config.Width = def.Width
+	}
+	if config.Height <= 0 {
+		config.Height = def.Height
+	}
+	if config.TTL <= 0 {
+		config.TTL = def.TTL
+	}
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = def.KeyPrefix
+	}
+	return &Captcha{config: config, store: store}
+}
+
+// Generate 生成一个新的验证码
+// 返回验证码ID、PNG 图片的 data URI 和可能的错误
+func (c *Captcha) Generate() (id, image string, err error) {
+	question, answer, err := c.challenge()
+	if err != nil {
+		return "", "", err
+	}
+	id, err = newID()
+	if err != nil {
+		return "", "", err
+	}
+	img, err := render(question, c.config.Width, c.config.Height, c.config.NoiseDots)
+	if err != nil {
+		return "", "", err
+	}
+	if err := c.store.Set(c.config.KeyPrefix+id, answer, c.config.TTL); err != nil {
+		return "", "", err
+	}
+	return id, img, nil
+}
+
+// Verify 校验验证码，无论成功与否都会使该验证码失效，防止暴力尝试
+// 缓存实现了 cache.Taker（内存缓存和 redis.Cache 均已实现）时原子地取出答案，并发提交同一个验证码时只有一次通过
+// id: 验证码ID
+// answer: 用户输入的答案
+func (c *Captcha) Verify(id, answer string) bool {
+	if id == "" || answer == "" {
+		return false
+	}
+	expected, ok := cache.Take(c.store, c.config.KeyPrefix+id)
+	if !ok {
+		return false
+	}
+	return strings.EqualFold(strings.TrimSpace(answer), expected)
+}
+
+// VerifyRequest 从请求中读取验证码ID与答案并校验
+// 依次从请求头 X-Captcha-Id / X-Captcha-Code、查询参数和表单参数 captcha_id / captcha_code 中读取
+func (c *Captcha) VerifyRequest(ctx *core.Context) bool {
+	id := ctx.GetHeader("X-Captcha-Id")
+	if id == "" {
+		id = ctx.Query("captcha_id")
+	}
+	code := ctx.GetHeader("X-Captcha-Code")
+	if code == "" {
+		code = ctx.Query("captcha_code")
+	}
+	if id == "" && code == "" && ctx.Request.Method != http.MethodGet {
+		id = ctx.PostForm("captcha_id")
+		code = ctx.PostForm("captcha_code")
+	}
+	return c.Verify(id, code)
+}
+
+// Handler 返回生成验证码的处理函数
+// 响应格式：{"captcha_id": "...", "image": "data:image/png;base64,..."}
+func (c *Captcha) Handler() core.HandlerFunc {
+	return func(ctx *core.Context) {
+		id, img, err := c.Generate()
+		if err != nil {
+			ctx.Fail(errs.ErrInternal.Wrap(err))
+			return
+		}
+		ctx.Success(map[string]string{
+			"captcha_id": id,
+			"image":      img,
+		})
+	}
+}
+
+// Middleware 返回验证码校验中间件，校验失败时中止请求
+func (c *Captcha) Middleware() core.HandlerFunc {
+	return func(ctx *core.Context) {
+		if !c.VerifyRequest(ctx) {
+			ctx.Fail(ErrInvalidCaptcha)
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// challenge 生成题目和答案
+func (c *Captcha) challenge() (question, answer string, err error) {
+	if c.config.Mode == ModeMath {
+		a, err := randInt(10)
+		if err != nil {
+			return "", "", err
+		}
+		b, err := randInt(10)
+		if err != nil {
+			return "", "", err
+		}
+		op, err := randInt(3)
+		if err != nil {
+			return "", "", err
+		}
+		switch op {
+		case 0:
+			return fmt.Sprintf("%d+%d=?", a, b), strconv.Itoa(a + b), nil
+		case 1:
+			if a < b {
+				a, b = b, a
+			}
+			return fmt.Sprintf("%d-%d=?", a, b), strconv.Itoa(a - b), nil
+		default:
+			return fmt.Sprintf("%d*%d=?", a, b), strconv.Itoa(a * b), nil
+		}
+	}
+
+	var sb strings.Builder
+	for i := 0; i < c.config.Length; i++ {
+		n, err := randInt(10)
+		if err != nil {
+			return "", "", err
+		}
+		sb.WriteByte(byte('0' + n))
+	}
+	return sb.String(), sb.String(), nil
+}
+
+// newID 生成随机的验证码ID
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// randInt 返回 [0, max) 范围内的随机整数
+func randInt(max int) (int, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max)))
+	if err != nil {
+		return 0, err
+	}
+	return int(n.Int64()), nil
+}
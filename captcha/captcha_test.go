@@ -0,0 +1,38 @@
+package captcha
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/xzl-go/easygo/cache"
+)
+
+func TestVerifyConcurrent(t *testing.T) {
+	store := cache.NewMemory(0)
+	c := New(store, Config{})
+	id, _, err := c.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	answer, ok := store.Get(c.config.KeyPrefix + id)
+	if !ok {
+		t.Fatal("答案没有保存到缓存")
+	}
+
+	var passed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if c.Verify(id, answer) {
+				passed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := passed.Load(); n != 1 {
+		t.Errorf("同一个验证码通过 %d 次，期望 1 次", n)
+	}
+}
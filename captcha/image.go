@@ -0,0 +1,138 @@
+package captcha
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	mrand "math/rand"
+)
+
+// glyphs 是 5x7 点阵字体，'#' 表示需要绘制的像素
+var glyphs = map[rune][7]string{
+	'0': {" ### ", "#   #", "#  ##", "# # #", "##  #", "#   #", " ### "},
+	'1': {"  #  ", " ##  ", "  #  ", "  #  ", "  #  ", "  #  ", " ### "},
+	'2': {" ### ", "#   #", "    #", "   # ", "  #  ", " #   ", "#####"},
+	'3': {"#####", "   # ", "  #  ", "   # ", "    #", "#   #", " ### "},
+	'4': {"   # ", "  ## ", " # # ", "#  # ", "#####", "   # ", "   # "},
+	'5': {"#####", "#    ", "#### ", "    #", "    #", "#   #", " ### "},
+	'6': {"  ## ", " #   ", "#    ", "#### ", "#   #", "#   #", " ### "},
+	'7': {"#####", "    #", "   # ", "  #  ", " #   ", " #   ", " #   "},
+	'8': {" ### ", "#   #", "#   #", " ### ", "#   #", "#   #", " ### "},
+	'9': {" ### ", "#   #", "#   #", " ####", "    #", "   # ", " ##  "},
+	'+': {"     ", "  #  ", "  #  ", "#####", "  #  ", "  #  ", "     "},
+	'-': {"     ", "     ", "     ", "#####", "     ", "     ", "     "},
+	'*': {"     ", "#   #", " # # ", "  #  ", " # # ", "#   #", "     "},
+	'=': {"     ", "     ", "#####", "     ", "#####", "     ", "     "},
+	'?': {" ### ", "#   #", "    #", "   # ", "  #  ", "     ", "  #  "},
+}
+
+// render 将文本绘制为带干扰的 PNG 图片，并返回 data URI
+func render(text string, width, height, noise int) (string, error) {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	bg := color.RGBA{R: 240, G: 240, B: 240, A: 255}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, bg)
+		}
+	}
+
+	runes := []rune(text)
+	cell := width / (len(runes) + 1)
+	scale := height / 10
+	if s := cell / 6; s < scale {
+		scale = s
+	}
+	if scale < 1 {
+		scale = 1
+	}
+
+	for i, r := range runes {
+		glyph, ok := glyphs[r]
+		if !ok {
+			continue
+		}
+		fg := randomColor()
+		ox := cell/2 + i*cell + mrand.Intn(3) - 1
+		oy := (height-7*scale)/2 + mrand.Intn(scale*2+1) - scale
+		for gy, row := range glyph {
+			for gx, ch := range row {
+				if ch != '#' {
+					continue
+				}
+				fillRect(img, ox+gx*scale, oy+gy*scale, scale, scale, fg)
+			}
+		}
+	}
+
+	// 干扰线
+	for i := 0; i < 3; i++ {
+		drawLine(img, 0, mrand.Intn(height), width-1, mrand.Intn(height), randomColor())
+	}
+	// 干扰点
+	for i := 0; i < noise; i++ {
+		img.Set(mrand.Intn(width), mrand.Intn(height), randomColor())
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// randomColor 返回随机的深色
+func randomColor() color.RGBA {
+	return color.RGBA{
+		R: uint8(mrand.Intn(150)),
+		G: uint8(mrand.Intn(150)),
+		B: uint8(mrand.Intn(150)),
+		A: 255,
+	}
+}
+
+// fillRect 填充矩形区域
+func fillRect(img *image.RGBA, x, y, w, h int, c color.Color) {
+	for dy := 0; dy < h; dy++ {
+		for dx := 0; dx < w; dx++ {
+			img.Set(x+dx, y+dy, c)
+		}
+	}
+}
+
+// drawLine 使用 Bresenham 算法绘制直线
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.Color) {
+	dx := abs(x1 - x0)
+	dy := -abs(y1 - y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		img.Set(x0, y0, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
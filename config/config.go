@@ -0,0 +1,270 @@
+// Package config 提供了统一的配置加载
+// 支持 YAML、JSON、TOML 文件和环境变量，按 默认值 -> 配置文件 -> 环境变量 的顺序合并到结构体，
+// 加载后按 validate 标签校验，并支持监听配置文件变更实现热更新
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/xzl-go/easygo/validator"
+)
+
+// Options 定义了配置加载选项
+type Options struct {
+	// EnvPrefix 环境变量前缀，例如 "APP" 时 APP_SERVER_ADDR 覆盖 server.addr；为空时不读取环境变量
+	EnvPrefix string
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Load 从文件加载配置到结构体
+// 结构体字段通过 yaml 标签声明键名（JSON、TOML 文件同样按 yaml 标签匹配），
+// 通过 default 标签声明默认值，通过 validate 标签声明校验规则，时长可以写作 "5s"、"1h30m"
+// path: 配置文件路径，按扩展名识别格式（.yaml、.yml、.json、.toml），为空时只使用默认值和环境变量
+// out: 目标结构体指针
+func Load(path string, out interface{}) error {
+	return LoadWithOptions(path, out, Options{})
+}
+
+// LoadWithOptions 按选项从文件和环境变量加载配置
+// path: 配置文件路径
+// out: 目标结构体指针
+// options: 加载选项
+func LoadWithOptions(path string, out interface{}, options Options) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config: out 必须是结构体指针，实际为 %T", out)
+	}
+
+	if err := applyDefaults(v.Elem()); err != nil {
+		return err
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("config: 读取配置文件失败: %w", err)
+		}
+		if err := decode(filepath.Ext(path), data, out); err != nil {
+			return fmt.Errorf("config: 解析 %s 失败: %w", path, err)
+		}
+	}
+	if options.EnvPrefix != "" {
+		if err := applyEnv(v.Elem(), strings.ToUpper(options.EnvPrefix)); err != nil {
+			return err
+		}
+	}
+	if err := validator.Validate(out); err != nil {
+		return fmt.Errorf("config: 配置校验失败: %w", err)
+	}
+	return nil
+}
+
+// decode 按格式解析配置文件
+// JSON 和 TOML 先解析为通用结构再转为 YAML 解码，保证各格式使用相同的键名和时长写法
+func decode(ext string, data []byte, out interface{}) error {
+	var doc interface{}
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		return yaml.Unmarshal(data, out)
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&doc); err != nil {
+			return err
+		}
+	case ".toml":
+		if err := toml.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("不支持的配置格式 %q", ext)
+	}
+	normalized, err := yaml.Marshal(normalize(doc))
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(normalized, out)
+}
+
+// normalize 将 json.Number 转换为数值，避免被编码为字符串
+func normalize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, item := range t {
+			t[k] = normalize(item)
+		}
+	case []interface{}:
+		for i, item := range t {
+			t[i] = normalize(item)
+		}
+	case json.Number:
+		if n, err := t.Int64(); err == nil {
+			return n
+		}
+		if f, err := t.Float64(); err == nil {
+			return f
+		}
+	}
+	return v
+}
+
+// applyDefaults 为零值字段设置 default 标签声明的默认值
+func applyDefaults(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		if isNested(fv) {
+			if err := applyDefaults(fv); err != nil {
+				return err
+			}
+			continue
+		}
+		def, ok := field.Tag.Lookup("default")
+		if !ok || !fv.IsZero() {
+			continue
+		}
+		if err := setValue(fv, def); err != nil {
+			return fmt.Errorf("config: 字段 %s 的默认值 %q 无效: %w", field.Name, def, err)
+		}
+	}
+	return nil
+}
+
+// applyEnv 使用环境变量覆盖配置，变量名为前缀加上以下划线连接的大写键名路径
+func applyEnv(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, inline := keyName(field)
+		if name == "-" {
+			continue
+		}
+		key := prefix
+		if !inline {
+			key += "_" + envName(name)
+		}
+		fv := v.Field(i)
+		if isNested(fv) {
+			if err := applyEnv(fv, key); err != nil {
+				return err
+			}
+			continue
+		}
+		raw, ok := os.LookupEnv(key)
+		if !ok {
+			continue
+		}
+		if err := setValue(fv, raw); err != nil {
+			return fmt.Errorf("config: 环境变量 %s 的值 %q 无效: %w", key, raw, err)
+		}
+	}
+	return nil
+}
+
+// keyName 返回字段的键名，以及是否为 yaml 内联字段
+func keyName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("yaml")
+	name, opts, _ := strings.Cut(tag, ",")
+	if strings.Contains(","+opts+",", ",inline,") {
+		return "", true
+	}
+	if name == "" {
+		name = strings.ToLower(field.Name)
+	}
+	return name, false
+}
+
+// envName 将键名转换为环境变量名，例如 "read-timeout" 转换为 "READ_TIMEOUT"
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// isNested 判断字段是否为需要递归处理的嵌套结构体
+func isNested(v reflect.Value) bool {
+	return v.Kind() == reflect.Struct && v.Type() != reflect.TypeOf(time.Time{})
+}
+
+// setValue 将字符串转换为字段类型并赋值，切片以逗号分隔
+func setValue(v reflect.Value, raw string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		parts := strings.Split(raw, ",")
+		slice := reflect.MakeSlice(v.Type(), 0, len(parts))
+		for _, part := range parts {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			item := reflect.New(v.Type().Elem()).Elem()
+			if err := setValue(item, part); err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, item)
+		}
+		v.Set(slice)
+	default:
+		return errors.New("不支持的字段类型 " + v.Type().String())
+	}
+	return nil
+}
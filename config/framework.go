@@ -0,0 +1,110 @@
+package config
+
+import "time"
+
+// Framework 是框架自身的配置，可通过 core.NewFromConfig 创建引擎
+// 业务配置可以内联该结构体，例如：
+//
+//	type AppConfig struct {
+//		config.Framework `yaml:",inline"`
+//		Redis redis.Config `yaml:"redis"`
+//	}
+type Framework struct {
+	Server  Server  `yaml:"server"`
+	Logger  Logger  `yaml:"logger"`
+	Tracing Tracing `yaml:"tracing"`
+	JWT     JWT     `yaml:"jwt"`
+}
+
+// Server 定义了 HTTP 服务器配置
+type Server struct {
+	// Addr 监听地址
+	Addr string `yaml:"addr" default:":8080"`
+	// Mode 运行模式，debug 或 release
+	Mode string `yaml:"mode" default:"debug" validate:"oneof=debug release"`
+	// ReadTimeout 读取整个请求的超时时间，0 表示不限制
+	ReadTimeout time.Duration `yaml:"read_timeout"`
+	// ReadHeaderTimeout 读取请求头的超时时间
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" default:"10s"`
+	// WriteTimeout 写出响应的超时时间，0 表示不限制
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	// IdleTimeout keep-alive 连接的空闲超时时间
+	IdleTimeout time.Duration `yaml:"idle_timeout" default:"120s"`
+	// MaxHeaderBytes 请求头的最大字节数
+	MaxHeaderBytes int `yaml:"max_header_bytes" default:"1048576" validate:"min=0"`
+	// H2C 是否在明文连接上支持 HTTP/2
+	H2C bool `yaml:"h2c"`
+	// ShutdownTimeout 优雅关闭时等待在途请求完成的最长时间
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" default:"30s"`
+	// PreStopDelay 优雅关闭时先置为未就绪并等待的时长
+	PreStopDelay time.Duration `yaml:"pre_stop_delay"`
+	// TrustedProxies 可信代理的 CIDR 或 IP，只信任这些代理设置的 X-Forwarded-For 和 X-Real-IP
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+// Logger 定义了日志配置
+type Logger struct {
+	// Level 最低日志级别
+	Level string `yaml:"level" default:"info" validate:"oneof=debug info warn error"`
+	// Dir 日志文件目录
+	Dir string `yaml:"dir" default:"logs"`
+	// MaxSize 单个日志文件的最大大小（MB），超过后切割，为 0 时不按大小切割
+	MaxSize int `yaml:"max_size" default:"100" validate:"min=0"`
+	// RotateInterval 按时间切割的周期，例如 24h 表示每天零点切割，为 0 时不按时间切割
+	RotateInterval time.Duration `yaml:"rotate_interval"`
+	// MaxAge 备份日志文件的最长保留时间，为 0 时不按时间清理
+	MaxAge time.Duration `yaml:"max_age"`
+	// MaxBackups 最多保留的备份日志文件数，为 0 时不按数量清理
+	MaxBackups int `yaml:"max_backups" default:"10" validate:"min=0"`
+	// Compress 是否使用 gzip 压缩备份日志文件
+	Compress bool `yaml:"compress"`
+	// Async 是否启用异步日志，日志由后台协程批量写入
+	Async bool `yaml:"async"`
+	// BufferSize 异步日志缓冲的日志条数
+	BufferSize int `yaml:"buffer_size" default:"1024" validate:"min=1"`
+	// FlushInterval 异步日志批量写入的最长间隔
+	FlushInterval time.Duration `yaml:"flush_interval" default:"1s"`
+	// DropWhenFull 异步日志缓冲区已满时丢弃新日志而不是阻塞
+	DropWhenFull bool `yaml:"drop_when_full"`
+	// Modules 按模块覆盖最低日志级别，例如 {"ratelimit": "debug", "cron": "warn"}
+	Modules map[string]string `yaml:"modules"`
+}
+
+// Tracing 定义了链路追踪配置
+type Tracing struct {
+	Enabled     bool   `yaml:"enabled"`
+	ServiceName string `yaml:"service_name" default:"easygo"`
+	// ServiceVersion 服务版本
+	ServiceVersion string `yaml:"service_version"`
+	// Environment 部署环境，例如 production
+	Environment string `yaml:"environment"`
+	// Attributes 附加的资源属性
+	Attributes map[string]string `yaml:"attributes"`
+	// Exporter 导出器类型
+	Exporter string `yaml:"exporter" default:"stdout" validate:"oneof=stdout otlp-grpc otlp-http jaeger none"`
+	// Endpoint 导出地址，为空时使用导出器的默认地址
+	Endpoint string `yaml:"endpoint"`
+	// Insecure 是否使用不加密的连接
+	Insecure bool `yaml:"insecure"`
+	// Headers 导出请求附加的头
+	Headers map[string]string `yaml:"headers"`
+	// Sampler 采样策略
+	Sampler string `yaml:"sampler" default:"always" validate:"oneof=always never ratio"`
+	// SampleRatio 按比例采样时的采样比例
+	SampleRatio float64 `yaml:"sample_ratio" default:"1" validate:"min=0,max=1"`
+	// ParentBased 是否优先遵循上游服务的采样决定
+	ParentBased bool `yaml:"parent_based" default:"true"`
+}
+
+// JWT 定义了 JWT 配置，Secret 和 PrivateKey 都为空时不创建 JWT 管理器
+type JWT struct {
+	// Secret HS256 签名密钥
+	Secret string        `yaml:"secret"`
+	Expire time.Duration `yaml:"expire" default:"24h"`
+	// PrivateKey PEM 私钥文件路径，配置后按密钥类型使用 RS256、ES256 或 EdDSA 签名，优先于 Secret
+	PrivateKey string `yaml:"private_key"`
+	// KeyID 签名密钥的ID，写入令牌头部的 kid
+	KeyID string `yaml:"key_id"`
+	// VerifyKeys 额外用于验证的公钥，键为密钥ID，值为 PEM 公钥文件路径，轮换密钥时保留旧公钥
+	VerifyKeys map[string]string `yaml:"verify_keys"`
+}
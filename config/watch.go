@@ -0,0 +1,160 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/xzl-go/easygo/logger"
+)
+
+// reloadDelay 是文件变更后等待的时间，合并编辑器保存时产生的多次事件
+const reloadDelay = 100 * time.Millisecond
+
+// Watcher 持有配置的当前值，并在配置文件变更时重新加载
+// 重新加载失败（格式错误、校验不通过）时保留旧配置并记录错误日志
+type Watcher[T any] struct {
+	path    string
+	options Options
+	value   atomic.Pointer[T]
+
+	mu        sync.Mutex
+	listeners []func(old, new *T)
+	content   []byte
+	timer     *time.Timer
+
+	fsWatcher *fsnotify.Watcher
+	done      chan struct{}
+}
+
+// Watch 加载配置并监听配置文件变更
+// 监听配置文件所在的目录，以兼容编辑器的原子替换和 Kubernetes ConfigMap 的符号链接切换
+// path: 配置文件路径
+// options: 加载选项
+func Watch[T any](path string, options Options) (*Watcher[T], error) {
+	w := &Watcher[T]{path: path, options: options, done: make(chan struct{})}
+	if _, err := w.load(); err != nil {
+		return nil, err
+	}
+
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := fsWatcher.Add(filepath.Dir(path)); err != nil {
+		fsWatcher.Close()
+		return nil, err
+	}
+	w.fsWatcher = fsWatcher
+	go w.run()
+	return w, nil
+}
+
+// Get 返回当前配置，返回值不应被修改
+func (w *Watcher[T]) Get() *T {
+	return w.value.Load()
+}
+
+// OnChange 注册配置变更回调，在配置重新加载成功后调用
+func (w *Watcher[T]) OnChange(fn func(old, new *T)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.listeners = append(w.listeners, fn)
+}
+
+// Close 停止监听
+func (w *Watcher[T]) Close() error {
+	select {
+	case <-w.done:
+		return nil
+	default:
+		close(w.done)
+	}
+	return w.fsWatcher.Close()
+}
+
+// run 处理文件变更事件
+func (w *Watcher[T]) run() {
+	for {
+		select {
+		case <-w.done:
+			return
+		case _, ok := <-w.fsWatcher.Events:
+			if !ok {
+				return
+			}
+			w.schedule()
+		case err, ok := <-w.fsWatcher.Errors:
+			if !ok {
+				return
+			}
+			logger.Error("[Config] 监听配置文件失败：%v", err)
+		}
+	}
+}
+
+// schedule 延迟重新加载，期间的多次事件只触发一次加载
+func (w *Watcher[T]) schedule() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.timer = time.AfterFunc(reloadDelay, w.reload)
+}
+
+// reload 重新加载配置并通知回调
+func (w *Watcher[T]) reload() {
+	select {
+	case <-w.done:
+		return
+	default:
+	}
+	old := w.Get()
+	changed, err := w.load()
+	if err != nil {
+		logger.Error("[Config] 重新加载 %s 失败，继续使用旧配置：%v", w.path, err)
+		return
+	}
+	if !changed {
+		return
+	}
+	logger.Info("[Config] 已重新加载 %s", w.path)
+
+	w.mu.Lock()
+	listeners := make([]func(old, new *T), len(w.listeners))
+	copy(listeners, w.listeners)
+	w.mu.Unlock()
+	for _, fn := range listeners {
+		fn(old, w.Get())
+	}
+}
+
+// load 读取并解析配置文件，内容未变化时返回 false
+func (w *Watcher[T]) load() (bool, error) {
+	content, err := os.ReadFile(w.path)
+	if err != nil {
+		return false, err
+	}
+	w.mu.Lock()
+	unchanged := w.content != nil && bytes.Equal(content, w.content)
+	w.mu.Unlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cfg := new(T)
+	if err := LoadWithOptions(w.path, cfg, w.options); err != nil {
+		return false, err
+	}
+	w.mu.Lock()
+	w.content = content
+	w.mu.Unlock()
+	w.value.Store(cfg)
+	return true, nil
+}
@@ -0,0 +1,35 @@
+// Package core 提供了EasyGo框架的核心功能
+package core
+
+// AuditEntriesKey 是上下文中保存审计记录的键
+const AuditEntriesKey = "audit_entries"
+
+// AuditEntry 是处理函数显式记录的审计事件
+type AuditEntry struct {
+	Action   string      // 操作，例如 "user.update"
+	Resource string      // 资源，例如 "user:42"
+	Before   interface{} // 变更前的数据
+	After    interface{} // 变更后的数据
+}
+
+// Audit 记录一条审计事件
+// 事件由 audit.Middleware 在请求结束后补充操作人、IP、追踪ID等信息并写入存储
+// action: 操作
+// resource: 资源
+// before: 变更前的数据，可为 nil
+// after: 变更后的数据，可为 nil
+func (c *Context) Audit(action, resource string, before, after interface{}) {
+	entries, _ := c.Get(AuditEntriesKey).([]AuditEntry)
+	c.Set(AuditEntriesKey, append(entries, AuditEntry{
+		Action:   action,
+		Resource: resource,
+		Before:   before,
+		After:    after,
+	}))
+}
+
+// AuditEntries 返回当前请求记录的审计事件
+func (c *Context) AuditEntries() []AuditEntry {
+	entries, _ := c.Get(AuditEntriesKey).([]AuditEntry)
+	return entries
+}
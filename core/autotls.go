@@ -0,0 +1,112 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// AutoTLSOptions 定义了自动申请证书的选项
+type AutoTLSOptions struct {
+	// Addr HTTPS 监听地址，默认 ":443"
+	Addr string
+	// HTTPAddr HTTP 监听地址，默认 ":80"，用于 HTTP-01 验证和将 HTTP 请求重定向到 HTTPS，为 "-" 时不监听
+	HTTPAddr string
+	// CacheDir 证书缓存目录，默认为用户缓存目录下的 easygo-autocert，多实例部署时应使用共享存储，
+	// 避免每次重启都重新申请证书而触发 Let's Encrypt 的频率限制
+	CacheDir string
+	// Email 证书到期或出现问题时 CA 通知的邮箱，可为空
+	Email string
+	// DirectoryURL ACME 服务地址，默认 Let's Encrypt 正式环境，调试时可使用测试环境
+	// "https://acme-staging-v02.api.letsencrypt.org/directory"
+	DirectoryURL string
+}
+
+// RunAutoTLS 启动 HTTPS 服务器，通过 Let's Encrypt 自动申请和续期域名证书，
+// 同时在 :80 监听，完成 HTTP-01 验证并将其他 HTTP 请求重定向到 HTTPS
+// 服务器需能从公网通过域名访问 443 端口（或 80 端口）
+// domains: 申请证书的域名，其他域名的 TLS 握手会被拒绝
+func (e *Engine) RunAutoTLS(domains ...string) error {
+	return e.RunAutoTLSWithOptions(AutoTLSOptions{}, domains...)
+}
+
+// RunAutoTLSWithOptions 按选项启动自动申请证书的 HTTPS 服务器
+// ServerOptions.TLSConfig 中除证书外的设置（例如最低 TLS 版本）仍然生效
+// options: 自动证书选项
+// domains: 申请证书的域名
+// 返回服务器运行错误（如果有），调用 Shutdown 正常关闭时返回 nil
+func (e *Engine) RunAutoTLSWithOptions(options AutoTLSOptions, domains ...string) error {
+	if len(domains) == 0 {
+		return errors.New("easygo: 自动申请证书需要至少一个域名")
+	}
+	if options.Addr == "" {
+		options.Addr = ":443"
+	}
+	if options.HTTPAddr == "" {
+		options.HTTPAddr = ":80"
+	}
+	if options.CacheDir == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			dir = os.TempDir()
+		}
+		options.CacheDir = filepath.Join(dir, "easygo-autocert")
+	}
+
+	m := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(domains...),
+		Cache:      autocert.DirCache(options.CacheDir),
+		Email:      options.Email,
+	}
+	if options.DirectoryURL != "" {
+		m.Client = &acme.Client{DirectoryURL: options.DirectoryURL}
+	}
+
+	srv := e.newServer(options.Addr)
+	defer e.removeServer(srv)
+	if srv.TLSConfig == nil {
+		srv.TLSConfig = m.TLSConfig()
+	} else {
+		srv.TLSConfig = srv.TLSConfig.Clone()
+		srv.TLSConfig.GetCertificate = m.GetCertificate
+		srv.TLSConfig.NextProtos = append([]string{"h2", "http/1.1"}, acme.ALPNProto)
+	}
+
+	// 先同步监听，端口被占用等错误立即返回
+	ln, err := net.Listen("tcp", options.Addr)
+	if err != nil {
+		return err
+	}
+	errCh := make(chan error, 1)
+	if options.HTTPAddr != "-" {
+		httpLn, err := net.Listen("tcp", options.HTTPAddr)
+		if err != nil {
+			ln.Close()
+			return err
+		}
+		// 与 HTTPS 服务器一同登记，Shutdown 时一并关闭
+		httpSrv := e.newServer(options.HTTPAddr)
+		defer e.removeServer(httpSrv)
+		httpSrv.Handler = m.HTTPHandler(nil)
+		httpSrv.TLSConfig = nil
+		go func() {
+			errCh <- serverError(httpSrv.Serve(httpLn))
+		}()
+		defer httpSrv.Close()
+	} else {
+		errCh <- nil
+	}
+
+	fmt.Printf("🔒 安全服务器启动（自动证书），监听地址：%s，域名：%v\n", options.Addr, domains)
+	err = serverError(srv.ServeTLS(ln, "", ""))
+	if err != nil {
+		return err
+	}
+	return <-errCh
+}
@@ -0,0 +1,170 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	errs "github.com/xzl-go/easygo/errors"
+)
+
+// BatchHeader 标记批量请求拆分出的子请求，子请求不能再次调用批量接口
+const BatchHeader = "X-Batch-Request"
+
+// BatchOptions 定义了批量请求接口配置
+type BatchOptions struct {
+	// MaxRequests 单次批量请求包含的最大子请求数，默认 20
+	MaxRequests int
+	// MaxBodySize 批量请求体的最大字节数，默认 1 MB
+	MaxBodySize int64
+	// Parallel 为 true 时并发执行子请求，默认按顺序执行
+	Parallel bool
+}
+
+// BatchRequest 是批量请求中的子请求
+type BatchRequest struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"` // 包含查询参数，例如 "/api/users?page=1"
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// BatchResult 是子请求的响应
+type BatchResult struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    interface{}       `json:"body,omitempty"` // JSON 响应原样嵌入，其他响应为字符串
+}
+
+// Batch 返回批量请求处理函数
+// 请求体为子请求数组，每个子请求都经过完整的路由和中间件处理，
+// 并继承批量请求的请求头（如 Authorization、Cookie），结果按顺序返回
+// 例如：app.POST("/batch", app.Batch(core.BatchOptions{}))
+// options: 批量请求配置
+func (e *Engine) Batch(options BatchOptions) HandlerFunc {
+	if options.MaxRequests <= 0 {
+		options.MaxRequests = 20
+	}
+	if options.MaxBodySize <= 0 {
+		options.MaxBodySize = 1 << 20
+	}
+
+	return func(c *Context) {
+		if c.GetHeader(BatchHeader) != "" {
+			c.Fail(errs.ErrBadRequest.WithMessage("批量请求不能嵌套"))
+			return
+		}
+
+		var requests []BatchRequest
+		decoder := json.NewDecoder(io.LimitReader(c.Request.Body, options.MaxBodySize))
+		if err := decoder.Decode(&requests); err != nil {
+			c.Fail(errs.ErrBadRequest.Wrap(err))
+			return
+		}
+		if len(requests) == 0 || len(requests) > options.MaxRequests {
+			c.Fail(errs.ErrBadRequest.WithMessage(fmt.Sprintf("子请求数量必须在 1 到 %d 之间", options.MaxRequests)))
+			return
+		}
+		for i, sub := range requests {
+			if sub.Method == "" || !strings.HasPrefix(sub.Path, "/") {
+				c.Fail(errs.ErrBadRequest.WithMessage(fmt.Sprintf("第 %d 个子请求缺少 method 或 path", i+1)))
+				return
+			}
+		}
+
+		results := make([]BatchResult, len(requests))
+		if options.Parallel {
+			var wg sync.WaitGroup
+			for i := range requests {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					results[i] = e.dispatch(c.Request, requests[i])
+				}(i)
+			}
+			wg.Wait()
+		} else {
+			for i := range requests {
+				results[i] = e.dispatch(c.Request, requests[i])
+			}
+		}
+		c.Success(results)
+	}
+}
+
+// dispatch 在进程内执行子请求
+func (e *Engine) dispatch(parent *http.Request, sub BatchRequest) BatchResult {
+	var body io.Reader = http.NoBody
+	if len(sub.Body) > 0 && string(sub.Body) != "null" {
+		body = bytes.NewReader(sub.Body)
+	}
+	r, err := http.NewRequestWithContext(parent.Context(), strings.ToUpper(sub.Method), sub.Path, body)
+	if err != nil {
+		return BatchResult{Status: http.StatusBadRequest, Body: err.Error()}
+	}
+
+	// 继承批量请求的请求头，与请求体相关的头由子请求决定
+	r.Header = parent.Header.Clone()
+	for _, h := range []string{"Content-Length", "Content-Type", "Accept-Encoding", "Content-Encoding"} {
+		r.Header.Del(h)
+	}
+	if body != http.NoBody {
+		r.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range sub.Headers {
+		r.Header.Set(k, v)
+	}
+	r.Header.Set(BatchHeader, "1")
+	r.Host = parent.Host
+	r.RemoteAddr = parent.RemoteAddr
+	r.TLS = parent.TLS
+
+	w := &batchRecorder{header: make(http.Header)}
+	e.ServeHTTP(w, r)
+
+	result := BatchResult{Status: w.status, Headers: make(map[string]string, len(w.header))}
+	if result.Status == 0 {
+		result.Status = http.StatusOK
+	}
+	for k := range w.header {
+		result.Headers[k] = w.header.Get(k)
+	}
+	raw := bytes.TrimSpace(w.body.Bytes())
+	if strings.HasPrefix(w.header.Get("Content-Type"), "application/json") && json.Valid(raw) {
+		result.Body = json.RawMessage(raw)
+	} else if len(raw) > 0 {
+		result.Body = string(raw)
+	}
+	return result
+}
+
+// batchRecorder 记录子请求的响应
+type batchRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// Header 返回响应头
+func (w *batchRecorder) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader 记录状态码，只有第一次调用生效
+func (w *batchRecorder) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+// Write 记录响应体
+func (w *batchRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
@@ -0,0 +1,289 @@
+package core
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Converter 将字符串转换为目标类型的值，用于表单、查询参数和路径参数绑定
+type Converter func(value string) (interface{}, error)
+
+var (
+	convertersMu sync.RWMutex
+	converters   = make(map[reflect.Type]Converter)
+
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// RegisterConverter 注册类型转换器，用于绑定自定义类型（如枚举、金额类型）
+// 转换器优先于内置转换和 encoding.TextUnmarshaler
+// sample: 目标类型的示例值，例如 OrderStatus(0)
+// fn: 转换函数，返回值必须是目标类型
+func RegisterConverter(sample interface{}, fn Converter) {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+	converters[reflect.TypeOf(sample)] = fn
+}
+
+// lookupConverter 查找已注册的转换器
+func lookupConverter(t reflect.Type) (Converter, bool) {
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
+	fn, ok := converters[t]
+	return fn, ok
+}
+
+// BindURI 将路径参数绑定到结构体，字段通过 uri 标签指定参数名
+// obj: 目标结构体指针
+func (c *Context) BindURI(obj interface{}) error {
+	values := make(map[string][]string, len(c.Params))
+	for _, p := range c.Params {
+		values[p.Key] = []string{p.Value}
+	}
+	return bindValues(obj, values, "uri")
+}
+
+// BindQuery 将查询参数绑定到结构体，字段通过 query 标签指定参数名
+// 支持 int、bool、float、time.Time 和切片等类型，例如 ?page=2&tags=a&tags=b
+// obj: 目标结构体指针
+func (c *Context) BindQuery(obj interface{}) error {
+	return bindValues(obj, c.Request.URL.Query(), "query")
+}
+
+// BindForm 将表单参数绑定到结构体，字段通过 form 标签指定参数名
+// 支持 application/x-www-form-urlencoded 和 multipart/form-data，表单中没有的字段会读取查询参数
+// obj: 目标结构体指针
+func (c *Context) BindForm(obj interface{}) error {
+	if err := c.parseForm(); err != nil {
+		return err
+	}
+	return bindValues(obj, c.Request.Form, "form")
+}
+
+// parseForm 按 Content-Type 解析表单
+func (c *Context) parseForm() error {
+	if strings.HasPrefix(c.Request.Header.Get("Content-Type"), "multipart/form-data") {
+		_, err := c.MultipartForm()
+		return err
+	}
+	return c.Request.ParseForm()
+}
+
+// bindValues 通过反射将字符串参数绑定到结构体
+// 字段名取自 tag 标签，未设置时使用字段名，"-" 表示忽略；支持指针、切片、嵌入结构体，
+// 以及 time.Time（time_format、time_utc、time_location 标签）、time.Duration、
+// encoding.TextUnmarshaler 和通过 RegisterConverter 注册的类型
+// obj: 目标结构体指针
+// values: 参数
+// tag: 读取字段名的标签
+func bindValues(obj interface{}, values map[string][]string, tag string) error {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("binding: 目标必须是结构体指针，实际为 %T", obj)
+	}
+	return bindStruct(v.Elem(), values, tag)
+}
+
+// bindStruct 绑定结构体的各个字段
+func bindStruct(v reflect.Value, values map[string][]string, tag string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+
+		name, hasTag := fieldName(field, tag)
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && !hasTag && fv.Kind() == reflect.Struct {
+			if err := bindStruct(fv, values, tag); err != nil {
+				return err
+			}
+			continue
+		}
+
+		vals, ok := values[name]
+		if !ok || len(vals) == 0 {
+			continue
+		}
+		if err := setField(fv, field, vals); err != nil {
+			return fmt.Errorf("binding: 字段 %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// fieldName 读取字段在参数中的名称
+func fieldName(field reflect.StructField, tag string) (string, bool) {
+	value, ok := field.Tag.Lookup(tag)
+	if !ok {
+		return field.Name, false
+	}
+	name, _, _ := strings.Cut(value, ",")
+	if name == "" {
+		return field.Name, true
+	}
+	return name, true
+}
+
+// setField 设置字段值，切片字段接收全部参数值，其余字段取第一个值
+func setField(v reflect.Value, field reflect.StructField, vals []string) error {
+	if v.Kind() == reflect.Slice && !convertible(v.Type()) {
+		slice := reflect.MakeSlice(v.Type(), 0, len(vals))
+		for _, s := range vals {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setValue(elem, field, s); err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, elem)
+		}
+		v.Set(slice)
+		return nil
+	}
+	return setValue(v, field, vals[0])
+}
+
+// convertible 判断类型是否可以从单个字符串整体转换
+func convertible(t reflect.Type) bool {
+	if _, ok := lookupConverter(t); ok {
+		return true
+	}
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// setValue 将单个字符串转换后设置到字段
+func setValue(v reflect.Value, field reflect.StructField, s string) error {
+	if fn, ok := lookupConverter(v.Type()); ok {
+		result, err := fn(s)
+		if err != nil {
+			return err
+		}
+		rv := reflect.ValueOf(result)
+		if !rv.IsValid() || !rv.Type().AssignableTo(v.Type()) {
+			return fmt.Errorf("转换器返回了 %T，期望 %s", result, v.Type())
+		}
+		v.Set(rv)
+		return nil
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if s == "" {
+			return nil
+		}
+		elem := reflect.New(v.Type().Elem())
+		if err := setValue(elem.Elem(), field, s); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	switch v.Type() {
+	case timeType:
+		return setTime(v, field, s)
+	case durationType:
+		if s == "" {
+			return nil
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	if v.CanAddr() {
+		if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(s))
+		}
+	}
+
+	if s == "" && v.Kind() != reflect.String {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("不支持的类型 %s，可通过 RegisterConverter 注册转换器", v.Type())
+	}
+	return nil
+}
+
+// setTime 按 time_format 标签解析时间
+// time_format 可以是 Go 时间布局，或 unix、unixmilli 表示时间戳，默认 RFC3339；
+// time_utc:"1" 表示按 UTC 解析，time_location 指定时区，默认使用本地时区
+func setTime(v reflect.Value, field reflect.StructField, s string) error {
+	if s == "" {
+		return nil
+	}
+	layout := field.Tag.Get("time_format")
+	switch layout {
+	case "unix", "unixmilli":
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		if layout == "unix" {
+			v.Set(reflect.ValueOf(time.Unix(n, 0)))
+		} else {
+			v.Set(reflect.ValueOf(time.UnixMilli(n)))
+		}
+		return nil
+	case "":
+		layout = time.RFC3339
+	}
+
+	loc := time.Local
+	if utc, _ := strconv.ParseBool(field.Tag.Get("time_utc")); utc {
+		loc = time.UTC
+	}
+	if name := field.Tag.Get("time_location"); name != "" {
+		l, err := time.LoadLocation(name)
+		if err != nil {
+			return err
+		}
+		loc = l
+	}
+	t, err := time.ParseInLocation(layout, s, loc)
+	if err != nil {
+		return err
+	}
+	v.Set(reflect.ValueOf(t))
+	return nil
+}
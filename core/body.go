@@ -0,0 +1,50 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+)
+
+// BodyBinding 将请求体解析到目标对象，用于 ShouldBindBodyWith
+type BodyBinding func(body []byte, obj interface{}) error
+
+var (
+	// JSONBinding 按 JSON 解析请求体
+	JSONBinding BodyBinding = json.Unmarshal
+	// XMLBinding 按 XML 解析请求体
+	XMLBinding BodyBinding = xml.Unmarshal
+)
+
+// GetRawData 读取并缓存请求体，可以多次调用，例如签名校验中间件读取后处理函数仍可绑定
+// 每次调用后 c.Request.Body 都被替换为从头读取缓存内容的 reader，之后的 BindJSON、BindXML 直接解析缓存；
+// 请求体全部读入内存，大文件上传应使用 MultipartReader 流式读取
+// 返回请求体字节数组和可能的错误
+func (c *Context) GetRawData() ([]byte, error) {
+	if !c.bodyCached {
+		if c.Request.Body != nil && c.Request.Body != http.NoBody {
+			body, err := io.ReadAll(c.Request.Body)
+			c.Request.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			c.body = body
+		}
+		c.bodyCached = true
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(c.body))
+	return c.body, nil
+}
+
+// ShouldBindBodyWith 使用缓存的请求体绑定目标对象，同一请求可以多次绑定，例如先按不同的结构体尝试解析
+// obj: 目标对象指针
+// binding: 请求体解析方式，例如 core.JSONBinding
+func (c *Context) ShouldBindBodyWith(obj interface{}, binding BodyBinding) error {
+	body, err := c.GetRawData()
+	if err != nil {
+		return err
+	}
+	return binding(body, obj)
+}
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"context"
+	"time"
+)
+
+// 确保 Context 实现了 context.Context，可直接传给数据库、Redis、链路追踪等调用
+var _ context.Context = (*Context)(nil)
+
+// requestContext 返回请求的 context，请求为空时返回 context.Background()
+func (c *Context) requestContext() context.Context {
+	if c.Request == nil {
+		return context.Background()
+	}
+	return c.Request.Context()
+}
+
+// Deadline 返回请求的截止时间，委托给 Request.Context()
+func (c *Context) Deadline() (deadline time.Time, ok bool) {
+	return c.requestContext().Deadline()
+}
+
+// Done 返回在请求取消（客户端断开、服务器关闭）或超时时关闭的通道，委托给 Request.Context()
+func (c *Context) Done() <-chan struct{} {
+	return c.requestContext().Done()
+}
+
+// Err 返回请求结束的原因，请求未结束时返回 nil，委托给 Request.Context()
+func (c *Context) Err() error {
+	return c.requestContext().Err()
+}
+
+// Value 返回 key 对应的值
+// key 为字符串时优先查找通过 Set 设置的值，找不到时委托给 Request.Context()
+func (c *Context) Value(key interface{}) interface{} {
+	if k, ok := key.(string); ok {
+		if v, exists := c.Keys[k]; exists {
+			return v
+		}
+	}
+	return c.requestContext().Value(key)
+}
+
+// WithTimeout 为当前请求设置超时，之后通过 c 或 c.Request.Context() 发起的调用都受该超时约束
+// 返回的 cancel 应使用 defer 调用以释放资源。
+// Context 在请求结束后会被复用，不要在处理函数返回后的 goroutine 中使用 c 作为 context
+// timeout: 超时时间
+func (c *Context) WithTimeout(timeout time.Duration) context.CancelFunc {
+	ctx, cancel := context.WithTimeout(c.requestContext(), timeout)
+	c.Request = c.Request.WithContext(ctx)
+	return cancel
+}
+
+// WithDeadline 为当前请求设置截止时间，用法同 WithTimeout
+// deadline: 截止时间
+func (c *Context) WithDeadline(deadline time.Time) context.CancelFunc {
+	ctx, cancel := context.WithDeadline(c.requestContext(), deadline)
+	c.Request = c.Request.WithContext(ctx)
+	return cancel
+}
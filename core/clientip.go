@@ -0,0 +1,79 @@
+package core
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// SetTrustedProxies 设置可信代理的地址范围，默认为空，即不信任任何代理
+// 只有连接的远端地址属于可信代理时，ClientIP 才读取 X-Forwarded-For 和 X-Real-IP，
+// 否则任何客户端都可以通过请求头伪造自己的地址
+// proxies: CIDR 或单个 IP，例如 "10.0.0.0/8"、"127.0.0.1"
+// 返回地址格式错误（如果有），出错时保留原设置
+func (e *Engine) SetTrustedProxies(proxies ...string) error {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if strings.Contains(proxy, "/") {
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				return fmt.Errorf("easygo: 可信代理 %q 格式错误: %w", proxy, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(proxy)
+		if err != nil {
+			return fmt.Errorf("easygo: 可信代理 %q 格式错误: %w", proxy, err)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	e.trustedProxies = prefixes
+	return nil
+}
+
+// isTrustedProxy 判断地址是否属于可信代理
+func (e *Engine) isTrustedProxy(addr netip.Addr) bool {
+	addr = addr.Unmap().WithZone("")
+	for _, prefix := range e.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP 获取客户端IP
+// 连接的远端地址属于可信代理（见 Engine.SetTrustedProxies）时，从右向左读取 X-Forwarded-For，
+// 返回第一个不属于可信代理的地址，没有 X-Forwarded-For 时读取 X-Real-IP；否则返回连接的远端地址
+func (c *Context) ClientIP() string {
+	remote := c.Request.RemoteAddr
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+	remoteAddr, err := netip.ParseAddr(remote)
+	if err != nil || c.engine == nil || !c.engine.isTrustedProxy(remoteAddr) {
+		return remote
+	}
+
+	if values := c.Request.Header.Values("X-Forwarded-For"); len(values) > 0 {
+		hops := strings.Split(strings.Join(values, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				// 格式错误的地址之前的部分不可信
+				return remote
+			}
+			if i == 0 || !c.engine.isTrustedProxy(addr) {
+				return addr.Unmap().String()
+			}
+		}
+	}
+	if addr, err := netip.ParseAddr(strings.TrimSpace(c.Request.Header.Get("X-Real-IP"))); err == nil {
+		return addr.Unmap().String()
+	}
+	return remote
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		trusted []string
+		remote  string
+		xff     string
+		realIP  string
+		want    string
+	}{
+		{name: "默认不信任请求头", remote: "203.0.113.7:1234", xff: "10.0.0.1", realIP: "10.0.0.2", want: "203.0.113.7"},
+		{name: "不可信的远端地址", trusted: []string{"10.0.0.0/8"}, remote: "203.0.113.7:1234", xff: "1.2.3.4", want: "203.0.113.7"},
+		{name: "取最右侧不可信的地址", trusted: []string{"10.0.0.0/8"}, remote: "10.0.0.5:1234", xff: "6.6.6.6, 198.51.100.9, 10.0.0.3", want: "198.51.100.9"},
+		{name: "全部可信时取最左侧", trusted: []string{"10.0.0.0/8"}, remote: "10.0.0.5:1234", xff: "10.0.0.9, 10.0.0.3", want: "10.0.0.9"},
+		{name: "格式错误时返回远端地址", trusted: []string{"10.0.0.0/8"}, remote: "10.0.0.5:1234", xff: "1.2.3.4, bogus", want: "10.0.0.5"},
+		{name: "读取 X-Real-IP", trusted: []string{"127.0.0.1"}, remote: "127.0.0.1:1234", realIP: "198.51.100.9", want: "198.51.100.9"},
+		{name: "IPv6 代理", trusted: []string{"::1"}, remote: "[::1]:1234", xff: "2001:db8::1", want: "2001:db8::1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New()
+			if err := e.SetTrustedProxies(tt.trusted...); err != nil {
+				t.Fatal(err)
+			}
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remote
+			if tt.xff != "" {
+				r.Header.Set("X-Forwarded-For", tt.xff)
+			}
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-IP", tt.realIP)
+			}
+			c := &Context{engine: e, Request: r}
+			if got := c.ClientIP(); got != tt.want {
+				t.Errorf("ClientIP() = %q，期望 %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetTrustedProxiesInvalid(t *testing.T) {
+	e := New()
+	if err := e.SetTrustedProxies("10.0.0.0/8"); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.SetTrustedProxies("not-an-ip"); err == nil {
+		t.Fatal("格式错误的地址没有返回错误")
+	}
+	if len(e.trustedProxies) != 1 {
+		t.Errorf("出错后可信代理被修改: %v", e.trustedProxies)
+	}
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"github.com/xzl-go/easygo/config"
+	"github.com/xzl-go/easygo/jwt"
+	"github.com/xzl-go/easygo/logger"
+	"github.com/xzl-go/easygo/tracing"
+)
+
+// NewFromConfig 从配置文件创建引擎，按 server、logger、tracing、jwt 配置初始化各组件
+// 环境变量 EASYGO_SERVER_ADDR 等可覆盖配置文件中的值
+// path: 配置文件路径，支持 YAML、JSON、TOML
+func NewFromConfig(path string) (*Engine, error) {
+	var cfg config.Framework
+	if err := config.LoadWithOptions(path, &cfg, config.Options{EnvPrefix: "EASYGO"}); err != nil {
+		return nil, err
+	}
+	return NewWithConfig(cfg)
+}
+
+// NewWithConfig 根据已加载的框架配置创建引擎
+// 设置运行模式、初始化日志、按需创建追踪器和 JWT 管理器，并配置服务器超时和优雅关闭选项；
+// 日志和追踪器的关闭已注册为引擎的关闭钩子
+// cfg: 框架配置
+func NewWithConfig(cfg config.Framework) (*Engine, error) {
+	level, err := logger.ParseLevel(cfg.Logger.Level)
+	if err != nil {
+		return nil, err
+	}
+	moduleLevels := make(map[string]logger.LogLevel, len(cfg.Logger.Modules))
+	for module, s := range cfg.Logger.Modules {
+		if moduleLevels[module], err = logger.ParseLevel(s); err != nil {
+			return nil, err
+		}
+	}
+	SetMode(cfg.Server.Mode)
+
+	e := New()
+	e.config = &cfg
+
+	logger.InitWithOptions(cfg.Logger.Dir, level)
+	if err := logger.SetRotation(logger.RotateOptions{
+		MaxSize:    cfg.Logger.MaxSize,
+		Interval:   cfg.Logger.RotateInterval,
+		MaxAge:     cfg.Logger.MaxAge,
+		MaxBackups: cfg.Logger.MaxBackups,
+		Compress:   cfg.Logger.Compress,
+	}); err != nil {
+		return nil, err
+	}
+	if cfg.Logger.Async {
+		overflow := logger.OverflowBlock
+		if cfg.Logger.DropWhenFull {
+			overflow = logger.OverflowDrop
+		}
+		logger.SetAsync(logger.AsyncOptions{
+			BufferSize:    cfg.Logger.BufferSize,
+			FlushInterval: cfg.Logger.FlushInterval,
+			Overflow:      overflow,
+		})
+	}
+	for module, level := range moduleLevels {
+		logger.SetModuleLevel(module, level)
+	}
+	e.OnCloseFunc(logger.Close)
+
+	if cfg.Tracing.Enabled {
+		t := cfg.Tracing
+		if e.tracer, err = tracing.NewTracerWithOptions(tracing.TracerOptions{
+			ServiceName:    t.ServiceName,
+			ServiceVersion: t.ServiceVersion,
+			Environment:    t.Environment,
+			Attributes:     t.Attributes,
+			Exporter:       t.Exporter,
+			Endpoint:       t.Endpoint,
+			Insecure:       t.Insecure,
+			Headers:        t.Headers,
+			Sampler:        t.Sampler,
+			SampleRatio:    t.SampleRatio,
+			ParentBased:    t.ParentBased,
+		}); err != nil {
+			return nil, err
+		}
+		e.OnClose(e.tracer.Shutdown)
+	}
+	if e.jwt, err = newJWTManager(cfg.JWT); err != nil {
+		return nil, err
+	}
+
+	server := cfg.Server
+	e.SetServerOptions(ServerOptions{
+		ReadTimeout:       server.ReadTimeout,
+		ReadHeaderTimeout: server.ReadHeaderTimeout,
+		WriteTimeout:      server.WriteTimeout,
+		IdleTimeout:       server.IdleTimeout,
+		MaxHeaderBytes:    server.MaxHeaderBytes,
+		H2C:               server.H2C,
+	})
+	if err := e.SetTrustedProxies(server.TrustedProxies...); err != nil {
+		return nil, err
+	}
+	options := e.shutdownOptions
+	options.DrainTimeout = server.ShutdownTimeout
+	options.PreStopDelay = server.PreStopDelay
+	e.SetShutdownOptions(options)
+	return e, nil
+}
+
+// newJWTManager 根据配置创建 JWT 管理器，未配置密钥时返回 nil
+func newJWTManager(cfg config.JWT) (*jwt.JWTManager, error) {
+	var signing *jwt.Key
+	switch {
+	case cfg.PrivateKey != "":
+		key, err := jwt.LoadSigningKeyFile(cfg.KeyID, cfg.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		signing = key
+	case cfg.Secret != "":
+		signing = jwt.NewHMACKey(cfg.KeyID, []byte(cfg.Secret))
+	default:
+		return nil, nil
+	}
+
+	var verification []*jwt.Key
+	for id, path := range cfg.VerifyKeys {
+		key, err := jwt.LoadVerificationKeyFile(id, path)
+		if err != nil {
+			return nil, err
+		}
+		verification = append(verification, key)
+	}
+	keys, err := jwt.NewKeySet(signing, verification...)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.NewJWTManagerWithKeys(keys, cfg.Expire), nil
+}
+
+// Config 返回创建引擎时使用的框架配置，未通过 NewFromConfig 或 NewWithConfig 创建时返回 nil
+func (e *Engine) Config() *config.Framework {
+	return e.config
+}
+
+// JWT 返回根据配置创建的 JWT 管理器，未配置 jwt.secret 和 jwt.private_key 时返回 nil
+func (e *Engine) JWT() *jwt.JWTManager {
+	return e.jwt
+}
+
+// Tracer 返回根据配置创建的追踪器，未启用链路追踪时返回 nil
+func (e *Engine) Tracer() *tracing.Tracer {
+	return e.tracer
+}
+
+// RunConfigured 使用配置中的监听地址启动服务器，并在收到退出信号时优雅关闭
+func (e *Engine) RunConfigured() error {
+	addr := ":8080"
+	if e.config != nil {
+		addr = e.config.Server.Addr
+	}
+	return e.RunGraceful(addr)
+}
@@ -6,30 +6,48 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/xzl-go/easygo/logger"
 )
 
 // Context 封装了HTTP请求上下文
 type Context struct {
 	engine     *Engine
-	Writer     http.ResponseWriter
+	writermem  responseWriter
+	Writer     ResponseWriter // 响应写入器，记录状态码和响应大小
 	Request    *http.Request
-	Params     map[string]string
+	Params     Params // 路径参数，复用上下文预分配的切片
 	handlers   []HandlerFunc
 	index      int
 	Keys       map[string]interface{}
-	StatusCode int
+	StatusCode int     // 通过 JSON、String 等方法设置的状态码，实际写出的状态码使用 c.Writer.Status()
+	Errors     []error // 处理过程中收集的错误，由错误处理中间件统一响应
+	viewData   map[string]interface{}
+	fullPath   string // 匹配的路由模式
+	body       []byte // GetRawData 缓存的请求体
+	bodyCached bool   // 请求体是否已缓存
 }
 
 // reset 重置上下文
 func (c *Context) reset(w http.ResponseWriter, r *http.Request) {
-	c.Writer = w
+	c.writermem.reset(w)
+	c.Writer = &c.writermem
 	c.Request = r
-	c.Params = make(map[string]string)
+	c.Params = c.Params[:0]
 	c.handlers = nil
 	c.index = -1
-	c.Keys = make(map[string]interface{})
+	c.Keys = nil // 首次 Set 时创建，未使用上下文值的请求不分配内存
+	c.StatusCode = 0
+	c.Errors = c.Errors[:0]
+	c.viewData = nil
+	c.fullPath = ""
+	c.body = nil
+	c.bodyCached = false
 }
 
 // Next 执行下一个处理函数
@@ -52,8 +70,11 @@ func (c *Context) JSON(code int, obj interface{}) {
 	}
 }
 
-// BindJSON 绑定JSON请求体
+// BindJSON 绑定JSON请求体，请求体已被 GetRawData 缓存时解析缓存的内容
 func (c *Context) BindJSON(obj interface{}) error {
+	if c.bodyCached {
+		return json.Unmarshal(c.body, obj)
+	}
 	decoder := json.NewDecoder(c.Request.Body)
 	return decoder.Decode(obj)
 }
@@ -68,9 +89,15 @@ func (c *Context) SetHeader(key, value string) {
 	c.Writer.Header().Set(key, value)
 }
 
+// FullPath 返回匹配的路由模式，例如 "/users/:id"，未匹配路由时返回空字符串
+// 用作指标和日志的标签，避免路径参数导致标签数量无限增长
+func (c *Context) FullPath() string {
+	return c.fullPath
+}
+
 // GetParam 获取URL参数
 func (c *Context) GetParam(key string) string {
-	return c.Params[key]
+	return c.Params.ByName(key)
 }
 
 // Set 设置上下文值
@@ -86,14 +113,76 @@ func (c *Context) Get(key string) interface{} {
 	return c.Keys[key]
 }
 
-// Abort 中止请求处理流程
+// Logger 返回当前请求的日志记录器，使用 RequestID 中间件时每行日志都带有请求ID
+func (c *Context) Logger() *logger.Entry {
+	return logger.FromContext(c)
+}
+
+// SpanContext 返回当前请求的追踪跨度上下文，使用 Tracing 中间件时包含 TraceID 和 SpanID，否则无效
+func (c *Context) SpanContext() trace.SpanContext {
+	return trace.SpanContextFromContext(c)
+}
+
+// Error 记录处理过程中发生的错误
+// 通常与 middleware.ErrorHandler 配合使用，由中间件统一生成错误响应
+func (c *Context) Error(err error) {
+	if err != nil {
+		c.Errors = append(c.Errors, err)
+	}
+}
+
+// Copy 返回当前上下文的只读副本，供处理函数返回后仍在运行的 goroutine 使用
+// Context 来自对象池，请求处理结束后会被后续请求复用，goroutine 中直接使用 c 会读到其他请求的数据。
+// 副本拥有独立的 Keys、Params 和 Errors，写出的响应会被丢弃；
+// 副本的 Done() 在原请求结束时关闭，需要脱离请求生命周期时使用 context.WithoutCancel(cp)
+func (c *Context) Copy() *Context {
+	cp := &Context{
+		engine:     c.engine,
+		Request:    c.Request,
+		Params:     append(Params(nil), c.Params...),
+		index:      abortIndex,
+		Keys:       make(map[string]interface{}, len(c.Keys)),
+		StatusCode: c.StatusCode,
+		Errors:     append([]error(nil), c.Errors...),
+		fullPath:   c.fullPath,
+		body:       c.body,
+		bodyCached: c.bodyCached,
+	}
+	cp.writermem.reset(&detachedWriter{header: c.Writer.Header().Clone()})
+	cp.writermem.status = c.Writer.Status()
+	cp.writermem.size = c.Writer.Size()
+	cp.Writer = &cp.writermem
+	for k, v := range c.Keys {
+		cp.Keys[k] = v
+	}
+	return cp
+}
+
+// detachedWriter 是副本使用的响应写入器，丢弃所有写入
+type detachedWriter struct {
+	header http.Header
+}
+
+func (w *detachedWriter) Header() http.Header         { return w.header }
+func (w *detachedWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *detachedWriter) WriteHeader(int)             {}
+
+// Written 判断是否已经写出响应状态码
+func (c *Context) Written() bool {
+	return c.Writer.Written()
+}
+
+// abortIndex 是中止后的处理链下标，大于任何处理链的长度
+const abortIndex = math.MaxInt32 / 2
+
+// Abort 中止请求处理流程，后续的中间件和处理函数不再执行
 func (c *Context) Abort() {
-	c.index = len(c.handlers)
+	c.index = abortIndex
 }
 
 // IsAborted 检查请求是否已被中止
 func (c *Context) IsAborted() bool {
-	return c.index == len(c.handlers)
+	return c.index >= abortIndex
 }
 
 // Status 设置HTTP响应状态码
@@ -102,13 +191,25 @@ func (c *Context) Status(code int) {
 	c.Writer.WriteHeader(code)
 }
 
+// Redirect 重定向到指定地址
+// code: HTTP状态码（如 302、301）
+// location: 目标地址
+func (c *Context) Redirect(code int, location string) {
+	c.StatusCode = code
+	http.Redirect(c.Writer, c.Request, location, code)
+}
+
 // String 返回纯文本响应
 // code: HTTP状态码
 // format: 格式化字符串
 // values: 格式化参数
 func (c *Context) String(code int, format string, values ...interface{}) {
+	c.Writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	c.Status(code)
-	c.Writer.Header().Set("Content-Type", "text/plain")
+	if len(values) > 0 {
+		fmt.Fprintf(c.Writer, format, values...)
+		return
+	}
 	c.Writer.Write([]byte(format))
 }
 
@@ -116,18 +217,64 @@ func (c *Context) String(code int, format string, values ...interface{}) {
 // code: HTTP状态码
 // obj: 要序列化的对象
 func (c *Context) XML(code int, obj interface{}) {
-	c.Status(code)
 	c.Writer.Header().Set("Content-Type", "application/xml")
+	c.Status(code)
 	encoder := xml.NewEncoder(c.Writer)
 	if err := encoder.Encode(obj); err != nil {
 		http.Error(c.Writer, err.Error(), 500)
 	}
 }
 
-// BindXML 将请求体解析为XML对象
+// HTML 渲染 HTML 模板
+// 优先使用 SetHTMLRender 设置的渲染器，否则使用 LoadHTMLGlob 等方法加载的模板。
+// data 为 map 或 nil 时会合并 SetViewData 设置的数据；渲染器实现了 ViewDataProvider 时还会注入其提供的数据
+// code: HTTP状态码
+// name: 模板名称
+// data: 模板数据
+func (c *Context) HTML(code int, name string, data interface{}) {
+	c.StatusCode = code
+	if c.engine.HTMLRender != nil {
+		data = c.mergeViewData(data)
+		if provider, ok := c.engine.HTMLRender.(ViewDataProvider); ok {
+			data = provider.ViewData(c, data)
+		}
+		c.Writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+		c.Writer.WriteHeader(code)
+		if err := c.engine.HTMLRender.Render(c.Writer, name, data); err != nil {
+			c.Error(err)
+		}
+		return
+	}
+
+	c.HTMLSet(code, "", name, data)
+}
+
+// HTMLSet 使用命名模板集渲染 HTML 模板
+// code: HTTP状态码
+// set: 模板集名称，通过 LoadHTMLSetGlob 或 LoadHTMLSetFS 加载
+// name: 模板名称
+// data: 模板数据
+func (c *Context) HTMLSet(code int, set, name string, data interface{}) {
+	c.StatusCode = code
+	tmpl, err := c.engine.htmlTemplate(set)
+	if err != nil {
+		http.Error(c.Writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	c.Writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+	c.Writer.WriteHeader(code)
+	if err := tmpl.ExecuteTemplate(c.Writer, name, c.mergeViewData(data)); err != nil {
+		c.Error(err)
+	}
+}
+
+// BindXML 将请求体解析为XML对象，请求体已被 GetRawData 缓存时解析缓存的内容
 // obj: 目标对象指针
 // 返回解析错误（如果有）
 func (c *Context) BindXML(obj interface{}) error {
+	if c.bodyCached {
+		return xml.Unmarshal(c.body, obj)
+	}
 	body, err := io.ReadAll(c.Request.Body)
 	if err != nil {
 		return err
@@ -161,7 +308,7 @@ func (c *Context) DefaultQuery(key, defaultValue string) string {
 func (c *Context) PostForm(key string) string {
 	// 确保表单已被解析
 	if c.Request.Form == nil {
-		_ = c.Request.ParseMultipartForm(32 << 20) // 32 MB 上限
+		_ = c.Request.ParseMultipartForm(defaultMultipartMemory)
 	}
 	return c.Request.FormValue(key)
 }
@@ -186,36 +333,31 @@ func (c *Context) Header(key string) string {
 // key: 参数名
 // 返回参数值
 func (c *Context) Param(key string) string {
-	return c.Params[key]
+	return c.Params.ByName(key)
 }
 
-// RawData 获取原始请求体数据
+// RawData 获取原始请求体数据，与 GetRawData 相同
 // 返回请求体字节数组和可能的错误
 func (c *Context) RawData() ([]byte, error) {
-	return io.ReadAll(c.Request.Body)
+	return c.GetRawData()
 }
 
-// Bind 根据 Content-Type 自动绑定请求体到目标对象
+// Bind 根据 Content-Type 自动绑定请求体到目标对象，GET 和 HEAD 请求绑定查询参数
 // obj: 目标对象指针
 // 返回绑定错误（如果有）
 func (c *Context) Bind(obj interface{}) error {
 	contentType := c.Request.Header.Get("Content-Type")
 
 	switch {
+	case c.Request.Method == http.MethodGet, c.Request.Method == http.MethodHead:
+		return c.BindQuery(obj)
 	case strings.HasPrefix(contentType, "application/json"):
 		return c.BindJSON(obj)
 	case strings.HasPrefix(contentType, "application/xml"):
 		return c.BindXML(obj)
 	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"),
 		strings.HasPrefix(contentType, "multipart/form-data"):
-		// 对于表单数据，我们不能直接 Bind 到任意 struct
-		// 需要手动解析或使用 reflect
-		// 这里我们只处理基本的 string map，如果需要更复杂的 struct 绑定，需要专门的库如 binding
-		// 暂时先返回错误，或直接使用 PostForm / Query 方法
-		_ = c.Request.ParseMultipartForm(32 << 20) // 确保表单已解析
-		// 如果 obj 是一个 map[string]string，我们可以尝试填充它
-		// 否则，让用户使用 PostForm/Query
-		return nil // 暂时不返回错误，允许后续手动获取参数
+		return c.BindForm(obj)
 	default:
 		return fmt.Errorf("unsupported content type: %s", contentType)
 	}
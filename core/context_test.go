@@ -0,0 +1,96 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCopyDoesNotShareBackingArrays(t *testing.T) {
+	e := New()
+	e.GET("/users/:id/orders/:order", func(c *Context) {
+		c.Set("user", "bob")
+		cp := c.Copy()
+
+		if &cp.Params[0] == &c.Params[0] {
+			t.Error("副本与原上下文共享 Params 的底层数组")
+		}
+		if reflect.ValueOf(cp.Keys).Pointer() == reflect.ValueOf(c.Keys).Pointer() {
+			t.Error("副本与原上下文共享 Keys")
+		}
+		if cp.handlers != nil {
+			t.Error("副本不应持有处理链")
+		}
+
+		// 修改副本不影响原上下文
+		cp.Params[0].Value = "changed"
+		cp.Set("user", "alice")
+		if c.Param("id") != "1" || c.Get("user") != "bob" {
+			t.Errorf("修改副本影响了原上下文：id=%s user=%v", c.Param("id"), c.Get("user"))
+		}
+		if cp.Param("order") != "2" {
+			t.Errorf("副本的参数 order=%s，期望 2", cp.Param("order"))
+		}
+	})
+	serve(e, http.MethodGet, "/users/1/orders/2")
+}
+
+// TestCopyOutlivesRequest 在处理函数返回后由 goroutine 继续使用副本，同时并发请求复用池中的上下文，
+// 需配合 go test -race 运行
+func TestCopyOutlivesRequest(t *testing.T) {
+	e := New()
+	var wg sync.WaitGroup
+	errs := make(chan string, 1000)
+	e.GET("/items/:id", func(c *Context) {
+		id := c.Param("id")
+		c.Set("id", id)
+		cp := c.Copy()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// 等待原上下文被回收并被其他请求复用
+			time.Sleep(5 * time.Millisecond)
+			if got := cp.Param("id"); got != id {
+				errs <- "Param: " + got + " != " + id
+			}
+			if got, _ := cp.Get("id").(string); got != id {
+				errs <- "Keys: " + got + " != " + id
+			}
+			if got := cp.Request.URL.Path; got != "/items/"+id {
+				errs <- "Request: " + got
+			}
+			if cp.FullPath() != "/items/:id" {
+				errs <- "FullPath: " + cp.FullPath()
+			}
+			// 副本的响应被丢弃，不会写入已被复用的响应写入器
+			cp.String(http.StatusOK, "late")
+		}()
+		c.String(http.StatusOK, "%s", id)
+	})
+
+	var clients sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		clients.Add(1)
+		go func(worker int) {
+			defer clients.Done()
+			for j := 0; j < 50; j++ {
+				id := strconv.Itoa(worker*1000 + j)
+				w := httptest.NewRecorder()
+				e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/"+id, nil))
+				if w.Body.String() != id {
+					errs <- "响应: " + w.Body.String() + " != " + id
+				}
+			}
+		}(i)
+	}
+	clients.Wait()
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
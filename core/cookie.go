@@ -0,0 +1,46 @@
+package core
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// SetCookie 设置响应 Cookie，SameSite 为 Lax
+// name: Cookie 名称
+// value: Cookie 值，会进行 URL 编码
+// maxAge: 有效期（秒），0 表示会话 Cookie，负数表示立即删除
+// path: 生效路径，为空时使用 "/"
+// domain: 生效域名
+// secure: 是否仅通过 HTTPS 发送
+// httpOnly: 是否禁止 JavaScript 访问
+func (c *Context) SetCookie(name, value string, maxAge int, path, domain string, secure, httpOnly bool) {
+	if path == "" {
+		path = "/"
+	}
+	c.SetHTTPCookie(&http.Cookie{
+		Name:     name,
+		Value:    url.QueryEscape(value),
+		MaxAge:   maxAge,
+		Path:     path,
+		Domain:   domain,
+		Secure:   secure,
+		HttpOnly: httpOnly,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// SetHTTPCookie 设置响应 Cookie，可完整控制 SameSite、Expires 等属性，值不会被编码
+func (c *Context) SetHTTPCookie(cookie *http.Cookie) {
+	http.SetCookie(c.Writer, cookie)
+}
+
+// Cookie 获取请求中的 Cookie 值，并进行 URL 解码
+// 不存在时返回 http.ErrNoCookie
+// name: Cookie 名称
+func (c *Context) Cookie(name string) (string, error) {
+	cookie, err := c.Request.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	return url.QueryUnescape(cookie.Value)
+}
@@ -5,8 +5,21 @@ import (
 	"context"
 	"fmt"
 	"html/template" // 导入 html/template 包
+	"io/fs"
+	"net"
 	"net/http"
+	"net/netip"
+	"net/url"
+	pathpkg "path"
+	"strings"
 	"sync"
+	"sync/atomic"
+
+	"github.com/xzl-go/easygo/config"
+	"github.com/xzl-go/easygo/health"
+	"github.com/xzl-go/easygo/jwt"
+	"github.com/xzl-go/easygo/tracing"
+	"github.com/xzl-go/easygo/validator"
 )
 
 // HandlerFunc 定义了请求处理函数的类型
@@ -27,7 +40,44 @@ type Engine struct {
 	HTMLRender  interface {
 		Render(w http.ResponseWriter, name string, data interface{}) error
 	}
+	htmlSets map[string]*htmlSet // 命名模板集，默认模板集的名称为空字符串
+	funcMap  template.FuncMap    // 内置模板的模板函数
+	spas     []*spa              // 托管的单页应用
+
+	serverMu        sync.Mutex
+	servers         []*http.Server                    // 运行中的 HTTP 服务器
+	closers         []func(ctx context.Context) error // 关闭钩子
+	ready           atomic.Bool                       // 就绪状态，关闭流程开始后置为 false
+	shutdownOptions ShutdownOptions                   // 关闭选项
+	serverOptions   ServerOptions                     // 服务器选项
+	serverConfig    func(srv *http.Server)            // 创建服务器时的自定义配置
+
+	config *config.Framework // NewFromConfig 使用的框架配置
+	jwt    *jwt.JWTManager   // 根据配置创建的 JWT 管理器
+	tracer *tracing.Tracer   // 根据配置创建的追踪器
+	health *health.Health    // EnableHealthChecks 创建的检查管理器
+
+	validator        *validator.Validator        // 引擎的验证器，为 nil 时使用全局验证器
+	translator       validator.Translator        // 校验消息翻译器
+	bindErrorHandler func(c *Context, err error) // BindAndValidate 失败时的处理函数
+	trustedProxies   []netip.Prefix              // 可信代理的地址范围
+
+	// RedirectTrailingSlash 请求路径以 / 结尾且去掉后匹配路由时重定向，例如 /users/ 重定向到 /users，默认开启
+	// GET 和 HEAD 请求返回 301，其他请求返回 308
+	RedirectTrailingSlash bool
+	// RedirectFixedPath 请求路径规范化（合并连续的 /、解析 . 和 ..）或修正大小写后匹配路由时重定向，
+	// 例如 //Users/../Users 重定向到 /users，默认关闭
+	RedirectFixedPath bool
+	// CaseInsensitive 路由匹配不区分静态路径中 ASCII 字母的大小写，直接处理而不重定向，默认关闭
+	// 精确匹配失败时才按不区分大小写匹配，参数值保持请求中的原样
+	CaseInsensitive bool
+}
+
+// htmlSet 是一组独立解析的模板
+type htmlSet struct {
 	templates *template.Template
+	// loader 重新解析模板的函数，调试模式下每次渲染前调用以实现模板热更新
+	loader func() (*template.Template, error)
 }
 
 // New 创建一个新的引擎实例
@@ -38,11 +88,19 @@ func New() *Engine {
 		},
 		router:      newRouter(),
 		middlewares: make([]HandlerFunc, 0),
+		htmlSets:    make(map[string]*htmlSet),
+
+		RedirectTrailingSlash: true,
 	}
 	engine.RouterGroup.engine = engine
+	engine.router.chain = engine.handlerChain
+	engine.ready.Store(true)
+	engine.shutdownOptions = DefaultShutdownOptions()
+	engine.serverOptions = DefaultServerOptions()
 	engine.pool.New = func() interface{} {
 		return &Context{
 			engine: engine,
+			Params: make(Params, 0, engine.router.maxParams),
 		}
 	}
 	return engine
@@ -51,34 +109,79 @@ func New() *Engine {
 // Use 添加中间件
 func (e *Engine) Use(middlewares ...HandlerFunc) {
 	e.middlewares = append(e.middlewares, middlewares...)
+	e.router.refreshChains()
 }
 
 // GET 注册GET请求处理函数
 // path: 请求路径
-// handler: 处理函数
-func (e *Engine) GET(path string, handler HandlerFunc) {
-	e.router.addRoute("GET", path, handler)
+// handlers: 处理函数，可以在最后的处理函数之前放置仅对该路由生效的中间件
+func (e *Engine) GET(path string, handlers ...HandlerFunc) {
+	e.router.addRoute("GET", path, handlers, nil)
 }
 
 // POST 注册POST请求处理函数
 // path: 请求路径
-// handler: 处理函数
-func (e *Engine) POST(path string, handler HandlerFunc) {
-	e.router.addRoute("POST", path, handler)
+// handlers: 处理函数，可以在最后的处理函数之前放置仅对该路由生效的中间件
+func (e *Engine) POST(path string, handlers ...HandlerFunc) {
+	e.router.addRoute("POST", path, handlers, nil)
 }
 
 // PUT 注册PUT请求处理函数
 // path: 请求路径
-// handler: 处理函数
-func (e *Engine) PUT(path string, handler HandlerFunc) {
-	e.router.addRoute("PUT", path, handler)
+// handlers: 处理函数，可以在最后的处理函数之前放置仅对该路由生效的中间件
+func (e *Engine) PUT(path string, handlers ...HandlerFunc) {
+	e.router.addRoute("PUT", path, handlers, nil)
 }
 
 // DELETE 注册DELETE请求处理函数
 // path: 请求路径
-// handler: 处理函数
-func (e *Engine) DELETE(path string, handler HandlerFunc) {
-	e.router.addRoute("DELETE", path, handler)
+// handlers: 处理函数，可以在最后的处理函数之前放置仅对该路由生效的中间件
+func (e *Engine) DELETE(path string, handlers ...HandlerFunc) {
+	e.router.addRoute("DELETE", path, handlers, nil)
+}
+
+// PATCH 注册PATCH请求处理函数
+// path: 请求路径
+// handlers: 处理函数，可以在最后的处理函数之前放置仅对该路由生效的中间件
+func (e *Engine) PATCH(path string, handlers ...HandlerFunc) {
+	e.router.addRoute("PATCH", path, handlers, nil)
+}
+
+// HEAD 注册HEAD请求处理函数，未注册时 HEAD 请求由同一路径的 GET 路由处理
+// path: 请求路径
+// handlers: 处理函数，可以在最后的处理函数之前放置仅对该路由生效的中间件
+func (e *Engine) HEAD(path string, handlers ...HandlerFunc) {
+	e.router.addRoute("HEAD", path, handlers, nil)
+}
+
+// OPTIONS 注册OPTIONS请求处理函数，未注册时 OPTIONS 请求自动响应 204 并在 Allow 头中列出允许的方法
+// path: 请求路径
+// handlers: 处理函数，可以在最后的处理函数之前放置仅对该路由生效的中间件
+func (e *Engine) OPTIONS(path string, handlers ...HandlerFunc) {
+	e.router.addRoute("OPTIONS", path, handlers, nil)
+}
+
+// Handle 注册指定请求方法的处理函数，用于其他请求方法，例如 PROPFIND
+// method: 请求方法
+// path: 请求路径
+// handlers: 处理函数，可以在最后的处理函数之前放置仅对该路由生效的中间件
+func (e *Engine) Handle(method, path string, handlers ...HandlerFunc) {
+	e.router.addRoute(checkMethod(method), path, handlers, nil)
+}
+
+// Any 为 GET、POST、PUT、PATCH、DELETE、HEAD、OPTIONS 注册同一组处理函数
+// path: 请求路径
+// handlers: 处理函数，可以在最后的处理函数之前放置仅对该路由生效的中间件
+func (e *Engine) Any(path string, handlers ...HandlerFunc) {
+	for _, method := range anyMethods {
+		e.router.addRoute(method, path, handlers, nil)
+	}
+}
+
+// SetConflictPolicy 设置路由冲突的处理方式，默认在注册冲突路由时 panic
+// 迁移存量项目时可以降级为警告
+func (e *Engine) SetConflictPolicy(policy ConflictPolicy) {
+	e.router.conflictPolicy = policy
 }
 
 // ServeHTTP 实现http.Handler接口
@@ -86,41 +189,115 @@ func (e *Engine) DELETE(path string, handler HandlerFunc) {
 func (e *Engine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := e.pool.Get().(*Context)
 	ctx.reset(w, r)
-	handler, params := e.router.getRoute(r.Method, r.URL.Path)
-	if handler != nil {
-		ctx.Params = params
-		ctx.handlers = append(e.middlewares, handler)
+	var handlers []HandlerFunc
+	// 未注册 HEAD 路由时由 GET 路由处理，net/http 会丢弃响应体
+	if n := e.router.lookup(r.Method, r.URL.Path, &ctx.Params, e.CaseInsensitive); n != nil {
+		ctx.fullPath = n.pattern
+		handlers = n.chain
+	} else if location := e.redirectPath(r, &ctx.Params); location != "" {
+		handlers = e.handlerChain(nil, []HandlerFunc{redirectHandler(location)})
+	} else if r.Method == http.MethodOptions && r.URL.Path != "*" {
+		// 未注册 OPTIONS 路由时列出允许的方法，只经过全局中间件，便于 CORS 中间件处理预检请求
+		if allow := e.router.allowed(r.URL.Path, &ctx.Params, e.CaseInsensitive); allow != "" {
+			handlers = e.handlerChain(nil, []HandlerFunc{optionsHandler(allow)})
+		}
+	}
+	if handlers == nil {
+		if handler := e.spaHandler(r); handler != nil {
+			// 未匹配任何路由时尝试由单页应用处理
+			handlers = e.handlerChain(nil, []HandlerFunc{handler})
+		}
+	}
+	if handlers != nil {
+		ctx.handlers = handlers
 		ctx.Next()
 	} else {
 		http.NotFound(w, r)
 	}
+	// 处理函数返回后上下文即被回收复用，需要在 goroutine 中继续使用时应调用 ctx.Copy()
 	e.pool.Put(ctx)
 }
 
+// redirectPath 返回未匹配路由的请求应重定向到的地址，不需要重定向时返回空字符串
+func (e *Engine) redirectPath(r *http.Request, params *Params) string {
+	path := r.URL.Path
+	if path == "/" || r.Method == http.MethodConnect {
+		return ""
+	}
+	defer func() { *params = (*params)[:0] }()
+
+	fixed := ""
+	if e.RedirectTrailingSlash && path[len(path)-1] == '/' {
+		if trimmed := strings.TrimRight(path, "/"); trimmed != "" &&
+			e.router.lookup(r.Method, trimmed, params, e.CaseInsensitive) != nil {
+			fixed = trimmed
+		}
+	}
+	if fixed == "" && e.RedirectFixedPath {
+		cleaned := pathpkg.Clean(path)
+		if !e.RedirectTrailingSlash && path[len(path)-1] == '/' && cleaned != "/" {
+			// 未开启 RedirectTrailingSlash 时保留末尾的 /，规范化后不会匹配任何路由
+			cleaned += "/"
+		}
+		method := r.Method
+		if method == http.MethodHead && e.router.roots[method] == nil {
+			method = http.MethodGet
+		}
+		if n := e.router.getRoute(method, cleaned, params); n != nil {
+			fixed = cleaned
+		} else if n, p := e.router.getRouteFold(method, cleaned, params); n != nil {
+			fixed = p
+		}
+	}
+	if fixed == "" || fixed == path {
+		return ""
+	}
+	return (&url.URL{Path: fixed, RawQuery: r.URL.RawQuery}).String()
+}
+
+// redirectHandler 返回重定向到修正后路径的处理函数
+// GET 和 HEAD 请求返回 301，其他请求返回 308，保留请求方法和请求体
+func redirectHandler(location string) HandlerFunc {
+	return func(c *Context) {
+		code := http.StatusPermanentRedirect
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			code = http.StatusMovedPermanently
+		}
+		c.Redirect(code, location)
+	}
+}
+
 // Run 启动HTTP服务器
 // addr: 服务器监听地址
-// 返回服务器运行错误（如果有）
+// 返回服务器运行错误（如果有），调用 Shutdown 正常关闭时返回 nil
 func (e *Engine) Run(addr string) error {
 	fmt.Printf("🚀 服务器启动，监听地址：%s\n", addr)
-	return http.ListenAndServe(addr, e)
+	srv := e.newServer(addr)
+	defer e.removeServer(srv)
+	return serverError(srv.ListenAndServe())
 }
 
 // RunTLS 启动HTTPS服务器
 // addr: 服务器监听地址
 // certFile: SSL证书文件路径
 // keyFile: SSL密钥文件路径
-// 返回服务器运行错误（如果有）
+// 返回服务器运行错误（如果有），调用 Shutdown 正常关闭时返回 nil
 func (e *Engine) RunTLS(addr, certFile, keyFile string) error {
 	fmt.Printf("🔒 安全服务器启动，监听地址：%s\n", addr)
-	return http.ListenAndServeTLS(addr, certFile, keyFile, e)
+	srv := e.newServer(addr)
+	defer e.removeServer(srv)
+	return serverError(srv.ListenAndServeTLS(certFile, keyFile))
 }
 
-// Shutdown 优雅关闭服务器
-// ctx: 上下文，用于控制关闭超时
-// 返回关闭错误（如果有）
-func (e *Engine) Shutdown(ctx context.Context) error {
-	// TODO: 实现优雅关闭
-	return nil
+// RunListener 在指定监听器上启动HTTP服务器，便于先同步完成监听再异步提供服务，
+// 例如测试中监听 127.0.0.1:0 获得随机端口，或使用 SystemdListeners 返回的监听器
+// ln: 监听器
+// 返回服务器运行错误（如果有），调用 Shutdown 正常关闭时返回 nil
+func (e *Engine) RunListener(ln net.Listener) error {
+	fmt.Printf("🚀 服务器启动，监听地址：%s\n", ln.Addr())
+	srv := e.newServer(ln.Addr().String())
+	defer e.removeServer(srv)
+	return serverError(srv.Serve(ln))
 }
 
 // SetHTMLRender 设置自定义的 HTML 渲染器
@@ -131,10 +308,63 @@ func (e *Engine) SetHTMLRender(render Renderer) {
 // LoadHTMLGlob 加载 HTML 模板文件
 // glob: 匹配模板文件的 glob 模式，例如 "templates/*"
 func (e *Engine) LoadHTMLGlob(glob string) {
-	e.templates = template.Must(template.ParseGlob(glob))
+	e.LoadHTMLSetGlob("", glob)
 }
 
 // LoadHTMLFiles 加载HTML文件
 func (e *Engine) LoadHTMLFiles(files ...string) {
-	e.templates = template.Must(template.ParseFiles(files...))
+	e.setHTMLLoader("", func() (*template.Template, error) {
+		return e.newTemplate().ParseFiles(files...)
+	})
+}
+
+// LoadHTMLFS 从文件系统加载 HTML 模板，支持 embed.FS，便于单文件部署
+// fsys: 模板所在的文件系统
+// patterns: 匹配模板文件的 glob 模式，例如 "templates/*.html"
+func (e *Engine) LoadHTMLFS(fsys fs.FS, patterns ...string) {
+	e.LoadHTMLSetFS("", fsys, patterns...)
+}
+
+// LoadHTMLSetGlob 将模板加载到命名模板集
+// 不同模板集相互独立，可以包含同名模板文件，例如 "admin" 与 "site" 目录下的 index.html
+// set: 模板集名称
+// glob: 匹配模板文件的 glob 模式，例如 "templates/admin/*"
+func (e *Engine) LoadHTMLSetGlob(set, glob string) {
+	e.setHTMLLoader(set, func() (*template.Template, error) {
+		return e.newTemplate().ParseGlob(glob)
+	})
+}
+
+// LoadHTMLSetFS 从文件系统将模板加载到命名模板集
+// set: 模板集名称
+// fsys: 模板所在的文件系统
+// patterns: 匹配模板文件的 glob 模式
+func (e *Engine) LoadHTMLSetFS(set string, fsys fs.FS, patterns ...string) {
+	e.setHTMLLoader(set, func() (*template.Template, error) {
+		return e.newTemplate().ParseFS(fsys, patterns...)
+	})
+}
+
+// setHTMLLoader 设置模板集的加载函数并立即加载一次
+// 发布模式下始终使用已编译的模板；调试模式下每次渲染前重新解析，修改模板无需重启服务
+func (e *Engine) setHTMLLoader(set string, loader func() (*template.Template, error)) {
+	e.htmlSets[set] = &htmlSet{
+		templates: template.Must(loader()),
+		loader:    loader,
+	}
+}
+
+// htmlTemplate 返回指定模板集中用于渲染的模板
+func (e *Engine) htmlTemplate(set string) (*template.Template, error) {
+	hs, ok := e.htmlSets[set]
+	if !ok {
+		if set == "" {
+			return nil, fmt.Errorf("未加载 HTML 模板，请先调用 LoadHTMLGlob、LoadHTMLFiles 或 LoadHTMLFS")
+		}
+		return nil, fmt.Errorf("模板集 %s 不存在", set)
+	}
+	if IsDebugging() {
+		return hs.loader()
+	}
+	return hs.templates, nil
 }
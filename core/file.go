@@ -0,0 +1,91 @@
+package core
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// File 返回本地文件内容，支持 Range 请求和 If-Modified-Since 协商缓存
+// filepath: 文件路径
+func (c *Context) File(filepath string) {
+	c.StatusCode = http.StatusOK
+	http.ServeFile(c.Writer, c.Request, filepath)
+}
+
+// FileAttachment 以附件形式返回本地文件，浏览器会弹出下载框而不是直接打开
+// 文件名包含中文等非 ASCII 字符时按 RFC 6266 编码
+// filepath: 文件路径
+// filename: 下载时保存的文件名，为空时使用 filepath 的文件名
+func (c *Context) FileAttachment(filepath, filename string) {
+	c.SetHeader("Content-Disposition", contentDisposition("attachment", filename, filepath))
+	c.File(filepath)
+}
+
+// Data 返回字节数据
+// code: HTTP状态码
+// contentType: 内容类型
+// data: 响应内容
+func (c *Context) Data(code int, contentType string, data []byte) {
+	c.SetHeader("Content-Type", contentType)
+	c.Status(code)
+	c.Writer.Write(data)
+}
+
+// DataFromReader 从 reader 读取响应内容，适合返回对象存储、数据库等来源的大文件
+// reader 实现了 io.ReadSeeker 且 code 为 200 时支持 Range 请求
+// code: HTTP状态码
+// contentLength: 内容长度，未知时传 -1
+// contentType: 内容类型
+// reader: 响应内容
+// extraHeaders: 额外的响应头，例如 {"Content-Disposition": `attachment; filename="report.csv"`}
+func (c *Context) DataFromReader(code int, contentLength int64, contentType string, reader io.Reader, extraHeaders map[string]string) {
+	for k, v := range extraHeaders {
+		c.SetHeader(k, v)
+	}
+	c.SetHeader("Content-Type", contentType)
+	if rs, ok := reader.(io.ReadSeeker); ok && code == http.StatusOK {
+		c.StatusCode = code
+		http.ServeContent(c.Writer, c.Request, "", time.Time{}, rs)
+		return
+	}
+	if contentLength >= 0 {
+		c.SetHeader("Content-Length", strconv.FormatInt(contentLength, 10))
+	}
+	c.Status(code)
+	io.Copy(c.Writer, reader)
+}
+
+// Stream 流式写出响应，每次调用 step 后立即发送给客户端
+// step 返回 false 时结束；客户端断开时也会结束并返回 true
+// step: 写出一段内容，返回是否继续
+func (c *Context) Stream(step func(w io.Writer) bool) bool {
+	done := c.Request.Context().Done()
+	for {
+		select {
+		case <-done:
+			return true
+		default:
+			keepOpen := step(c.Writer)
+			c.Writer.Flush()
+			if !keepOpen {
+				return false
+			}
+		}
+	}
+}
+
+// contentDisposition 生成 Content-Disposition 响应头
+// filename 为空时使用 path 的文件名
+func contentDisposition(disposition, filename, path string) string {
+	if filename == "" {
+		filename = filepath.Base(path)
+	}
+	if v := mime.FormatMediaType(disposition, map[string]string{"filename": filename}); v != "" {
+		return v
+	}
+	return disposition
+}
@@ -0,0 +1,50 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/xzl-go/easygo/health"
+)
+
+// errNotReady 是引擎未就绪时就绪检查返回的错误
+var errNotReady = errors.New("服务未就绪或正在关闭")
+
+// EnableHealthChecks 注册存活探针 /healthz 和就绪探针 /readyz，返回检查管理器用于添加检查项
+// 全部检查项正常时返回 200，否则返回 503，响应体为 JSON 格式的检查报告。
+// 就绪检查内置 server 检查项，反映引擎的就绪状态：SetReady(false) 或优雅关闭开始后 /readyz 立即返回 503，
+// 负载均衡器据此摘除实例。重复调用返回同一个检查管理器。
+func (e *Engine) EnableHealthChecks() *health.Health {
+	e.serverMu.Lock()
+	defer e.serverMu.Unlock()
+	if e.health != nil {
+		return e.health
+	}
+
+	e.health = health.New(0)
+	e.health.AddReadinessCheck("server", health.CheckerFunc(func(ctx context.Context) error {
+		if !e.Ready() {
+			return errNotReady
+		}
+		return nil
+	}))
+	e.GET("/healthz", func(c *Context) {
+		writeHealthReport(c, e.health.Liveness(c.Request.Context()))
+	})
+	e.GET("/readyz", func(c *Context) {
+		writeHealthReport(c, e.health.Readiness(c.Request.Context()))
+	})
+	return e.health
+}
+
+// writeHealthReport 输出检查报告
+func writeHealthReport(c *Context, report health.Report) {
+	// 探针结果必须实时，禁止代理缓存
+	c.SetHeader("Cache-Control", "no-store")
+	if report.Up() {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+	c.JSON(http.StatusServiceUnavailable, report)
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/xzl-go/easygo/validator"
+)
+
+const (
+	// LangKey 是上下文中保存请求语言的键，由国际化中间件写入
+	LangKey = "lang"
+	// TranslatorKey 是上下文中保存翻译器的键，由国际化中间件写入
+	TranslatorKey = "translator"
+)
+
+// Localizer 是支持参数的翻译器，*i18n.I18n 实现了该接口
+type Localizer interface {
+	// Localize 翻译消息键，args 为单个参数表时替换占位符，否则按 fmt.Sprintf 格式化译文
+	Localize(key, lang string, args ...interface{}) string
+}
+
+// Lang 返回请求语言，即国际化中间件协商的语言，未注册中间件时返回空字符串
+func (c *Context) Lang() string {
+	lang, _ := c.Get(LangKey).(string)
+	return lang
+}
+
+// T 按请求语言翻译消息键
+// 优先使用国际化中间件绑定的翻译器，其次是 Engine.SetTranslator 设置的翻译器，都没有时返回消息键
+// key: 消息键
+// args: 翻译参数，可以是单个 i18n.Params，也可以是译文中 fmt 占位符对应的参数
+func (c *Context) T(key string, args ...interface{}) string {
+	translator := c.translator()
+	if translator == nil {
+		return key
+	}
+	if localizer, ok := translator.(Localizer); ok {
+		return localizer.Localize(key, c.Lang(), args...)
+	}
+	message := translator.Translate(key, c.Lang())
+	// 没有译文时返回消息键本身，不做格式化
+	if len(args) > 0 && message != key {
+		message = fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+// JSONError 返回错误响应，消息为按请求语言翻译的消息键，业务错误码为状态码乘以 100（与 errors 包的通用错误码一致）
+// 已定义业务错误时应使用 Fail
+// status: HTTP 状态码
+// key: 消息键
+// args: 翻译参数，同 T
+func (c *Context) JSONError(status int, key string, args ...interface{}) {
+	message := c.T(key, args...)
+	if message == key && len(args) == 0 {
+		if text := http.StatusText(status); text != "" {
+			message = text
+		}
+	}
+	c.JSON(status, Response{
+		Code:    status * 100,
+		Message: message,
+		TraceID: c.TraceID(),
+	})
+}
+
+// translator 返回请求使用的翻译器，没有时返回 nil
+func (c *Context) translator() validator.Translator {
+	if translator, ok := c.Get(TranslatorKey).(validator.Translator); ok {
+		return translator
+	}
+	if c.engine != nil {
+		return c.engine.translator
+	}
+	return nil
+}
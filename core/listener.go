@@ -0,0 +1,96 @@
+package core
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// RunUnix 在 Unix 域套接字上启动HTTP服务器，用于部署在同一主机的 nginx 等反向代理之后
+// 套接字文件已存在且没有进程监听时先删除，服务器关闭时自动删除套接字文件；
+// 文件权限由进程的 umask 决定，反向代理以其他用户运行时需调整 umask 或套接字所在目录的权限
+// socketPath: 套接字文件路径，例如 "/run/myapp/http.sock"
+// 返回服务器运行错误（如果有），调用 Shutdown 正常关闭时返回 nil
+func (e *Engine) RunUnix(socketPath string) error {
+	if err := removeStaleSocket(socketPath); err != nil {
+		return err
+	}
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return err
+	}
+	return e.RunListener(ln)
+}
+
+// RunSystemd 在 systemd 套接字激活传入的第一个监听器上启动HTTP服务器，
+// 监听端口由 .socket 单元持有，服务重启期间新连接在内核中排队而不会被拒绝
+// 返回服务器运行错误（如果有），调用 Shutdown 正常关闭时返回 nil
+func (e *Engine) RunSystemd() error {
+	listeners, err := SystemdListeners()
+	if err != nil {
+		return err
+	}
+	if len(listeners) == 0 {
+		return fmt.Errorf("easygo: 没有 systemd 传入的监听器")
+	}
+	for _, ln := range listeners[1:] {
+		ln.Close()
+	}
+	return e.RunListener(listeners[0])
+}
+
+// SystemdListeners 返回 systemd 套接字激活传入的监听器，按 .socket 单元中的声明顺序排列
+// 进程不是由 systemd 套接字激活启动时返回空列表；返回后清除 LISTEN_* 环境变量，避免子进程重复使用
+func SystemdListeners() ([]net.Listener, error) {
+	defer func() {
+		os.Unsetenv("LISTEN_PID")
+		os.Unsetenv("LISTEN_FDS")
+		os.Unsetenv("LISTEN_FDNAMES")
+	}()
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n <= 0 {
+		return nil, nil
+	}
+
+	// systemd 传入的文件描述符从 3 开始
+	const listenFdsStart = 3
+	listeners := make([]net.Listener, 0, n)
+	for fd := listenFdsStart; fd < listenFdsStart+n; fd++ {
+		f := os.NewFile(uintptr(fd), "LISTEN_FD_"+strconv.Itoa(fd))
+		ln, err := net.FileListener(f)
+		// FileListener 复制了文件描述符，原文件可以关闭
+		f.Close()
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, fmt.Errorf("easygo: 无法使用 systemd 传入的文件描述符 %d: %w", fd, err)
+		}
+		listeners = append(listeners, ln)
+	}
+	return listeners, nil
+}
+
+// removeStaleSocket 删除没有进程监听的套接字文件，文件存在且不是套接字时返回错误
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("easygo: %s 已存在且不是套接字文件", path)
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return fmt.Errorf("easygo: 套接字 %s 正在被其他进程监听", path)
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrChannelClosed 表示等待的通道已关闭
+var ErrChannelClosed = errors.New("core: channel closed")
+
+// LongPoll 处理长轮询请求
+// wait 在超时或客户端断开时应尽快返回 ctx.Err()，可配合 Receive 或 Notifier.Wait 使用：
+// 返回数据时响应 200，超时响应 204，客户端已断开时不写出任何响应，其他错误按 Fail 处理
+// timeout: 最长等待时间
+// wait: 等待数据的函数
+func (c *Context) LongPoll(timeout time.Duration, wait func(ctx context.Context) (interface{}, error)) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	data, err := wait(ctx)
+	switch {
+	case err == nil:
+		c.Success(data)
+	case c.Request.Context().Err() != nil:
+		// 客户端已断开，无需响应
+		c.Abort()
+	case errors.Is(err, context.DeadlineExceeded):
+		c.Status(http.StatusNoContent)
+	default:
+		c.Fail(err)
+	}
+}
+
+// Receive 从通道接收一个值，ctx 结束时返回 ctx.Err()，通道关闭时返回 ErrChannelClosed
+func Receive[T any](ctx context.Context, ch <-chan T) (T, error) {
+	var zero T
+	select {
+	case v, ok := <-ch:
+		if !ok {
+			return zero, ErrChannelClosed
+		}
+		return v, nil
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// Notifier 是基于版本号的广播通知，适用于长轮询场景：
+// 客户端携带上次看到的版本号请求，有新版本时立即返回，否则等待 Notify
+type Notifier struct {
+	mu      sync.Mutex
+	version uint64
+	changed chan struct{}
+}
+
+// NewNotifier 创建广播通知
+func NewNotifier() *Notifier {
+	return &Notifier{changed: make(chan struct{})}
+}
+
+// Notify 递增版本号并唤醒所有等待者
+func (n *Notifier) Notify() uint64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.version++
+	close(n.changed)
+	n.changed = make(chan struct{})
+	return n.version
+}
+
+// Version 返回当前版本号
+func (n *Notifier) Version() uint64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.version
+}
+
+// Wait 等待版本号大于 since，返回最新版本号
+// ctx: 等待上下文，结束时返回 ctx.Err()
+// since: 客户端已看到的版本号
+func (n *Notifier) Wait(ctx context.Context, since uint64) (uint64, error) {
+	for {
+		n.mu.Lock()
+		version, changed := n.version, n.changed
+		n.mu.Unlock()
+		if version > since {
+			return version, nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return version, ctx.Err()
+		}
+	}
+}
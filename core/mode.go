@@ -0,0 +1,41 @@
+// Package core 提供了EasyGo框架的核心功能
+package core
+
+import "os"
+
+// 运行模式常量
+const (
+	DebugMode   = "debug"   // 调试模式
+	ReleaseMode = "release" // 发布模式
+)
+
+// ModeEnv 是读取运行模式的环境变量名
+const ModeEnv = "EASYGO_MODE"
+
+// mode 当前运行模式，默认调试模式
+var mode = DebugMode
+
+func init() {
+	SetMode(os.Getenv(ModeEnv))
+}
+
+// SetMode 设置运行模式
+// value: DebugMode 或 ReleaseMode，为空时使用调试模式
+func SetMode(value string) {
+	switch value {
+	case ReleaseMode:
+		mode = ReleaseMode
+	default:
+		mode = DebugMode
+	}
+}
+
+// Mode 返回当前运行模式
+func Mode() string {
+	return mode
+}
+
+// IsDebugging 判断是否处于调试模式
+func IsDebugging() bool {
+	return mode == DebugMode
+}
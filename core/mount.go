@@ -0,0 +1,52 @@
+package core
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// MountOptions 定义了挂载 http.Handler 的选项
+type MountOptions struct {
+	// StripPrefix 是否在调用处理器前去掉挂载前缀，默认保留完整路径
+	// 按完整路径路由的处理器（http.DefaultServeMux、gRPC-Gateway 等）保持默认，
+	// 按相对路径路由的处理器（http.FileServer、子路由器等）需要开启
+	StripPrefix bool
+}
+
+// Mount 将 http.Handler 挂载到路径前缀下，前缀及其下的全部路径、Any 支持的全部请求方法都交给该处理器
+// 用于嵌入已有的处理器，例如 http.DefaultServeMux 上的 pprof、gRPC-Gateway、第三方路由器；
+// 请求先经过全局中间件、路由组中间件和 middlewares，处理器可以通过 r.Context() 取得请求的 context
+// prefix: 路径前缀，例如 "/debug"，挂载点下的其他路由会与之冲突
+// handler: 挂载的处理器
+// middlewares: 仅对挂载点生效的中间件
+func (group *RouterGroup) Mount(prefix string, handler http.Handler, middlewares ...HandlerFunc) {
+	group.MountWithOptions(prefix, handler, MountOptions{}, middlewares...)
+}
+
+// MountWithOptions 按选项将 http.Handler 挂载到路径前缀下
+// prefix: 路径前缀
+// handler: 挂载的处理器
+// options: 挂载选项
+// middlewares: 仅对挂载点生效的中间件
+func (group *RouterGroup) MountWithOptions(prefix string, handler http.Handler, options MountOptions, middlewares ...HandlerFunc) {
+	serve := func(c *Context) {
+		r := c.Request
+		if options.StripPrefix {
+			// 通配参数是挂载前缀之后的路径，不受大小写不敏感匹配的影响
+			r = new(http.Request)
+			*r = *c.Request
+			r.URL = new(url.URL)
+			*r.URL = *c.Request.URL
+			r.URL.Path = "/" + c.Param("path")
+			r.URL.RawPath = ""
+		}
+		handler.ServeHTTP(c.Writer, r)
+	}
+	handlers := append(append([]HandlerFunc(nil), middlewares...), serve)
+
+	full := cleanPattern(group.prefix + prefix)
+	if full != "/" {
+		group.Any(prefix, handlers...)
+	}
+	group.Any(prefix+"/*path", handlers...)
+}
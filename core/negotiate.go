@@ -0,0 +1,212 @@
+package core
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ugorji/go/codec"
+	"gopkg.in/yaml.v3"
+
+	errs "github.com/xzl-go/easygo/errors"
+)
+
+// 常用的响应媒体类型
+const (
+	MIMEJSON    = "application/json"
+	MIMEXML     = "application/xml"
+	MIMEYAML    = "application/yaml"
+	MIMEMsgPack = "application/msgpack"
+	MIMEHTML    = "text/html"
+	MIMEPlain   = "text/plain"
+)
+
+// ErrNotAcceptable 没有客户端可以接受的响应格式
+var ErrNotAcceptable = errs.New(40600, "error.not_acceptable", http.StatusNotAcceptable, "Not acceptable")
+
+// ContentRenderer 以某种媒体类型写出响应
+type ContentRenderer func(c *Context, code int, data interface{})
+
+// HTMLContent 是内容协商时 HTML 响应使用的模板，其他格式的响应序列化 Data
+type HTMLContent struct {
+	Name string      // 模板名称
+	Data interface{} // 模板数据
+}
+
+// defaultOffers 是 Negotiate 未指定可选格式时使用的格式
+var defaultOffers = []string{MIMEJSON, MIMEXML, MIMEYAML, MIMEMsgPack}
+
+// msgpackHandle 是 MsgPack 编码配置
+var msgpackHandle = &codec.MsgpackHandle{}
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = map[string]ContentRenderer{
+		MIMEJSON:    func(c *Context, code int, data interface{}) { c.JSON(code, data) },
+		MIMEXML:     func(c *Context, code int, data interface{}) { c.XML(code, data) },
+		"text/xml":  func(c *Context, code int, data interface{}) { c.XML(code, data) },
+		MIMEYAML:    func(c *Context, code int, data interface{}) { c.YAML(code, data) },
+		MIMEMsgPack: func(c *Context, code int, data interface{}) { c.MsgPack(code, data) },
+		MIMEHTML:    renderHTMLContent,
+		MIMEPlain:   func(c *Context, code int, data interface{}) { c.String(code, "%v", data) },
+	}
+)
+
+// RegisterContentRenderer 注册或替换媒体类型的渲染函数，供 Negotiate 使用，例如 application/x-protobuf
+// mediaType: 媒体类型，不含参数
+// renderer: 渲染函数
+func RegisterContentRenderer(mediaType string, renderer ContentRenderer) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	renderers[strings.ToLower(mediaType)] = renderer
+}
+
+// lookupRenderer 查找媒体类型的渲染函数
+func lookupRenderer(mediaType string) (ContentRenderer, bool) {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	renderer, ok := renderers[strings.ToLower(mediaType)]
+	return renderer, ok
+}
+
+// Negotiate 按 Accept 请求头从可选格式中选择响应格式并写出响应，没有可接受的格式时返回 406
+// 未指定可选格式时依次为 JSON、XML、YAML、MsgPack，data 为 HTMLContent 时追加 HTML；
+// Accept 为空或 */* 时使用第一个可选格式
+// code: HTTP状态码
+// data: 响应数据，需要 HTML 响应时使用 HTMLContent 指定模板
+// offers: 可选的媒体类型，按优先级排列，需已注册渲染函数
+func (c *Context) Negotiate(code int, data interface{}, offers ...string) {
+	if len(offers) == 0 {
+		offers = defaultOffers
+		if _, ok := data.(HTMLContent); ok {
+			offers = append(offers[:len(offers):len(offers)], MIMEHTML)
+		}
+	}
+	c.Writer.Header().Add("Vary", "Accept")
+
+	format := c.NegotiateFormat(offers...)
+	renderer, ok := lookupRenderer(format)
+	if !ok {
+		c.Fail(ErrNotAcceptable)
+		return
+	}
+	if content, ok := data.(HTMLContent); ok && format != MIMEHTML {
+		data = content.Data
+	}
+	renderer(c, code, data)
+}
+
+// NegotiateFormat 按 Accept 请求头从可选格式中选择最合适的一个，没有可接受的格式时返回空字符串
+// 按质量值 q 选择，q 相同时按 offers 的顺序；Accept 中更具体的媒体范围优先决定 q，例如 text/html;q=0 排除 text/*
+// offers: 可选的媒体类型，按优先级排列
+func (c *Context) NegotiateFormat(offers ...string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+	header := c.Request.Header.Get("Accept")
+	if strings.TrimSpace(header) == "" {
+		return offers[0]
+	}
+	ranges := parseAccept(header)
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		if q := acceptQuality(ranges, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+// mediaRange 是 Accept 请求头中的一项
+type mediaRange struct {
+	typ, subtype string
+	q            float64
+}
+
+// parseAccept 解析 Accept 请求头，忽略格式错误的项
+func parseAccept(header string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		typ, subtype, ok := strings.Cut(mediaType, "/")
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		ranges = append(ranges, mediaRange{typ: typ, subtype: subtype, q: q})
+	}
+	// 具体的媒体类型优先于 type/*，type/* 优先于 */*
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].specificity() > ranges[j].specificity()
+	})
+	return ranges
+}
+
+// specificity 返回媒体范围的具体程度
+func (r mediaRange) specificity() int {
+	switch {
+	case r.typ == "*":
+		return 0
+	case r.subtype == "*":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// acceptQuality 返回最具体的匹配项给出的质量值，不匹配时返回 0
+func acceptQuality(ranges []mediaRange, offer string) float64 {
+	typ, subtype, _ := strings.Cut(strings.ToLower(offer), "/")
+	for _, r := range ranges {
+		if (r.typ == "*" || r.typ == typ) && (r.subtype == "*" || r.subtype == subtype) {
+			return r.q
+		}
+	}
+	return 0
+}
+
+// YAML 返回 YAML 格式响应
+// code: HTTP状态码
+// obj: 要序列化的对象
+func (c *Context) YAML(code int, obj interface{}) {
+	body, err := yaml.Marshal(obj)
+	if err != nil {
+		http.Error(c.Writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	c.Data(code, MIMEYAML+"; charset=utf-8", body)
+}
+
+// MsgPack 返回 MessagePack 格式响应
+// code: HTTP状态码
+// obj: 要序列化的对象
+func (c *Context) MsgPack(code int, obj interface{}) {
+	var buf bytes.Buffer
+	if err := codec.NewEncoder(&buf, msgpackHandle).Encode(obj); err != nil {
+		http.Error(c.Writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	c.Data(code, MIMEMsgPack, buf.Bytes())
+}
+
+// renderHTMLContent 渲染 HTMLContent 指定的模板，data 不是 HTMLContent 时返回 406
+func renderHTMLContent(c *Context, code int, data interface{}) {
+	content, ok := data.(HTMLContent)
+	if !ok {
+		c.Fail(ErrNotAcceptable)
+		return
+	}
+	c.HTML(code, content.Name, content.Data)
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"path"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// DefaultPprofPrefix 是性能分析端点的默认前缀
+const DefaultPprofPrefix = "/debug/pprof"
+
+// processStart 是进程启动时间，用于计算运行时长
+var processStart = time.Now()
+
+// pprofProfiles 是通过 pprof.Handler 输出的命名性能分析
+var pprofProfiles = []string{"allocs", "block", "goroutine", "heap", "mutex", "threadcreate"}
+
+// EnablePprof 在框架路由下挂载 net/http/pprof 性能分析端点，以及与其同级的 vars 运行时变量端点
+// 默认前缀下的端点为 /debug/pprof/（索引）、/debug/pprof/profile、/debug/pprof/heap 等，以及 /debug/vars。
+// vars 端点以 JSON 输出 expvar 发布的全部变量（包括 memstats、cmdline）和 easygo 运行时信息（协程数、运行时长等），
+// 连接池等统计可通过 expvar.Publish 发布后一并输出。
+// 性能分析会暴露内部信息并消耗资源，生产环境应通过 middlewares 添加认证或只在内网端口启用。
+// prefix: 端点前缀，为空时使用 "/debug/pprof"
+// middlewares: 端点的中间件，例如 Basic 认证、IP 白名单
+func (e *Engine) EnablePprof(prefix string, middlewares ...HandlerFunc) {
+	if prefix == "" {
+		prefix = DefaultPprofPrefix
+	}
+	prefix = "/" + strings.Trim(prefix, "/")
+
+	group := e.Group(prefix)
+	group.Use(middlewares...)
+	group.GET("/", func(c *Context) {
+		// 索引页使用相对链接，需以 / 结尾
+		if !strings.HasSuffix(c.Request.URL.Path, "/") {
+			u := *c.Request.URL
+			u.Path += "/"
+			c.Redirect(http.StatusMovedPermanently, u.RequestURI())
+			return
+		}
+		pprof.Index(c.Writer, c.Request)
+	})
+	group.GET("/cmdline", wrapHandlerFunc(pprof.Cmdline))
+	group.GET("/profile", wrapHandlerFunc(pprof.Profile))
+	group.GET("/symbol", wrapHandlerFunc(pprof.Symbol))
+	group.POST("/symbol", wrapHandlerFunc(pprof.Symbol))
+	group.GET("/trace", wrapHandlerFunc(pprof.Trace))
+	for _, name := range pprofProfiles {
+		group.GET("/"+name, wrapHandler(pprof.Handler(name)))
+	}
+
+	vars := e.Group(strings.TrimSuffix(path.Dir(prefix), "/"))
+	vars.Use(middlewares...)
+	vars.GET("/vars", e.varsHandler)
+}
+
+// varsHandler 输出 expvar 变量和 easygo 运行时信息
+func (e *Engine) varsHandler(c *Context) {
+	var b strings.Builder
+	b.WriteString("{\n")
+	first := true
+	write := func(key, value string) {
+		if !first {
+			b.WriteString(",\n")
+		}
+		first = false
+		k, _ := json.Marshal(key)
+		b.Write(k)
+		b.WriteString(": ")
+		b.WriteString(value)
+	}
+	expvar.Do(func(kv expvar.KeyValue) {
+		write(kv.Key, kv.Value.String())
+	})
+	info, _ := json.Marshal(map[string]interface{}{
+		"goroutines":     runtime.NumGoroutine(),
+		"num_cpu":        runtime.NumCPU(),
+		"gomaxprocs":     runtime.GOMAXPROCS(0),
+		"cgo_calls":      runtime.NumCgoCall(),
+		"go_version":     runtime.Version(),
+		"uptime_seconds": int64(time.Since(processStart).Seconds()),
+		"ready":          e.Ready(),
+	})
+	write("easygo", string(info))
+	b.WriteString("\n}\n")
+
+	c.SetHeader("Content-Type", "application/json; charset=utf-8")
+	c.SetHeader("Cache-Control", "no-store")
+	c.Writer.WriteHeader(http.StatusOK)
+	c.Writer.Write([]byte(b.String()))
+}
+
+// wrapHandlerFunc 将 http.HandlerFunc 适配为 HandlerFunc
+func wrapHandlerFunc(h http.HandlerFunc) HandlerFunc {
+	return func(c *Context) {
+		h(c.Writer, c.Request)
+	}
+}
+
+// wrapHandler 将 http.Handler 适配为 HandlerFunc
+func wrapHandler(h http.Handler) HandlerFunc {
+	return func(c *Context) {
+		h.ServeHTTP(c.Writer, c.Request)
+	}
+}
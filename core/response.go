@@ -0,0 +1,69 @@
+// Package core 提供了EasyGo框架的核心功能
+package core
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	errs "github.com/xzl-go/easygo/errors"
+	"github.com/xzl-go/easygo/validator"
+)
+
+// TraceIDKey 是上下文中保存追踪ID的键
+const TraceIDKey = "trace_id"
+
+// Response 是统一的响应结构
+type Response struct {
+	Code    int         `json:"code"`               // 业务错误码，0 表示成功
+	Message string      `json:"message"`            // 提示消息
+	Data    interface{} `json:"data,omitempty"`     // 业务数据
+	TraceID string      `json:"trace_id,omitempty"` // 追踪ID，便于排查问题
+}
+
+// TraceID 获取当前请求的追踪ID
+// 优先读取上下文中的 trace_id，其次读取请求头 X-Request-ID
+func (c *Context) TraceID() string {
+	if v, ok := c.Get(TraceIDKey).(string); ok && v != "" {
+		return v
+	}
+	return c.GetHeader("X-Request-ID")
+}
+
+// Success 返回成功响应
+// data: 业务数据
+func (c *Context) Success(data interface{}) {
+	c.JSON(http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    data,
+		TraceID: c.TraceID(),
+	})
+}
+
+// Fail 返回失败响应
+// err: 错误，非业务错误将按内部错误处理；错误链中包含 validator.ValidationErrors 时 data 为字段错误列表
+func (c *Context) Fail(err error) {
+	e := errs.From(err)
+	if e == nil {
+		e = errs.ErrInternal
+	}
+	var data interface{}
+	if fieldErrors := FieldErrors(err); fieldErrors != nil {
+		data = fieldErrors
+	}
+	c.JSON(e.Status, Response{
+		Code:    e.Code,
+		Message: e.Message,
+		Data:    data,
+		TraceID: c.TraceID(),
+	})
+}
+
+// FieldErrors 从错误链中取出字段校验错误，没有时返回 nil
+func FieldErrors(err error) validator.ValidationErrors {
+	var fieldErrors validator.ValidationErrors
+	if stderrors.As(err, &fieldErrors) {
+		return fieldErrors
+	}
+	return nil
+}
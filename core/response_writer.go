@@ -0,0 +1,104 @@
+package core
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// noWritten 表示尚未写出响应
+const noWritten = -1
+
+// ResponseWriter 封装了 http.ResponseWriter，记录响应状态码和写出的字节数
+// 通过 c.Writer 写出的响应，无论来自 JSON、String、http.ServeContent 还是直接调用 Write，都会被记录
+type ResponseWriter interface {
+	http.ResponseWriter
+	http.Flusher
+	http.Hijacker
+
+	// Status 返回响应状态码，尚未写出时返回 200
+	Status() int
+	// Size 返回已写出的响应体字节数，尚未写出时返回 -1
+	Size() int
+	// Written 返回响应头是否已经写出
+	Written() bool
+	// Unwrap 返回原始的 http.ResponseWriter，供 http.ResponseController 使用
+	Unwrap() http.ResponseWriter
+}
+
+// responseWriter 是 ResponseWriter 的默认实现，随 Context 一起复用
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int
+}
+
+// reset 绑定新的 http.ResponseWriter
+func (w *responseWriter) reset(writer http.ResponseWriter) {
+	w.ResponseWriter = writer
+	w.status = http.StatusOK
+	w.size = noWritten
+}
+
+// WriteHeader 写出状态码，只有第一次调用生效，重复调用不会产生 superfluous WriteHeader 警告
+func (w *responseWriter) WriteHeader(code int) {
+	if code <= 0 || w.Written() {
+		return
+	}
+	w.status = code
+	w.size = 0
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write 写出响应体，尚未写出响应头时先写出当前状态码
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.Written() {
+		w.WriteHeader(w.status)
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
+}
+
+// Status 返回响应状态码
+func (w *responseWriter) Status() int {
+	return w.status
+}
+
+// Size 返回已写出的响应体字节数
+func (w *responseWriter) Size() int {
+	return w.size
+}
+
+// Written 返回响应头是否已经写出
+func (w *responseWriter) Written() bool {
+	return w.size != noWritten
+}
+
+// Unwrap 返回原始的 http.ResponseWriter
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Flush 将缓冲的数据发送给客户端
+func (w *responseWriter) Flush() {
+	if !w.Written() {
+		w.WriteHeader(w.status)
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack 接管底层连接，用于 WebSocket 等协议升级
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("core: ResponseWriter 不支持 Hijack")
+	}
+	if w.size < 0 {
+		w.size = 0
+	}
+	return h.Hijack()
+}
@@ -2,118 +2,577 @@
 package core
 
 import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
 )
 
-// MiddlewareFunc 定义了中间件函数的类型
-// type MiddlewareFunc func(ctx *Context) // 删除了此行
+// nodeKind 是路由树节点的类型
+type nodeKind uint8
 
-// node 表示路由树中的节点
+const (
+	staticNode   nodeKind = iota // 静态路径，例如 /users/
+	paramNode                    // 参数，例如 :id，匹配一个路径段
+	catchAllNode                 // 通配，例如 *path，匹配剩余路径
+)
+
+// node 表示基数树（压缩前缀树）中的节点
+// 静态节点的 path 是相对于父节点的公共前缀；参数和通配节点的 path 是完整的参数段，例如 :id(int)
 type node struct {
-	pattern  string           // 路由模式
-	part     string           // 路由部分
-	children map[string]*node // 子节点
-	isWild   bool             // 是否是通配符节点
-	handler  HandlerFunc      // 处理函数
+	path       string         // 节点路径
+	kind       nodeKind       // 节点类型
+	indices    string         // 静态子节点路径的首字节，与 children 一一对应，按优先级排序
+	children   []*node        // 静态子节点
+	wild       []*node        // 参数和通配子节点，只出现在以 / 结尾的位置，通配节点排在最后
+	priority   uint32         // 子树中注册的路由数，优先级高的子节点先匹配
+	param      string         // 参数名，例如 :id(int) 的参数名为 id
+	constraint *regexp.Regexp // 参数约束，参数值不匹配时该节点不匹配
+	pattern    string         // 路由模式，未注册路由的中间节点为空
+	handlers   []HandlerFunc  // 路由中间件和处理函数
+	group      *RouterGroup   // 注册路由的路由组，用于解析组中间件
+	chain      []HandlerFunc  // 完整的处理链：全局中间件、路由组中间件、路由中间件和处理函数
 }
 
 // router 是路由管理器
-// 实现了基于前缀树的路由匹配
+// 每种请求方法一棵基数树，静态路径优先于参数，参数优先于通配；
+// 匹配过程不分配内存，路径参数写入上下文预分配的切片
 type router struct {
-	roots    map[string]*node       // 路由树根节点
-	handlers map[string]HandlerFunc // 路由处理函数
-	engine   *Engine                // 引擎引用
+	roots          map[string]*node           // 路由树根节点
+	handlers       map[routeKey][]HandlerFunc // 路由中间件和处理函数
+	maxParams      int                        // 单个路由的最大参数数量，用于预分配上下文的参数切片
+	conflictPolicy ConflictPolicy             // 路由冲突的处理方式
+	// chain 组装路由的完整处理链，注册路由和添加中间件时调用，请求时直接使用节点缓存的处理链
+	chain func(group *RouterGroup, handlers []HandlerFunc) []HandlerFunc
+}
+
+// routeKey 标识一个已注册的路由
+// 请求方法可以包含 '-'（例如 M-SEARCH），不能与路径拼接为字符串
+type routeKey struct {
+	method, path string
+}
+
+// ConflictPolicy 定义了注册路由时发现冲突的处理方式
+type ConflictPolicy int
+
+const (
+	ConflictPanic ConflictPolicy = iota // 直接 panic，默认
+	ConflictWarn                        // 打印警告，后注册的路由覆盖或与已有路由共存，共存时静态路由优先匹配
+)
+
+// Param 是一个路径参数
+type Param struct {
+	Key   string
+	Value string
+}
+
+// Params 是路径参数列表，按在路由中出现的顺序排列
+type Params []Param
+
+// Get 返回参数值和参数是否存在
+// key: 参数名
+func (ps Params) Get(key string) (string, bool) {
+	for _, p := range ps {
+		if p.Key == key {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+// ByName 返回参数值，参数不存在时返回空字符串
+// key: 参数名
+func (ps Params) ByName(key string) string {
+	v, _ := ps.Get(key)
+	return v
 }
 
 // newRouter 创建新的路由器
 func newRouter() *router {
 	return &router{
 		roots:    make(map[string]*node),
-		handlers: make(map[string]HandlerFunc),
+		handlers: make(map[routeKey][]HandlerFunc),
 	}
 }
 
-// parsePattern 解析路由模式
-func parsePattern(pattern string) []string {
-	parts := strings.Split(pattern, "/")
-	result := make([]string, 0)
-	for _, part := range parts {
-		if part != "" {
-			result = append(result, part)
-			if part[0] == '*' {
+// cleanPattern 规范化路由模式：补全开头的 /，合并连续的 /，去掉末尾的 /
+// 因此 /users/ 与 /users 是同一个路由
+func cleanPattern(pattern string) string {
+	if pattern == "" || pattern == "/" {
+		return "/"
+	}
+	var b strings.Builder
+	b.Grow(len(pattern) + 1)
+	if pattern[0] != '/' {
+		b.WriteByte('/')
+	}
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '/' && (b.Len() > 0 && i > 0 && pattern[i-1] == '/') {
+			continue
+		}
+		b.WriteByte(pattern[i])
+	}
+	s := b.String()
+	if len(s) > 1 && s[len(s)-1] == '/' {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// insert 插入路由
+func (r *router) insert(method, pattern string, handlers []HandlerFunc, group *RouterGroup) {
+	root, ok := r.roots[method]
+	if !ok {
+		root = &node{path: "/"}
+		r.roots[method] = root
+	}
+
+	var conflicts []string
+	n, rest := root, pattern[1:]
+	n.priority++
+	params := 0
+	for rest != "" {
+		// 参数或通配段
+		if rest[0] == ':' || rest[0] == '*' {
+			end := strings.IndexByte(rest, '/')
+			if end < 0 {
+				end = len(rest)
+			}
+			part := rest[:end]
+			if rest[0] == '*' && end != len(rest) {
+				panic(fmt.Sprintf("easygo: 路由 %s 的通配参数 %s 必须位于末尾", pattern, part))
+			}
+			if len(part) < 2 {
+				panic(fmt.Sprintf("easygo: 路由 %s 的参数缺少名称", pattern))
+			}
+			child := n.findWild(part)
+			if child == nil {
+				// 参数不能与同一位置的其他路由共存，否则匹配结果取决于注册顺序，例如 /users/:id 与 /users/list
+				conflicts = append(conflicts, n.subtreeRoutes(method)...)
+				child = &node{path: part, kind: paramNode}
+				if part[0] == '*' {
+					child.kind = catchAllNode
+				}
+				child.param, child.constraint = parseParam(pattern, part)
+				n.addWild(child)
+			}
+			n = child
+			n.priority++
+			params++
+			rest = rest[end:]
+			continue
+		}
+
+		// 静态段，直到下一个参数或通配段
+		end := len(rest)
+		for i := 0; i+1 < len(rest); i++ {
+			if rest[i] == '/' && (rest[i+1] == ':' || rest[i+1] == '*') {
+				end = i + 1
 				break
 			}
 		}
+		static := rest[:end]
+		// 参数和通配节点只出现在路径段开头，静态段与其共存同样视为冲突
+		if len(n.wild) > 0 {
+			for _, w := range n.wild {
+				conflicts = append(conflicts, w.routes(method)...)
+			}
+		}
+		i := strings.IndexByte(n.indices, static[0])
+		if i < 0 {
+			child := &node{path: static}
+			n.indices += string(static[0])
+			n.children = append(n.children, child)
+			i = len(n.children) - 1
+		}
+		child := n.children[i]
+		l := commonPrefix(child.path, static)
+		if l < len(child.path) {
+			child = child.split(l)
+			n.children[i] = child
+		}
+		child.priority++
+		n.sortChild(i)
+		n = child
+		rest = rest[l:]
+	}
+
+	if n.handlers != nil {
+		conflicts = append(conflicts, method+" "+n.pattern)
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		msg := fmt.Sprintf("easygo: 路由 %s %s 与已注册的路由冲突：%s", method, pattern, strings.Join(conflicts, ", "))
+		if r.conflictPolicy != ConflictWarn {
+			panic(msg)
+		}
+		fmt.Printf("⚠️  %s\n", msg)
+	}
+
+	n.pattern = pattern
+	n.handlers = handlers
+	n.group = group
+	n.chain = r.buildChain(n)
+	r.handlers[routeKey{method: method, path: pattern}] = handlers
+	r.maxParams = max(r.maxParams, params)
+}
+
+// buildChain 返回节点的完整处理链
+func (r *router) buildChain(n *node) []HandlerFunc {
+	if r.chain == nil {
+		return n.handlers
+	}
+	return r.chain(n.group, n.handlers)
+}
+
+// refreshChains 重新组装全部路由的处理链，在添加全局或路由组中间件后调用
+func (r *router) refreshChains() {
+	var walk func(n *node)
+	walk = func(n *node) {
+		if n.handlers != nil {
+			n.chain = r.buildChain(n)
+		}
+		for _, child := range n.children {
+			walk(child)
+		}
+		for _, w := range n.wild {
+			walk(w)
+		}
+	}
+	for _, root := range r.roots {
+		walk(root)
+	}
+}
+
+// split 在第 l 个字节处拆分静态节点，返回拆分出的父节点，原节点成为其唯一的子节点
+func (n *node) split(l int) *node {
+	child := *n
+	child.path = n.path[l:]
+	return &node{
+		path:     n.path[:l],
+		indices:  string(child.path[0]),
+		children: []*node{&child},
+		priority: child.priority,
+	}
+}
+
+// sortChild 提升第 i 个子节点的排名，保持子节点按优先级从高到低排列
+func (n *node) sortChild(i int) {
+	for ; i > 0 && n.children[i-1].priority < n.children[i].priority; i-- {
+		n.children[i-1], n.children[i] = n.children[i], n.children[i-1]
+		b := []byte(n.indices)
+		b[i-1], b[i] = b[i], b[i-1]
+		n.indices = string(b)
+	}
+}
+
+// findWild 查找路径相同的参数或通配子节点
+func (n *node) findWild(part string) *node {
+	for _, w := range n.wild {
+		if w.path == part {
+			return w
+		}
+	}
+	return nil
+}
+
+// addWild 添加参数或通配子节点，通配节点排在参数节点之后
+func (n *node) addWild(child *node) {
+	n.wild = append(n.wild, child)
+	sort.SliceStable(n.wild, func(i, j int) bool { return n.wild[i].kind < n.wild[j].kind })
+}
+
+// subtreeRoutes 返回该节点下位于同一路径段的静态路由和参数路由，即插入新参数时冲突的路由
+func (n *node) subtreeRoutes(method string) []string {
+	var result []string
+	for _, child := range n.children {
+		result = append(result, child.routes(method)...)
+	}
+	for _, w := range n.wild {
+		result = append(result, w.routes(method)...)
 	}
 	return result
 }
 
-// insert 插入路由
-func (r *router) insert(method, pattern string, handler HandlerFunc) {
-	parts := parsePattern(pattern)
-	key := method + "-" + pattern
-	if _, ok := r.roots[method]; !ok {
-		r.roots[method] = &node{children: make(map[string]*node)}
-	}
-	root := r.roots[method]
-	for _, part := range parts {
-		if _, ok := root.children[part]; !ok {
-			root.children[part] = &node{
-				part:     part,
-				children: make(map[string]*node),
-				isWild:   part[0] == ':' || part[0] == '*',
+// routes 返回以该节点为根的子树中已注册的路由
+func (n *node) routes(method string) []string {
+	var result []string
+	if n.handlers != nil {
+		result = append(result, method+" "+n.pattern)
+	}
+	result = append(result, n.subtreeRoutes(method)...)
+	sort.Strings(result)
+	return result
+}
+
+// commonPrefix 返回两个字符串公共前缀的长度
+func commonPrefix(a, b string) int {
+	l := min(len(a), len(b))
+	i := 0
+	for i < l && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// match 从该节点开始匹配路径，匹配成功时返回注册了路由的节点，参数追加到 params
+// 静态子节点失败时回退尝试参数和通配子节点，整个过程不分配内存（params 容量足够时）
+func (n *node) match(path string, params *Params) *node {
+	switch n.kind {
+	case staticNode:
+		if len(path) < len(n.path) || path[:len(n.path)] != n.path {
+			return nil
+		}
+		path = path[len(n.path):]
+	case paramNode:
+		end := strings.IndexByte(path, '/')
+		if end < 0 {
+			end = len(path)
+		}
+		if end == 0 || (n.constraint != nil && !n.constraint.MatchString(path[:end])) {
+			return nil
+		}
+		*params = append(*params, Param{Key: n.param, Value: path[:end]})
+		path = path[end:]
+	case catchAllNode:
+		if n.handlers == nil || (n.constraint != nil && !n.constraint.MatchString(path)) {
+			return nil
+		}
+		*params = append(*params, Param{Key: n.param, Value: path})
+		return n
+	}
+
+	if path == "" {
+		if n.handlers != nil {
+			return n
+		}
+		// 以 / 结尾的路径由通配路由匹配，通配参数为空，例如 /static/ 匹配 /static/*filepath
+		if w := n.catchAll(); w != nil {
+			if found := w.match(path, params); found != nil {
+				return found
+			}
+		}
+	} else {
+		if i := strings.IndexByte(n.indices, path[0]); i >= 0 {
+			if found := n.children[i].match(path, params); found != nil {
+				return found
 			}
 		}
-		root = root.children[part]
+		for _, w := range n.wild {
+			if found := w.match(path, params); found != nil {
+				return found
+			}
+		}
+	}
+	if n.kind == paramNode {
+		*params = (*params)[:len(*params)-1]
 	}
-	root.pattern = pattern
-	root.handler = handler
-	r.handlers[key] = handler
+	return nil
 }
 
-// search 搜索路由
-func (r *router) search(method, path string) (*node, map[string]string) {
-	searchParts := parsePattern(path)
-	params := make(map[string]string)
-	root, ok := r.roots[method]
-	if !ok {
-		return nil, nil
+// catchAll 返回通配子节点，没有时返回 nil
+func (n *node) catchAll() *node {
+	if len(n.wild) > 0 && n.wild[len(n.wild)-1].kind == catchAllNode {
+		return n.wild[len(n.wild)-1]
 	}
+	return nil
+}
 
-	n := root
-	for i, part := range searchParts {
-		var found bool
-		for _, child := range n.children {
-			if child.part == part || child.isWild {
-				if child.part[0] == '*' {
-					params[child.part[1:]] = strings.Join(searchParts[i:], "/")
-					return child, params
-				}
-				if child.part[0] == ':' {
-					params[child.part[1:]] = part
-				}
-				n = child
-				found = true
-				break
+// matchFold 与 match 相同，但静态路径不区分 ASCII 字母的大小写，匹配的路径写入 fixed
+func (n *node) matchFold(path string, params *Params, fixed []byte) (*node, []byte) {
+	switch n.kind {
+	case staticNode:
+		if len(path) < len(n.path) || !equalFoldASCII(path[:len(n.path)], n.path) {
+			return nil, fixed
+		}
+		fixed = append(fixed, n.path...)
+		path = path[len(n.path):]
+	case paramNode:
+		end := strings.IndexByte(path, '/')
+		if end < 0 {
+			end = len(path)
+		}
+		if end == 0 || (n.constraint != nil && !n.constraint.MatchString(path[:end])) {
+			return nil, fixed
+		}
+		*params = append(*params, Param{Key: n.param, Value: path[:end]})
+		fixed = append(fixed, path[:end]...)
+		path = path[end:]
+	case catchAllNode:
+		if n.handlers == nil || (n.constraint != nil && !n.constraint.MatchString(path)) {
+			return nil, fixed
+		}
+		*params = append(*params, Param{Key: n.param, Value: path})
+		return n, append(fixed, path...)
+	}
+
+	if path == "" {
+		if n.handlers != nil {
+			return n, fixed
+		}
+		if w := n.catchAll(); w != nil {
+			if found, f := w.matchFold(path, params, fixed); found != nil {
+				return found, f
 			}
 		}
-		if !found {
-			return nil, nil
+	} else {
+		for i := 0; i < len(n.indices); i++ {
+			if lowerASCII(n.indices[i]) != lowerASCII(path[0]) {
+				continue
+			}
+			if found, f := n.children[i].matchFold(path, params, fixed); found != nil {
+				return found, f
+			}
 		}
+		for _, w := range n.wild {
+			if found, f := w.matchFold(path, params, fixed); found != nil {
+				return found, f
+			}
+		}
+	}
+	if n.kind == paramNode {
+		*params = (*params)[:len(*params)-1]
+	}
+	return nil, fixed
+}
+
+// equalFoldASCII 比较两个字符串是否相等，不区分 ASCII 字母的大小写
+// 节点路径可能在多字节字符中间拆分，因此不使用 strings.EqualFold
+func equalFoldASCII(a, b string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := 0; i < len(a); i++ {
+		if lowerASCII(a[i]) != lowerASCII(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// lowerASCII 将 ASCII 大写字母转换为小写
+func lowerASCII(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// anyMethods 是 Any 注册的请求方法
+var anyMethods = []string{
+	http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch,
+	http.MethodDelete, http.MethodHead, http.MethodOptions,
+}
+
+// checkMethod 检查请求方法是否为合法的 HTTP token，返回大写的方法名
+func checkMethod(method string) string {
+	if method == "" {
+		panic("easygo: 请求方法不能为空")
+	}
+	for i := 0; i < len(method); i++ {
+		c := method[i]
+		if c <= ' ' || c >= 0x7f || strings.IndexByte(`()<>@,;:\"/[]?={}`, c) >= 0 {
+			panic("easygo: 请求方法 " + method + " 不合法")
+		}
+	}
+	return strings.ToUpper(method)
+}
+
+// allowed 返回路径允许的请求方法，以逗号分隔，用于 OPTIONS 响应的 Allow 头，没有匹配的路由时返回空字符串
+// 注册了 GET 路由时包含 HEAD，匹配到任何路由时包含 OPTIONS
+func (r *router) allowed(path string, params *Params, fold bool) string {
+	methods := make([]string, 0, len(r.roots)+2)
+	for method := range r.roots {
+		if r.lookup(method, path, params, fold) != nil {
+			methods = append(methods, method)
+		}
+		*params = (*params)[:0]
+	}
+	if len(methods) == 0 {
+		return ""
+	}
+	if slices.Contains(methods, http.MethodGet) && !slices.Contains(methods, http.MethodHead) {
+		methods = append(methods, http.MethodHead)
+	}
+	if !slices.Contains(methods, http.MethodOptions) {
+		methods = append(methods, http.MethodOptions)
+	}
+	sort.Strings(methods)
+	return strings.Join(methods, ", ")
+}
+
+// optionsHandler 返回自动响应 OPTIONS 请求的处理函数
+func optionsHandler(allow string) HandlerFunc {
+	return func(c *Context) {
+		c.SetHeader("Allow", allow)
+		c.Status(http.StatusNoContent)
 	}
-	return n, params
 }
 
 // addRoute 添加路由
-func (r *router) addRoute(method, pattern string, handler HandlerFunc) {
-	r.insert(method, pattern, handler)
+// handlers: 路由中间件和处理函数，至少包含一个处理函数
+// group: 注册路由的路由组，直接在引擎上注册时为 nil
+func (r *router) addRoute(method, pattern string, handlers []HandlerFunc, group *RouterGroup) {
+	pattern = cleanPattern(pattern)
+	if len(handlers) == 0 {
+		panic("easygo: 路由 " + method + " " + pattern + " 缺少处理函数")
+	}
+	for _, h := range handlers {
+		if h == nil {
+			panic("easygo: 路由 " + method + " " + pattern + " 的处理函数不能为 nil")
+		}
+	}
+	// 复制一份，避免调用方修改切片影响已注册的路由
+	r.insert(method, pattern, append([]HandlerFunc(nil), handlers...), group)
+}
+
+// getRoute 获取匹配的路由节点，节点包含处理链、所属的路由组和路由模式，路径参数追加到 params
+func (r *router) getRoute(method, path string, params *Params) *node {
+	root, ok := r.roots[method]
+	if !ok {
+		return nil
+	}
+	return root.match(path, params)
 }
 
-// getRoute 获取路由
-func (r *router) getRoute(method, path string) (HandlerFunc, map[string]string) {
-	n, params := r.search(method, path)
-	if n != nil {
-		return n.handler, params
+// getRouteFold 不区分大小写获取匹配的路由节点，同时返回按路由模式修正大小写后的路径
+// 只比较 ASCII 字母的大小写，参数值保持请求中的原样
+func (r *router) getRouteFold(method, path string, params *Params) (*node, string) {
+	root, ok := r.roots[method]
+	if !ok {
+		return nil, ""
+	}
+	n, fixed := root.matchFold(path, params, make([]byte, 0, len(path)))
+	if n == nil {
+		return nil, ""
+	}
+	return n, string(fixed)
+}
+
+// lookup 查找请求的路由，HEAD 请求没有匹配的路由时使用 GET 路由
+// fold 为 true 时精确匹配失败后不区分大小写匹配
+func (r *router) lookup(method, path string, params *Params, fold bool) *node {
+	head := method == http.MethodHead
+	if n := r.getRoute(method, path, params); n != nil {
+		return n
+	}
+	if head {
+		if n := r.getRoute(http.MethodGet, path, params); n != nil {
+			return n
+		}
+	}
+	if !fold {
+		return nil
+	}
+	if n, _ := r.getRouteFold(method, path, params); n != nil {
+		return n
+	}
+	if head {
+		if n, _ := r.getRouteFold(http.MethodGet, path, params); n != nil {
+			return n
+		}
 	}
-	return nil, nil
+	return nil
 }
@@ -3,40 +3,97 @@ package core
 // RouterGroup 是路由组
 type RouterGroup struct {
 	engine      *Engine
+	parent      *RouterGroup // 父路由组，顶层路由组为 nil
 	prefix      string
 	middlewares []HandlerFunc
 }
 
 // Group 创建一个新的路由组
+// 子路由组继承父路由组的中间件，执行顺序为：全局中间件、父路由组中间件、子路由组中间件、处理函数
 func (group *RouterGroup) Group(prefix string) *RouterGroup {
 	return &RouterGroup{
 		engine:      group.engine,
+		parent:      group,
 		prefix:      group.prefix + prefix,
 		middlewares: make([]HandlerFunc, 0),
 	}
 }
 
-// Use 添加中间件
+// Use 添加中间件，对该路由组及其子路由组的全部路由生效，包括调用 Use 之前注册的路由
 func (group *RouterGroup) Use(middlewares ...HandlerFunc) {
 	group.middlewares = append(group.middlewares, middlewares...)
+	group.engine.router.refreshChains()
 }
 
 // GET 注册GET请求处理函数
-func (group *RouterGroup) GET(pattern string, handler HandlerFunc) {
-	group.engine.router.addRoute("GET", group.prefix+pattern, handler)
+// handlers: 处理函数，可以在最后的处理函数之前放置仅对该路由生效的中间件
+func (group *RouterGroup) GET(pattern string, handlers ...HandlerFunc) {
+	group.engine.router.addRoute("GET", group.prefix+pattern, handlers, group)
 }
 
 // POST 注册POST请求处理函数
-func (group *RouterGroup) POST(pattern string, handler HandlerFunc) {
-	group.engine.router.addRoute("POST", group.prefix+pattern, handler)
+// handlers: 处理函数，可以在最后的处理函数之前放置仅对该路由生效的中间件
+func (group *RouterGroup) POST(pattern string, handlers ...HandlerFunc) {
+	group.engine.router.addRoute("POST", group.prefix+pattern, handlers, group)
 }
 
 // PUT 注册PUT请求处理函数
-func (group *RouterGroup) PUT(pattern string, handler HandlerFunc) {
-	group.engine.router.addRoute("PUT", group.prefix+pattern, handler)
+// handlers: 处理函数，可以在最后的处理函数之前放置仅对该路由生效的中间件
+func (group *RouterGroup) PUT(pattern string, handlers ...HandlerFunc) {
+	group.engine.router.addRoute("PUT", group.prefix+pattern, handlers, group)
 }
 
 // DELETE 注册DELETE请求处理函数
-func (group *RouterGroup) DELETE(pattern string, handler HandlerFunc) {
-	group.engine.router.addRoute("DELETE", group.prefix+pattern, handler)
+// handlers: 处理函数，可以在最后的处理函数之前放置仅对该路由生效的中间件
+func (group *RouterGroup) DELETE(pattern string, handlers ...HandlerFunc) {
+	group.engine.router.addRoute("DELETE", group.prefix+pattern, handlers, group)
+}
+
+// PATCH 注册PATCH请求处理函数
+// handlers: 处理函数，可以在最后的处理函数之前放置仅对该路由生效的中间件
+func (group *RouterGroup) PATCH(pattern string, handlers ...HandlerFunc) {
+	group.engine.router.addRoute("PATCH", group.prefix+pattern, handlers, group)
+}
+
+// HEAD 注册HEAD请求处理函数，未注册时 HEAD 请求由同一路径的 GET 路由处理
+// handlers: 处理函数，可以在最后的处理函数之前放置仅对该路由生效的中间件
+func (group *RouterGroup) HEAD(pattern string, handlers ...HandlerFunc) {
+	group.engine.router.addRoute("HEAD", group.prefix+pattern, handlers, group)
+}
+
+// OPTIONS 注册OPTIONS请求处理函数，未注册时 OPTIONS 请求自动响应 204 并在 Allow 头中列出允许的方法
+// handlers: 处理函数，可以在最后的处理函数之前放置仅对该路由生效的中间件
+func (group *RouterGroup) OPTIONS(pattern string, handlers ...HandlerFunc) {
+	group.engine.router.addRoute("OPTIONS", group.prefix+pattern, handlers, group)
+}
+
+// Handle 注册指定请求方法的处理函数，用于其他请求方法，例如 PROPFIND
+// handlers: 处理函数，可以在最后的处理函数之前放置仅对该路由生效的中间件
+func (group *RouterGroup) Handle(method, pattern string, handlers ...HandlerFunc) {
+	group.engine.router.addRoute(checkMethod(method), group.prefix+pattern, handlers, group)
+}
+
+// Any 为 GET、POST、PUT、PATCH、DELETE、HEAD、OPTIONS 注册同一组处理函数
+// handlers: 处理函数，可以在最后的处理函数之前放置仅对该路由生效的中间件
+func (group *RouterGroup) Any(pattern string, handlers ...HandlerFunc) {
+	for _, method := range anyMethods {
+		group.engine.router.addRoute(method, group.prefix+pattern, handlers, group)
+	}
+}
+
+// handlerChain 组装处理链：全局中间件、由外到内的路由组中间件、路由中间件和处理函数
+// 路由的处理链在注册路由和添加中间件时组装并缓存在路由节点上，请求之间共享，处理过程中不会修改
+func (e *Engine) handlerChain(group *RouterGroup, handlers []HandlerFunc) []HandlerFunc {
+	var groups []*RouterGroup
+	size := len(e.middlewares) + len(handlers)
+	for g := group; g != nil; g = g.parent {
+		groups = append(groups, g)
+		size += len(g.middlewares)
+	}
+	chain := make([]HandlerFunc, 0, size)
+	chain = append(chain, e.middlewares...)
+	for i := len(groups) - 1; i >= 0; i-- {
+		chain = append(chain, groups[i].middlewares...)
+	}
+	return append(chain, handlers...)
 }
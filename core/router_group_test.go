@@ -0,0 +1,137 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// recordMiddleware 返回记录执行顺序的中间件
+func recordMiddleware(order *[]string, name string) HandlerFunc {
+	return func(c *Context) {
+		*order = append(*order, name)
+		c.Next()
+	}
+}
+
+// serve 发送请求并返回响应
+func serve(e *Engine, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func TestNestedGroupOrder(t *testing.T) {
+	e := New()
+	var order []string
+	e.Use(recordMiddleware(&order, "global"))
+	outer := e.Group("/api")
+	outer.Use(recordMiddleware(&order, "outer"))
+	inner := outer.Group("/admin")
+	inner.Use(recordMiddleware(&order, "inner"))
+	inner.GET("/stats", recordMiddleware(&order, "route"), func(c *Context) {
+		order = append(order, "handler")
+	})
+
+	serve(e, http.MethodGet, "/api/admin/stats")
+	want := []string{"global", "outer", "inner", "route", "handler"}
+	if !reflect.DeepEqual(order, want) {
+		t.Fatalf("执行顺序为 %v，期望 %v", order, want)
+	}
+}
+
+func TestOuterGroupAbort(t *testing.T) {
+	e := New()
+	var order []string
+	outer := e.Group("/api")
+	outer.Use(func(c *Context) {
+		order = append(order, "outer")
+		c.String(http.StatusForbidden, "forbidden")
+		c.Abort()
+	})
+	inner := outer.Group("/admin")
+	inner.Use(recordMiddleware(&order, "inner"))
+	inner.GET("/stats", func(c *Context) {
+		order = append(order, "handler")
+	})
+
+	w := serve(e, http.MethodGet, "/api/admin/stats")
+	if w.Code != http.StatusForbidden {
+		t.Errorf("状态码为 %d，期望 %d", w.Code, http.StatusForbidden)
+	}
+	if want := []string{"outer"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("执行顺序为 %v，期望 %v", order, want)
+	}
+}
+
+func TestAbortAfterNextInInnerGroup(t *testing.T) {
+	e := New()
+	var order []string
+	g := e.Group("/api")
+	g.Use(func(c *Context) {
+		order = append(order, "outer-before")
+		c.Next()
+		order = append(order, "outer-after")
+	})
+	inner := g.Group("/v1")
+	inner.Use(func(c *Context) {
+		order = append(order, "inner")
+		c.Abort()
+	})
+	inner.GET("/users", func(c *Context) {
+		order = append(order, "handler")
+	})
+
+	serve(e, http.MethodGet, "/api/v1/users")
+	want := []string{"outer-before", "inner", "outer-after"}
+	if !reflect.DeepEqual(order, want) {
+		t.Fatalf("执行顺序为 %v，期望 %v", order, want)
+	}
+}
+
+func TestUseAfterRouteRegistration(t *testing.T) {
+	e := New()
+	var order []string
+	outer := e.Group("/api")
+	inner := outer.Group("/admin")
+	inner.GET("/stats", func(c *Context) {
+		order = append(order, "handler")
+	})
+	e.GET("/ping", func(c *Context) {
+		order = append(order, "ping")
+	})
+
+	// 注册路由之后添加的中间件同样生效
+	inner.Use(recordMiddleware(&order, "inner"))
+	outer.Use(recordMiddleware(&order, "outer"))
+	e.Use(recordMiddleware(&order, "global"))
+
+	serve(e, http.MethodGet, "/api/admin/stats")
+	if want := []string{"global", "outer", "inner", "handler"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("执行顺序为 %v，期望 %v", order, want)
+	}
+
+	order = nil
+	serve(e, http.MethodGet, "/ping")
+	if want := []string{"global", "ping"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("执行顺序为 %v，期望 %v", order, want)
+	}
+}
+
+func TestSiblingGroupsIsolated(t *testing.T) {
+	e := New()
+	var order []string
+	api := e.Group("/api")
+	a := api.Group("/a")
+	a.Use(recordMiddleware(&order, "a"))
+	a.GET("/x", func(c *Context) { order = append(order, "a-handler") })
+	b := api.Group("/b")
+	b.Use(recordMiddleware(&order, "b"))
+	b.GET("/x", func(c *Context) { order = append(order, "b-handler") })
+
+	serve(e, http.MethodGet, "/api/b/x")
+	if want := []string{"b", "b-handler"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("执行顺序为 %v，期望 %v", order, want)
+	}
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	errs "github.com/xzl-go/easygo/errors"
+)
+
+// paramTypes 是内置的路径参数类型，例如 /users/:id(int)
+var paramTypes = map[string]string{
+	"int":   `-?[0-9]+`,
+	"uint":  `[0-9]+`,
+	"uuid":  `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+	"alpha": `[a-zA-Z]+`,
+	"alnum": `[a-zA-Z0-9]+`,
+}
+
+// uuidPattern 匹配 UUID
+var uuidPattern = regexp.MustCompile(`^` + paramTypes["uuid"] + `$`)
+
+// parseParam 解析参数段，例如 ":id(int)" 返回参数名 id 和约束，没有约束时返回 nil
+// 约束可以是内置类型 int、uint、uuid、alpha、alnum，也可以是正则表达式，例如 :slug([a-z-]+)，正则表达式匹配整个参数值
+func parseParam(pattern, part string) (string, *regexp.Regexp) {
+	name := part[1:]
+	i := strings.IndexByte(name, '(')
+	if i < 0 {
+		return name, nil
+	}
+	if !strings.HasSuffix(name, ")") || i == 0 {
+		panic(fmt.Sprintf("easygo: 路由 %s 的参数 %s 格式错误，约束应为 :name(int) 或 :name(正则表达式)，且不能包含 /", pattern, part))
+	}
+	expr := name[i+1 : len(name)-1]
+	if t, ok := paramTypes[expr]; ok {
+		expr = t
+	}
+	re, err := regexp.Compile(`^(?:` + expr + `)$`)
+	if err != nil {
+		panic(fmt.Sprintf("easygo: 路由 %s 的参数 %s 约束无效：%v", pattern, part, err))
+	}
+	return name[:i], re
+}
+
+// ParamInt 获取整数类型的路径参数，参数不是整数时返回 errors.ErrBadRequest
+// 路由使用 :id(int) 约束时参数一定是整数（超出 int 范围的除外）
+// key: 参数名
+func (c *Context) ParamInt(key string) (int, error) {
+	v, err := strconv.Atoi(c.Params.ByName(key))
+	if err != nil {
+		return 0, errs.ErrBadRequest.Wrap(fmt.Errorf("路径参数 %s 不是整数：%w", key, err))
+	}
+	return v, nil
+}
+
+// ParamUUID 获取 UUID 类型的路径参数并转换为小写，参数不是 UUID 时返回 errors.ErrBadRequest
+// key: 参数名
+func (c *Context) ParamUUID(key string) (string, error) {
+	v := c.Params.ByName(key)
+	if !uuidPattern.MatchString(v) {
+		return "", errs.ErrBadRequest.Wrap(fmt.Errorf("路径参数 %s 不是 UUID：%q", key, v))
+	}
+	return strings.ToLower(v), nil
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// discardWriter 是不记录任何内容的 http.ResponseWriter，避免测量到响应记录器的内存分配
+type discardWriter struct {
+	header http.Header
+}
+
+func (w *discardWriter) Header() http.Header         { return w.header }
+func (w *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardWriter) WriteHeader(int)             {}
+
+// newBenchEngine 创建包含全局中间件、路由组中间件和多种路由的引擎
+func newBenchEngine() *Engine {
+	e := New()
+	next := func(c *Context) { c.Next() }
+	e.Use(next)
+	api := e.Group("/api")
+	api.Use(next)
+	noop := func(c *Context) {}
+	api.GET("/users", noop)
+	api.GET("/users/:id", noop)
+	api.GET("/users/:id/orders/:order", noop)
+	api.GET("/orders", noop)
+	api.POST("/orders", noop)
+	e.GET("/files/*path", noop)
+	return e
+}
+
+func TestServeHTTPAllocs(t *testing.T) {
+	e := newBenchEngine()
+	w := &discardWriter{header: make(http.Header)}
+	for _, path := range []string{"/api/users", "/api/users/42", "/api/users/42/orders/7", "/files/a/b.txt"} {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		e.ServeHTTP(w, r) // 预热上下文池
+		if allocs := testing.AllocsPerRun(100, func() { e.ServeHTTP(w, r) }); allocs != 0 {
+			t.Errorf("GET %s: 每次请求分配 %v 次内存，期望 0", path, allocs)
+		}
+	}
+
+	r := httptest.NewRequest(http.MethodHead, "/api/users", nil)
+	if allocs := testing.AllocsPerRun(100, func() { e.ServeHTTP(w, r) }); allocs != 0 {
+		t.Errorf("HEAD /api/users: 每次请求分配 %v 次内存，期望 0", allocs)
+	}
+}
+
+func BenchmarkRouterStatic(b *testing.B) {
+	e := newBenchEngine()
+	w := &discardWriter{header: make(http.Header)}
+	r := httptest.NewRequest(http.MethodGet, "/api/orders", nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e.ServeHTTP(w, r)
+	}
+}
+
+func BenchmarkRouterParam(b *testing.B) {
+	e := newBenchEngine()
+	w := &discardWriter{header: make(http.Header)}
+	r := httptest.NewRequest(http.MethodGet, "/api/users/42/orders/7", nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e.ServeHTTP(w, r)
+	}
+}
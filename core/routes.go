@@ -0,0 +1,66 @@
+package core
+
+import (
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// RouteInfo 是已注册路由的信息
+type RouteInfo struct {
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	Handler     string   `json:"handler"`               // 处理函数名称
+	Middlewares []string `json:"middlewares,omitempty"` // 路由中间件名称，不含全局和路由组中间件
+}
+
+// Routes 返回已注册的路由，按路径和方法排序
+func (e *Engine) Routes() []RouteInfo {
+	routes := make([]RouteInfo, 0, len(e.router.handlers))
+	for key, handlers := range e.router.handlers {
+		info := RouteInfo{Method: key.method, Path: key.path, Handler: FuncName(handlers[len(handlers)-1])}
+		for _, h := range handlers[:len(handlers)-1] {
+			info.Middlewares = append(info.Middlewares, FuncName(h))
+		}
+		routes = append(routes, info)
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// Middlewares 返回全局中间件的名称，按执行顺序排列
+func (e *Engine) Middlewares() []string {
+	names := make([]string, len(e.middlewares))
+	for i, m := range e.middlewares {
+		names[i] = FuncName(m)
+	}
+	return names
+}
+
+// closureSuffix 匹配闭包函数名的后缀，例如 ".func1"、".func2.1"
+var closureSuffix = regexp.MustCompile(`(\.func\d+)+(\.\d+)*$`)
+
+// FuncName 返回函数的简短名称，例如 "middleware.Logger"
+// 中间件工厂返回的闭包以工厂函数命名
+func FuncName(fn interface{}) string {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return ""
+	}
+	f := runtime.FuncForPC(v.Pointer())
+	if f == nil {
+		return ""
+	}
+	name := closureSuffix.ReplaceAllString(f.Name(), "")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,24 @@
+package core
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRoutesMethodWithHyphen(t *testing.T) {
+	e := New()
+	noop := func(c *Context) {}
+	e.Handle("M-SEARCH", "/upnp", noop)
+	e.GET("/users/:id", noop)
+
+	routes := e.Routes()
+	if len(routes) != 2 {
+		t.Fatalf("Routes 返回 %d 个路由，期望 2", len(routes))
+	}
+	if got := routes[0]; got.Method != "M-SEARCH" || got.Path != "/upnp" {
+		t.Errorf("routes[0] = %s %s，期望 M-SEARCH /upnp", got.Method, got.Path)
+	}
+	if got := routes[1]; got.Method != http.MethodGet || got.Path != "/users/:id" {
+		t.Errorf("routes[1] = %s %s，期望 GET /users/:id", got.Method, got.Path)
+	}
+}
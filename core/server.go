@@ -0,0 +1,293 @@
+// Package core 提供了EasyGo框架的核心功能
+package core
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// ShutdownOptions 定义了优雅关闭选项，适配 Kubernetes 滚动更新
+type ShutdownOptions struct {
+	// PreStopDelay 收到退出信号后，先将就绪状态置为未就绪，等待该时长再关闭监听，
+	// 以便负载均衡器和 Service Endpoints 摘除本实例，避免滚动更新期间出现 502
+	PreStopDelay time.Duration
+	// DrainTimeout 关闭监听后等待在途请求完成的最长时间，超时后强制关闭连接
+	DrainTimeout time.Duration
+	// CloseTimeout 执行关闭钩子的最长时间，与 DrainTimeout 分开计算，
+	// 避免等待在途请求耗尽超时后关闭钩子拿到已取消的上下文
+	CloseTimeout time.Duration
+	// Signals 触发优雅关闭的信号，默认为 SIGINT 和 SIGTERM
+	Signals []os.Signal
+}
+
+// DefaultShutdownOptions 返回默认的关闭选项
+func DefaultShutdownOptions() ShutdownOptions {
+	return ShutdownOptions{
+		PreStopDelay: 0,
+		DrainTimeout: 30 * time.Second,
+		CloseTimeout: 10 * time.Second,
+		Signals:      []os.Signal{os.Interrupt, syscall.SIGTERM},
+	}
+}
+
+// SetShutdownOptions 设置优雅关闭选项
+func (e *Engine) SetShutdownOptions(options ShutdownOptions) {
+	if options.DrainTimeout <= 0 {
+		options.DrainTimeout = DefaultShutdownOptions().DrainTimeout
+	}
+	if options.CloseTimeout <= 0 {
+		options.CloseTimeout = DefaultShutdownOptions().CloseTimeout
+	}
+	if len(options.Signals) == 0 {
+		options.Signals = DefaultShutdownOptions().Signals
+	}
+	e.shutdownOptions = options
+}
+
+// Ready 返回引擎是否就绪，可用于就绪探针
+func (e *Engine) Ready() bool {
+	return e.ready.Load()
+}
+
+// SetReady 手动设置就绪状态，例如在预热完成前置为未就绪
+func (e *Engine) SetReady(ready bool) {
+	e.ready.Store(ready)
+}
+
+// ReadinessHandler 返回就绪探针处理函数，就绪时返回 200，否则返回 503
+func (e *Engine) ReadinessHandler() HandlerFunc {
+	return func(c *Context) {
+		if e.Ready() {
+			c.String(http.StatusOK, "ready")
+			return
+		}
+		c.String(http.StatusServiceUnavailable, "not ready")
+	}
+}
+
+// RunGraceful 启动HTTP服务器，并在收到退出信号时按关闭选项优雅关闭：
+// 先将就绪状态置为未就绪，等待 PreStopDelay，然后关闭监听并在 DrainTimeout 内等待在途请求完成
+// addr: 服务器监听地址
+func (e *Engine) RunGraceful(addr string) error {
+	return e.runGraceful(addr, e.shutdownOptions)
+}
+
+// runGraceful 启动HTTP服务器，并在收到退出信号时按指定的关闭选项优雅关闭
+func (e *Engine) runGraceful(addr string, options ShutdownOptions) error {
+	// 先同步登记服务器再监听信号，启动期间收到信号时 Shutdown 也能关闭该服务器
+	srv := e.newServer(addr)
+	defer e.removeServer(srv)
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, options.Signals...)
+	defer signal.Stop(sigCh)
+
+	fmt.Printf("🚀 服务器启动，监听地址：%s\n", addr)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- serverError(srv.ListenAndServe())
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case sig := <-sigCh:
+		fmt.Printf("⏳ 收到信号 %v，开始优雅关闭\n", sig)
+	}
+
+	e.SetReady(false)
+	if d := options.PreStopDelay; d > 0 {
+		time.Sleep(d)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), options.DrainTimeout)
+	defer cancel()
+	if err := e.Shutdown(ctx); err != nil {
+		return err
+	}
+	return <-errCh
+}
+
+// RunWithGracefulShutdown 启动HTTP服务器，收到 SIGINT 或 SIGTERM 时停止接收新连接，
+// 并在 timeout 内等待在途请求完成，超时后强制关闭剩余连接
+// addr: 服务器监听地址
+// timeout: 等待在途请求完成的最长时间，仅对本次调用生效，不修改引擎的关闭选项
+func (e *Engine) RunWithGracefulShutdown(addr string, timeout time.Duration) error {
+	options := e.shutdownOptions
+	if timeout > 0 {
+		options.DrainTimeout = timeout
+	}
+	return e.runGraceful(addr, options)
+}
+
+// OnClose 注册关闭钩子，用于释放数据库连接池、Redis、链路追踪等资源
+// 优雅关闭时在服务器停止后按注册的逆序执行（与 defer 顺序一致），每个钩子只执行一次
+// fn: 关闭函数，ctx 携带 CloseTimeout 超时
+func (e *Engine) OnClose(fn func(ctx context.Context) error) {
+	e.serverMu.Lock()
+	defer e.serverMu.Unlock()
+	e.closers = append(e.closers, fn)
+}
+
+// OnCloseFunc 注册无参数的关闭钩子，例如 logger.Close、cron.StopCron
+func (e *Engine) OnCloseFunc(fn func()) {
+	e.OnClose(func(context.Context) error {
+		fn()
+		return nil
+	})
+}
+
+// Shutdown 优雅关闭服务器
+// 将就绪状态置为未就绪，关闭监听并等待在途请求完成，然后逆序执行关闭钩子；
+// 关闭钩子使用单独的 CloseTimeout 超时，不受等待在途请求所用时间的影响
+// ctx: 上下文，用于控制等待在途请求的超时
+// 返回关闭错误（如果有）
+func (e *Engine) Shutdown(ctx context.Context) error {
+	e.SetReady(false)
+
+	e.serverMu.Lock()
+	servers := e.servers
+	closers := e.closers
+	e.servers = nil
+	e.closers = nil
+	e.serverMu.Unlock()
+
+	var errs []error
+	for _, srv := range servers {
+		if err := srv.Shutdown(ctx); err != nil {
+			// 超时仍未完成的请求，强制关闭其连接
+			errs = append(errs, err, srv.Close())
+		}
+	}
+	if len(closers) == 0 {
+		return errors.Join(errs...)
+	}
+	closeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), e.shutdownOptions.CloseTimeout)
+	defer cancel()
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := closers[i](closeCtx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ServerOptions 定义了 HTTP 服务器选项
+type ServerOptions struct {
+	// ReadTimeout 读取整个请求（包括请求体）的超时时间，为 0 时不限制，上传大文件的服务应设置较大的值
+	ReadTimeout time.Duration
+	// ReadHeaderTimeout 读取请求头的超时时间，防止慢速攻击（Slowloris），默认 10 秒
+	ReadHeaderTimeout time.Duration
+	// WriteTimeout 从读取完请求头到写完响应的超时时间，为 0 时不限制；设置后 SSE、WebSocket 等长连接会被中断
+	WriteTimeout time.Duration
+	// IdleTimeout keep-alive 连接的空闲超时时间，默认 120 秒
+	IdleTimeout time.Duration
+	// MaxHeaderBytes 请求头的最大字节数，默认 1 MB
+	MaxHeaderBytes int
+	// TLSConfig RunTLS 使用的 TLS 配置，其中包含证书时 RunTLS 的证书文件参数可以为空
+	TLSConfig *tls.Config
+	// H2C 是否在明文连接上支持 HTTP/2（h2c），用于 gRPC 或位于终止 TLS 的负载均衡器之后的服务
+	H2C bool
+}
+
+// DefaultServerOptions 返回默认的服务器选项
+func DefaultServerOptions() ServerOptions {
+	return ServerOptions{
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       120 * time.Second,
+		MaxHeaderBytes:    http.DefaultMaxHeaderBytes,
+	}
+}
+
+// SetServerOptions 设置 HTTP 服务器选项，对之后启动的服务器生效
+// ReadHeaderTimeout、IdleTimeout、MaxHeaderBytes 为 0 时使用默认值
+func (e *Engine) SetServerOptions(options ServerOptions) {
+	defaults := DefaultServerOptions()
+	if options.ReadHeaderTimeout <= 0 {
+		options.ReadHeaderTimeout = defaults.ReadHeaderTimeout
+	}
+	if options.IdleTimeout <= 0 {
+		options.IdleTimeout = defaults.IdleTimeout
+	}
+	if options.MaxHeaderBytes <= 0 {
+		options.MaxHeaderBytes = defaults.MaxHeaderBytes
+	}
+	e.serverOptions = options
+}
+
+// RunH2C 启动同时支持 HTTP/1.1 和明文 HTTP/2（h2c）的服务器
+// addr: 服务器监听地址
+// 返回服务器运行错误（如果有），调用 Shutdown 正常关闭时返回 nil
+func (e *Engine) RunH2C(addr string) error {
+	fmt.Printf("🚀 服务器启动（h2c），监听地址：%s\n", addr)
+	srv := e.newServer(addr)
+	defer e.removeServer(srv)
+	enableH2C(srv)
+	return serverError(srv.ListenAndServe())
+}
+
+// enableH2C 在服务器的明文连接上启用 HTTP/2
+func enableH2C(srv *http.Server) {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+	srv.Protocols = protocols
+}
+
+// ConfigureServer 设置创建 HTTP 服务器时的自定义配置，在 ServerOptions 之后应用，用于选项未覆盖的字段
+// fn: 配置函数，不应修改 Addr 和 Handler
+func (e *Engine) ConfigureServer(fn func(srv *http.Server)) {
+	e.serverConfig = fn
+}
+
+// newServer 创建并登记 HTTP 服务器
+func (e *Engine) newServer(addr string) *http.Server {
+	options := e.serverOptions
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           e,
+		ReadTimeout:       options.ReadTimeout,
+		ReadHeaderTimeout: options.ReadHeaderTimeout,
+		WriteTimeout:      options.WriteTimeout,
+		IdleTimeout:       options.IdleTimeout,
+		MaxHeaderBytes:    options.MaxHeaderBytes,
+		TLSConfig:         options.TLSConfig,
+	}
+	if options.H2C {
+		enableH2C(srv)
+	}
+	if e.serverConfig != nil {
+		e.serverConfig(srv)
+	}
+	e.serverMu.Lock()
+	e.servers = append(e.servers, srv)
+	e.serverMu.Unlock()
+	return srv
+}
+
+// removeServer 取消登记已停止的服务器，监听失败（例如端口被占用）的服务器不再保留在引擎中
+func (e *Engine) removeServer(srv *http.Server) {
+	e.serverMu.Lock()
+	defer e.serverMu.Unlock()
+	for i, s := range e.servers {
+		if s == srv {
+			e.servers = append(e.servers[:i], e.servers[i+1:]...)
+			return
+		}
+	}
+}
+
+// serverError 将正常关闭产生的 http.ErrServerClosed 转换为 nil
+func serverError(err error) error {
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,146 @@
+package core
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/signal"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// waitServers 等待引擎登记指定数量的服务器
+func waitServers(t *testing.T, e *Engine, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		e.serverMu.Lock()
+		count := len(e.servers)
+		e.serverMu.Unlock()
+		if count >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("等待服务器登记超时")
+}
+
+// newSignalEngine 创建以 os.Interrupt 触发优雅关闭的引擎
+func newSignalEngine(t *testing.T) *Engine {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows 不支持向自身进程发送信号")
+	}
+	// 测试自身也监听该信号，避免信号在 RunGraceful 监听前到达时终止测试进程
+	guard := make(chan os.Signal, 1)
+	signal.Notify(guard, os.Interrupt)
+	t.Cleanup(func() { signal.Stop(guard) })
+
+	e := New()
+	options := DefaultShutdownOptions()
+	options.Signals = []os.Signal{os.Interrupt}
+	options.DrainTimeout = time.Second
+	e.SetShutdownOptions(options)
+	return e
+}
+
+// runUntilSignal 启动服务器，登记后重复发送 os.Interrupt 直到 run 返回
+func runUntilSignal(t *testing.T, e *Engine, run func() error) {
+	t.Helper()
+	errCh := make(chan error, 1)
+	go func() { errCh <- run() }()
+	waitServers(t, e, 1)
+
+	// 服务器登记后才开始监听信号，重复发送直到返回
+	p, _ := os.FindProcess(os.Getpid())
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(3 * time.Second)
+	for {
+		if err := p.Signal(os.Interrupt); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case err := <-errCh:
+			if err != nil {
+				t.Fatalf("服务器返回错误: %v", err)
+			}
+			return
+		case <-ticker.C:
+		case <-timeout:
+			t.Fatal("收到信号后服务器没有关闭")
+		}
+	}
+}
+
+func TestRunGracefulSignal(t *testing.T) {
+	e := newSignalEngine(t)
+	closed := make(chan struct{})
+	e.OnCloseFunc(func() { close(closed) })
+
+	runUntilSignal(t, e, func() error { return e.RunGraceful("127.0.0.1:0") })
+	select {
+	case <-closed:
+	default:
+		t.Error("关闭钩子没有执行")
+	}
+}
+
+func TestRunWithGracefulShutdownKeepsOptions(t *testing.T) {
+	e := newSignalEngine(t)
+	runUntilSignal(t, e, func() error { return e.RunWithGracefulShutdown("127.0.0.1:0", 5*time.Second) })
+	if got := e.shutdownOptions.DrainTimeout; got != time.Second {
+		t.Errorf("DrainTimeout = %v，期望保持 1s", got)
+	}
+}
+
+func TestShutdownBeforeListen(t *testing.T) {
+	e := New()
+	srv := e.newServer("127.0.0.1:0")
+	if err := e.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// 已关闭的服务器开始监听时立即返回
+	if err := serverError(srv.ListenAndServe()); err != nil {
+		t.Fatalf("ListenAndServe 返回错误: %v", err)
+	}
+}
+
+func TestShutdownCloseTimeout(t *testing.T) {
+	e := New()
+	var closeErr error
+	e.OnClose(func(ctx context.Context) error {
+		closeErr = ctx.Err()
+		return nil
+	})
+
+	// 等待在途请求已耗尽超时，关闭钩子仍有自己的超时
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := e.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if closeErr != nil {
+		t.Errorf("关闭钩子的上下文已结束: %v", closeErr)
+	}
+}
+
+func TestRunGracefulListenError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	e := New()
+	if err := e.RunGraceful(ln.Addr().String()); err == nil {
+		t.Fatal("端口被占用时应返回错误")
+	}
+	e.serverMu.Lock()
+	count := len(e.servers)
+	e.serverMu.Unlock()
+	if count != 0 {
+		t.Errorf("监听失败的服务器仍登记在引擎中: %d", count)
+	}
+}
@@ -0,0 +1,99 @@
+// Package core 提供了EasyGo框架的核心功能
+package core
+
+import (
+	"net/http"
+	"path"
+	"strings"
+)
+
+// spa 是单页应用的托管配置
+type spa struct {
+	prefix      string
+	root        http.FileSystem
+	apiPrefixes []string
+}
+
+// SPA 托管单页应用（React、Vue 等前端构建产物）
+// 存在的静态文件直接返回；对于未匹配任何路由、不带扩展名且不属于 API 前缀的 GET 请求，
+// 返回 index.html，由前端路由（History API）处理。
+// prefix: 访问路径前缀，例如 "/" 或 "/app"
+// dir: 前端构建产物目录，例如 "web/dist"
+// apiPrefixes: 不做回退的 API 路径前缀，默认为 "/api"
+func (e *Engine) SPA(prefix, dir string, apiPrefixes ...string) {
+	if len(apiPrefixes) == 0 {
+		apiPrefixes = []string{"/api"}
+	}
+	e.spas = append(e.spas, &spa{
+		prefix:      "/" + strings.Trim(prefix, "/"),
+		root:        http.Dir(dir),
+		apiPrefixes: apiPrefixes,
+	})
+}
+
+// spaHandler 返回匹配请求的单页应用处理函数，没有匹配时返回 nil
+func (e *Engine) spaHandler(r *http.Request) HandlerFunc {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return nil
+	}
+	for _, s := range e.spas {
+		if rel, ok := s.match(r.URL.Path); ok {
+			return s.handler(rel)
+		}
+	}
+	return nil
+}
+
+// match 判断请求路径是否属于该单页应用，返回相对路径
+func (s *spa) match(p string) (string, bool) {
+	for _, api := range s.apiPrefixes {
+		if p == api || strings.HasPrefix(p, strings.TrimSuffix(api, "/")+"/") {
+			return "", false
+		}
+	}
+	if s.prefix == "/" {
+		return p, true
+	}
+	if p == s.prefix || strings.HasPrefix(p, s.prefix+"/") {
+		return strings.TrimPrefix(p, s.prefix), true
+	}
+	return "", false
+}
+
+// handler 返回静态文件或 index.html
+func (s *spa) handler(rel string) HandlerFunc {
+	return func(c *Context) {
+		name := path.Clean("/" + rel)
+		if name != "/" && s.serveFile(c, name) {
+			return
+		}
+		// 带扩展名的资源不存在时返回 404，避免把缺失的 js/css 当作页面返回
+		if path.Ext(name) != "" {
+			c.StatusCode = http.StatusNotFound
+			http.NotFound(c.Writer, c.Request)
+			return
+		}
+		// index.html 不缓存，保证发布新版本后立即生效
+		c.SetHeader("Cache-Control", "no-cache")
+		if !s.serveFile(c, "/index.html") {
+			c.StatusCode = http.StatusNotFound
+			http.NotFound(c.Writer, c.Request)
+		}
+	}
+}
+
+// serveFile 返回文件内容，文件不存在或为目录时返回 false
+func (s *spa) serveFile(c *Context, name string) bool {
+	f, err := s.root.Open(name)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		return false
+	}
+	c.StatusCode = http.StatusOK
+	http.ServeContent(c.Writer, c.Request, info.Name(), info.ModTime(), f)
+	return true
+}
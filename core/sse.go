@@ -0,0 +1,161 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SSEvent 是一条 Server-Sent Events 消息
+type SSEvent struct {
+	ID    string        // 事件 ID，客户端重连时通过 Last-Event-ID 请求头带回
+	Event string        // 事件类型，为空时客户端按 message 事件处理
+	Data  interface{}   // 事件数据，string 和 []byte 原样发送，其他类型编码为 JSON
+	Retry time.Duration // 建议客户端的重连间隔，为 0 时不发送
+}
+
+// SSEOptions 定义了 Server-Sent Events 选项
+type SSEOptions struct {
+	// Heartbeat 心跳间隔，定期发送注释行防止代理和负载均衡器断开空闲连接，默认 15 秒，为负数时禁用
+	Heartbeat time.Duration
+	// Retry 建议客户端断线后的重连间隔，为 0 时使用浏览器默认值
+	Retry time.Duration
+}
+
+// SSEStream 是 Server-Sent Events 连接，可在多个 goroutine 中并发发送事件
+type SSEStream struct {
+	c           *Context
+	mu          sync.Mutex
+	lastEventID string
+}
+
+// SSE 建立 Server-Sent Events 连接并调用 fn 推送事件，fn 返回后连接关闭
+// fn 应在 stream.Done() 关闭（客户端断开）时尽快返回
+// options: 连接选项
+// fn: 推送事件的函数
+func (c *Context) SSE(options SSEOptions, fn func(stream *SSEStream)) {
+	if options.Heartbeat == 0 {
+		options.Heartbeat = 15 * time.Second
+	}
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no") // 关闭 Nginx 缓冲
+	c.Status(http.StatusOK)
+
+	stream := &SSEStream{c: c, lastEventID: c.GetHeader("Last-Event-ID")}
+	if options.Retry > 0 {
+		stream.write("retry: " + strconv.FormatInt(options.Retry.Milliseconds(), 10) + "\n\n")
+	} else {
+		c.Writer.Flush()
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	if options.Heartbeat > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ticker := time.NewTicker(options.Heartbeat)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					stream.write(": ping\n\n")
+				case <-stop:
+					return
+				case <-stream.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	fn(stream)
+	// 等待心跳停止后再返回，避免 Context 回收后仍被写入
+	close(stop)
+	wg.Wait()
+}
+
+// LastEventID 返回客户端重连时携带的最后一个事件 ID，首次连接时为空
+func (s *SSEStream) LastEventID() string {
+	return s.lastEventID
+}
+
+// Done 返回在客户端断开时关闭的通道
+func (s *SSEStream) Done() <-chan struct{} {
+	return s.c.Request.Context().Done()
+}
+
+// Send 发送事件并立即刷新，客户端已断开时返回错误
+func (s *SSEStream) Send(event SSEvent) error {
+	if err := s.c.Request.Context().Err(); err != nil {
+		return err
+	}
+	var b strings.Builder
+	if event.ID != "" {
+		b.WriteString("id: " + singleLine(event.ID) + "\n")
+	}
+	if event.Event != "" {
+		b.WriteString("event: " + singleLine(event.Event) + "\n")
+	}
+	if event.Retry > 0 {
+		b.WriteString("retry: " + strconv.FormatInt(event.Retry.Milliseconds(), 10) + "\n")
+	}
+	data, err := sseData(event.Data)
+	if err != nil {
+		return err
+	}
+	// 多行数据拆分为多个 data 字段，客户端收到后以换行拼接
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+	return s.write(b.String())
+}
+
+// SendData 发送指定类型的事件
+// event: 事件类型
+// data: 事件数据
+func (s *SSEStream) SendData(event string, data interface{}) error {
+	return s.Send(SSEvent{Event: event, Data: data})
+}
+
+// write 写出原始内容并刷新
+func (s *SSEStream) write(raw string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.c.Writer.Write([]byte(raw)); err != nil {
+		return err
+	}
+	s.c.Writer.Flush()
+	return nil
+}
+
+// sseData 将事件数据转换为字符串
+func sseData(data interface{}) (string, error) {
+	switch d := data.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return strings.ReplaceAll(d, "\r\n", "\n"), nil
+	case []byte:
+		return strings.ReplaceAll(string(d), "\r\n", "\n"), nil
+	default:
+		b, err := json.Marshal(d)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+}
+
+// singleLine 去除字段中的换行，避免破坏事件格式
+func singleLine(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
@@ -0,0 +1,167 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StaticOptions 定义了静态文件服务选项
+type StaticOptions struct {
+	// Index 访问目录时返回的索引文件，默认 "index.html"，为 "-" 时禁用
+	Index string
+	// MaxAge 浏览器缓存时长，为 0 时使用 "no-cache"，每次通过 ETag / Last-Modified 协商缓存
+	MaxAge time.Duration
+}
+
+// Static 将本地目录挂载到指定路径下提供静态文件服务
+// relativePath: 访问路径前缀，例如 "/assets"
+// root: 本地目录，例如 "./public"
+func (group *RouterGroup) Static(relativePath, root string) {
+	group.StaticFSWithOptions(relativePath, os.DirFS(root), StaticOptions{})
+}
+
+// StaticFS 将文件系统挂载到指定路径下提供静态文件服务，支持 embed.FS
+// 如果 embed.FS 中的文件位于子目录，可先使用 fs.Sub 截取子目录
+// relativePath: 访问路径前缀，例如 "/static"
+// fsys: 静态文件所在的文件系统
+func (group *RouterGroup) StaticFS(relativePath string, fsys fs.FS) {
+	group.StaticFSWithOptions(relativePath, fsys, StaticOptions{})
+}
+
+// StaticFSWithOptions 按选项挂载静态文件服务
+// 响应携带 ETag 和 Last-Modified，支持条件请求和 Range 请求；不提供目录列表。
+// embed.FS 中的文件没有修改时间，ETag 根据文件内容计算
+// relativePath: 访问路径前缀
+// fsys: 静态文件所在的文件系统
+// options: 静态文件服务选项
+func (group *RouterGroup) StaticFSWithOptions(relativePath string, fsys fs.FS, options StaticOptions) {
+	if options.Index == "" {
+		options.Index = "index.html"
+	}
+	s := &staticFS{fsys: fsys, options: options}
+	handler := func(c *Context) {
+		s.serve(c, c.Param("filepath"))
+	}
+	group.GET(path.Join(relativePath, "/*filepath"), handler)
+	if relativePath = path.Clean("/" + relativePath); relativePath != "/" {
+		group.GET(relativePath, handler)
+	}
+}
+
+// StaticFile 将单个本地文件挂载到指定路径，例如 favicon.ico
+// relativePath: 访问路径
+// filepath: 本地文件路径
+func (group *RouterGroup) StaticFile(relativePath, filepath string) {
+	dir, name := path.Split(filepath)
+	if dir == "" {
+		dir = "."
+	}
+	s := &staticFS{fsys: os.DirFS(dir), options: StaticOptions{Index: "-"}}
+	group.GET(relativePath, func(c *Context) {
+		s.serve(c, name)
+	})
+}
+
+// staticFS 是挂载的静态文件系统
+type staticFS struct {
+	fsys    fs.FS
+	options StaticOptions
+	etags   sync.Map // 无修改时间的文件按内容计算的 ETag，键为文件路径
+}
+
+// serve 返回文件内容
+// rel: 相对于挂载点的文件路径
+func (s *staticFS) serve(c *Context, rel string) {
+	name := strings.TrimPrefix(path.Clean("/"+rel), "/")
+	if name == "" {
+		name = "."
+	}
+	info, err := fs.Stat(s.fsys, name)
+	if err != nil {
+		s.notFound(c)
+		return
+	}
+	if info.IsDir() {
+		if s.options.Index == "-" {
+			s.notFound(c)
+			return
+		}
+		// 目录需以 "/" 结尾，保证页面中的相对路径正确
+		if !strings.HasSuffix(c.Request.URL.Path, "/") {
+			c.Redirect(http.StatusMovedPermanently, c.Request.URL.Path+"/")
+			return
+		}
+		name = path.Join(name, s.options.Index)
+		if info, err = fs.Stat(s.fsys, name); err != nil || info.IsDir() {
+			s.notFound(c)
+			return
+		}
+	}
+
+	f, err := s.fsys.Open(name)
+	if err != nil {
+		s.notFound(c)
+		return
+	}
+	defer f.Close()
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			s.notFound(c)
+			return
+		}
+		content = bytes.NewReader(data)
+	}
+
+	etag, err := s.etag(name, info, content)
+	if err != nil {
+		s.notFound(c)
+		return
+	}
+	c.SetHeader("ETag", etag)
+	if s.options.MaxAge > 0 {
+		c.SetHeader("Cache-Control", "public, max-age="+strconv.Itoa(int(s.options.MaxAge.Seconds())))
+	} else {
+		c.SetHeader("Cache-Control", "no-cache")
+	}
+	c.StatusCode = http.StatusOK
+	http.ServeContent(c.Writer, c.Request, info.Name(), info.ModTime(), content)
+}
+
+// etag 计算文件的 ETag：有修改时间时由大小和修改时间生成，否则由内容的哈希生成并缓存
+func (s *staticFS) etag(name string, info fs.FileInfo, content io.ReadSeeker) (string, error) {
+	if !info.ModTime().IsZero() {
+		return fmt.Sprintf(`W/"%x-%x"`, info.Size(), info.ModTime().UnixNano()), nil
+	}
+	if v, ok := s.etags.Load(name); ok {
+		return v.(string), nil
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, content); err != nil {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	etag := `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+	s.etags.Store(name, etag)
+	return etag, nil
+}
+
+// notFound 返回 404
+func (s *staticFS) notFound(c *Context) {
+	c.StatusCode = http.StatusNotFound
+	http.NotFound(c.Writer, c.Request)
+}
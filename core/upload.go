@@ -0,0 +1,221 @@
+package core
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"  // 注册 GIF 解码器，用于读取图片尺寸
+	_ "image/jpeg" // 注册 JPEG 解码器
+	_ "image/png"  // 注册 PNG 解码器
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	errs "github.com/xzl-go/easygo/errors"
+)
+
+// defaultMultipartMemory 解析 multipart 表单时保存在内存中的最大字节数，超出部分写入临时文件
+const defaultMultipartMemory = 32 << 20
+
+// ErrUpload 上传文件不符合校验规则，原始错误为 *UploadError
+var ErrUpload = errs.New(40002, "error.upload", http.StatusBadRequest, "Invalid upload")
+
+// 上传校验规则名称，用于 UploadError.Rule
+const (
+	UploadRuleRequired  = "required"
+	UploadRuleMaxFiles  = "max_files"
+	UploadRuleMaxSize   = "max_size"
+	UploadRuleType      = "type"
+	UploadRuleDimension = "dimension"
+)
+
+// UploadRule 定义了上传文件的校验规则，零值字段不做校验
+type UploadRule struct {
+	Required     bool     // 是否必须上传
+	MaxFiles     int      // 最大文件数量
+	MaxSize      int64    // 单个文件最大字节数
+	AllowedTypes []string // 允许的 MIME 类型，根据文件内容识别而非扩展名，支持 "image/*" 通配
+	MinWidth     int      // 图片最小宽度（像素），设置任一尺寸限制时要求文件必须是图片
+	MinHeight    int      // 图片最小高度
+	MaxWidth     int      // 图片最大宽度
+	MaxHeight    int      // 图片最大高度
+}
+
+// UploadError 是结构化的上传校验错误
+type UploadError struct {
+	Field    string `json:"field"`              // 表单字段名
+	Filename string `json:"filename,omitempty"` // 出错的文件名
+	Rule     string `json:"rule"`               // 未通过的规则
+	Message  string `json:"message"`            // 错误描述
+}
+
+// Error 实现 error 接口
+func (e *UploadError) Error() string {
+	if e.Filename != "" {
+		return fmt.Sprintf("%s(%s): %s", e.Field, e.Filename, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// MultipartForm 解析并返回 multipart 表单
+func (c *Context) MultipartForm() (*multipart.Form, error) {
+	if c.Request.MultipartForm == nil {
+		if err := c.Request.ParseMultipartForm(defaultMultipartMemory); err != nil {
+			return nil, err
+		}
+	}
+	return c.Request.MultipartForm, nil
+}
+
+// MultipartReader 返回 multipart 请求体的流式 reader，逐个读取表单字段和文件，不在内存或临时文件中缓冲，
+// 适合大文件直接转存到对象存储；与 MultipartForm、FormFile 互斥，只能使用其中一种方式读取
+func (c *Context) MultipartReader() (*multipart.Reader, error) {
+	return c.Request.MultipartReader()
+}
+
+// FormFile 获取上传的第一个文件
+// name: 表单字段名
+func (c *Context) FormFile(name string) (*multipart.FileHeader, error) {
+	if _, err := c.MultipartForm(); err != nil {
+		return nil, err
+	}
+	_, fh, err := c.Request.FormFile(name)
+	return fh, err
+}
+
+// FormFiles 获取上传的文件并按规则校验
+// name: 表单字段名
+// rule: 校验规则
+// 校验失败时返回 ErrUpload，可通过 errors.As 取得 *UploadError
+func (c *Context) FormFiles(name string, rule UploadRule) ([]*multipart.FileHeader, error) {
+	form, err := c.MultipartForm()
+	if err != nil && err != http.ErrNotMultipart {
+		return nil, ErrUpload.Wrap(err)
+	}
+	var files []*multipart.FileHeader
+	if form != nil {
+		files = form.File[name]
+	}
+	if err := rule.Validate(name, files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// SaveUploadedFile 保存上传的文件，目标目录不存在时自动创建
+// file: 上传的文件
+// dst: 目标路径
+func (c *Context) SaveUploadedFile(file *multipart.FileHeader, dst string) error {
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, src)
+	return err
+}
+
+// Validate 按规则校验同一字段上传的全部文件
+// field: 表单字段名
+// files: 上传的文件
+func (r UploadRule) Validate(field string, files []*multipart.FileHeader) error {
+	if len(files) == 0 {
+		if r.Required {
+			return uploadError(field, "", UploadRuleRequired, "必须上传文件")
+		}
+		return nil
+	}
+	if r.MaxFiles > 0 && len(files) > r.MaxFiles {
+		return uploadError(field, "", UploadRuleMaxFiles, fmt.Sprintf("最多上传 %d 个文件", r.MaxFiles))
+	}
+	for _, fh := range files {
+		if err := r.validateFile(field, fh); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateFile 校验单个文件
+func (r UploadRule) validateFile(field string, fh *multipart.FileHeader) error {
+	if r.MaxSize > 0 && fh.Size > r.MaxSize {
+		return uploadError(field, fh.Filename, UploadRuleMaxSize, fmt.Sprintf("文件大小不能超过 %d 字节", r.MaxSize))
+	}
+
+	checkDimension := r.MinWidth > 0 || r.MinHeight > 0 || r.MaxWidth > 0 || r.MaxHeight > 0
+	if len(r.AllowedTypes) == 0 && !checkDimension {
+		return nil
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return ErrUpload.Wrap(err)
+	}
+	defer f.Close()
+
+	if len(r.AllowedTypes) > 0 {
+		head := make([]byte, 512)
+		n, err := io.ReadFull(f, head)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return ErrUpload.Wrap(err)
+		}
+		mimeType := http.DetectContentType(head[:n])
+		if !matchMIME(mimeType, r.AllowedTypes) {
+			return uploadError(field, fh.Filename, UploadRuleType, fmt.Sprintf("不支持的文件类型 %s", mimeType))
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return ErrUpload.Wrap(err)
+		}
+	}
+
+	if checkDimension {
+		cfg, _, err := image.DecodeConfig(f)
+		if err != nil {
+			return uploadError(field, fh.Filename, UploadRuleDimension, "文件不是有效的图片")
+		}
+		if (r.MinWidth > 0 && cfg.Width < r.MinWidth) || (r.MinHeight > 0 && cfg.Height < r.MinHeight) ||
+			(r.MaxWidth > 0 && cfg.Width > r.MaxWidth) || (r.MaxHeight > 0 && cfg.Height > r.MaxHeight) {
+			return uploadError(field, fh.Filename, UploadRuleDimension, fmt.Sprintf("图片尺寸 %dx%d 不符合要求", cfg.Width, cfg.Height))
+		}
+	}
+	return nil
+}
+
+// uploadError 创建包装了 *UploadError 的业务错误
+func uploadError(field, filename, rule, message string) error {
+	return ErrUpload.WithMessage(message).Wrap(&UploadError{
+		Field:    field,
+		Filename: filename,
+		Rule:     rule,
+		Message:  message,
+	})
+}
+
+// matchMIME 判断 MIME 类型是否在允许列表中
+func matchMIME(mimeType string, allowed []string) bool {
+	if i := strings.Index(mimeType, ";"); i >= 0 {
+		mimeType = mimeType[:i]
+	}
+	mimeType = strings.TrimSpace(mimeType)
+	for _, a := range allowed {
+		if a == mimeType || a == "*/*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(a, "/*"); ok && strings.HasPrefix(mimeType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
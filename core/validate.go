@@ -0,0 +1,83 @@
+package core
+
+import (
+	"reflect"
+
+	errs "github.com/xzl-go/easygo/errors"
+	"github.com/xzl-go/easygo/validator"
+)
+
+// SetValidator 设置引擎使用的验证器，ShouldBindAndValidate 使用该验证器校验，未设置时使用全局验证器
+// v: 验证器，例如 validator.New()
+func (e *Engine) SetValidator(v *validator.Validator) {
+	e.validator = v
+}
+
+// Validator 返回引擎使用的验证器，未设置时返回 nil
+func (e *Engine) Validator() *validator.Validator {
+	return e.validator
+}
+
+// SetTranslator 设置翻译器，ShouldBindAndValidate 和 Context.T 按请求语言翻译，*i18n.I18n 实现了该接口
+// 国际化中间件绑定的翻译器优先；请求语言取自上下文中的 lang（国际化中间件写入），其次是 Accept-Language 请求头
+// translator: 翻译器
+func (e *Engine) SetTranslator(translator validator.Translator) {
+	e.translator = translator
+}
+
+// SetBindErrorHandler 设置 BindAndValidate 失败时的处理函数，用于自定义错误响应格式
+// 默认通过 Fail 返回统一响应：绑定失败为 400，校验失败为 422 且 data 为字段错误列表
+// handler: 处理函数，err 为 ShouldBindAndValidate 返回的错误
+func (e *Engine) SetBindErrorHandler(handler func(c *Context, err error)) {
+	e.bindErrorHandler = handler
+}
+
+// ShouldBindAndValidate 按 Content-Type 绑定请求（同 Bind）并按 validate 标签校验，不写响应
+// 绑定失败返回 errors.ErrBadRequest，校验失败返回 errors.ErrUnprocessable，
+// 其原因为按请求语言翻译的 validator.ValidationErrors，可通过 errors.As 取出
+// obj: 目标对象指针
+func (c *Context) ShouldBindAndValidate(obj interface{}) error {
+	if err := c.Bind(obj); err != nil {
+		return errs.ErrBadRequest.Wrap(err)
+	}
+	if reflect.Indirect(reflect.ValueOf(obj)).Kind() != reflect.Struct {
+		return nil
+	}
+	validate := validator.Validate
+	if c.engine != nil && c.engine.validator != nil {
+		validate = c.engine.validator.Validate
+	}
+	if err := validate(obj); err != nil {
+		lang := c.Lang()
+		if lang == "" {
+			lang = c.GetHeader("Accept-Language")
+		}
+		if fieldErrors := validator.Translate(err, c.translator(), lang); fieldErrors != nil {
+			return errs.ErrUnprocessable.Wrap(fieldErrors)
+		}
+		return errs.ErrUnprocessable.Wrap(err)
+	}
+	return nil
+}
+
+// BindAndValidate 绑定并校验请求，失败时写出错误响应并中止请求，处理函数直接返回即可：
+//
+//	if err := c.BindAndValidate(&req); err != nil {
+//		return
+//	}
+//
+// 错误响应可通过 Engine.SetBindErrorHandler 自定义
+// obj: 目标对象指针
+func (c *Context) BindAndValidate(obj interface{}) error {
+	err := c.ShouldBindAndValidate(obj)
+	if err == nil {
+		return nil
+	}
+	if c.engine != nil && c.engine.bindErrorHandler != nil {
+		c.engine.bindErrorHandler(c, err)
+	} else {
+		c.Fail(err)
+	}
+	c.Abort()
+	return err
+}
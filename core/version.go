@@ -0,0 +1,93 @@
+package core
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	errs "github.com/xzl-go/easygo/errors"
+)
+
+// APIVersionKey 是上下文中保存 API 版本的键
+const APIVersionKey = "api_version"
+
+// ErrVersionSunset API 版本已下线
+var ErrVersionSunset = errs.New(41000, "error.api_version_sunset", http.StatusGone, "API version is no longer available")
+
+// VersionOptions 定义了 API 版本选项
+type VersionOptions struct {
+	// Prefix 版本的路径前缀，默认 "/" + 版本号，例如 "/v1"
+	Prefix string
+	// Deprecation 版本的弃用时间，非零时响应携带 Deprecation 头（RFC 9745），可以是未来的时间
+	Deprecation time.Time
+	// Sunset 版本的下线时间，非零时响应携带 Sunset 头（RFC 8594）
+	Sunset time.Time
+	// Link 迁移说明文档地址，设置后响应携带 rel="deprecation" 的 Link 头
+	Link string
+	// RejectAfterSunset 是否在下线时间之后拒绝请求，返回 410
+	RejectAfterSunset bool
+}
+
+// Version 创建 API 版本路由组，前缀为 "/" + 版本号
+// 版本路由组与普通路由组一样可以通过 Use 添加该版本独有的中间件，处理函数可以通过 c.APIVersion() 取得版本号
+// version: 版本号，例如 "v1"
+func (group *RouterGroup) Version(version string) *RouterGroup {
+	return group.VersionWithOptions(version, VersionOptions{})
+}
+
+// VersionWithOptions 按选项创建 API 版本路由组，用于标记弃用和下线时间，提醒客户端迁移到新版本
+// version: 版本号
+// options: 版本选项
+func (group *RouterGroup) VersionWithOptions(version string, options VersionOptions) *RouterGroup {
+	if options.Prefix == "" {
+		options.Prefix = "/" + strings.Trim(version, "/")
+	}
+	var deprecation, sunset, link string
+	if !options.Deprecation.IsZero() {
+		deprecation = "@" + strconv.FormatInt(options.Deprecation.Unix(), 10)
+	}
+	if !options.Sunset.IsZero() {
+		sunset = options.Sunset.UTC().Format(http.TimeFormat)
+	}
+	if options.Link != "" {
+		link = "<" + options.Link + `>; rel="deprecation"; type="text/html"`
+	}
+
+	v := group.Group(options.Prefix)
+	v.Use(func(c *Context) {
+		c.Set(APIVersionKey, version)
+		header := c.Writer.Header()
+		if deprecation != "" {
+			header.Set("Deprecation", deprecation)
+		}
+		if sunset != "" {
+			header.Set("Sunset", sunset)
+		}
+		if link != "" {
+			header.Add("Link", link)
+		}
+		if options.RejectAfterSunset && !options.Sunset.IsZero() && time.Now().After(options.Sunset) {
+			c.Fail(ErrVersionSunset)
+			c.Abort()
+			return
+		}
+		c.Next()
+	})
+	return v
+}
+
+// APIVersion 返回请求匹配的 API 版本号，不在版本路由组中时返回空字符串
+func (c *Context) APIVersion() string {
+	version, _ := c.Get(APIVersionKey).(string)
+	return version
+}
+
+// MountEngine 将另一个引擎挂载到路径前缀下，子引擎看到的路径去掉了前缀
+// 子引擎有独立的全局中间件、路由和错误处理，适合按版本或模块拆分的应用组合在同一个端口上提供服务；
+// 请求先经过当前引擎的全局中间件和路由组中间件，再经过子引擎的全局中间件
+// prefix: 路径前缀，例如 "/v2"
+// sub: 子引擎
+func (group *RouterGroup) MountEngine(prefix string, sub *Engine) {
+	group.MountWithOptions(prefix, sub, MountOptions{StripPrefix: true})
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"html/template"
+)
+
+// ViewDataProvider 是可以向模板数据注入请求相关值的渲染器，
+// render 包中的 HTML 和 Jet 渲染器实现了该接口，注入 CSRF 令牌、当前用户和翻译函数
+type ViewDataProvider interface {
+	ViewData(c *Context, data interface{}) interface{}
+}
+
+// SetFuncMap 设置模板函数，需在 LoadHTMLGlob、LoadHTMLFiles、LoadHTMLFS 等加载模板的方法之前调用
+// funcMap: 模板函数，例如 template.FuncMap{"upper": strings.ToUpper}
+func (e *Engine) SetFuncMap(funcMap template.FuncMap) {
+	e.funcMap = funcMap
+}
+
+// newTemplate 创建带有模板函数的空模板，用于解析模板文件
+func (e *Engine) newTemplate() *template.Template {
+	return template.New("").Funcs(e.funcMap)
+}
+
+// SetViewData 设置当前请求的模板数据，通常由中间件设置当前用户、菜单等公共数据
+// 渲染时与 HTML 的 data 参数合并，data 中的同名键优先
+// key: 模板中访问的键
+// value: 值
+func (c *Context) SetViewData(key string, value interface{}) {
+	if c.viewData == nil {
+		c.viewData = make(map[string]interface{})
+	}
+	c.viewData[key] = value
+}
+
+// ViewData 返回当前请求通过 SetViewData 设置的模板数据
+func (c *Context) ViewData() map[string]interface{} {
+	return c.viewData
+}
+
+// mergeViewData 合并请求的模板数据与渲染数据
+// data 为 nil 或 map[string]interface{} 时合并，其他类型（如结构体）原样返回
+func (c *Context) mergeViewData(data interface{}) interface{} {
+	if len(c.viewData) == 0 {
+		return data
+	}
+	var view map[string]interface{}
+	switch d := data.(type) {
+	case nil:
+		view = make(map[string]interface{}, len(c.viewData))
+	case map[string]interface{}:
+		view = make(map[string]interface{}, len(c.viewData)+len(d))
+		for k, v := range d {
+			view[k] = v
+		}
+	default:
+		return data
+	}
+	for k, v := range c.viewData {
+		if _, exists := view[k]; !exists {
+			view[k] = v
+		}
+	}
+	return view
+}
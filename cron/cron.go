@@ -1,26 +1,67 @@
 package cron
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
 	"github.com/robfig/cron/v3"
 )
 
-var c *cron.Cron
+var (
+	mu   sync.Mutex
+	c    *cron.Cron
+	jobs = make(map[string]*job)
+	seq  int // 未命名任务的序号
+)
+
+// running 表示定时任务管理器是否在运行
+var running atomic.Bool
 
-// InitCron 初始化定时任务管理器
+// scheduler 返回调度器，不存在时创建，调用方需持有锁
+func scheduler() *cron.Cron {
+	if c == nil {
+		c = cron.New()
+	}
+	return c
+}
+
+// InitCron 初始化并启动定时任务管理器，之前注册的任务开始按计划执行
 func InitCron() {
-	c = cron.New()
-	c.Start()
+	mu.Lock()
+	defer mu.Unlock()
+	scheduler().Start()
+	running.Store(true)
 }
 
-// AddJob 添加定时任务
+// AddJob 添加定时任务，任务名称自动生成为 job-1、job-2 …
+// 需要查看执行状态或在运行时管理的任务使用 Register 指定名称
+// spec: cron 表达式，例如 "0 3 * * *"、"@every 1m"
+// cmd: 任务函数
 func AddJob(spec string, cmd func()) error {
-	_, err := c.AddFunc(spec, cmd)
-	return err
+	mu.Lock()
+	seq++
+	name := fmt.Sprintf("job-%d", seq)
+	mu.Unlock()
+	return Register(Job{Name: name, Spec: spec, Func: cmd})
 }
 
 // StopCron 停止定时任务管理器
 func StopCron() {
+	mu.Lock()
+	defer mu.Unlock()
 	if c != nil {
 		c.Stop()
+		running.Store(false)
+	}
+}
+
+// Check 检查定时任务管理器是否在运行，可通过 health.CheckerFunc(cron.Check) 注册为健康检查项
+func Check(ctx context.Context) error {
+	if !running.Load() {
+		return errors.New("cron: 定时任务管理器未运行")
 	}
+	return nil
 }
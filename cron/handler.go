@@ -0,0 +1,39 @@
+package cron
+
+import (
+	"github.com/xzl-go/easygo/core"
+)
+
+// RegisterRoutes 注册任务管理接口，应注册在受保护的管理路由组下：
+// GET /jobs 列出任务，GET /jobs/:name 查看任务，POST /jobs/:name/run 立即执行，
+// POST /jobs/:name/enable 启用，POST /jobs/:name/disable 停用
+// group: 路由组，例如 app.Group("/admin/cron")
+func RegisterRoutes(group *core.RouterGroup) {
+	group.GET("/jobs", func(c *core.Context) {
+		c.Success(ListJobs())
+	})
+	group.GET("/jobs/:name", func(c *core.Context) {
+		info, ok := GetJob(c.Param("name"))
+		if !ok {
+			c.Fail(ErrJobNotFound)
+			return
+		}
+		c.Success(info)
+	})
+	group.POST("/jobs/:name/run", action(Trigger))
+	group.POST("/jobs/:name/enable", action(Enable))
+	group.POST("/jobs/:name/disable", action(Disable))
+}
+
+// action 返回对任务执行操作并返回任务状态的处理函数
+func action(fn func(name string) error) core.HandlerFunc {
+	return func(c *core.Context) {
+		name := c.Param("name")
+		if err := fn(name); err != nil {
+			c.Fail(err)
+			return
+		}
+		info, _ := GetJob(name)
+		c.Success(info)
+	}
+}
@@ -0,0 +1,351 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	errs "github.com/xzl-go/easygo/errors"
+	"github.com/xzl-go/easygo/lock"
+	"github.com/xzl-go/easygo/logger"
+)
+
+var (
+	// ErrJobNotFound 任务不存在
+	ErrJobNotFound = errs.New(40420, "error.cron.job_not_found", http.StatusNotFound, "Job not found")
+	// ErrJobExists 同名任务已注册
+	ErrJobExists = errs.New(40920, "error.cron.job_exists", http.StatusConflict, "Job already exists")
+)
+
+// OverlapPolicy 定义了上一次执行尚未结束时到达执行时间的处理方式
+type OverlapPolicy int
+
+const (
+	// OverlapAllow 允许多次执行同时进行
+	OverlapAllow OverlapPolicy = iota
+	// OverlapSkip 跳过本次执行
+	OverlapSkip
+	// OverlapQueue 等待上一次执行结束后再执行
+	OverlapQueue
+)
+
+// Job 定义了一个具名的定时任务
+type Job struct {
+	// Name 任务名称，全局唯一，用于查询和管理任务
+	Name string
+	// Spec cron 表达式，例如 "0 3 * * *"、"@every 1m"、"@daily"
+	Spec string
+	// Description 任务说明，在任务列表中展示
+	Description string
+	// Func 任务函数，panic 会被恢复并记为一次失败
+	Func func()
+	// Run 带 context 和错误返回的任务函数，与 Func 二选一；返回错误、超时或 panic 记为一次失败
+	Run func(ctx context.Context) error
+	// Timeout 使用 Run 时单次执行的超时时间，超时后取消 ctx，任务需响应 ctx 取消；为 0 时不限制
+	Timeout time.Duration
+	// Retries 执行失败后的重试次数，为 0 时不重试
+	Retries int
+	// Backoff 第 retry 次重试前的等待时间，默认从 1 秒开始指数增长，最长 1 分钟
+	Backoff func(retry int) time.Duration
+	// Overlap 上一次执行尚未结束时的处理方式，默认 OverlapAllow
+	Overlap OverlapPolicy
+	// Locker 分布式锁，设置后每次执行前以 "cron:" 加任务名称为键尝试加锁，
+	// 多实例部署时只有获取到锁的实例执行，其他实例跳过本次执行；锁失效时取消 Run 的 ctx
+	Locker lock.Locker
+	// Disabled 为 true 时注册后不按计划执行，可通过 Enable 启用
+	Disabled bool
+}
+
+// JobInfo 是任务的状态
+type JobInfo struct {
+	Name         string        `json:"name"`
+	Spec         string        `json:"spec"`
+	Description  string        `json:"description,omitempty"`
+	Enabled      bool          `json:"enabled"`
+	Running      bool          `json:"running"`              // 是否正在执行
+	LastRun      time.Time     `json:"last_run"`             // 最近一次开始执行的时间，未执行过时为零值
+	NextRun      time.Time     `json:"next_run"`             // 下次计划执行的时间，停用时为零值
+	LastDuration time.Duration `json:"last_duration"`        // 最近一次执行的耗时
+	LastError    string        `json:"last_error,omitempty"` // 最近一次执行的错误，成功时为空
+	Runs         int64         `json:"runs"`                 // 执行次数
+	Failures     int64         `json:"failures"`             // 失败次数
+	Skipped      int64         `json:"skipped"`              // 因上一次执行尚未结束或其他实例正在执行而跳过的次数
+}
+
+// job 是已注册的任务
+type job struct {
+	Job
+	schedule cron.Schedule
+	entryID  cron.EntryID // 已加入调度器的条目，停用时为 0
+	running  atomic.Int32
+	lock     chan struct{} // 防止重叠执行
+
+	mu           sync.Mutex
+	lastRun      time.Time
+	lastDuration time.Duration
+	lastError    string
+	runs         int64
+	failures     int64
+	skipped      int64
+}
+
+// Register 注册具名任务，定时任务管理器启动后按计划执行
+// def: 任务定义，Name、Spec 不能为空，Func 和 Run 需设置其一
+func Register(def Job) error {
+	if def.Name == "" || (def.Func == nil && def.Run == nil) {
+		return errors.New("cron: 任务名称和任务函数不能为空")
+	}
+	schedule, err := cron.ParseStandard(def.Spec)
+	if err != nil {
+		return fmt.Errorf("cron: 任务 %s 的表达式无效: %w", def.Name, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := jobs[def.Name]; ok {
+		return ErrJobExists.WithMessage(fmt.Sprintf("Job %s already exists", def.Name))
+	}
+	j := &job{Job: def, schedule: schedule, lock: make(chan struct{}, 1)}
+	if !def.Disabled {
+		j.entryID = scheduler().Schedule(schedule, cron.FuncJob(j.run))
+	}
+	jobs[def.Name] = j
+	return nil
+}
+
+// Remove 删除任务，正在执行的任务不受影响
+// name: 任务名称
+func Remove(name string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	j, ok := jobs[name]
+	if !ok {
+		return ErrJobNotFound
+	}
+	if j.entryID != 0 {
+		scheduler().Remove(j.entryID)
+	}
+	delete(jobs, name)
+	return nil
+}
+
+// Enable 启用任务，任务恢复按计划执行
+// name: 任务名称
+func Enable(name string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	j, ok := jobs[name]
+	if !ok {
+		return ErrJobNotFound
+	}
+	if j.entryID == 0 {
+		j.entryID = scheduler().Schedule(j.schedule, cron.FuncJob(j.run))
+	}
+	return nil
+}
+
+// Disable 停用任务，任务不再按计划执行，仍可通过 Trigger 手动执行
+// name: 任务名称
+func Disable(name string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	j, ok := jobs[name]
+	if !ok {
+		return ErrJobNotFound
+	}
+	if j.entryID != 0 {
+		scheduler().Remove(j.entryID)
+		j.entryID = 0
+	}
+	return nil
+}
+
+// Trigger 立即在后台执行一次任务，不影响计划执行时间，停用的任务也可以执行
+// name: 任务名称
+func Trigger(name string) error {
+	mu.Lock()
+	j, ok := jobs[name]
+	mu.Unlock()
+	if !ok {
+		return ErrJobNotFound
+	}
+	go j.run()
+	return nil
+}
+
+// GetJob 返回任务的状态
+// name: 任务名称
+func GetJob(name string) (JobInfo, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	j, ok := jobs[name]
+	if !ok {
+		return JobInfo{}, false
+	}
+	return j.info(), true
+}
+
+// ListJobs 返回全部任务的状态，按名称排序
+func ListJobs() []JobInfo {
+	mu.Lock()
+	defer mu.Unlock()
+	list := make([]JobInfo, 0, len(jobs))
+	for _, j := range jobs {
+		list = append(list, j.info())
+	}
+	sort.Slice(list, func(a, b int) bool { return list[a].Name < list[b].Name })
+	return list
+}
+
+// info 返回任务的状态，调用方需持有锁
+func (j *job) info() JobInfo {
+	info := JobInfo{
+		Name:        j.Name,
+		Spec:        j.Spec,
+		Description: j.Description,
+		Enabled:     j.entryID != 0,
+		Running:     j.running.Load() > 0,
+	}
+	if info.Enabled {
+		info.NextRun = scheduler().Entry(j.entryID).Next
+		if info.NextRun.IsZero() {
+			// 调度器未启动时条目还没有计算下次执行时间
+			info.NextRun = j.schedule.Next(time.Now())
+		}
+	}
+	j.mu.Lock()
+	info.LastRun = j.lastRun
+	info.LastDuration = j.lastDuration
+	info.LastError = j.lastError
+	info.Runs = j.runs
+	info.Failures = j.failures
+	info.Skipped = j.skipped
+	j.mu.Unlock()
+	return info
+}
+
+// run 按重叠策略执行一次任务，设置了分布式锁时持锁执行
+func (j *job) run() {
+	switch j.Overlap {
+	case OverlapSkip:
+		select {
+		case j.lock <- struct{}{}:
+		default:
+			j.skip()
+			logger.Module("cron").Warn("[Cron] 任务 %s 上一次执行尚未结束，跳过本次执行", j.Name)
+			return
+		}
+		defer func() { <-j.lock }()
+	case OverlapQueue:
+		j.lock <- struct{}{}
+		defer func() { <-j.lock }()
+	}
+
+	if j.Locker == nil {
+		j.runOnce(context.Background())
+		return
+	}
+	err := lock.Do(context.Background(), j.Locker, "cron:"+j.Name, func(ctx context.Context) error {
+		j.runOnce(ctx)
+		return nil
+	})
+	switch {
+	case errors.Is(err, lock.ErrNotAcquired):
+		j.skip()
+		logger.Module("cron").Info("[Cron] 任务 %s 正由其他实例执行，跳过本次执行", j.Name)
+	case err != nil:
+		logger.Module("cron").Error("[Cron] 任务 %s 的分布式锁出错：%v", j.Name, err)
+	}
+}
+
+// skip 记录一次被跳过的执行
+func (j *job) skip() {
+	j.mu.Lock()
+	j.skipped++
+	j.mu.Unlock()
+	reportSkip(j.Name)
+}
+
+// runOnce 执行一次任务并记录结果
+// ctx: 任务的上下文，持有的分布式锁失效时被取消
+func (j *job) runOnce(ctx context.Context) {
+	j.running.Add(1)
+	defer j.running.Add(-1)
+
+	start := time.Now()
+	j.mu.Lock()
+	j.lastRun = start
+	j.mu.Unlock()
+
+	err := j.execute(ctx)
+	duration := time.Since(start)
+
+	j.mu.Lock()
+	j.lastDuration = duration
+	j.runs++
+	j.lastError = ""
+	if err != nil {
+		j.failures++
+		j.lastError = err.Error()
+	}
+	j.mu.Unlock()
+	report(j.Name, duration, err)
+}
+
+// execute 执行任务，失败时按退避策略重试
+func (j *job) execute(ctx context.Context) error {
+	for retry := 0; ; retry++ {
+		err := j.call(ctx)
+		if err == nil {
+			return nil
+		}
+		// 分布式锁失效后不再重试，避免与获取到锁的实例同时执行
+		if retry >= j.Retries || ctx.Err() != nil {
+			logger.Module("cron").Error("[Cron] 任务 %s 执行失败：%v", j.Name, err)
+			return err
+		}
+		wait := j.backoff(retry + 1)
+		logger.Module("cron").Warn("[Cron] 任务 %s 执行失败，%v 后进行第 %d 次重试：%v", j.Name, wait, retry+1, err)
+		time.Sleep(wait)
+	}
+}
+
+// backoff 返回第 retry 次重试前的等待时间
+func (j *job) backoff(retry int) time.Duration {
+	if j.Backoff != nil {
+		return j.Backoff(retry)
+	}
+	d := time.Second
+	for i := 1; i < retry && d < time.Minute; i++ {
+		d *= 2
+	}
+	return min(d, time.Minute)
+}
+
+// call 调用一次任务函数，将 panic 恢复为错误
+func (j *job) call(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+			logger.Module("cron").Error("[Cron] 任务 %s panic：%v\n%s", j.Name, r, debug.Stack())
+		}
+	}()
+	if j.Run == nil {
+		j.Func()
+		return nil
+	}
+	if j.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.Timeout)
+		defer cancel()
+	}
+	return j.Run(ctx)
+}
@@ -0,0 +1,50 @@
+package cron
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xzl-go/easygo/lock"
+)
+
+func TestJobLocker(t *testing.T) {
+	locker := lock.NewMemory(lock.Options{})
+	runs := 0
+	if err := Register(Job{
+		Name:     "locked",
+		Spec:     "@every 1h",
+		Func:     func() { runs++ },
+		Locker:   locker,
+		Disabled: true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	defer Remove("locked")
+	mu.Lock()
+	j := jobs["locked"]
+	mu.Unlock()
+
+	// 其他实例持有锁时跳过本次执行
+	held, err := locker.TryAcquire(context.Background(), "cron:locked")
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.run()
+	if info, _ := GetJob("locked"); runs != 0 || info.Skipped != 1 || info.Runs != 0 {
+		t.Fatalf("持有锁时 runs=%d skipped=%d，期望跳过执行", runs, info.Skipped)
+	}
+
+	// 锁释放后正常执行，执行结束后释放锁
+	if err := held.Release(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	j.run()
+	if info, _ := GetJob("locked"); runs != 1 || info.Runs != 1 {
+		t.Fatalf("锁释放后 runs=%d，期望执行一次", runs)
+	}
+	l, err := locker.TryAcquire(context.Background(), "cron:locked")
+	if err != nil {
+		t.Fatalf("执行结束后锁没有释放: %v", err)
+	}
+	l.Release(context.Background())
+}
@@ -0,0 +1,65 @@
+package cron
+
+import (
+	"context"
+	"time"
+
+	"github.com/xzl-go/easygo/logger"
+	"github.com/xzl-go/easygo/metrics"
+)
+
+// sink 是任务指标输出，为 nil 时不记录指标
+var sink metrics.Sink
+
+// SetMetrics 设置任务指标输出，每次执行结束后记录并推送，指标与 metrics.Job 一致：
+// task_duration、task_runs_total、task_failures_total、task_last_success_timestamp，
+// 以及因重叠或其他实例正在执行而被跳过的 task_skipped_total，标签 task 为任务名称
+// s: 指标输出，例如 metrics.NewStatsD 或 metrics.NewPushgateway 的返回值
+func SetMetrics(s metrics.Sink) {
+	mu.Lock()
+	defer mu.Unlock()
+	sink = s
+}
+
+// currentSink 返回任务指标输出
+func currentSink() metrics.Sink {
+	mu.Lock()
+	defer mu.Unlock()
+	return sink
+}
+
+// report 记录一次执行的指标
+func report(name string, d time.Duration, err error) {
+	s := currentSink()
+	if s == nil {
+		return
+	}
+	tags := metrics.Tags{"task": name}
+	s.Timing("task_duration", d, tags)
+	s.Count("task_runs_total", 1, tags)
+	if err != nil {
+		s.Count("task_failures_total", 1, tags)
+	} else {
+		s.Gauge("task_last_success_timestamp", float64(time.Now().Unix()), tags)
+	}
+	flush(s, name)
+}
+
+// reportSkip 记录一次被跳过的执行
+func reportSkip(name string) {
+	s := currentSink()
+	if s == nil {
+		return
+	}
+	s.Count("task_skipped_total", 1, metrics.Tags{"task": name})
+	flush(s, name)
+}
+
+// flush 推送指标
+func flush(s metrics.Sink, name string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.Flush(ctx); err != nil {
+		logger.Module("cron").Error("[Cron] 推送任务 %s 的指标失败：%v", name, err)
+	}
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"net/http"
+
+	"gorm.io/gorm"
+
+	"github.com/xzl-go/easygo/core"
+	"github.com/xzl-go/easygo/logger"
+)
+
+const (
+	// ContextKey 是连接管理器在上下文中的键
+	ContextKey = "db"
+	// TxKey 是 Transaction 中间件开启的事务在上下文中的键
+	TxKey = "db_tx"
+)
+
+// Inject 返回将连接管理器注入上下文的中间件，处理函数通过 db.From(c) 获取连接
+func Inject(m *Manager) core.HandlerFunc {
+	return func(c *core.Context) {
+		c.Set(ContextKey, m)
+		c.Next()
+	}
+}
+
+// From 返回绑定了当前请求上下文的连接
+// 请求处于 Transaction 中间件开启的事务中时返回该事务；
+// 客户端断开时正在执行的 SQL 会被取消，开启链路追踪时 SQL 的 span 挂在请求的 span 下
+// name: 连接名称，省略时返回默认连接
+func From(c *core.Context, name ...string) *gorm.DB {
+	if tx, ok := c.Get(TxKey).(*gorm.DB); ok && len(name) == 0 {
+		return tx
+	}
+	m, ok := c.Get(ContextKey).(*Manager)
+	if !ok {
+		return nil
+	}
+	db := m.Default()
+	if len(name) > 0 {
+		db = m.DB(name[0])
+	}
+	if db == nil {
+		return nil
+	}
+	return db.WithContext(c)
+}
+
+// Transaction 返回事务中间件，为请求开启默认连接上的事务，处理函数通过 db.From(c) 获取事务
+// 处理过程中发生 panic、调用了 c.Error、请求被中止或响应状态码不小于 400 时回滚，否则提交。
+// 事务在处理函数返回后提交，提交失败只能记录日志，此时响应可能已经发出
+// m: 连接管理器
+func Transaction(m *Manager) core.HandlerFunc {
+	return func(c *core.Context) {
+		tx := m.Default().WithContext(c).Begin()
+		if tx.Error != nil {
+			c.Fail(tx.Error)
+			c.Abort()
+			return
+		}
+		c.Set(TxKey, tx)
+
+		committed := false
+		defer func() {
+			c.Set(TxKey, nil)
+			if !committed {
+				if err := tx.Rollback().Error; err != nil {
+					logger.Error("[DB] 回滚事务失败：%v", err)
+				}
+			}
+		}()
+
+		c.Next()
+
+		if len(c.Errors) > 0 || c.IsAborted() || c.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+		committed = true
+		if err := tx.Commit().Error; err != nil {
+			logger.Error("[DB] 提交事务失败：%v", err)
+		}
+	}
+}
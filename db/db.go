@@ -0,0 +1,251 @@
+// Package db 提供了基于 GORM 的数据库连接管理
+// 支持多个命名连接、连接池配置、读写分离和链路追踪，并提供注入上下文和事务中间件
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
+)
+
+// DefaultName 是默认连接的名称
+const DefaultName = "default"
+
+// Config 定义了数据库连接配置
+type Config struct {
+	// Driver 数据库驱动：mysql、postgres、sqlite（或 sqlite3）
+	Driver string `json:"driver" yaml:"driver" default:"mysql"`
+	// DSN 主库连接字符串
+	DSN string `json:"dsn" yaml:"dsn"`
+	// Replicas 只读副本的连接字符串，配置后查询自动路由到副本，写入和事务使用主库
+	Replicas []string `json:"replicas" yaml:"replicas"`
+	// MaxOpenConns 最大打开连接数，默认 100
+	MaxOpenConns int `json:"max_open_conns" yaml:"max_open_conns" default:"100"`
+	// MaxIdleConns 最大空闲连接数，默认 10
+	MaxIdleConns int `json:"max_idle_conns" yaml:"max_idle_conns" default:"10"`
+	// ConnMaxLifetime 连接最长存活时间，默认 1 小时
+	ConnMaxLifetime time.Duration `json:"conn_max_lifetime" yaml:"conn_max_lifetime" default:"1h"`
+	// ConnMaxIdleTime 连接最长空闲时间，0 表示不限制
+	ConnMaxIdleTime time.Duration `json:"conn_max_idle_time" yaml:"conn_max_idle_time"`
+	// SlowThreshold 慢查询阈值，超过时输出警告日志，默认 200 毫秒
+	SlowThreshold time.Duration `json:"slow_threshold" yaml:"slow_threshold" default:"200ms"`
+	// Tracing 是否为 SQL 创建链路追踪 span
+	Tracing bool `json:"tracing" yaml:"tracing"`
+}
+
+// withDefaults 为未设置的字段填充默认值
+func (c Config) withDefaults() Config {
+	if c.Driver == "" {
+		c.Driver = "mysql"
+	}
+	if c.MaxOpenConns <= 0 {
+		c.MaxOpenConns = 100
+	}
+	if c.MaxIdleConns <= 0 {
+		c.MaxIdleConns = 10
+	}
+	if c.ConnMaxLifetime <= 0 {
+		c.ConnMaxLifetime = time.Hour
+	}
+	if c.SlowThreshold <= 0 {
+		c.SlowThreshold = 200 * time.Millisecond
+	}
+	return c
+}
+
+// dialector 根据驱动创建 GORM 方言
+func dialector(driver, dsn string) (gorm.Dialector, error) {
+	switch driver {
+	case "mysql":
+		return mysql.Open(dsn), nil
+	case "postgres":
+		return postgres.Open(dsn), nil
+	case "sqlite", "sqlite3":
+		return sqlite.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("db: 不支持的数据库驱动 %q", driver)
+	}
+}
+
+// Open 根据配置创建数据库连接
+func Open(config Config) (*gorm.DB, error) {
+	config = config.withDefaults()
+	if config.DSN == "" {
+		return nil, errors.New("db: 未配置连接字符串")
+	}
+	primary, err := dialector(config.Driver, config.DSN)
+	if err != nil {
+		return nil, err
+	}
+	db, err := gorm.Open(primary, &gorm.Config{
+		Logger: gormlogger.New(log.New(os.Stdout, "\r\n", log.LstdFlags), gormlogger.Config{
+			SlowThreshold:             config.SlowThreshold,
+			LogLevel:                  gormlogger.Warn,
+			IgnoreRecordNotFoundError: true,
+			Colorful:                  true,
+		}),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db: 连接数据库失败: %w", err)
+	}
+
+	if len(config.Replicas) > 0 {
+		replicas := make([]gorm.Dialector, 0, len(config.Replicas))
+		for _, dsn := range config.Replicas {
+			d, err := dialector(config.Driver, dsn)
+			if err != nil {
+				return nil, err
+			}
+			replicas = append(replicas, d)
+		}
+		resolver := dbresolver.Register(dbresolver.Config{
+			Replicas: replicas,
+			Policy:   dbresolver.RandomPolicy{},
+		}).
+			SetMaxOpenConns(config.MaxOpenConns).
+			SetMaxIdleConns(config.MaxIdleConns).
+			SetConnMaxLifetime(config.ConnMaxLifetime).
+			SetConnMaxIdleTime(config.ConnMaxIdleTime)
+		if err := db.Use(resolver); err != nil {
+			return nil, fmt.Errorf("db: 配置读写分离失败: %w", err)
+		}
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(config.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(config.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(config.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+
+	if config.Tracing {
+		if err := db.Use(&tracingPlugin{}); err != nil {
+			return nil, fmt.Errorf("db: 注册链路追踪失败: %w", err)
+		}
+	}
+	return db, nil
+}
+
+// Manager 管理多个命名的数据库连接
+// 实现了 app.Module，启动时检查全部连接，停止时关闭全部连接
+type Manager struct {
+	mu  sync.RWMutex
+	dbs map[string]*gorm.DB
+}
+
+// NewManager 根据配置创建连接管理器
+// configs: 连接名称到配置的映射，默认连接的名称为 "default"
+func NewManager(configs map[string]Config) (*Manager, error) {
+	m := &Manager{dbs: make(map[string]*gorm.DB)}
+	for name, config := range configs {
+		db, err := Open(config)
+		if err != nil {
+			m.Close()
+			return nil, fmt.Errorf("db: 创建连接 %s 失败: %w", name, err)
+		}
+		m.dbs[name] = db
+	}
+	return m, nil
+}
+
+// Add 添加已创建的连接
+func (m *Manager) Add(name string, db *gorm.DB) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dbs[name] = db
+}
+
+// DB 返回指定名称的连接，不存在时返回 nil
+func (m *Manager) DB(name string) *gorm.DB {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.dbs[name]
+}
+
+// Default 返回默认连接
+func (m *Manager) Default() *gorm.DB {
+	return m.DB(DefaultName)
+}
+
+// Ping 检查全部连接，返回第一个失败连接的错误，可用于健康检查
+func (m *Manager) Ping(ctx context.Context) error {
+	m.mu.RLock()
+	names := make([]string, 0, len(m.dbs))
+	for name := range m.dbs {
+		names = append(names, name)
+	}
+	m.mu.RUnlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		sqlDB, err := m.DB(name).DB()
+		if err == nil {
+			err = sqlDB.PingContext(ctx)
+		}
+		if err != nil {
+			return fmt.Errorf("db: 连接 %s 不可用: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Stats 返回各连接的连接池统计，可通过 expvar 或指标系统导出
+func (m *Manager) Stats() map[string]sql.DBStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	stats := make(map[string]sql.DBStats, len(m.dbs))
+	for name, db := range m.dbs {
+		if sqlDB, err := db.DB(); err == nil {
+			stats[name] = sqlDB.Stats()
+		}
+	}
+	return stats
+}
+
+// Close 关闭全部连接
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var errs []error
+	for name, db := range m.dbs {
+		sqlDB, err := db.DB()
+		if err == nil {
+			err = sqlDB.Close()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("db: 关闭连接 %s 失败: %w", name, err))
+		}
+	}
+	m.dbs = make(map[string]*gorm.DB)
+	return errors.Join(errs...)
+}
+
+// Name 返回模块名称
+func (m *Manager) Name() string {
+	return "db"
+}
+
+// Start 检查全部连接是否可用
+func (m *Manager) Start(ctx context.Context) error {
+	return m.Ping(ctx)
+}
+
+// Stop 关闭全部连接
+func (m *Manager) Stop(context.Context) error {
+	return m.Close()
+}
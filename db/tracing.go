@@ -0,0 +1,74 @@
+package db
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// spanKey 是 span 在 GORM 语句中的键
+const spanKey = "easygo:span"
+
+// tracingPlugin 为每条 SQL 创建链路追踪 span，父 span 取自 db.WithContext 传入的上下文
+type tracingPlugin struct{}
+
+// Name 返回插件名称
+func (p *tracingPlugin) Name() string {
+	return "easygo:tracing"
+}
+
+// Initialize 注册回调
+func (p *tracingPlugin) Initialize(db *gorm.DB) error {
+	tracer := otel.Tracer("github.com/xzl-go/easygo/db")
+	cb := db.Callback()
+	callbacks := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+	for _, c := range callbacks {
+		operation := c.operation
+		before := func(tx *gorm.DB) {
+			ctx, span := tracer.Start(tx.Statement.Context, "db."+operation, trace.WithSpanKind(trace.SpanKindClient))
+			tx.Statement.Context = ctx
+			tx.InstanceSet(spanKey, span)
+		}
+		after := func(tx *gorm.DB) {
+			v, ok := tx.InstanceGet(spanKey)
+			if !ok {
+				return
+			}
+			span := v.(trace.Span)
+			defer span.End()
+			span.SetAttributes(
+				attribute.String("db.system", tx.Dialector.Name()),
+				attribute.String("db.operation", operation),
+				attribute.String("db.sql.table", tx.Statement.Table),
+				attribute.String("db.statement", tx.Statement.SQL.String()),
+				attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+			)
+			if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+				span.RecordError(tx.Error)
+				span.SetStatus(codes.Error, tx.Error.Error())
+			}
+		}
+		if err := c.before("easygo:before_"+operation, before); err != nil {
+			return err
+		}
+		if err := c.after("easygo:after_"+operation, after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+// Package election 提供了基于分布式锁的领导者选举
+// 多个副本竞争同一把锁，持有锁的副本成为领导者，负责执行缓存预热、定时调度等只能单实例运行的任务。
+// 锁可以是 lock.NewRedis 或 lock.NewEtcd，领导者失去锁（续期失败、网络分区）后自动退位并重新参与选举
+package election
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/xzl-go/easygo/lock"
+	"github.com/xzl-go/easygo/logger"
+)
+
+// Options 定义了选举配置
+type Options struct {
+	// OnElected 成为领导者时调用，ctx 在失去领导权或停止选举时取消
+	// 函数可以阻塞运行直到 ctx 取消，也可以启动任务后立即返回
+	OnElected func(ctx context.Context)
+	// OnRevoked 失去领导权时调用，此时 OnElected 已返回
+	OnRevoked func()
+	// RetryInterval 竞选出错后的重试间隔，默认 1 秒
+	RetryInterval time.Duration
+}
+
+// Elector 是领导者选举，实现了 app.Module，可通过 app.Register 接入应用生命周期
+type Elector struct {
+	locker  lock.Locker
+	name    string
+	options Options
+	leader  atomic.Bool
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New 创建领导者选举
+// locker: 分布式锁
+// name: 选举名称，同名的副本相互竞争
+// options: 选举配置
+func New(locker lock.Locker, name string, options Options) *Elector {
+	if options.RetryInterval <= 0 {
+		options.RetryInterval = time.Second
+	}
+	return &Elector{locker: locker, name: name, options: options}
+}
+
+// Name 返回模块名称
+func (e *Elector) Name() string {
+	return "election " + e.name
+}
+
+// IsLeader 返回当前副本是否为领导者
+func (e *Elector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Start 在后台开始参与选举，重复调用无效
+func (e *Elector) Start(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.cancel != nil {
+		return nil
+	}
+	runCtx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+	e.done = make(chan struct{})
+	go e.run(runCtx, e.done)
+	return nil
+}
+
+// Stop 停止选举，如果当前为领导者则等待 OnElected 返回并释放锁
+func (e *Elector) Stop(ctx context.Context) error {
+	e.mu.Lock()
+	cancel, done := e.cancel, e.done
+	e.cancel = nil
+	e.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run 循环竞选，直到 ctx 取消
+func (e *Elector) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	key := "election/" + e.name
+	for {
+		l, err := e.locker.Acquire(ctx, key)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Error("选举 %s 竞选失败：%v", e.name, err)
+			select {
+			case <-time.After(e.options.RetryInterval):
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+		e.lead(ctx, l)
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// lead 作为领导者运行，直到失去锁或停止选举
+func (e *Elector) lead(ctx context.Context, l lock.Lock) {
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	e.leader.Store(true)
+	logger.Info("选举 %s：当前副本成为领导者，令牌 %d", e.name, l.Token())
+
+	elected := make(chan struct{})
+	go func() {
+		defer close(elected)
+		if e.options.OnElected != nil {
+			e.options.OnElected(leaderCtx)
+		}
+	}()
+
+	select {
+	case <-l.Lost():
+		logger.Error("选举 %s：领导权已丢失", e.name)
+	case <-ctx.Done():
+	}
+	e.leader.Store(false)
+	cancel()
+	<-elected
+
+	releaseCtx, releaseCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer releaseCancel()
+	if err := l.Release(releaseCtx); err != nil && !errors.Is(err, lock.ErrLockLost) {
+		logger.Error("选举 %s：释放锁失败：%v", e.name, err)
+	}
+	if e.options.OnRevoked != nil {
+		e.options.OnRevoked()
+	}
+	logger.Info("选举 %s：当前副本已退位", e.name)
+}
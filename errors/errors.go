@@ -0,0 +1,128 @@
+// Package errors 提供了统一的业务错误类型和错误码体系
+// 每个错误包含业务错误码、国际化消息键和对应的 HTTP 状态码
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+)
+
+// Error 是带错误码的业务错误
+type Error struct {
+	Code    int       // 业务错误码
+	Key     string    // 国际化消息键
+	Message string    // 默认消息（未配置翻译时使用）
+	Status  int       // HTTP 状态码
+	cause   error     // 原始错误
+	stack   []uintptr // 包装时的调用栈
+}
+
+// New 创建一个业务错误
+// code: 业务错误码
+// key: 国际化消息键
+// status: HTTP 状态码
+// message: 默认消息
+func New(code int, key string, status int, message string) *Error {
+	return &Error{Code: code, Key: key, Status: status, Message: message}
+}
+
+// 预定义错误
+var (
+	ErrBadRequest      = New(40000, "error.bad_request", http.StatusBadRequest, "Bad request")
+	ErrValidation      = New(40001, "error.validation", http.StatusBadRequest, "Validation failed")
+	ErrUnauthorized    = New(40100, "error.unauthorized", http.StatusUnauthorized, "Unauthorized access")
+	ErrForbidden       = New(40300, "error.forbidden", http.StatusForbidden, "Access forbidden")
+	ErrNotFound        = New(40400, "error.not_found", http.StatusNotFound, "Requested resource not found")
+	ErrConflict        = New(40900, "error.conflict", http.StatusConflict, "Resource conflict")
+	ErrUnprocessable   = New(42200, "error.unprocessable", http.StatusUnprocessableEntity, "Unprocessable entity")
+	ErrTooManyRequests = New(42900, "error.too_many_requests", http.StatusTooManyRequests, "Too many requests")
+	ErrInternal        = New(50000, "error.internal", http.StatusInternalServerError, "Internal server error")
+)
+
+// Error 实现 error 接口
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("%d %s: %v", e.Code, e.Message, e.cause)
+	}
+	return fmt.Sprintf("%d %s", e.Code, e.Message)
+}
+
+// Unwrap 返回原始错误，支持 errors.Is / errors.As
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is 判断两个业务错误是否为同一错误码
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Wrap 包装原始错误并记录调用栈，返回新的业务错误，不修改预定义错误
+func (e *Error) Wrap(err error) *Error {
+	c := *e
+	c.cause = err
+	c.stack = callers(3)
+	return &c
+}
+
+// WithMessage 返回替换了默认消息的新业务错误
+func (e *Error) WithMessage(message string) *Error {
+	c := *e
+	c.Message = message
+	return &c
+}
+
+// Cause 返回原始错误
+func (e *Error) Cause() error {
+	return e.cause
+}
+
+// Stack 返回包装错误时记录的调用栈
+func (e *Error) Stack() string {
+	if len(e.stack) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&sb, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
+
+// callers 记录调用栈
+func callers(skip int) []uintptr {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(skip, pcs)
+	return pcs[:n]
+}
+
+// As 从错误链中提取业务错误
+func As(err error) (*Error, bool) {
+	var e *Error
+	if stderrors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
+
+// From 将任意错误转换为业务错误，未知错误视为内部错误
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := As(err); ok {
+		return e
+	}
+	c := ErrInternal.Wrap(err)
+	c.stack = callers(3)
+	return c
+}
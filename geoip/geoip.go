@@ -0,0 +1,149 @@
+// Package geoip 提供了基于 MaxMind 格式数据库（GeoLite2 / GeoIP2 / 兼容的 mmdb 文件）的 IP 地理位置查询，
+// 以及将客户端所在国家、地区、城市写入请求上下文的中间件，可用于本地化默认值、风控规则和地区封禁
+package geoip
+
+import (
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// ErrNotFound IP 不在数据库中（如内网地址）
+var ErrNotFound = errors.New("geoip: 未找到 IP 对应的位置")
+
+// Location 是 IP 对应的地理位置
+type Location struct {
+	IP          string  `json:"ip"`
+	CountryCode string  `json:"country_code"`          // ISO 3166-1 国家代码，例如 "CN"
+	Country     string  `json:"country"`               // 国家名称
+	RegionCode  string  `json:"region_code,omitempty"` // 一级行政区代码
+	Region      string  `json:"region,omitempty"`      // 一级行政区名称（省/州）
+	City        string  `json:"city,omitempty"`        // 城市名称，仅 City 库提供
+	TimeZone    string  `json:"time_zone,omitempty"`   // 时区，例如 "Asia/Shanghai"
+	Latitude    float64 `json:"latitude,omitempty"`
+	Longitude   float64 `json:"longitude,omitempty"`
+}
+
+// Lookuper 定义了 IP 地理位置查询，便于替换为在线查询服务或在测试中模拟
+type Lookuper interface {
+	Lookup(ip string) (*Location, error)
+}
+
+// record 是 mmdb 中 City / Country 库的记录结构
+type record struct {
+	Country struct {
+		IsoCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	Subdivisions []struct {
+		IsoCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"subdivisions"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Location struct {
+		TimeZone  string  `maxminddb:"time_zone"`
+		Latitude  float64 `maxminddb:"latitude"`
+		Longitude float64 `maxminddb:"longitude"`
+	} `maxminddb:"location"`
+}
+
+// DB 是 MaxMind 格式数据库，支持运行时重新加载
+type DB struct {
+	mu       sync.RWMutex
+	reader   *maxminddb.Reader
+	path     string
+	language string
+}
+
+// Open 打开数据库文件
+// path: mmdb 文件路径，City 库和 Country 库均可
+// language: 名称语言，例如 "zh-CN"、"en"，对应语言缺失时回退到英文
+func Open(path, language string) (*DB, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &DB{reader: reader, path: path, language: language}, nil
+}
+
+// FromBytes 从内存数据创建数据库，可配合 embed 使用
+// data: mmdb 文件内容
+// language: 名称语言
+func FromBytes(data []byte, language string) (*DB, error) {
+	reader, err := maxminddb.FromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	return &DB{reader: reader, language: language}, nil
+}
+
+// Reload 重新加载数据库文件，用于定期更新数据库后无需重启服务
+// 仅对通过 Open 打开的数据库有效
+func (db *DB) Reload() error {
+	if db.path == "" {
+		return errors.New("geoip: 数据库不是从文件加载的")
+	}
+	reader, err := maxminddb.Open(db.path)
+	if err != nil {
+		return err
+	}
+	db.mu.Lock()
+	old := db.reader
+	db.reader = reader
+	db.mu.Unlock()
+	return old.Close()
+}
+
+// Lookup 查询 IP 地理位置
+// ip: IPv4 或 IPv6 地址
+func (db *DB) Lookup(ip string) (*Location, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, errors.New("geoip: 无效的 IP 地址 " + ip)
+	}
+
+	var r record
+	db.mu.RLock()
+	_, found, err := db.reader.LookupNetwork(parsed, &r)
+	db.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrNotFound
+	}
+
+	loc := &Location{
+		IP:          ip,
+		CountryCode: r.Country.IsoCode,
+		Country:     db.name(r.Country.Names),
+		City:        db.name(r.City.Names),
+		TimeZone:    r.Location.TimeZone,
+		Latitude:    r.Location.Latitude,
+		Longitude:   r.Location.Longitude,
+	}
+	if len(r.Subdivisions) > 0 {
+		loc.RegionCode = r.Subdivisions[0].IsoCode
+		loc.Region = db.name(r.Subdivisions[0].Names)
+	}
+	return loc, nil
+}
+
+// Close 关闭数据库
+func (db *DB) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.reader.Close()
+}
+
+// name 按配置的语言取名称
+func (db *DB) name(names map[string]string) string {
+	if n, ok := names[db.language]; ok {
+		return n
+	}
+	return names["en"]
+}
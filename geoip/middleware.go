@@ -0,0 +1,83 @@
+package geoip
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/xzl-go/easygo/core"
+	errs "github.com/xzl-go/easygo/errors"
+)
+
+// ContextKey 是上下文中保存 *Location 的键
+const ContextKey = "geo"
+
+// ErrRegionBlocked 请求来自被封禁的国家或地区
+var ErrRegionBlocked = errs.New(40320, "error.geoip.blocked", http.StatusForbidden, "Access from your region is not allowed")
+
+// Options 定义了中间件配置
+type Options struct {
+	// Allow 允许访问的国家代码，不为空时仅允许列表中的国家访问
+	Allow []string
+	// Block 禁止访问的国家代码
+	Block []string
+	// AllowUnknown 配置了 Allow 时，是否放行无法识别位置的请求（如内网地址），默认拒绝
+	AllowUnknown bool
+	// BlockUnknown 只配置了 Block 时，是否拒绝无法识别位置的请求（如内网地址、查询失败），默认放行；
+	// 需要严格封禁时开启，避免无法识别的地址绕过封禁
+	BlockUnknown bool
+}
+
+// Middleware 返回地理位置中间件
+// 根据客户端 IP 查询位置并写入上下文键 geo，配置了 Allow / Block 时按国家代码拦截请求
+// 客户端 IP 取 Context.ClientIP，只采用 Engine.SetTrustedProxies 设置的可信代理转发的地址，
+// 客户端无法通过伪造 X-Forwarded-For 绕过封禁
+// lookuper: 地理位置查询
+// options: 中间件配置
+func Middleware(lookuper Lookuper, options Options) core.HandlerFunc {
+	allow := countrySet(options.Allow)
+	block := countrySet(options.Block)
+
+	return func(c *core.Context) {
+		loc, _ := lookuper.Lookup(c.ClientIP())
+		if loc != nil {
+			c.Set(ContextKey, loc)
+		}
+
+		if blocked(loc, allow, block, options) {
+			c.Fail(ErrRegionBlocked)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// FromContext 获取中间件写入上下文的位置，未识别时返回 nil
+func FromContext(c *core.Context) *Location {
+	loc, _ := c.Get(ContextKey).(*Location)
+	return loc
+}
+
+// blocked 判断是否拦截请求
+func blocked(loc *Location, allow, block map[string]bool, options Options) bool {
+	if loc == nil || loc.CountryCode == "" {
+		if len(allow) > 0 {
+			return !options.AllowUnknown
+		}
+		return len(block) > 0 && options.BlockUnknown
+	}
+	code := strings.ToUpper(loc.CountryCode)
+	if block[code] {
+		return true
+	}
+	return len(allow) > 0 && !allow[code]
+}
+
+// countrySet 将国家代码列表转换为集合
+func countrySet(codes []string) map[string]bool {
+	set := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		set[strings.ToUpper(code)] = true
+	}
+	return set
+}
@@ -0,0 +1,55 @@
+package geoip
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/xzl-go/easygo/core"
+)
+
+// staticLookuper 按 IP 返回固定位置
+type staticLookuper map[string]string
+
+func (l staticLookuper) Lookup(ip string) (*Location, error) {
+	if code, ok := l[ip]; ok {
+		return &Location{IP: ip, CountryCode: code}, nil
+	}
+	return nil, nil
+}
+
+func TestMiddleware(t *testing.T) {
+	lookuper := staticLookuper{"203.0.113.7": "KP", "198.51.100.9": "US"}
+	tests := []struct {
+		name    string
+		options Options
+		remote  string
+		xff     string
+		want    int
+	}{
+		{name: "封禁的国家", options: Options{Block: []string{"kp"}}, remote: "203.0.113.7", want: http.StatusForbidden},
+		{name: "伪造 X-Forwarded-For", options: Options{Block: []string{"KP"}}, remote: "203.0.113.7", xff: "10.0.0.1", want: http.StatusForbidden},
+		{name: "未知位置默认放行", options: Options{Block: []string{"KP"}}, remote: "10.0.0.1", want: http.StatusOK},
+		{name: "拒绝未知位置", options: Options{Block: []string{"KP"}, BlockUnknown: true}, remote: "10.0.0.1", want: http.StatusForbidden},
+		{name: "允许列表之外", options: Options{Allow: []string{"US"}}, remote: "203.0.113.7", want: http.StatusForbidden},
+		{name: "允许列表", options: Options{Allow: []string{"US"}}, remote: "198.51.100.9", want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := core.New()
+			app.Use(Middleware(lookuper, tt.options))
+			app.GET("/", func(c *core.Context) { c.String(http.StatusOK, "ok") })
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remote + ":1234"
+			if tt.xff != "" {
+				r.Header.Set("X-Forwarded-For", tt.xff)
+			}
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Errorf("状态码 %d，期望 %d", w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+// Package health 提供了存活和就绪检查
+// 数据库、Redis、定时任务等子系统以 Checker 的形式注册检查项，
+// 检查结果汇总为 JSON 报告，供 Kubernetes 探针和负载均衡器使用
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout 是单个检查项的默认超时时间
+const DefaultTimeout = 3 * time.Second
+
+// Status 是检查状态
+type Status string
+
+// 检查状态
+const (
+	StatusUp   Status = "up"   // 正常
+	StatusDown Status = "down" // 异常
+)
+
+// Checker 定义了检查项
+type Checker interface {
+	// Check 执行检查，返回 nil 表示正常，ctx 携带检查超时
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc 将函数适配为 Checker，例如 health.CheckerFunc(dbManager.Ping)
+type CheckerFunc func(ctx context.Context) error
+
+// Check 调用 f(ctx)
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// CheckResult 是单个检查项的结果
+type CheckResult struct {
+	Status   Status `json:"status"`          // 检查状态
+	Duration string `json:"duration"`        // 检查耗时
+	Error    string `json:"error,omitempty"` // 失败原因
+}
+
+// Report 是汇总的检查报告，任一检查项异常时整体状态为 down
+type Report struct {
+	Status Status                 `json:"status"`           // 整体状态
+	Checks map[string]CheckResult `json:"checks,omitempty"` // 各检查项的结果
+}
+
+// Up 返回整体状态是否正常
+func (r Report) Up() bool {
+	return r.Status == StatusUp
+}
+
+// Health 管理存活检查和就绪检查
+// 存活检查失败表示进程需要重启，应只检查进程自身（如死锁检测），不要依赖外部服务；
+// 就绪检查失败表示暂时不能接收流量，通常检查数据库、Redis 等依赖
+type Health struct {
+	mu        sync.RWMutex
+	timeout   time.Duration
+	liveness  map[string]Checker
+	readiness map[string]Checker
+}
+
+// New 创建检查管理器
+// timeout: 单个检查项的超时时间，为 0 时使用 DefaultTimeout
+func New(timeout time.Duration) *Health {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Health{
+		timeout:   timeout,
+		liveness:  make(map[string]Checker),
+		readiness: make(map[string]Checker),
+	}
+}
+
+// AddLivenessCheck 添加存活检查项，同名检查项会被替换
+// name: 检查项名称
+// checker: 检查项
+func (h *Health) AddLivenessCheck(name string, checker Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.liveness[name] = checker
+}
+
+// AddReadinessCheck 添加就绪检查项，同名检查项会被替换
+// name: 检查项名称，例如 "db"、"redis"
+// checker: 检查项
+func (h *Health) AddReadinessCheck(name string, checker Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.readiness[name] = checker
+}
+
+// RemoveCheck 移除存活和就绪检查项
+func (h *Health) RemoveCheck(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.liveness, name)
+	delete(h.readiness, name)
+}
+
+// Liveness 执行全部存活检查
+func (h *Health) Liveness(ctx context.Context) Report {
+	return h.run(ctx, h.checkers(h.liveness))
+}
+
+// Readiness 执行全部就绪检查
+func (h *Health) Readiness(ctx context.Context) Report {
+	return h.run(ctx, h.checkers(h.readiness))
+}
+
+// checkers 复制检查项，避免执行检查时持有锁
+func (h *Health) checkers(m map[string]Checker) map[string]Checker {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	checkers := make(map[string]Checker, len(m))
+	for name, checker := range m {
+		checkers[name] = checker
+	}
+	return checkers
+}
+
+// run 并发执行检查项并汇总结果，单个检查项超时或 panic 视为异常
+func (h *Health) run(ctx context.Context, checkers map[string]Checker) Report {
+	report := Report{Status: StatusUp, Checks: make(map[string]CheckResult, len(checkers))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, checker := range checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := h.check(ctx, checker)
+			mu.Lock()
+			report.Checks[name] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	for _, result := range report.Checks {
+		if result.Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+// check 在超时时间内执行单个检查项
+func (h *Health) check(ctx context.Context, checker Checker) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("health: 检查项 panic: %v", r)
+			}
+		}()
+		done <- checker.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		// 不响应 ctx 的检查项不会阻塞探针
+		err = ctx.Err()
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("health: 检查超过 %s", h.timeout)
+		}
+	}
+
+	result := CheckResult{Status: StatusUp, Duration: time.Since(start).Round(time.Microsecond).String()}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,107 @@
+package httpclient
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen 表示熔断器处于打开状态，请求未发出
+var ErrCircuitOpen = errors.New("httpclient: 熔断器已打开")
+
+// Breaker 定义了熔断器钩子，可接入 sony/gobreaker 等实现
+type Breaker interface {
+	// Allow 在每次尝试前调用，返回错误时不发出请求，通常返回 ErrCircuitOpen
+	Allow(host string) error
+	// Report 在每次尝试后调用，err 为 nil 表示成功；网络错误和 5xx 响应作为失败上报
+	Report(host string, err error)
+}
+
+// 熔断器状态
+const (
+	stateClosed   = iota // 关闭：正常放行
+	stateOpen            // 打开：拒绝请求
+	stateHalfOpen        // 半开：放行一个探测请求
+)
+
+// hostState 是单个主机的熔断状态
+type hostState struct {
+	state    int
+	failures int
+	openedAt time.Time
+}
+
+// ConsecutiveBreaker 是按主机统计连续失败次数的熔断器
+// 连续失败达到阈值后打开，冷却时间过后放行一个探测请求，探测成功则关闭，失败则重新打开
+type ConsecutiveBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+}
+
+// NewBreaker 创建按连续失败次数熔断的熔断器
+// threshold: 连续失败多少次后打开，默认 5
+// cooldown: 打开后多久放行探测请求，默认 30 秒
+func NewBreaker(threshold int, cooldown time.Duration) *ConsecutiveBreaker {
+	if threshold <= 0 {
+		threshold = 5
+	}
+	if cooldown <= 0 {
+		cooldown = 30 * time.Second
+	}
+	return &ConsecutiveBreaker{threshold: threshold, cooldown: cooldown, hosts: make(map[string]*hostState)}
+}
+
+// Allow 返回是否允许向主机发送请求
+func (b *ConsecutiveBreaker) Allow(host string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := b.hosts[host]
+	if s == nil {
+		return nil
+	}
+	switch s.state {
+	case stateOpen:
+		if time.Since(s.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		s.state = stateHalfOpen
+		return nil
+	case stateHalfOpen:
+		// 探测请求尚未返回
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+// Report 记录请求结果
+func (b *ConsecutiveBreaker) Report(host string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := b.hosts[host]
+	if err == nil {
+		if s != nil {
+			delete(b.hosts, host)
+		}
+		return
+	}
+	if s == nil {
+		s = &hostState{}
+		b.hosts[host] = s
+	}
+	s.failures++
+	if s.state == stateHalfOpen || s.failures >= b.threshold {
+		s.state = stateOpen
+		s.openedAt = time.Now()
+	}
+}
+
+// Open 返回主机的熔断器是否处于打开状态
+func (b *ConsecutiveBreaker) Open(host string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := b.hosts[host]
+	return s != nil && s.state != stateClosed
+}
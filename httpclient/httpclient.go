@@ -0,0 +1,292 @@
+// Package httpclient 提供了带链路追踪、重试和熔断的 HTTP 客户端
+// 请求自动注入 W3C traceparent 和 baggage 请求头并记录客户端跨度，EasyGo 服务之间的调用会串成一条调用链
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName 是客户端跨度使用的追踪器名称
+const tracerName = "github.com/xzl-go/easygo/httpclient"
+
+// BackoffFunc 根据已重试次数计算下次重试的等待时间
+type BackoffFunc func(retry int) time.Duration
+
+// ExponentialBackoff 返回带完全抖动的指数退避函数，等待时间在 0 到 base * 2^(retry-1) 之间随机，上限为 max
+// 随机抖动使多个客户端的重试错开，避免同时冲击刚恢复的服务
+func ExponentialBackoff(base, max time.Duration) BackoffFunc {
+	return func(retry int) time.Duration {
+		d := base
+		for i := 1; i < retry && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		if d <= 0 {
+			return 0
+		}
+		return rand.N(d) + 1
+	}
+}
+
+// RetryPolicy 判断一次尝试的结果是否需要重试，resp 和 err 中只有一个不为 nil
+type RetryPolicy func(resp *http.Response, err error) bool
+
+// DefaultRetryPolicy 在网络错误、429 和 502/503/504 时重试，调用方取消请求时不重试
+func DefaultRetryPolicy(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, ErrCircuitOpen)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// Options 定义了 HTTP 客户端配置
+type Options struct {
+	// Timeout 单次尝试的超时时间，默认 10 秒，可通过 WithTimeout 为单个请求覆盖
+	Timeout time.Duration
+	// MaxRetries 最多重试次数，默认 0 不重试，可通过 WithRetries 为单个请求覆盖
+	MaxRetries int
+	// Backoff 重试等待时间，默认 ExponentialBackoff(100ms, 2s)；响应带 Retry-After 时优先使用
+	Backoff BackoffFunc
+	// RetryPolicy 判断是否重试，默认 DefaultRetryPolicy
+	RetryPolicy RetryPolicy
+	// RetryNonIdempotent 是否重试 POST、PATCH 等非幂等请求，默认只重试幂等方法和带 Idempotency-Key 请求头的请求
+	RetryNonIdempotent bool
+	// Breaker 熔断器，为 nil 时不熔断
+	Breaker Breaker
+	// Transport 底层传输，默认 http.DefaultTransport
+	Transport http.RoundTripper
+	// TracerProvider 创建客户端跨度的追踪器提供者，默认使用全局追踪器提供者
+	TracerProvider trace.TracerProvider
+	// Propagator 注入追踪上下文的传播器，默认为 W3C traceparent 和 baggage
+	Propagator propagation.TextMapPropagator
+}
+
+// New 创建 HTTP 客户端，客户端的 Timeout 为 0，总耗时由请求的 context 控制
+// options: 客户端配置
+func New(options Options) *http.Client {
+	return &http.Client{Transport: NewTransport(options)}
+}
+
+// Transport 是带链路追踪、重试和熔断的 http.RoundTripper
+type Transport struct {
+	options Options
+	tracer  trace.Tracer
+}
+
+// NewTransport 创建传输，可用于替换已有客户端的 Transport
+// options: 客户端配置
+func NewTransport(options Options) *Transport {
+	if options.Timeout <= 0 {
+		options.Timeout = 10 * time.Second
+	}
+	if options.Backoff == nil {
+		options.Backoff = ExponentialBackoff(100*time.Millisecond, 2*time.Second)
+	}
+	if options.RetryPolicy == nil {
+		options.RetryPolicy = DefaultRetryPolicy
+	}
+	if options.Transport == nil {
+		options.Transport = http.DefaultTransport
+	}
+	if options.TracerProvider == nil {
+		options.TracerProvider = otel.GetTracerProvider()
+	}
+	if options.Propagator == nil {
+		options.Propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	}
+	return &Transport{options: options, tracer: options.TracerProvider.Tracer(tracerName)}
+}
+
+// RoundTrip 发送请求，按重试策略重试，每次尝试记录一个客户端跨度
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	maxRetries := t.options.MaxRetries
+	if n, ok := ctx.Value(retriesKey{}).(int); ok {
+		maxRetries = n
+	}
+	if !t.retryable(req) {
+		maxRetries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+		resp, err := t.attempt(req, attempt)
+		if attempt >= maxRetries || !t.options.RetryPolicy(resp, err) {
+			return resp, err
+		}
+
+		wait := t.options.Backoff(attempt + 1)
+		if resp != nil {
+			if d, ok := retryAfter(resp); ok {
+				wait = d
+			}
+			// 丢弃响应体以复用连接
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryable 判断请求是否可以重试：请求体可以重放，且方法幂等或带有幂等键
+func (t *Transport) retryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if t.options.RetryNonIdempotent {
+		return true
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != "" || req.Header.Get("X-Idempotency-Key") != ""
+}
+
+// attempt 执行一次尝试：检查熔断器、创建客户端跨度、注入追踪上下文并设置单次超时
+func (t *Transport) attempt(req *http.Request, attempt int) (*http.Response, error) {
+	host := req.URL.Host
+	if b := t.options.Breaker; b != nil {
+		if err := b.Allow(host); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, span := t.tracer.Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(t.attributes(req, attempt)...),
+	)
+	timeout := t.options.Timeout
+	if d, ok := ctx.Value(timeoutKey{}).(time.Duration); ok {
+		timeout = d
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+
+	out := req.Clone(ctx)
+	t.options.Propagator.Inject(ctx, propagation.HeaderCarrier(out.Header))
+	resp, err := t.options.Transport.RoundTrip(out)
+
+	if b := t.options.Breaker; b != nil {
+		failure := err
+		if err == nil && resp.StatusCode >= http.StatusInternalServerError {
+			failure = fmt.Errorf("httpclient: %s 返回 %s", host, resp.Status)
+		}
+		b.Report(host, failure)
+	}
+
+	if err != nil {
+		cancel()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(semconv.ErrorTypeKey.String(fmt.Sprintf("%T", err)))
+		span.End()
+		return nil, err
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	span.End()
+	// 单次超时的 context 需要在读完响应体后才能取消
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// attributes 返回客户端跨度的语义属性，URL 中的用户名和密码不会被记录
+func (t *Transport) attributes(req *http.Request, attempt int) []attribute.KeyValue {
+	u := *req.URL
+	u.User = nil
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.URLFull(u.String()),
+		semconv.ServerAddress(req.URL.Hostname()),
+	}
+	if port, err := strconv.Atoi(req.URL.Port()); err == nil {
+		attrs = append(attrs, semconv.ServerPort(port))
+	}
+	if attempt > 0 {
+		attrs = append(attrs, attribute.Int("http.resend_count", attempt))
+	}
+	return attrs
+}
+
+// retryAfter 解析 Retry-After 响应头，支持秒数和 HTTP 日期
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// cancelBody 在关闭响应体时取消单次尝试的 context
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close 关闭响应体并取消 context
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// timeoutKey 是单个请求超时时间在 context 中的键
+type timeoutKey struct{}
+
+// retriesKey 是单个请求重试次数在 context 中的键
+type retriesKey struct{}
+
+// WithTimeout 为单个请求设置每次尝试的超时时间，覆盖 Options.Timeout
+// ctx: 请求的 context
+// timeout: 单次尝试的超时时间
+func WithTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, timeoutKey{}, timeout)
+}
+
+// WithRetries 为单个请求设置最多重试次数，覆盖 Options.MaxRetries
+// ctx: 请求的 context
+// retries: 最多重试次数，0 表示不重试
+func WithRetries(ctx context.Context, retries int) context.Context {
+	return context.WithValue(ctx, retriesKey{}, retries)
+}
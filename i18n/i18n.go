@@ -2,76 +2,221 @@ package i18n
 
 import (
 	"encoding/json"
-	"os"
-	"path/filepath"
+	"fmt"
 	"strings"
+	"sync"
 
-	"github.com/xzl-go/easygo/core"
+	"github.com/fsnotify/fsnotify"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
 )
 
+// Params 是翻译参数，替换译文中的 {name} 占位符；count 参数同时用于选择复数形式
+type Params map[string]interface{}
+
 // I18n 国际化管理器
+// 译文来自按添加顺序合并的加载器（LoadTranslations 加载的目录也是一个加载器）和 AddTranslations 添加的译文，
+// 重新加载时重建全部译文后整体替换，查找译文不受影响
 type I18n struct {
+	mu           sync.RWMutex
 	translations map[string]map[string]string
+	langs        map[string]string // 规范化的语言（小写、以 - 分隔）到已加载语言名的映射
 	defaultLang  string
+
+	sourceMu sync.Mutex
+	sources  []*source                    // 译文加载器
+	static   map[string]map[string]string // AddTranslations 添加的译文
+
+	done      chan struct{} // 关闭后停止定时刷新和目录监听
+	closeOnce sync.Once
+	watchers  []*fsnotify.Watcher
 }
 
 // New 创建新的国际化管理器
 func New(defaultLang string) *I18n {
 	return &I18n{
 		translations: make(map[string]map[string]string),
+		langs:        make(map[string]string),
 		defaultLang:  defaultLang,
+		static:       make(map[string]map[string]string),
+		done:         make(chan struct{}),
 	}
 }
 
-// LoadTranslations 加载翻译文件
+// LoadTranslations 加载翻译文件，支持 .json、.yaml、.yml 和 .toml
+// 文件名为语言，例如 zh.json、en.yaml；嵌套的键展开为点分隔的键，例如 {"error": {"auth": "..."}} 对应 error.auth；
+// 同一语言的多个文件合并；调用 Reload 或 Watch 时重新读取该目录
 func (i *I18n) LoadTranslations(dir string) error {
-	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if info.IsDir() || !strings.HasSuffix(path, ".json") {
-			return nil
-		}
+	return i.AddLoader(NewDirLoader(dir), LoaderOptions{})
+}
 
-		lang := strings.TrimSuffix(filepath.Base(path), ".json")
-		data, err := os.ReadFile(path)
-		if err != nil {
-			return err
-		}
+// AddTranslations 添加一种语言的译文，与已有的译文合并，优先于加载器加载的同名键，重新加载时保留
+// lang: 语言
+// messages: 译文，值可以是字符串或嵌套的 map
+func (i *I18n) AddTranslations(lang string, messages map[string]interface{}) {
+	i.sourceMu.Lock()
+	defer i.sourceMu.Unlock()
+	translations, ok := i.static[lang]
+	if !ok {
+		translations = make(map[string]string)
+		i.static[lang] = translations
+	}
+	flatten("", messages, translations)
+	i.rebuild()
+}
 
-		var translations map[string]string
-		if err := json.Unmarshal(data, &translations); err != nil {
-			return err
+// rebuild 按顺序合并各加载器和 AddTranslations 的译文并整体替换，调用方需持有 sourceMu
+func (i *I18n) rebuild() {
+	translations := make(map[string]map[string]string)
+	langs := make(map[string]string)
+	merge := func(set map[string]map[string]string) {
+		for lang, messages := range set {
+			merged, ok := translations[lang]
+			if !ok {
+				merged = make(map[string]string, len(messages))
+				translations[lang] = merged
+				langs[normalizeLang(lang)] = lang
+			}
+			for k, v := range messages {
+				merged[k] = v
+			}
 		}
+	}
+	for _, s := range i.sources {
+		merge(s.translations)
+	}
+	merge(i.static)
+
+	i.mu.Lock()
+	i.translations, i.langs = translations, langs
+	i.mu.Unlock()
+}
+
+// isTranslationFile 判断是否为支持的翻译文件格式
+func isTranslationFile(ext string) bool {
+	switch ext {
+	case ".json", ".yaml", ".yml", ".toml":
+		return true
+	}
+	return false
+}
+
+// parseTranslations 按文件格式解析译文
+func parseTranslations(data []byte, ext string) (map[string]interface{}, error) {
+	var messages map[string]interface{}
+	var err error
+	switch ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &messages)
+	case ".toml":
+		err = toml.Unmarshal(data, &messages)
+	default:
+		err = json.Unmarshal(data, &messages)
+	}
+	return messages, err
+}
 
-		i.translations[lang] = translations
-		return nil
-	})
+// flatten 将嵌套的译文展开为点分隔的键
+func flatten(prefix string, messages map[string]interface{}, out map[string]string) {
+	for k, v := range messages {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch value := v.(type) {
+		case map[string]interface{}:
+			flatten(key, value, out)
+		case nil:
+		default:
+			out[key] = fmt.Sprint(value)
+		}
+	}
 }
 
-// Translate 获取翻译
+// Translate 获取翻译，当前语言和默认语言都没有译文时返回键本身
 func (i *I18n) Translate(key, lang string) string {
-	if translations, ok := i.translations[lang]; ok {
-		if translation, ok := translations[key]; ok {
-			return translation
+	if translation, ok := i.lookup(lang, key); ok {
+		return translation
+	}
+	return key
+}
+
+// Localize 翻译消息键，实现 core.Localizer
+// args 为单个 Params 时同 TranslateWith，否则按 fmt.Sprintf 格式化译文
+// key: 消息键
+// lang: 语言
+// args: 翻译参数
+func (i *I18n) Localize(key, lang string, args ...interface{}) string {
+	if len(args) == 1 {
+		switch params := args[0].(type) {
+		case Params:
+			return i.TranslateWith(key, lang, params)
+		case map[string]interface{}:
+			return i.TranslateWith(key, lang, params)
 		}
 	}
-	if translations, ok := i.translations[i.defaultLang]; ok {
-		if translation, ok := translations[key]; ok {
-			return translation
+	message := i.Translate(key, lang)
+	// 没有译文时返回消息键本身，不做格式化
+	if len(args) > 0 && message != key {
+		message = fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+// TranslateWith 获取翻译并替换参数，例如 "你好，{name}" 配合 Params{"name": "Bob"}
+// 参数中有 count 时按语言的复数规则选择译文：依次查找 key.zero（count 为 0 时）、key.<类别>、key.other、key，
+// 类别为 CLDR 复数类别 zero、one、two、few、many、other，例如：
+//
+//	{"cart": {"items": {"zero": "购物车是空的", "other": "{count} 件商品"}}}
+//
+// key: 翻译键
+// lang: 语言
+// params: 参数，可为 nil
+func (i *I18n) TranslateWith(key, lang string, params Params) string {
+	keys := []string{key}
+	if count, ok := params["count"]; ok {
+		if n, ok := toInt(count); ok {
+			keys = []string{key + "." + PluralCategory(lang, n), key + ".other", key}
+			if n == 0 {
+				keys = append([]string{key + ".zero"}, keys...)
+			}
 		}
 	}
-	return key
+	translation, ok := i.lookup(lang, keys...)
+	if !ok {
+		translation = key
+	}
+	return interpolate(translation, params)
 }
 
-// Middleware 创建国际化中间件
-func (i *I18n) Middleware() core.HandlerFunc {
-	return func(c *core.Context) {
-		lang := c.GetHeader("Accept-Language")
-		if lang == "" {
-			lang = i.defaultLang
+// lookup 按回退链查找译文：当前语言（如 zh-CN）、基础语言（zh）、默认语言，每种语言中按 keys 的顺序查找
+func (i *I18n) lookup(lang string, keys ...string) (string, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	normalized := normalizeLang(lang)
+	base, _, _ := strings.Cut(normalized, "-")
+	for _, l := range []string{normalized, base, normalizeLang(i.defaultLang)} {
+		translations, ok := i.translations[i.langs[l]]
+		if !ok {
+			continue
+		}
+		for _, key := range keys {
+			if translation, ok := translations[key]; ok {
+				return translation, true
+			}
 		}
-		c.Set("lang", lang)
-		c.Next()
 	}
+	return "", false
+}
+
+// interpolate 将译文中的 {name} 替换为参数值
+func interpolate(s string, params Params) string {
+	if len(params) == 0 || !strings.Contains(s, "{") {
+		return s
+	}
+	pairs := make([]string, 0, len(params)*2)
+	for k, v := range params {
+		pairs = append(pairs, "{"+k+"}", fmt.Sprint(v))
+	}
+	return strings.NewReplacer(pairs...).Replace(s)
 }
@@ -0,0 +1,184 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/xzl-go/easygo/logger"
+)
+
+// TranslationLoader 定义了译文来源，例如翻译文件目录、数据库或远程翻译服务
+// 加载结果缓存在内存中，查找译文不会调用加载器，只在添加、刷新和 Reload 时重新加载
+type TranslationLoader interface {
+	// Load 返回各语言的译文，键为语言，值可以是字符串或嵌套的 map
+	Load(ctx context.Context) (map[string]map[string]interface{}, error)
+}
+
+// LoaderFunc 将函数适配为 TranslationLoader
+type LoaderFunc func(ctx context.Context) (map[string]map[string]interface{}, error)
+
+// Load 调用 f(ctx)
+func (f LoaderFunc) Load(ctx context.Context) (map[string]map[string]interface{}, error) {
+	return f(ctx)
+}
+
+// LoaderOptions 定义了加载器选项
+type LoaderOptions struct {
+	// Refresh 定时重新加载的间隔，为 0 时只在 Reload 时重新加载
+	Refresh time.Duration
+	// Timeout 单次加载的超时时间，默认 10 秒
+	Timeout time.Duration
+}
+
+// source 是一个加载器及其最近一次成功加载的译文
+type source struct {
+	loader       TranslationLoader
+	options      LoaderOptions
+	translations map[string]map[string]string
+}
+
+// AddLoader 添加译文加载器并立即加载，加载失败时不添加
+// 多个加载器按添加顺序合并，后添加的覆盖先添加的同名键；重新加载失败时保留该加载器上一次的译文
+// loader: 译文加载器
+// options: 加载器选项
+func (i *I18n) AddLoader(loader TranslationLoader, options LoaderOptions) error {
+	if options.Timeout <= 0 {
+		options.Timeout = 10 * time.Second
+	}
+	s := &source{loader: loader, options: options}
+	translations, err := s.load(context.Background())
+	if err != nil {
+		return err
+	}
+
+	i.sourceMu.Lock()
+	s.translations = translations
+	i.sources = append(i.sources, s)
+	i.rebuild()
+	i.sourceMu.Unlock()
+
+	if options.Refresh > 0 {
+		go i.refresh(s)
+	}
+	return nil
+}
+
+// Reload 重新加载全部加载器并整体替换译文，返回各加载器的加载错误
+// ctx: 上下文，取消后未完成的加载器保留上一次的译文
+func (i *I18n) Reload(ctx context.Context) error {
+	i.sourceMu.Lock()
+	sources := make([]*source, len(i.sources))
+	copy(sources, i.sources)
+	i.sourceMu.Unlock()
+	return i.reload(ctx, sources)
+}
+
+// reload 重新加载指定的加载器，加载在锁外进行，避免慢速的远程加载器阻塞其他操作
+func (i *I18n) reload(ctx context.Context, sources []*source) error {
+	var errs []error
+	results := make(map[*source]map[string]map[string]string, len(sources))
+	for _, s := range sources {
+		translations, err := s.load(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		results[s] = translations
+	}
+
+	i.sourceMu.Lock()
+	defer i.sourceMu.Unlock()
+	for s, translations := range results {
+		s.translations = translations
+	}
+	if len(results) > 0 {
+		i.rebuild()
+	}
+	return errors.Join(errs...)
+}
+
+// refresh 定时重新加载加载器，直到管理器关闭
+func (i *I18n) refresh(s *source) {
+	ticker := time.NewTicker(s.options.Refresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-i.done:
+			return
+		case <-ticker.C:
+			if err := i.reload(context.Background(), []*source{s}); err != nil {
+				logger.Module("i18n").Warn("[I18n] 刷新译文失败，继续使用旧译文：%v", err)
+			}
+		}
+	}
+}
+
+// load 调用加载器并展开嵌套的键
+func (s *source) load(ctx context.Context) (map[string]map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.options.Timeout)
+	defer cancel()
+	messages, err := s.loader.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	translations := make(map[string]map[string]string, len(messages))
+	for lang, m := range messages {
+		flat := make(map[string]string)
+		flatten("", m, flat)
+		translations[lang] = flat
+	}
+	return translations, nil
+}
+
+// DirLoader 从目录加载翻译文件，文件名为语言，支持 .json、.yaml、.yml 和 .toml，包括子目录中的文件
+type DirLoader struct {
+	dir string
+}
+
+// NewDirLoader 创建翻译文件目录加载器
+// dir: 翻译文件目录
+func NewDirLoader(dir string) *DirLoader {
+	return &DirLoader{dir: filepath.Clean(dir)}
+}
+
+// Load 读取目录中的翻译文件，同一语言的多个文件合并
+func (l *DirLoader) Load(ctx context.Context) (map[string]map[string]interface{}, error) {
+	result := make(map[string]map[string]interface{})
+	err := filepath.Walk(l.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := filepath.Ext(path)
+		if info.IsDir() || !isTranslationFile(ext) {
+			return nil
+		}
+
+		lang := strings.TrimSuffix(filepath.Base(path), ext)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		messages, err := parseTranslations(data, ext)
+		if err != nil {
+			return fmt.Errorf("i18n: 无法解析翻译文件 %s: %w", path, err)
+		}
+		// 先展开再合并，不同文件中同一嵌套对象下的键不会相互覆盖
+		flat := make(map[string]string)
+		flatten("", messages, flat)
+		merged, ok := result[lang]
+		if !ok {
+			merged = make(map[string]interface{}, len(flat))
+			result[lang] = merged
+		}
+		for k, v := range flat {
+			merged[k] = v
+		}
+		return nil
+	})
+	return result, err
+}
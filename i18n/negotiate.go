@@ -0,0 +1,142 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xzl-go/easygo/core"
+)
+
+// MiddlewareConfig 定义了国际化中间件配置
+type MiddlewareConfig struct {
+	// QueryParam 指定语言的查询参数，例如 ?lang=en，默认 "lang"，为 "-" 时不读取
+	QueryParam string
+	// CookieName 指定语言的 Cookie，默认 "lang"，为 "-" 时不读取
+	CookieName string
+}
+
+// Middleware 创建国际化中间件
+// 按查询参数 lang、Cookie lang、Accept-Language 请求头的顺序协商语言，
+// 将匹配到的已加载语言写入上下文键 lang 和 Content-Language 响应头，都不匹配时使用默认语言；
+// 同时将管理器绑定到上下文，处理函数可以直接使用 c.T 和 c.JSONError
+func (i *I18n) Middleware() core.HandlerFunc {
+	return i.MiddlewareWithConfig(MiddlewareConfig{})
+}
+
+// MiddlewareWithConfig 按配置创建国际化中间件
+// config: 中间件配置
+func (i *I18n) MiddlewareWithConfig(config MiddlewareConfig) core.HandlerFunc {
+	if config.QueryParam == "" {
+		config.QueryParam = "lang"
+	}
+	if config.CookieName == "" {
+		config.CookieName = "lang"
+	}
+	return func(c *core.Context) {
+		var candidates []string
+		if config.QueryParam != "-" {
+			if lang := c.Query(config.QueryParam); lang != "" {
+				candidates = append(candidates, lang)
+			}
+		}
+		if config.CookieName != "-" {
+			if cookie, err := c.Request.Cookie(config.CookieName); err == nil && cookie.Value != "" {
+				candidates = append(candidates, cookie.Value)
+			}
+		}
+		candidates = append(candidates, ParseAcceptLanguage(c.GetHeader("Accept-Language"))...)
+
+		lang := i.Match(candidates...)
+		c.Set(core.LangKey, lang)
+		c.Set(core.TranslatorKey, i)
+		c.Writer.Header().Set("Content-Language", lang)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		c.Next()
+	}
+}
+
+// Match 返回第一个可用的语言：已加载语言中与候选语言相同的（不区分大小写），
+// 其次是候选语言的基础语言（zh-CN 匹配 zh）或同一基础语言的区域变体（zh 匹配 zh-CN），都不匹配时返回默认语言
+// candidates: 按优先级排列的候选语言
+func (i *I18n) Match(candidates ...string) string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	for _, candidate := range candidates {
+		normalized := normalizeLang(candidate)
+		if lang, ok := i.langs[normalized]; ok {
+			return lang
+		}
+		base, _, _ := strings.Cut(normalized, "-")
+		if lang, ok := i.langs[base]; ok {
+			return lang
+		}
+		// 按名称排序，保证同一基础语言有多个区域变体时结果稳定
+		variants := make([]string, 0)
+		for l := range i.langs {
+			if strings.HasPrefix(l, base+"-") {
+				variants = append(variants, l)
+			}
+		}
+		if len(variants) > 0 {
+			sort.Strings(variants)
+			return i.langs[variants[0]]
+		}
+	}
+	return i.defaultLang
+}
+
+// Languages 返回已加载的语言，按名称排序
+func (i *I18n) Languages() []string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	langs := make([]string, 0, len(i.translations))
+	for lang := range i.translations {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// ParseAcceptLanguage 解析 Accept-Language 请求头，返回按质量值从高到低排列的语言，
+// 质量值相同时保持原顺序，忽略 q=0 和通配符 *
+// 例如 "zh-CN,zh;q=0.9,en;q=0.8" 返回 ["zh-CN", "zh", "en"]
+// header: 请求头的值
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		lang string
+		q    float64
+	}
+	var langs []weighted
+	for _, part := range strings.Split(header, ",") {
+		lang, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang = strings.TrimSpace(lang)
+		if lang == "" || lang == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.EqualFold(strings.TrimSpace(name), "q") {
+				if v, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		langs = append(langs, weighted{lang: lang, q: q})
+	}
+	sort.SliceStable(langs, func(a, b int) bool { return langs[a].q > langs[b].q })
+	result := make([]string, len(langs))
+	for idx, l := range langs {
+		result[idx] = l.lang
+	}
+	return result
+}
+
+// normalizeLang 将语言规范化为小写、以 - 分隔的形式，例如 zh_CN 规范化为 zh-cn
+func normalizeLang(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
@@ -0,0 +1,178 @@
+package i18n
+
+import (
+	"strings"
+	"sync"
+)
+
+// 复数类别，取自 CLDR
+const (
+	PluralZero  = "zero"
+	PluralOne   = "one"
+	PluralTwo   = "two"
+	PluralFew   = "few"
+	PluralMany  = "many"
+	PluralOther = "other"
+)
+
+// PluralRule 根据数量返回复数类别
+type PluralRule func(n int) string
+
+var (
+	pluralMu    sync.RWMutex
+	pluralRules = map[string]PluralRule{}
+)
+
+func init() {
+	for _, lang := range []string{"zh", "ja", "ko", "vi", "th", "id", "ms"} {
+		pluralRules[lang] = pluralNone
+	}
+	for _, lang := range []string{"en", "de", "nl", "sv", "da", "no", "nb", "fi", "it", "es", "el", "hu", "tr", "bg"} {
+		pluralRules[lang] = pluralOneOther
+	}
+	pluralRules["fr"] = pluralFrench
+	pluralRules["pt"] = pluralFrench
+	pluralRules["ru"] = pluralSlavic
+	pluralRules["uk"] = pluralSlavic
+	pluralRules["be"] = pluralSlavic
+	pluralRules["pl"] = pluralPolish
+	pluralRules["cs"] = pluralCzech
+	pluralRules["sk"] = pluralCzech
+	pluralRules["ar"] = pluralArabic
+}
+
+// RegisterPluralRule 注册或替换语言的复数规则
+// lang: 语言，例如 "en"，区域变体（如 "pt-PT"）优先于基础语言
+// rule: 复数规则
+func RegisterPluralRule(lang string, rule PluralRule) {
+	pluralMu.Lock()
+	defer pluralMu.Unlock()
+	pluralRules[strings.ToLower(lang)] = rule
+}
+
+// PluralCategory 返回数量在语言中的复数类别，未知语言按 one/other 处理
+// lang: 语言，例如 "ru"、"zh-CN"
+// n: 数量
+func PluralCategory(lang string, n int) string {
+	lang = strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+	pluralMu.RLock()
+	rule, ok := pluralRules[lang]
+	if !ok {
+		base, _, _ := strings.Cut(lang, "-")
+		rule, ok = pluralRules[base]
+	}
+	pluralMu.RUnlock()
+	if !ok {
+		rule = pluralOneOther
+	}
+	if n < 0 {
+		n = -n
+	}
+	return rule(n)
+}
+
+// pluralNone 没有复数变化的语言，例如中文、日文
+func pluralNone(int) string {
+	return PluralOther
+}
+
+// pluralOneOther 单数为 1，例如英语、德语
+func pluralOneOther(n int) string {
+	if n == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// pluralFrench 0 和 1 为单数，例如法语、葡萄牙语
+func pluralFrench(n int) string {
+	if n <= 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// pluralSlavic 东斯拉夫语言，例如俄语、乌克兰语
+func pluralSlavic(n int) string {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return PluralOne
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return PluralFew
+	default:
+		return PluralMany
+	}
+}
+
+// pluralPolish 波兰语
+func pluralPolish(n int) string {
+	switch {
+	case n == 1:
+		return PluralOne
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return PluralFew
+	default:
+		return PluralMany
+	}
+}
+
+// pluralCzech 捷克语、斯洛伐克语
+func pluralCzech(n int) string {
+	switch {
+	case n == 1:
+		return PluralOne
+	case n >= 2 && n <= 4:
+		return PluralFew
+	default:
+		return PluralOther
+	}
+}
+
+// pluralArabic 阿拉伯语
+func pluralArabic(n int) string {
+	switch {
+	case n == 0:
+		return PluralZero
+	case n == 1:
+		return PluralOne
+	case n == 2:
+		return PluralTwo
+	case n%100 >= 3 && n%100 <= 10:
+		return PluralFew
+	case n%100 >= 11:
+		return PluralMany
+	default:
+		return PluralOther
+	}
+}
+
+// toInt 将数量参数转换为整数
+func toInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int8:
+		return int(n), true
+	case int16:
+		return int(n), true
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case uint:
+		return int(n), true
+	case uint8:
+		return int(n), true
+	case uint16:
+		return int(n), true
+	case uint32:
+		return int(n), true
+	case uint64:
+		return int(n), true
+	case float32:
+		return int(n), true
+	case float64:
+		return int(n), true
+	}
+	return 0, false
+}
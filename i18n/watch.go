@@ -0,0 +1,118 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/xzl-go/easygo/logger"
+)
+
+// reloadDelay 是文件变更后等待的时间，合并编辑器保存时产生的多次事件
+const reloadDelay = 100 * time.Millisecond
+
+// Watch 监听翻译文件目录，文件新增、修改或删除后重新加载该目录，无需重启服务
+// 目录尚未通过 LoadTranslations 加载时先加载；重新加载失败（例如文件格式错误）时保留旧译文并记录错误日志
+// dir: 翻译文件目录
+func (i *I18n) Watch(dir string) error {
+	dir = filepath.Clean(dir)
+	if len(i.dirSources(dir)) == 0 {
+		if err := i.LoadTranslations(dir); err != nil {
+			return err
+		}
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	// fsnotify 不递归监听，逐个添加子目录
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		return w.Add(path)
+	})
+	if err != nil {
+		w.Close()
+		return err
+	}
+
+	i.sourceMu.Lock()
+	i.watchers = append(i.watchers, w)
+	i.sourceMu.Unlock()
+	go i.watch(w, dir)
+	return nil
+}
+
+// Close 停止定时刷新和目录监听，已加载的译文仍然可用
+func (i *I18n) Close() error {
+	i.closeOnce.Do(func() { close(i.done) })
+	i.sourceMu.Lock()
+	defer i.sourceMu.Unlock()
+	var errs []error
+	for _, w := range i.watchers {
+		errs = append(errs, w.Close())
+	}
+	i.watchers = nil
+	return errors.Join(errs...)
+}
+
+// watch 处理目录的文件变更事件，延迟合并多次事件后重新加载
+func (i *I18n) watch(w *fsnotify.Watcher, dir string) {
+	var timer *time.Timer
+	for {
+		select {
+		case <-i.done:
+			return
+		case event, ok := <-w.Events:
+			if !ok {
+				return
+			}
+			// 监听新建的子目录
+			if event.Has(fsnotify.Create) {
+				_ = w.Add(event.Name)
+			}
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(reloadDelay, func() { i.reloadDir(dir) })
+		case err, ok := <-w.Errors:
+			if !ok {
+				return
+			}
+			logger.Module("i18n").Error("[I18n] 监听翻译文件失败：%v", err)
+		}
+	}
+}
+
+// reloadDir 重新加载指定目录的加载器
+func (i *I18n) reloadDir(dir string) {
+	select {
+	case <-i.done:
+		return
+	default:
+	}
+	if err := i.reload(context.Background(), i.dirSources(dir)); err != nil {
+		logger.Module("i18n").Error("[I18n] 重新加载 %s 失败，继续使用旧译文：%v", dir, err)
+		return
+	}
+	logger.Module("i18n").Info("[I18n] 已重新加载 %s", dir)
+}
+
+// dirSources 返回加载指定目录的加载器
+func (i *I18n) dirSources(dir string) []*source {
+	i.sourceMu.Lock()
+	defer i.sourceMu.Unlock()
+	var sources []*source
+	for _, s := range i.sources {
+		if l, ok := s.loader.(*DirLoader); ok && l.dir == dir {
+			sources = append(sources, s)
+		}
+	}
+	return sources
+}
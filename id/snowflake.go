@@ -0,0 +1,151 @@
+// Package id 提供了分布式唯一ID生成功能
+// 包括雪花算法ID、ULID 和 UUID，无需依赖外部服务
+package id
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// 雪花算法位分配：41 位毫秒时间戳 + 10 位节点ID + 12 位序列号
+const (
+	nodeBits     = 10
+	sequenceBits = 12
+	maxNodeID    = -1 ^ (-1 << nodeBits)
+	maxSequence  = -1 ^ (-1 << sequenceBits)
+	timeShift    = nodeBits + sequenceBits
+	nodeShift    = sequenceBits
+)
+
+// Epoch 是雪花算法的起始时间（2024-01-01 00:00:00 UTC），单位毫秒
+var Epoch int64 = 1704067200000
+
+// NodeIDEnv 是读取节点ID的环境变量名
+const NodeIDEnv = "EASYGO_NODE_ID"
+
+// 常见错误
+var (
+	ErrInvalidNodeID = fmt.Errorf("id: 节点ID必须在 0-%d 之间", maxNodeID)
+	ErrClockBackward = errors.New("id: 系统时钟回拨")
+)
+
+// Registry 定义了节点ID注册中心
+// 可基于 Redis、数据库或 etcd 实现，为每个实例分配不重复的节点ID
+type Registry interface {
+	// Acquire 申请一个节点ID
+	Acquire(ctx context.Context) (int64, error)
+}
+
+// Node 是雪花算法ID生成节点
+type Node struct {
+	mu       sync.Mutex
+	nodeID   int64
+	lastTime int64
+	sequence int64
+}
+
+// NewNode 创建一个雪花算法ID生成节点
+// nodeID: 节点ID，范围 0-1023
+func NewNode(nodeID int64) (*Node, error) {
+	if nodeID < 0 || nodeID > maxNodeID {
+		return nil, ErrInvalidNodeID
+	}
+	return &Node{nodeID: nodeID}, nil
+}
+
+// NewNodeFromEnv 从环境变量 EASYGO_NODE_ID 读取节点ID并创建节点
+// 未设置环境变量时，根据主机名计算节点ID
+func NewNodeFromEnv() (*Node, error) {
+	if v := os.Getenv(NodeIDEnv); v != "" {
+		nodeID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("id: 无法解析环境变量 %s: %w", NodeIDEnv, err)
+		}
+		return NewNode(nodeID)
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	h := fnv.New32a()
+	h.Write([]byte(host))
+	return NewNode(int64(h.Sum32() % (maxNodeID + 1)))
+}
+
+// NewNodeFromRegistry 从注册中心申请节点ID并创建节点
+func NewNodeFromRegistry(ctx context.Context, registry Registry) (*Node, error) {
+	nodeID, err := registry.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("id: 申请节点ID失败: %w", err)
+	}
+	return NewNode(nodeID)
+}
+
+// Generate 生成一个新的ID
+// 同一毫秒内序列号用尽时等待下一毫秒；时钟回拨超过 10ms 时返回错误
+func (n *Node) Generate() (int64, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := time.Now().UnixMilli()
+	if now < n.lastTime {
+		// 小幅回拨时等待时钟追上，避免生成重复ID
+		if n.lastTime-now > 10 {
+			return 0, ErrClockBackward
+		}
+		time.Sleep(time.Duration(n.lastTime-now) * time.Millisecond)
+		now = time.Now().UnixMilli()
+	}
+
+	if now == n.lastTime {
+		n.sequence = (n.sequence + 1) & maxSequence
+		if n.sequence == 0 {
+			for now <= n.lastTime {
+				now = time.Now().UnixMilli()
+			}
+		}
+	} else {
+		n.sequence = 0
+	}
+	n.lastTime = now
+
+	return (now-Epoch)<<timeShift | n.nodeID<<nodeShift | n.sequence, nil
+}
+
+// NodeID 返回节点ID
+func (n *Node) NodeID() int64 {
+	return n.nodeID
+}
+
+// ParseSnowflake 解析雪花算法ID
+// 返回生成时间、节点ID和序列号
+func ParseSnowflake(id int64) (t time.Time, nodeID, sequence int64) {
+	t = time.UnixMilli(id>>timeShift + Epoch)
+	nodeID = id >> nodeShift & maxNodeID
+	sequence = id & maxSequence
+	return
+}
+
+var (
+	defaultNode     *Node
+	defaultNodeErr  error
+	defaultNodeOnce sync.Once
+)
+
+// Next 使用默认节点生成雪花算法ID
+// 默认节点根据 NewNodeFromEnv 的规则确定节点ID
+func Next() (int64, error) {
+	defaultNodeOnce.Do(func() {
+		defaultNode, defaultNodeErr = NewNodeFromEnv()
+	})
+	if defaultNodeErr != nil {
+		return 0, defaultNodeErr
+	}
+	return defaultNode.Generate()
+}
@@ -0,0 +1,102 @@
+package id
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+// crockford 是 ULID 使用的 Crockford Base32 字符表
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ErrInvalidULID 表示 ULID 格式不正确
+var ErrInvalidULID = errors.New("id: 无效的 ULID")
+
+// NewULID 生成一个 ULID（48 位毫秒时间戳 + 80 位随机数）
+// 返回 26 个字符的字符串，按字典序排序即按时间排序
+func NewULID() (string, error) {
+	var b [16]byte
+	ms := uint64(time.Now().UnixMilli())
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(ms)
+		ms >>= 8
+	}
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", err
+	}
+
+	// 128 位按 5 位一组编码，首字符只使用 3 位
+	var out [26]byte
+	hi := uint64(b[0])<<56 | uint64(b[1])<<48 | uint64(b[2])<<40 | uint64(b[3])<<32 |
+		uint64(b[4])<<24 | uint64(b[5])<<16 | uint64(b[6])<<8 | uint64(b[7])
+	lo := uint64(b[8])<<56 | uint64(b[9])<<48 | uint64(b[10])<<40 | uint64(b[11])<<32 |
+		uint64(b[12])<<24 | uint64(b[13])<<16 | uint64(b[14])<<8 | uint64(b[15])
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:]), nil
+}
+
+// ULIDTime 解析 ULID 中的时间戳
+func ULIDTime(ulid string) (time.Time, error) {
+	if len(ulid) != 26 {
+		return time.Time{}, ErrInvalidULID
+	}
+	var ms uint64
+	// 前 10 个字符编码了 48 位时间戳（首字符高 2 位为 0）
+	for _, c := range strings.ToUpper(ulid[:10]) {
+		idx := strings.IndexRune(crockford, c)
+		if idx < 0 {
+			return time.Time{}, ErrInvalidULID
+		}
+		ms = ms<<5 | uint64(idx)
+	}
+	return time.UnixMilli(int64(ms)), nil
+}
+
+// NewUUID 生成一个随机 UUID（版本 4）
+func NewUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return formatUUID(b), nil
+}
+
+// NewUUIDv7 生成一个按时间排序的 UUID（版本 7）
+// 适合作为数据库主键，避免随机 UUID 导致的索引碎片
+func NewUUIDv7() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", err
+	}
+	ms := uint64(time.Now().UnixMilli())
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(ms)
+		ms >>= 8
+	}
+	b[6] = b[6]&0x0f | 0x70
+	b[8] = b[8]&0x3f | 0x80
+	return formatUUID(b), nil
+}
+
+// formatUUID 按 8-4-4-4-12 格式输出 UUID
+func formatUUID(b [16]byte) string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+	return string(buf[:])
+}
@@ -0,0 +1,139 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// reservedClaims 是 Claims 的固定字段和标准声明，自定义声明不能使用这些名称
+var reservedClaims = map[string]bool{
+	"user_id": true, "username": true, "token_type": true,
+	"iss": true, "sub": true, "aud": true, "exp": true, "nbf": true, "iat": true, "jti": true,
+}
+
+// claimsJSON 与 Claims 字段相同但没有自定义的 JSON 方法，避免递归
+type claimsJSON Claims
+
+// MarshalJSON 将自定义声明与其他声明平铺输出
+func (c Claims) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(claimsJSON(c))
+	if err != nil || len(c.Extra) == 0 {
+		return data, err
+	}
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range c.Extra {
+		if _, exists := fields[key]; exists || reservedClaims[key] {
+			continue
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		fields[key] = raw
+	}
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON 解析载荷，未知的声明放入 Extra，数字保留为 json.Number
+func (c *Claims) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*claimsJSON)(c)); err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil {
+		return err
+	}
+	for key := range reservedClaims {
+		delete(fields, key)
+	}
+	c.Extra = nil
+	if len(fields) > 0 {
+		c.Extra = fields
+	}
+	return nil
+}
+
+// Get 返回自定义声明，不存在时返回 nil
+func (c *Claims) Get(key string) interface{} {
+	return c.Extra[key]
+}
+
+// GetString 返回字符串类型的自定义声明，不存在或类型不符时返回空字符串
+func (c *Claims) GetString(key string) string {
+	s, _ := c.Extra[key].(string)
+	return s
+}
+
+// GetStrings 返回字符串列表类型的自定义声明，如角色和权限范围
+// 单个字符串视为只有一个元素的列表，以空格分隔的字符串（OAuth2 scope 格式）按空格拆分
+func (c *Claims) GetStrings(key string) []string {
+	switch v := c.Extra[key].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	case string:
+		return strings.Fields(v)
+	}
+	return nil
+}
+
+// GetInt64 返回整数类型的自定义声明，不存在或类型不符时返回 0
+func (c *Claims) GetInt64(key string) int64 {
+	switch v := c.Extra[key].(type) {
+	case json.Number:
+		n, _ := v.Int64()
+		return n
+	case int:
+		return int64(v)
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+// GetFloat64 返回数字类型的自定义声明，不存在或类型不符时返回 0
+func (c *Claims) GetFloat64(key string) float64 {
+	switch v := c.Extra[key].(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		return f
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return 0
+}
+
+// GetBool 返回布尔类型的自定义声明，不存在或类型不符时返回 false
+func (c *Claims) GetBool(key string) bool {
+	b, _ := c.Extra[key].(bool)
+	return b
+}
+
+// HasString 判断字符串列表类型的自定义声明是否包含指定值，如 claims.HasString("roles", "admin")
+func (c *Claims) HasString(key, value string) bool {
+	for _, s := range c.GetStrings(key) {
+		if s == value {
+			return true
+		}
+	}
+	return false
+}
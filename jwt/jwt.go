@@ -2,63 +2,157 @@
 package jwt
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/xzl-go/easygo/id"
+)
+
+// 令牌类型
+const (
+	TokenTypeAccess  = "access"  // 访问令牌
+	TokenTypeRefresh = "refresh" // 刷新令牌
 )
 
+// DefaultRefreshDuration 是刷新令牌的默认有效期
+const DefaultRefreshDuration = 7 * 24 * time.Hour
+
 // Claims 定义了JWT的载荷结构
-// 包含用户ID、用户名和标准JWT声明
+// 包含用户ID、用户名、标准JWT声明和自定义声明
 type Claims struct {
-	UserID               string `json:"user_id"`  // 用户ID
-	Username             string `json:"username"` // 用户名
-	jwt.RegisteredClaims        // 标准JWT声明（过期时间、签发时间等）
+	UserID               string                 `json:"user_id"`              // 用户ID
+	Username             string                 `json:"username"`             // 用户名
+	TokenType            string                 `json:"token_type,omitempty"` // 令牌类型，为空视为访问令牌
+	Extra                map[string]interface{} `json:"-"`                    // 自定义声明，如角色、租户ID、权限范围，与其他声明平铺在载荷中
+	jwt.RegisteredClaims                        // 标准JWT声明（过期时间、签发时间、令牌ID等）
 }
 
 // JWTManager 是JWT管理器
 // 负责JWT令牌的生成、验证和刷新
 type JWTManager struct {
-	secretKey     []byte        // 用于签名的密钥
-	tokenDuration time.Duration // 令牌有效期
+	keys            *KeySet       // 签名和验证使用的密钥
+	tokenDuration   time.Duration // 令牌有效期
+	refreshDuration time.Duration // 刷新令牌有效期
+	store           TokenStore    // 已吊销令牌的存储，为 nil 时不支持吊销
 }
 
-// NewJWTManager 创建一个新的JWT管理器
+// NewJWTManager 创建一个使用 HS256 对称密钥的JWT管理器
 // secretKey: 用于签名的密钥
 // duration: 令牌有效期
 func NewJWTManager(secretKey string, duration time.Duration) *JWTManager {
+	keys, _ := NewKeySet(NewHMACKey("", []byte(secretKey)))
+	return NewJWTManagerWithKeys(keys, duration)
+}
+
+// NewJWTManagerWithKeys 创建一个使用密钥集合的JWT管理器，支持 RS256、ES256、EdDSA 等算法和密钥轮换
+// keys: 密钥集合
+// duration: 令牌有效期
+func NewJWTManagerWithKeys(keys *KeySet, duration time.Duration) *JWTManager {
 	return &JWTManager{
-		secretKey:     []byte(secretKey),
-		tokenDuration: duration,
+		keys:            keys,
+		tokenDuration:   duration,
+		refreshDuration: DefaultRefreshDuration,
 	}
 }
 
+// SetRefreshDuration 设置刷新令牌有效期，默认 7 天
+func (m *JWTManager) SetRefreshDuration(duration time.Duration) {
+	m.refreshDuration = duration
+}
+
+// SetTokenStore 设置已吊销令牌的存储，设置后 VerifyTokenContext 会拒绝已吊销的令牌
+// 多实例部署时应使用 Redis 等共享存储
+func (m *JWTManager) SetTokenStore(store TokenStore) {
+	m.store = store
+}
+
+// Keys 返回密钥集合，可用于轮换密钥
+func (m *JWTManager) Keys() *KeySet {
+	return m.keys
+}
+
 // GenerateToken 生成JWT令牌
 // userID: 用户ID
 // username: 用户名
 // 返回生成的令牌字符串和可能的错误
 func (m *JWTManager) GenerateToken(userID, username string) (string, error) {
-	claims := &Claims{
-		UserID:   userID,
-		Username: username,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.tokenDuration)), // 设置过期时间
-			IssuedAt:  jwt.NewNumericDate(time.Now()),                      // 设置签发时间
-			Issuer:    "easygo",                                            // 设置签发者
-		},
+	return m.GenerateTokenWithClaims(userID, username, nil)
+}
+
+// GenerateTokenWithClaims 生成带有自定义声明的JWT令牌
+// 验证后通过 Claims.GetString、GetStrings 等方法读取自定义声明
+// userID: 用户ID
+// username: 用户名
+// extra: 自定义声明，如 {"roles": []string{"admin"}, "tenant_id": "t1"}，不能与标准声明重名
+func (m *JWTManager) GenerateTokenWithClaims(userID, username string, extra map[string]interface{}) (string, error) {
+	claims, err := m.newClaims(userID, username, TokenTypeAccess, m.tokenDuration, extra)
+	if err != nil {
+		return "", err
 	}
+	return m.keys.sign(claims)
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(m.secretKey)
+// newClaims 创建带有唯一令牌ID的载荷
+func (m *JWTManager) newClaims(userID, username, tokenType string, duration time.Duration, extra map[string]interface{}) (*Claims, error) {
+	for key := range extra {
+		if reservedClaims[key] {
+			return nil, fmt.Errorf("jwt: 自定义声明 %q 与标准声明重名", key)
+		}
+	}
+	jti, err := id.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &Claims{
+		UserID:    userID,
+		Username:  username,
+		TokenType: tokenType,
+		Extra:     extra,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,                                   // 令牌ID，用于吊销
+			ExpiresAt: jwt.NewNumericDate(now.Add(duration)), // 设置过期时间
+			IssuedAt:  jwt.NewNumericDate(now),               // 设置签发时间
+			Issuer:    "easygo",                              // 设置签发者
+		},
+	}, nil
 }
 
-// VerifyToken 验证JWT令牌
+// VerifyToken 验证JWT访问令牌，不检查令牌是否已被吊销
 // tokenString: 要验证的令牌字符串
 // 返回令牌的载荷和可能的错误
 func (m *JWTManager) VerifyToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return m.secretKey, nil
-	})
+	claims, err := m.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType == TokenTypeRefresh {
+		return nil, ErrTokenType
+	}
+	return claims, nil
+}
+
+// VerifyTokenContext 验证JWT访问令牌，并在设置了令牌存储时检查令牌是否已被吊销
+// ctx: 上下文
+// tokenString: 要验证的令牌字符串
+func (m *JWTManager) VerifyTokenContext(ctx context.Context, tokenString string) (*Claims, error) {
+	claims, err := m.VerifyToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkRevoked(ctx, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// parse 解析并验证令牌签名和有效期
+func (m *JWTManager) parse(tokenString string, options ...jwt.ParserOption) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, m.keys.keyFunc, options...)
 
 	if err != nil {
 		return nil, err
@@ -71,17 +165,16 @@ func (m *JWTManager) VerifyToken(tokenString string) (*Claims, error) {
 	return nil, errors.New("无效的令牌")
 }
 
-// RefreshToken 刷新JWT令牌
+// RefreshToken 刷新JWT令牌，已吊销的令牌不能刷新
+// 新令牌使用新的令牌ID，避免沿用旧ID的吊销记录在旧令牌过期后失效
 // tokenString: 要刷新的令牌字符串
 // 返回新的令牌字符串和可能的错误
+//
+// Deprecated: 使用 GenerateTokenPair 和 RefreshTokenPair，刷新令牌只能使用一次且可以单独吊销
 func (m *JWTManager) RefreshToken(tokenString string) (string, error) {
-	claims, err := m.VerifyToken(tokenString)
+	claims, err := m.VerifyTokenContext(context.Background(), tokenString)
 	if err != nil {
 		return "", err
 	}
-
-	// 更新过期时间
-	claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(m.tokenDuration))
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(m.secretKey)
+	return m.GenerateTokenWithClaims(claims.UserID, claims.Username, claims.Extra)
 }
@@ -0,0 +1,293 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key 是带有密钥ID（kid）的签名密钥
+// 对称密钥（HS256）同时用于签名和验证；非对称密钥持有私钥时可签名，只持有公钥时仅用于验证
+type Key struct {
+	ID        string            // 密钥ID，写入令牌头部的 kid
+	Method    jwt.SigningMethod // 签名算法
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// CanSign 返回密钥能否用于签名
+func (k *Key) CanSign() bool {
+	return k.signKey != nil
+}
+
+// PublicKey 返回非对称密钥的公钥，对称密钥返回 nil
+func (k *Key) PublicKey() crypto.PublicKey {
+	if _, ok := k.verifyKey.([]byte); ok {
+		return nil
+	}
+	return k.verifyKey
+}
+
+// NewHMACKey 创建 HS256 对称密钥
+// id: 密钥ID，可为空
+// secret: 密钥
+func NewHMACKey(id string, secret []byte) *Key {
+	return &Key{ID: id, Method: jwt.SigningMethodHS256, signKey: secret, verifyKey: secret}
+}
+
+// NewSigningKey 根据私钥创建签名密钥，算法由密钥类型决定：
+// RSA 使用 RS256，ECDSA 按曲线使用 ES256、ES384 或 ES512，Ed25519 使用 EdDSA
+// id: 密钥ID
+// privateKey: *rsa.PrivateKey、*ecdsa.PrivateKey 或 ed25519.PrivateKey
+func NewSigningKey(id string, privateKey crypto.Signer) (*Key, error) {
+	method, err := methodFor(privateKey.Public())
+	if err != nil {
+		return nil, err
+	}
+	return &Key{ID: id, Method: method, signKey: privateKey, verifyKey: privateKey.Public()}, nil
+}
+
+// NewVerificationKey 根据公钥创建仅用于验证的密钥，如其他服务签发令牌的公钥或已轮换下来的旧公钥
+// id: 密钥ID
+// publicKey: *rsa.PublicKey、*ecdsa.PublicKey 或 ed25519.PublicKey
+func NewVerificationKey(id string, publicKey crypto.PublicKey) (*Key, error) {
+	method, err := methodFor(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{ID: id, Method: method, verifyKey: publicKey}, nil
+}
+
+// methodFor 根据公钥类型选择签名算法
+func methodFor(publicKey crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch pub := publicKey.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			return jwt.SigningMethodES256, nil
+		case elliptic.P384():
+			return jwt.SigningMethodES384, nil
+		case elliptic.P521():
+			return jwt.SigningMethodES512, nil
+		}
+		return nil, fmt.Errorf("jwt: 不支持的椭圆曲线 %s", pub.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("jwt: 不支持的密钥类型 %T", publicKey)
+	}
+}
+
+// ParseSigningKeyPEM 从 PEM 格式的私钥创建签名密钥，支持 PKCS#8、PKCS#1（RSA）和 SEC 1（EC）编码
+// id: 密钥ID
+// data: PEM 内容
+func ParseSigningKeyPEM(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("jwt: 无效的 PEM 内容")
+	}
+	var privateKey interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		privateKey, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwt: 解析私钥失败: %w", err)
+	}
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("jwt: 不支持的私钥类型 %T", privateKey)
+	}
+	return NewSigningKey(id, signer)
+}
+
+// ParseVerificationKeyPEM 从 PEM 格式的公钥或证书创建验证密钥，支持 PKIX、PKCS#1（RSA）公钥和 X.509 证书
+// id: 密钥ID
+// data: PEM 内容
+func ParseVerificationKeyPEM(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("jwt: 无效的 PEM 内容")
+	}
+	var publicKey interface{}
+	var err error
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		publicKey, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			publicKey = cert.PublicKey
+		}
+	default:
+		publicKey, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwt: 解析公钥失败: %w", err)
+	}
+	return NewVerificationKey(id, publicKey)
+}
+
+// LoadSigningKeyFile 从 PEM 文件加载签名密钥
+// id: 密钥ID
+// path: 私钥文件路径
+func LoadSigningKeyFile(id, path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSigningKeyPEM(id, data)
+}
+
+// LoadVerificationKeyFile 从 PEM 文件加载验证密钥
+// id: 密钥ID
+// path: 公钥或证书文件路径
+func LoadVerificationKeyFile(id, path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseVerificationKeyPEM(id, data)
+}
+
+// ErrNoSigningKey 密钥集合没有签名密钥，只能验证令牌
+var ErrNoSigningKey = errors.New("jwt: 没有签名密钥")
+
+// KeySet 是一组按密钥ID索引的密钥，使用当前签名密钥签发令牌，按令牌头部的 kid 选择密钥验证
+// 轮换密钥时旧密钥保留用于验证，直到已签发的令牌全部过期后再移除，从而不中断已登录的用户。
+// 多实例部署时先在所有实例上 Add 新公钥，再逐个 Rotate，避免新令牌被尚未更新的实例拒绝。
+type KeySet struct {
+	mu      sync.RWMutex
+	signing *Key
+	keys    map[string]*Key
+}
+
+// NewKeySet 创建密钥集合
+// signing: 当前签名密钥，为 nil 时密钥集合只能验证令牌
+// verification: 其他仅用于验证的密钥
+func NewKeySet(signing *Key, verification ...*Key) (*KeySet, error) {
+	s := &KeySet{keys: make(map[string]*Key)}
+	if signing != nil {
+		if !signing.CanSign() {
+			return nil, errors.New("jwt: 签名密钥缺少私钥")
+		}
+		s.signing = signing
+		s.keys[signing.ID] = signing
+	}
+	for _, key := range verification {
+		if _, exists := s.keys[key.ID]; exists {
+			return nil, fmt.Errorf("jwt: 密钥ID %q 重复", key.ID)
+		}
+		s.keys[key.ID] = key
+	}
+	return s, nil
+}
+
+// SigningKey 返回当前签名密钥，只能验证时返回 nil
+func (s *KeySet) SigningKey() *Key {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.signing
+}
+
+// Key 返回指定ID的密钥，不存在时返回 nil
+func (s *KeySet) Key(id string) *Key {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.keys[id]
+}
+
+// Keys 返回全部密钥
+func (s *KeySet) Keys() []*Key {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]*Key, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Add 添加用于验证的密钥，ID 已存在时替换（不能替换当前签名密钥）
+func (s *KeySet) Add(keys ...*Key) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		if s.signing != nil && key.ID == s.signing.ID {
+			return fmt.Errorf("jwt: 不能替换当前签名密钥 %q", key.ID)
+		}
+	}
+	for _, key := range keys {
+		s.keys[key.ID] = key
+	}
+	return nil
+}
+
+// Rotate 将新密钥设为签名密钥，原签名密钥保留用于验证
+func (s *KeySet) Rotate(key *Key) error {
+	if !key.CanSign() {
+		return errors.New("jwt: 签名密钥缺少私钥")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.signing != nil && key.ID == s.signing.ID {
+		return fmt.Errorf("jwt: 新密钥ID %q 与当前签名密钥相同", key.ID)
+	}
+	s.keys[key.ID] = key
+	s.signing = key
+	return nil
+}
+
+// Remove 移除不再使用的密钥，使用该密钥签发的令牌将无法通过验证
+func (s *KeySet) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.signing != nil && id == s.signing.ID {
+		return fmt.Errorf("jwt: 不能移除当前签名密钥 %q", id)
+	}
+	delete(s.keys, id)
+	return nil
+}
+
+// sign 使用当前签名密钥签名，密钥ID非空时写入令牌头部
+func (s *KeySet) sign(claims jwt.Claims) (string, error) {
+	key := s.SigningKey()
+	if key == nil {
+		return "", ErrNoSigningKey
+	}
+	token := jwt.NewWithClaims(key.Method, claims)
+	if key.ID != "" {
+		token.Header["kid"] = key.ID
+	}
+	return token.SignedString(key.signKey)
+}
+
+// keyFunc 按令牌头部的 kid 选择验证密钥，并要求令牌算法与密钥一致，防止算法混淆攻击
+func (s *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	id, _ := token.Header["kid"].(string)
+	key := s.Key(id)
+	if key == nil {
+		return nil, fmt.Errorf("jwt: 未知的密钥ID %q", id)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("jwt: 令牌算法 %s 与密钥算法 %s 不一致", token.Method.Alg(), key.Method.Alg())
+	}
+	return key.verifyKey, nil
+}
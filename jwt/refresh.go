@@ -0,0 +1,159 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	// ErrTokenType 令牌类型不符，如使用刷新令牌访问接口或使用访问令牌刷新
+	ErrTokenType = errors.New("jwt: 令牌类型不符")
+	// ErrTokenRevoked 令牌已被吊销
+	ErrTokenRevoked = errors.New("jwt: 令牌已被吊销")
+	// ErrTokenReused 刷新令牌已被使用过，可能已泄露，客户端应重新登录
+	ErrTokenReused = errors.New("jwt: 刷新令牌已被使用")
+	// ErrNoTokenStore 未设置令牌存储，无法吊销令牌
+	ErrNoTokenStore = errors.New("jwt: 未设置令牌存储")
+)
+
+// TokenPair 是一组访问令牌和刷新令牌
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	RefreshToken     string    `json:"refresh_token"`
+	ExpiresAt        time.Time `json:"expires_at"`         // 访问令牌过期时间
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"` // 刷新令牌过期时间
+}
+
+// GenerateTokenPair 签发访问令牌和有效期更长的刷新令牌
+// 访问令牌用于调用接口，过期后客户端使用刷新令牌换取新的令牌对
+// userID: 用户ID
+// username: 用户名
+func (m *JWTManager) GenerateTokenPair(userID, username string) (*TokenPair, error) {
+	return m.GenerateTokenPairWithClaims(userID, username, nil)
+}
+
+// GenerateTokenPairWithClaims 签发带有自定义声明的令牌对，刷新时自定义声明保留到新的令牌对中
+// userID: 用户ID
+// username: 用户名
+// extra: 自定义声明
+func (m *JWTManager) GenerateTokenPairWithClaims(userID, username string, extra map[string]interface{}) (*TokenPair, error) {
+	access, err := m.newClaims(userID, username, TokenTypeAccess, m.tokenDuration, extra)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := m.newClaims(userID, username, TokenTypeRefresh, m.refreshDuration, extra)
+	if err != nil {
+		return nil, err
+	}
+	pair := &TokenPair{
+		ExpiresAt:        access.ExpiresAt.Time,
+		RefreshExpiresAt: refresh.ExpiresAt.Time,
+	}
+	if pair.AccessToken, err = m.keys.sign(access); err != nil {
+		return nil, err
+	}
+	if pair.RefreshToken, err = m.keys.sign(refresh); err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// VerifyRefreshToken 验证刷新令牌，并在设置了令牌存储时检查令牌是否已被吊销
+// ctx: 上下文
+// refreshToken: 刷新令牌
+func (m *JWTManager) VerifyRefreshToken(ctx context.Context, refreshToken string) (*Claims, error) {
+	claims, err := m.parse(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != TokenTypeRefresh {
+		return nil, ErrTokenType
+	}
+	if err := m.checkRevoked(ctx, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// RefreshTokenPair 使用刷新令牌换取新的令牌对
+// 设置了令牌存储时旧的刷新令牌随即吊销，每个刷新令牌只能使用一次：
+// 吊销是原子操作，并发使用同一个刷新令牌时只有一个请求成功，其余返回 ErrTokenReused
+// ctx: 上下文
+// refreshToken: 刷新令牌
+func (m *JWTManager) RefreshTokenPair(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	claims, err := m.VerifyRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if m.store != nil {
+		if claims.ID == "" || claims.ExpiresAt == nil {
+			return nil, errors.New("jwt: 刷新令牌缺少ID或过期时间，无法吊销")
+		}
+		err := m.store.Revoke(ctx, claims.ID, claims.ExpiresAt.Time)
+		if errors.Is(err, ErrTokenRevoked) {
+			return nil, ErrTokenReused
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m.GenerateTokenPairWithClaims(claims.UserID, claims.Username, claims.Extra)
+}
+
+// Revoke 吊销令牌，用于退出登录或强制下线，访问令牌和刷新令牌均可吊销
+// 已过期或已吊销的令牌无需吊销，直接返回 nil
+// ctx: 上下文
+// tokens: 要吊销的令牌
+func (m *JWTManager) Revoke(ctx context.Context, tokens ...string) error {
+	if m.store == nil {
+		return ErrNoTokenStore
+	}
+	for _, token := range tokens {
+		claims, err := m.parse(token)
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := m.RevokeClaims(ctx, claims); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RevokeClaims 根据已验证的载荷吊销令牌，例如在 JWT 中间件之后的退出登录接口中使用
+// 令牌已被吊销时返回 nil
+// ctx: 上下文
+// claims: 令牌载荷
+func (m *JWTManager) RevokeClaims(ctx context.Context, claims *Claims) error {
+	if m.store == nil {
+		return ErrNoTokenStore
+	}
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return errors.New("jwt: 令牌缺少ID或过期时间，无法吊销")
+	}
+	if err := m.store.Revoke(ctx, claims.ID, claims.ExpiresAt.Time); err != nil && !errors.Is(err, ErrTokenRevoked) {
+		return err
+	}
+	return nil
+}
+
+// checkRevoked 检查令牌是否已被吊销，未设置令牌存储时不检查
+func (m *JWTManager) checkRevoked(ctx context.Context, claims *Claims) error {
+	if m.store == nil || claims.ID == "" {
+		return nil
+	}
+	revoked, err := m.store.IsRevoked(ctx, claims.ID)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		return ErrTokenRevoked
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRefreshTokenPairConcurrentReuse(t *testing.T) {
+	m := NewJWTManager("test-secret", time.Minute)
+	m.SetTokenStore(NewMemoryTokenStore())
+	pair, err := m.GenerateTokenPair("u-1", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = m.RefreshTokenPair(context.Background(), pair.RefreshToken)
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, ErrTokenReused), errors.Is(err, ErrTokenRevoked):
+		default:
+			t.Errorf("意外的错误: %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("%d 个并发刷新成功，期望只有 1 个", succeeded)
+	}
+}
+
+func TestRefreshTokenRejectsRevoked(t *testing.T) {
+	m := NewJWTManager("test-secret", time.Minute)
+	m.SetTokenStore(NewMemoryTokenStore())
+	token, err := m.GenerateToken("u-1", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Revoke(context.Background(), token); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.RefreshToken(token); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("刷新已吊销的令牌返回 %v，期望 ErrTokenRevoked", err)
+	}
+	// 重复吊销不报错
+	if err := m.Revoke(context.Background(), token); err != nil {
+		t.Errorf("重复吊销返回错误: %v", err)
+	}
+}
+
+func TestRefreshTokenNewID(t *testing.T) {
+	m := NewJWTManager("test-secret", time.Minute)
+	token, err := m.GenerateToken("u-1", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	refreshed, err := m.RefreshToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, _ := m.VerifyToken(token)
+	renewed, err := m.VerifyToken(refreshed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if old.ID == renewed.ID {
+		t.Error("刷新后的令牌沿用了旧的令牌ID")
+	}
+}
@@ -0,0 +1,105 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/xzl-go/easygo/cache"
+)
+
+// TokenStore 定义了已吊销令牌的存储，以令牌ID（jti）为键，记录保留到令牌原本的过期时间
+type TokenStore interface {
+	// Revoke 吊销令牌，令牌已被吊销时返回 ErrTokenRevoked
+	// 检查和写入必须是原子操作，并发吊销同一个令牌时只有一次成功，以保证每个刷新令牌只能使用一次
+	Revoke(ctx context.Context, id string, expiresAt time.Time) error
+	// IsRevoked 判断令牌是否已被吊销
+	IsRevoked(ctx context.Context, id string) (bool, error)
+}
+
+// CacheTokenStore 是基于 cache.Cache 的令牌存储
+// 缓存需实现 cache.Adder（内存缓存和 redis.Cache 均已实现），否则并发吊销同一个令牌时不保证只有一次成功
+type CacheTokenStore struct {
+	cache  cache.Cache
+	prefix string
+}
+
+// NewCacheTokenStore 创建基于缓存的令牌存储
+// c: 缓存
+// prefix: 键前缀，为空时使用 "jwt:revoked:"
+func NewCacheTokenStore(c cache.Cache, prefix string) *CacheTokenStore {
+	if prefix == "" {
+		prefix = "jwt:revoked:"
+	}
+	return &CacheTokenStore{cache: c, prefix: prefix}
+}
+
+// NewMemoryTokenStore 创建基于内存的令牌存储，适用于单实例和开发环境
+func NewMemoryTokenStore() *CacheTokenStore {
+	return NewCacheTokenStore(cache.NewMemory(time.Minute), "")
+}
+
+// Revoke 吊销令牌，令牌已被吊销时返回 ErrTokenRevoked
+func (s *CacheTokenStore) Revoke(_ context.Context, id string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	ok, err := cache.SetNX(s.cache, s.prefix+id, "1", ttl)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+// IsRevoked 判断令牌是否已被吊销
+func (s *CacheTokenStore) IsRevoked(_ context.Context, id string) (bool, error) {
+	_, ok := s.cache.Get(s.prefix + id)
+	return ok, nil
+}
+
+// RedisTokenStore 是基于 Redis 的令牌存储，适用于多实例部署
+type RedisTokenStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisTokenStore 创建基于 Redis 的令牌存储
+// client: Redis 客户端
+// prefix: 键前缀，为空时使用 "jwt:revoked:"
+func NewRedisTokenStore(client redis.UniversalClient, prefix string) *RedisTokenStore {
+	if prefix == "" {
+		prefix = "jwt:revoked:"
+	}
+	return &RedisTokenStore{client: client, prefix: prefix}
+}
+
+// Revoke 吊销令牌，令牌已被吊销时返回 ErrTokenRevoked
+func (s *RedisTokenStore) Revoke(ctx context.Context, id string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	ok, err := s.client.SetNX(ctx, s.prefix+id, 1, ttl).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+// IsRevoked 判断令牌是否已被吊销
+func (s *RedisTokenStore) IsRevoked(ctx context.Context, id string) (bool, error) {
+	err := s.client.Get(ctx, s.prefix+id).Err()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,94 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+)
+
+// Etcd 是基于 etcd 的分布式锁
+// 每把锁使用独立的租约，由 etcd 客户端自动续租；栅栏令牌为加锁时的 etcd 修订号，全局单调递增。
+// etcd 的租约总是自动续期，Options.DisableRenew 对其无效
+type Etcd struct {
+	client  *clientv3.Client
+	options Options
+}
+
+// NewEtcd 创建基于 etcd 的分布式锁
+// client: etcd 客户端
+// options: 锁配置，TTL 向上取整到秒
+func NewEtcd(client *clientv3.Client, options Options) *Etcd {
+	return &Etcd{client: client, options: options.withDefaults("/easygo/lock/")}
+}
+
+// TryAcquire 尝试获取锁
+func (e *Etcd) TryAcquire(ctx context.Context, key string) (Lock, error) {
+	return e.acquire(ctx, key, func(m *concurrency.Mutex) error {
+		err := m.TryLock(ctx)
+		if errors.Is(err, concurrency.ErrLocked) {
+			return ErrNotAcquired
+		}
+		return err
+	})
+}
+
+// Acquire 获取锁，锁已被占用时等待
+func (e *Etcd) Acquire(ctx context.Context, key string) (Lock, error) {
+	return e.acquire(ctx, key, func(m *concurrency.Mutex) error {
+		return m.Lock(ctx)
+	})
+}
+
+// acquire 创建租约并加锁，加锁失败时撤销租约
+func (e *Etcd) acquire(ctx context.Context, key string, lock func(m *concurrency.Mutex) error) (Lock, error) {
+	ttl := int(math.Ceil(e.options.TTL.Seconds()))
+	session, err := concurrency.NewSession(e.client, concurrency.WithTTL(ttl), concurrency.WithContext(context.WithoutCancel(ctx)))
+	if err != nil {
+		return nil, err
+	}
+	mutex := concurrency.NewMutex(session, e.options.Prefix+key)
+	if err := lock(mutex); err != nil {
+		session.Close()
+		return nil, err
+	}
+	return &etcdLock{key: key, session: session, mutex: mutex}, nil
+}
+
+// etcdLock 是基于 etcd 租约的锁
+type etcdLock struct {
+	key     string
+	session *concurrency.Session
+	mutex   *concurrency.Mutex
+}
+
+// Key 返回锁的键
+func (l *etcdLock) Key() string {
+	return l.key
+}
+
+// Token 返回加锁时的 etcd 修订号
+func (l *etcdLock) Token() int64 {
+	return l.mutex.Header().Revision
+}
+
+// Lost 返回租约失效通知
+func (l *etcdLock) Lost() <-chan struct{} {
+	return l.session.Done()
+}
+
+// Release 释放锁并撤销租约
+func (l *etcdLock) Release(ctx context.Context) error {
+	select {
+	case <-l.session.Done():
+		return ErrLockLost
+	default:
+	}
+	err := l.mutex.Unlock(ctx)
+	if cerr := l.session.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,226 @@
+// Package lock 提供了分布式锁
+// 内置 Redis（兼容多节点 Redlock）、etcd 和进程内三种实现，支持超时、自动续期和栅栏令牌（fencing token）。
+// 栅栏令牌随每次加锁单调递增，写入共享资源时携带令牌，存储端拒绝比已见过的令牌更小的写入，
+// 即可避免持锁方因 GC 停顿或网络分区而在锁过期后继续写入
+package lock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotAcquired 表示锁已被其他持有者占用
+	ErrNotAcquired = errors.New("lock: 锁已被占用")
+	// ErrLockLost 表示锁已过期或被其他持有者获取
+	ErrLockLost = errors.New("lock: 锁已失效")
+)
+
+// Options 定义了锁配置
+type Options struct {
+	// Prefix 键前缀，Redis 默认 "lock:"，etcd 默认 "/easygo/lock/"
+	Prefix string
+	// TTL 锁的有效期，默认 30 秒
+	TTL time.Duration
+	// RetryInterval Acquire 等待锁时的重试间隔，默认 100 毫秒
+	RetryInterval time.Duration
+	// DisableRenew 关闭自动续期，锁在 TTL 后自动失效
+	DisableRenew bool
+}
+
+// withDefaults 填充默认值
+func (o Options) withDefaults(prefix string) Options {
+	if o.Prefix == "" {
+		o.Prefix = prefix
+	}
+	if o.TTL <= 0 {
+		o.TTL = 30 * time.Second
+	}
+	if o.RetryInterval <= 0 {
+		o.RetryInterval = 100 * time.Millisecond
+	}
+	return o
+}
+
+// Lock 是已获取的锁
+type Lock interface {
+	// Key 返回锁的键
+	Key() string
+	// Token 返回栅栏令牌，同一个键每次加锁单调递增
+	Token() int64
+	// Lost 返回一个通道，锁续期失败、过期或被释放时关闭
+	Lost() <-chan struct{}
+	// Release 释放锁，锁已失效时返回 ErrLockLost
+	Release(ctx context.Context) error
+}
+
+// Locker 定义了分布式锁的行为
+type Locker interface {
+	// TryAcquire 尝试获取锁，锁已被占用时立即返回 ErrNotAcquired
+	TryAcquire(ctx context.Context, key string) (Lock, error)
+	// Acquire 获取锁，锁已被占用时等待，直到获取成功或 ctx 结束
+	Acquire(ctx context.Context, key string) (Lock, error)
+}
+
+// Do 尝试获取锁并执行 fn，执行完毕后释放锁
+// 锁已被占用时返回 ErrNotAcquired；锁在执行过程中失效时取消 fn 的上下文。
+// 适用于多实例部署下只需一个实例执行的定时任务
+// ctx: 上下文
+// locker: 分布式锁
+// key: 锁的键
+// fn: 持锁期间执行的函数
+func Do(ctx context.Context, locker Locker, key string, fn func(ctx context.Context) error) error {
+	l, err := locker.TryAcquire(ctx, key)
+	if err != nil {
+		return err
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-l.Lost():
+			cancel()
+		case <-runCtx.Done():
+		}
+	}()
+
+	err = fn(runCtx)
+	releaseCtx, releaseCancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer releaseCancel()
+	if rerr := l.Release(releaseCtx); rerr != nil && err == nil {
+		err = rerr
+	}
+	return err
+}
+
+// acquireLoop 重复调用 try 直到获取成功或 ctx 结束
+func acquireLoop(ctx context.Context, interval time.Duration, try func(ctx context.Context) (Lock, error)) (Lock, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		l, err := try(ctx)
+		if !errors.Is(err, ErrNotAcquired) {
+			return l, err
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// renewableLock 是通过定期续期保持有效的锁，供 Redis 和进程内实现使用
+type renewableLock struct {
+	key     string
+	token   int64
+	ttl     time.Duration
+	renew   func(ctx context.Context) (bool, error)
+	release func(ctx context.Context) (bool, error)
+
+	lost     chan struct{}
+	lostOnce sync.Once
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// newRenewableLock 创建锁并启动续期协程
+// validity: 加锁后锁的剩余有效时间
+func newRenewableLock(key string, token int64, options Options, validity time.Duration,
+	renew, release func(ctx context.Context) (bool, error)) *renewableLock {
+	l := &renewableLock{
+		key:     key,
+		token:   token,
+		ttl:     options.TTL,
+		renew:   renew,
+		release: release,
+		lost:    make(chan struct{}),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go l.keepAlive(validity, !options.DisableRenew)
+	return l
+}
+
+// Key 返回锁的键
+func (l *renewableLock) Key() string {
+	return l.key
+}
+
+// Token 返回栅栏令牌
+func (l *renewableLock) Token() int64 {
+	return l.token
+}
+
+// Lost 返回锁失效通知
+func (l *renewableLock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Release 停止续期并释放锁
+func (l *renewableLock) Release(ctx context.Context) error {
+	l.stopOnce.Do(func() { close(l.stop) })
+	<-l.done
+	select {
+	case <-l.lost:
+		return ErrLockLost
+	default:
+	}
+	defer l.markLost()
+	owned, err := l.release(ctx)
+	if err != nil {
+		return err
+	}
+	if !owned {
+		return ErrLockLost
+	}
+	return nil
+}
+
+// keepAlive 每隔 TTL/3 续期一次；续期出错时继续重试，直到锁过期
+func (l *renewableLock) keepAlive(validity time.Duration, renew bool) {
+	defer close(l.done)
+	deadline := time.Now().Add(validity)
+	if !renew {
+		timer := time.NewTimer(validity)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			l.markLost()
+		case <-l.stop:
+		}
+		return
+	}
+
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-l.stop:
+			return
+		}
+		start := time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), l.ttl/3)
+		owned, err := l.renew(ctx)
+		cancel()
+		switch {
+		case err == nil && owned:
+			deadline = start.Add(l.ttl)
+		case err == nil && !owned:
+			l.markLost()
+			return
+		case time.Now().After(deadline):
+			l.markLost()
+			return
+		}
+	}
+}
+
+// markLost 标记锁已失效
+func (l *renewableLock) markLost() {
+	l.lostOnce.Do(func() { close(l.lost) })
+}
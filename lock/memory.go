@@ -0,0 +1,78 @@
+package lock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory 是进程内的锁实现，适用于单实例部署和测试
+type Memory struct {
+	mu      sync.Mutex
+	options Options
+	owners  map[string]memoryOwner
+	fences  map[string]int64
+}
+
+// memoryOwner 是锁的当前持有者
+type memoryOwner struct {
+	value    string
+	expireAt time.Time
+}
+
+// NewMemory 创建进程内锁
+// options: 锁配置
+func NewMemory(options Options) *Memory {
+	return &Memory{
+		options: options.withDefaults(""),
+		owners:  make(map[string]memoryOwner),
+		fences:  make(map[string]int64),
+	}
+}
+
+// TryAcquire 尝试获取锁
+func (m *Memory) TryAcquire(ctx context.Context, key string) (Lock, error) {
+	value, err := randomValue()
+	if err != nil {
+		return nil, err
+	}
+	ttl := m.options.TTL
+
+	m.mu.Lock()
+	if owner, ok := m.owners[key]; ok && time.Now().Before(owner.expireAt) {
+		m.mu.Unlock()
+		return nil, ErrNotAcquired
+	}
+	m.owners[key] = memoryOwner{value: value, expireAt: time.Now().Add(ttl)}
+	m.fences[key]++
+	token := m.fences[key]
+	m.mu.Unlock()
+
+	// update 在仍持有锁时执行 fn
+	update := func(fn func()) bool {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		owner, ok := m.owners[key]
+		if !ok || owner.value != value || time.Now().After(owner.expireAt) {
+			return false
+		}
+		fn()
+		return true
+	}
+	renew := func(context.Context) (bool, error) {
+		return update(func() {
+			m.owners[key] = memoryOwner{value: value, expireAt: time.Now().Add(ttl)}
+		}), nil
+	}
+	release := func(context.Context) (bool, error) {
+		return update(func() { delete(m.owners, key) }), nil
+	}
+	return newRenewableLock(key, token, m.options, ttl, renew, release), nil
+}
+
+// Acquire 获取锁，锁已被占用时等待
+func (m *Memory) Acquire(ctx context.Context, key string) (Lock, error) {
+	return acquireLoop(ctx, m.options.RetryInterval, func(ctx context.Context) (Lock, error) {
+		return m.TryAcquire(ctx, key)
+	})
+}
@@ -0,0 +1,176 @@
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// acquireScript 加锁成功时递增并返回栅栏令牌，否则返回 0
+	acquireScript = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("INCR", KEYS[2])
+end
+return 0`)
+	// renewScript 仅当锁仍由自己持有时延长有效期
+	renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+	// releaseScript 仅当锁仍由自己持有时删除
+	releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// Redis 是基于 Redis 的分布式锁
+// 传入多个相互独立的 Redis 节点时使用 Redlock 算法，需在多数节点上加锁成功；
+// 此时栅栏令牌取各节点计数的最大值，只有单节点部署时才严格单调递增
+type Redis struct {
+	clients []redis.UniversalClient
+	options Options
+	quorum  int
+}
+
+// NewRedis 创建基于 Redis 的分布式锁
+// options: 锁配置
+// clients: Redis 客户端，多个时为相互独立的节点
+func NewRedis(options Options, clients ...redis.UniversalClient) *Redis {
+	if len(clients) == 0 {
+		panic("lock: 至少需要一个 Redis 客户端")
+	}
+	return &Redis{
+		clients: clients,
+		options: options.withDefaults("lock:"),
+		quorum:  len(clients)/2 + 1,
+	}
+}
+
+// TryAcquire 尝试获取锁
+func (r *Redis) TryAcquire(ctx context.Context, key string) (Lock, error) {
+	// 使用 hash tag 保证锁和令牌计数位于 Redis Cluster 的同一个槽
+	lockKey := r.options.Prefix + "{" + key + "}"
+	fenceKey := lockKey + ":fence"
+	value, err := randomValue()
+	if err != nil {
+		return nil, err
+	}
+	ttl := r.options.TTL.Milliseconds()
+
+	start := time.Now()
+	var (
+		mu       sync.Mutex
+		token    int64
+		firstErr error
+	)
+	acquired := r.each(ctx, func(ctx context.Context, client redis.UniversalClient) (bool, error) {
+		n, err := acquireScript.Run(ctx, client, []string{lockKey, fenceKey}, value, ttl).Int64()
+		if err != nil {
+			mu.Lock()
+			if firstErr == nil {
+				firstErr = err
+			}
+			mu.Unlock()
+			return false, err
+		}
+		mu.Lock()
+		token = max(token, n)
+		mu.Unlock()
+		return n > 0, nil
+	})
+
+	// 扣除加锁耗时和时钟漂移后的剩余有效时间
+	drift := r.options.TTL/100 + 2*time.Millisecond
+	validity := r.options.TTL - time.Since(start) - drift
+
+	release := func(ctx context.Context) (bool, error) {
+		n := r.each(ctx, func(ctx context.Context, client redis.UniversalClient) (bool, error) {
+			deleted, err := releaseScript.Run(ctx, client, []string{lockKey}, value).Int64()
+			return deleted == 1, err
+		})
+		return n >= r.quorum, nil
+	}
+
+	if acquired < r.quorum || validity <= 0 {
+		// 回滚已加锁的节点
+		_, _ = release(context.WithoutCancel(ctx))
+		if firstErr != nil {
+			return nil, fmt.Errorf("%w: %w", ErrNotAcquired, firstErr)
+		}
+		return nil, ErrNotAcquired
+	}
+
+	renew := func(ctx context.Context) (bool, error) {
+		var (
+			mu       sync.Mutex
+			renewErr error
+		)
+		n := r.each(ctx, func(ctx context.Context, client redis.UniversalClient) (bool, error) {
+			ok, err := renewScript.Run(ctx, client, []string{lockKey}, value, ttl).Int64()
+			if err != nil {
+				mu.Lock()
+				renewErr = err
+				mu.Unlock()
+			}
+			return ok == 1, err
+		})
+		if n >= r.quorum {
+			return true, nil
+		}
+		return false, renewErr
+	}
+	return newRenewableLock(key, token, r.options, validity, renew, release), nil
+}
+
+// Acquire 获取锁，锁已被占用时等待
+func (r *Redis) Acquire(ctx context.Context, key string) (Lock, error) {
+	return acquireLoop(ctx, r.options.RetryInterval, func(ctx context.Context) (Lock, error) {
+		return r.TryAcquire(ctx, key)
+	})
+}
+
+// each 在所有节点上并发执行 fn，返回成功的节点数
+func (r *Redis) each(ctx context.Context, fn func(ctx context.Context, client redis.UniversalClient) (bool, error)) int {
+	if len(r.clients) == 1 {
+		if ok, _ := fn(ctx, r.clients[0]); ok {
+			return 1
+		}
+		return 0
+	}
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+		n  int
+	)
+	for _, client := range r.clients {
+		wg.Add(1)
+		go func(client redis.UniversalClient) {
+			defer wg.Done()
+			if ok, _ := fn(ctx, client); ok {
+				mu.Lock()
+				n++
+				mu.Unlock()
+			}
+		}(client)
+	}
+	wg.Wait()
+	return n
+}
+
+// randomValue 生成锁的持有者标识
+func randomValue() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("lock: 生成随机值失败: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
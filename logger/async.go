@@ -0,0 +1,210 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy 定义了异步日志缓冲区已满时的处理方式
+type OverflowPolicy int
+
+const (
+	OverflowBlock OverflowPolicy = iota // 阻塞等待，不丢失日志，默认
+	OverflowDrop                        // 丢弃新日志，不阻塞业务，丢弃数量会在下次写入时记录一条警告
+)
+
+// AsyncOptions 定义了异步日志配置
+type AsyncOptions struct {
+	// BufferSize 缓冲的日志条数，默认 1024
+	BufferSize int
+	// FlushInterval 批量写入的最长间隔，默认 1 秒
+	FlushInterval time.Duration
+	// BatchSize 累积到该字节数时立即写入，默认 64KB
+	BatchSize int
+	// Overflow 缓冲区已满时的处理方式
+	Overflow OverflowPolicy
+}
+
+// asyncEntry 是待写入的日志行
+type asyncEntry struct {
+	console string
+	file    string
+}
+
+// asyncWriter 在后台协程中批量写入日志
+type asyncWriter struct {
+	logger  *Logger
+	options AsyncOptions
+	entries chan asyncEntry
+	flushes chan chan struct{}
+	done    chan struct{}
+
+	mu      sync.RWMutex // 保护 closed，避免向已关闭的通道发送
+	closed  bool
+	dropped atomic.Int64 // 尚未报告的丢弃数
+}
+
+// newAsyncWriter 创建并启动异步写入协程
+func newAsyncWriter(l *Logger, options AsyncOptions) *asyncWriter {
+	if options.BufferSize <= 0 {
+		options.BufferSize = 1024
+	}
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = time.Second
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 64 * 1024
+	}
+	a := &asyncWriter{
+		logger:  l,
+		options: options,
+		entries: make(chan asyncEntry, options.BufferSize),
+		flushes: make(chan chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// enqueue 将日志放入缓冲区，异步写入已关闭时返回 false
+func (a *asyncWriter) enqueue(console, file string) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		return false
+	}
+	entry := asyncEntry{console: console, file: file}
+	if a.options.Overflow == OverflowDrop {
+		select {
+		case a.entries <- entry:
+		default:
+			a.dropped.Add(1)
+			a.logger.dropped.Add(1)
+		}
+		return true
+	}
+	a.entries <- entry
+	return true
+}
+
+// run 从缓冲区读取日志，按批量大小、时间间隔或 Flush 请求写入
+func (a *asyncWriter) run() {
+	defer close(a.done)
+	ticker := time.NewTicker(a.options.FlushInterval)
+	defer ticker.Stop()
+
+	var console, file bytes.Buffer
+	add := func(e asyncEntry) {
+		console.WriteString(e.console)
+		console.WriteByte('\n')
+		file.WriteString(e.file)
+		file.WriteByte('\n')
+	}
+	flush := func() {
+		if n := a.dropped.Swap(0); n > 0 {
+			add(entryOf(formatEntry(WARN, time.Now(), fmt.Sprintf("异步日志缓冲区已满，丢弃了 %d 条日志", n))))
+		}
+		if console.Len() > 0 {
+			a.logger.writeBatch(console.Bytes(), file.Bytes())
+			console.Reset()
+			file.Reset()
+		}
+	}
+
+	for {
+		select {
+		case e, ok := <-a.entries:
+			if !ok {
+				flush()
+				return
+			}
+			add(e)
+			if file.Len() >= a.options.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case req := <-a.flushes:
+			// 写入 Flush 调用之前已进入缓冲区的日志
+			for n := len(a.entries); n > 0; n-- {
+				add(<-a.entries)
+			}
+			flush()
+			close(req)
+		}
+	}
+}
+
+// entryOf 将格式化结果转换为 asyncEntry
+func entryOf(console, file string) asyncEntry {
+	return asyncEntry{console: console, file: file}
+}
+
+// flush 等待缓冲区中的日志写入完成
+func (a *asyncWriter) flush() {
+	req := make(chan struct{})
+	select {
+	case a.flushes <- req:
+		<-req
+	case <-a.done:
+	}
+}
+
+// close 停止接收日志，写入缓冲区中剩余的日志后返回
+func (a *asyncWriter) close() {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.entries)
+	}
+	a.mu.Unlock()
+	<-a.done
+}
+
+// writeBatch 将一批日志写入控制台和文件
+func (l *Logger) writeBatch(console, file []byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stdLogger.Writer().Write(console)
+	if l.logFile != nil {
+		l.logFile.Write(file)
+	}
+}
+
+// asyncWriter 返回异步写入器，同步模式下返回 nil
+func (l *Logger) asyncWriter() *asyncWriter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.async
+}
+
+// SetAsync 启用异步日志：日志先进入缓冲区，由后台协程批量写入控制台和文件，
+// 减少业务协程在锁和磁盘 I/O 上的等待；进程退出前应调用 Close 或 Flush，否则缓冲区中的日志会丢失
+// options: 异步日志配置
+func (l *Logger) SetAsync(options AsyncOptions) {
+	l.mu.Lock()
+	old := l.async
+	l.async = newAsyncWriter(l, options)
+	l.mu.Unlock()
+	if old != nil {
+		old.close()
+	}
+}
+
+// Flush 等待异步缓冲区中的日志写入完成，并等待钩子缓冲区中的日志交给钩子
+func (l *Logger) Flush() {
+	if a := l.asyncWriter(); a != nil {
+		a.flush()
+	}
+	for _, h := range l.hookRunners() {
+		h.flush()
+	}
+}
+
+// Dropped 返回异步模式下因缓冲区已满而丢弃的日志条数
+func (l *Logger) Dropped() int64 {
+	return l.dropped.Load()
+}
@@ -0,0 +1,181 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fields 是一组日志字段
+type Fields map[string]interface{}
+
+// Entry 是携带上下文字段的日志记录器，输出时在消息前加上 [key=value ...]
+// 通常由请求ID中间件创建并放入请求的 context，处理函数通过 FromContext 或 c.Logger() 获取；
+// 也可以通过 Logger.With 从指定的日志记录器派生
+type Entry struct {
+	logger *Logger // 输出日志的记录器，为 nil 时使用包级别日志记录器
+	prefix string  // 已格式化的字段
+	module string  // 模块名，用于匹配模块级别覆盖
+}
+
+// With 返回追加了字段的日志记录器，原记录器不受影响
+// key: 字段名，为 ModuleKey 时同时设置模块名
+// value: 字段值，包含空格、引号或等号时输出为带引号的字符串
+func (e *Entry) With(key string, value interface{}) *Entry {
+	child := &Entry{}
+	if e != nil {
+		*child = *e
+	}
+	field := formatField(key, value)
+	if child.prefix == "" {
+		child.prefix = field
+	} else {
+		child.prefix += " " + field
+	}
+	if key == ModuleKey {
+		child.module = fmt.Sprint(value)
+	}
+	return child
+}
+
+// WithFields 返回追加了多个字段的日志记录器，字段按名称排序输出
+// fields: 字段
+func (e *Entry) WithFields(fields Fields) *Entry {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	child := &Entry{}
+	if e != nil {
+		*child = *e
+	}
+	for _, key := range keys {
+		child = child.With(key, fields[key])
+	}
+	return child
+}
+
+// Module 返回设置了模块名的日志记录器
+// name: 模块名，例如 "ratelimit"、"cron"
+func (e *Entry) Module(name string) *Entry {
+	return e.With(ModuleKey, name)
+}
+
+// formatField 将字段格式化为 key=value
+func formatField(key string, value interface{}) string {
+	s := fmt.Sprint(value)
+	if s == "" || strings.ContainsAny(s, " \t\r\n\"=") {
+		s = strconv.Quote(s)
+	}
+	return key + "=" + s
+}
+
+// log 按模块级别覆盖或全局级别过滤后输出日志
+func (e *Entry) log(level LogLevel, format string, v ...interface{}) {
+	var l *Logger
+	var threshold LogLevel
+	if e != nil && e.logger != nil {
+		l, threshold = e.logger, e.logger.level
+	} else {
+		l, threshold = packageLogger(level), minLevel
+	}
+	if l == nil {
+		return
+	}
+	if e != nil && e.module != "" {
+		if override, ok := ModuleLevel(e.module); ok {
+			threshold = override
+		}
+	}
+	if level < threshold {
+		return
+	}
+	msg := fmt.Sprintf(format, v...)
+	if e != nil && e.prefix != "" {
+		msg = "[" + e.prefix + "] " + msg
+	}
+	l.output(level, msg)
+}
+
+// Debug 记录调试级别日志
+func (e *Entry) Debug(format string, v ...interface{}) {
+	e.log(DEBUG, format, v...)
+}
+
+// Info 记录信息级别日志
+func (e *Entry) Info(format string, v ...interface{}) {
+	e.log(INFO, format, v...)
+}
+
+// Warn 记录警告级别日志
+func (e *Entry) Warn(format string, v ...interface{}) {
+	e.log(WARN, format, v...)
+}
+
+// Error 记录错误级别日志
+func (e *Entry) Error(format string, v ...interface{}) {
+	e.log(ERROR, format, v...)
+}
+
+// With 返回带有字段的日志记录器，输出到包级别日志记录器
+// key: 字段名
+// value: 字段值
+func With(key string, value interface{}) *Entry {
+	return (*Entry)(nil).With(key, value)
+}
+
+// WithFields 返回带有多个字段的日志记录器，输出到包级别日志记录器
+// fields: 字段
+func WithFields(fields Fields) *Entry {
+	return (*Entry)(nil).WithFields(fields)
+}
+
+// Module 返回设置了模块名的日志记录器，输出到包级别日志记录器
+// name: 模块名
+func Module(name string) *Entry {
+	return (*Entry)(nil).Module(name)
+}
+
+// With 返回带有字段、输出到该记录器的日志记录器
+// key: 字段名
+// value: 字段值
+func (l *Logger) With(key string, value interface{}) *Entry {
+	return (&Entry{logger: l}).With(key, value)
+}
+
+// WithFields 返回带有多个字段、输出到该记录器的日志记录器
+// fields: 字段
+func (l *Logger) WithFields(fields Fields) *Entry {
+	return (&Entry{logger: l}).WithFields(fields)
+}
+
+// Module 返回设置了模块名、输出到该记录器的日志记录器
+// name: 模块名
+func (l *Logger) Module(name string) *Entry {
+	return (&Entry{logger: l}).Module(name)
+}
+
+// entryKey 是日志记录器在 context 中的键
+type entryKey struct{}
+
+// NewContext 返回携带日志记录器的 context
+// ctx: 父 context
+// entry: 日志记录器
+func NewContext(ctx context.Context, entry *Entry) context.Context {
+	return context.WithValue(ctx, entryKey{}, entry)
+}
+
+// FromContext 返回 context 中的日志记录器，不存在时返回不带字段的记录器
+// ctx: 请求的 context，*core.Context 也可直接传入
+func FromContext(ctx context.Context) *Entry {
+	if ctx != nil {
+		if entry, ok := ctx.Value(entryKey{}).(*Entry); ok {
+			return entry
+		}
+	}
+	return &Entry{}
+}
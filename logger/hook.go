@@ -0,0 +1,222 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// hookBufferSize 是每个钩子缓冲的日志条数，缓冲区已满时丢弃，避免慢速钩子阻塞业务
+const hookBufferSize = 1024
+
+// hookBatchSize 是 BatchHook 单次写入的最大日志条数
+const hookBatchSize = 100
+
+// Record 是交给钩子的一条日志
+type Record struct {
+	Level   LogLevel  `json:"level"`   // 日志级别
+	Time    time.Time `json:"time"`    // 记录时间
+	Message string    `json:"message"` // 日志消息，包含 [key=value ...] 字段前缀
+}
+
+// WriterHook 定义了日志输出目标，例如 syslog、Kafka、HTTP 收集器
+// 日志在控制台和文件之外再交给钩子，钩子在独立的协程中执行：
+// 钩子返回错误、panic 或写入过慢都不会影响业务和其他输出，缓冲区已满时日志被丢弃。
+// 同一个钩子添加到多个记录器时（例如包级别的 AddHook），Write 可能被并发调用
+type WriterHook interface {
+	// Write 写入一条日志
+	Write(r Record) error
+}
+
+// BatchHook 是支持批量写入的钩子，积压多条日志时一次写入，例如 HTTP 收集器
+type BatchHook interface {
+	WriterHook
+	// WriteBatch 写入多条日志
+	WriteBatch(records []Record) error
+}
+
+// HookFunc 将函数适配为 WriterHook，例如包装 Kafka 生产者
+type HookFunc func(r Record) error
+
+// Write 调用 f(r)
+func (f HookFunc) Write(r Record) error {
+	return f(r)
+}
+
+// String 返回日志级别的名称，例如 "INFO"
+func (level LogLevel) String() string {
+	return getLevelString(level)
+}
+
+// MarshalText 将日志级别编码为名称，使 Record 编码为 JSON 时输出 "level":"INFO"
+func (level LogLevel) MarshalText() ([]byte, error) {
+	return []byte(level.String()), nil
+}
+
+// hookRunner 在独立的协程中将日志交给钩子
+type hookRunner struct {
+	hook    WriterHook
+	level   LogLevel
+	records chan Record
+	flushes chan chan struct{}
+	done    chan struct{}
+
+	mu      sync.RWMutex // 保护 closed，避免向已关闭的通道发送
+	closed  bool
+	failing atomic.Bool  // 钩子是否处于失败状态，只在状态变化时报告
+	dropped atomic.Int64 // 缓冲区已满而丢弃的日志条数
+}
+
+// newHookRunner 创建并启动钩子协程
+func newHookRunner(hook WriterHook, level LogLevel) *hookRunner {
+	h := &hookRunner{
+		hook:    hook,
+		level:   level,
+		records: make(chan Record, hookBufferSize),
+		flushes: make(chan chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go h.run()
+	return h
+}
+
+// send 将日志放入钩子的缓冲区，缓冲区已满时丢弃
+func (h *hookRunner) send(r Record) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.closed {
+		return
+	}
+	select {
+	case h.records <- r:
+	default:
+		h.dropped.Add(1)
+	}
+}
+
+// run 从缓冲区读取日志并交给钩子
+func (h *hookRunner) run() {
+	defer close(h.done)
+	for {
+		select {
+		case r, ok := <-h.records:
+			if !ok {
+				return
+			}
+			batch := []Record{r}
+			// 积压的日志一并写入
+			for len(batch) < hookBatchSize && len(h.records) > 0 {
+				if r, ok = <-h.records; !ok {
+					break
+				}
+				batch = append(batch, r)
+			}
+			h.deliver(batch)
+			if !ok {
+				return
+			}
+		case req := <-h.flushes:
+			var batch []Record
+			for n := len(h.records); n > 0; n-- {
+				batch = append(batch, <-h.records)
+			}
+			for len(batch) > 0 {
+				n := min(len(batch), hookBatchSize)
+				h.deliver(batch[:n])
+				batch = batch[n:]
+			}
+			close(req)
+		}
+	}
+}
+
+// deliver 调用钩子写入日志，恢复钩子的 panic，并在失败和恢复时向标准错误报告
+func (h *hookRunner) deliver(batch []Record) {
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		if b, ok := h.hook.(BatchHook); ok {
+			return b.WriteBatch(batch)
+		}
+		// 单条失败不影响其余日志，返回最后一个错误
+		for _, r := range batch {
+			if werr := h.hook.Write(r); werr != nil {
+				err = werr
+			}
+		}
+		return err
+	}()
+	// 钩子失败时不能再写入日志记录器，否则可能递归
+	if err != nil {
+		if !h.failing.Swap(true) {
+			fmt.Fprintf(os.Stderr, "logger: 日志钩子 %T 写入失败: %v\n", h.hook, err)
+		}
+	} else if h.failing.Swap(false) {
+		fmt.Fprintf(os.Stderr, "logger: 日志钩子 %T 已恢复\n", h.hook)
+	}
+}
+
+// flush 等待缓冲区中的日志交给钩子
+func (h *hookRunner) flush() {
+	req := make(chan struct{})
+	select {
+	case h.flushes <- req:
+		<-req
+	case <-h.done:
+	}
+}
+
+// close 停止接收日志，将缓冲区中剩余的日志交给钩子后返回
+func (h *hookRunner) close() {
+	h.mu.Lock()
+	if !h.closed {
+		h.closed = true
+		close(h.records)
+	}
+	h.mu.Unlock()
+	<-h.done
+}
+
+// AddHook 添加日志输出目标，不低于 level 的日志会交给钩子
+// Close 会将缓冲区中剩余的日志交给钩子，但不关闭钩子本身
+// hook: 输出目标
+// level: 钩子的最低日志级别，例如只将 ERROR 及以上发送到告警收集器
+func (l *Logger) AddHook(hook WriterHook, level LogLevel) {
+	runner := newHookRunner(hook, level)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// 复制后替换，输出日志时无需在持有锁的情况下遍历
+	hooks := make([]*hookRunner, len(l.hooks), len(l.hooks)+1)
+	copy(hooks, l.hooks)
+	l.hooks = append(hooks, runner)
+}
+
+// HookDropped 返回因钩子缓冲区已满而丢弃的日志条数
+func (l *Logger) HookDropped() int64 {
+	var n int64
+	for _, h := range l.hookRunners() {
+		n += h.dropped.Load()
+	}
+	return n
+}
+
+// hookRunners 返回当前的钩子
+func (l *Logger) hookRunners() []*hookRunner {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.hooks
+}
+
+// fireHooks 将日志交给级别匹配的钩子
+func (l *Logger) fireHooks(r Record) {
+	for _, h := range l.hookRunners() {
+		if r.Level >= h.level {
+			h.send(r)
+		}
+	}
+}
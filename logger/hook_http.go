@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HTTPHookOptions 定义了 HTTP 日志收集器配置
+type HTTPHookOptions struct {
+	// URL 收集器地址，日志以 JSON 数组 POST 到该地址
+	URL string
+	// Headers 附加的请求头，例如认证令牌
+	Headers map[string]string
+	// Client 发送请求的客户端，默认超时 5 秒
+	Client *http.Client
+}
+
+// HTTPHook 将日志以 JSON 数组发送到 HTTP 收集器，例如 Loki、Logstash、Vector 的 HTTP 输入
+// 请求体格式：[{"level":"ERROR","time":"2024-01-02T15:04:05Z","message":"..."}]
+type HTTPHook struct {
+	options HTTPHookOptions
+}
+
+// NewHTTPHook 创建 HTTP 日志钩子
+// options: 收集器配置
+func NewHTTPHook(options HTTPHookOptions) *HTTPHook {
+	if options.Client == nil {
+		options.Client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &HTTPHook{options: options}
+}
+
+// Write 发送一条日志
+func (h *HTTPHook) Write(r Record) error {
+	return h.WriteBatch([]Record{r})
+}
+
+// WriteBatch 以一个请求发送多条日志，收集器返回非 2xx 状态码时返回错误
+func (h *HTTPHook) WriteBatch(records []Record) error {
+	body, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, h.options.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range h.options.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := h.options.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("logger: 日志收集器返回 %s", resp.Status)
+	}
+	return nil
+}
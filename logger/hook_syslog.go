@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// syslogFacility 是 syslog 的 user 设施
+const syslogFacility = 1 << 3
+
+// syslogLocalAddrs 是本机 syslog 守护进程的常见套接字路径
+var syslogLocalAddrs = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogHook 将日志发送到 syslog，使用 RFC 3164 格式，不依赖 log/syslog，可在所有平台编译
+// 连接断开时在下次写入时重连
+type SyslogHook struct {
+	network  string
+	addr     string
+	tag      string
+	hostname string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslogHook 连接 syslog 守护进程
+// network: 网络类型，"udp"、"tcp"，为空时连接本机的 unix 套接字
+// addr: syslog 地址，例如 "syslog.internal:514"，network 为空时忽略
+// tag: 日志标签，通常为程序名，为空时使用 os.Args[0]
+func NewSyslogHook(network, addr, tag string) (*SyslogHook, error) {
+	if tag == "" {
+		tag = os.Args[0]
+	}
+	hostname, _ := os.Hostname()
+	h := &SyslogHook{network: network, addr: addr, tag: tag, hostname: hostname}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err := h.connect(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// connect 建立连接，调用方需持有锁
+func (h *SyslogHook) connect() error {
+	if h.conn != nil {
+		h.conn.Close()
+		h.conn = nil
+	}
+	if h.network != "" {
+		conn, err := net.DialTimeout(h.network, h.addr, 5*time.Second)
+		if err != nil {
+			return fmt.Errorf("logger: 无法连接 syslog %s: %w", h.addr, err)
+		}
+		h.conn = conn
+		return nil
+	}
+	for _, path := range syslogLocalAddrs {
+		for _, network := range []string{"unixgram", "unix"} {
+			if conn, err := net.Dial(network, path); err == nil {
+				h.conn = conn
+				return nil
+			}
+		}
+	}
+	return errors.New("logger: 找不到本机的 syslog 守护进程")
+}
+
+// Write 发送一条日志，发送失败时重连并重试一次
+func (h *SyslogHook) Write(r Record) error {
+	msg := h.format(r)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.conn != nil {
+		if err := h.write(msg); err == nil {
+			return nil
+		}
+	}
+	if err := h.connect(); err != nil {
+		return err
+	}
+	return h.write(msg)
+}
+
+// write 写入连接，调用方需持有锁
+func (h *SyslogHook) write(msg string) error {
+	h.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	_, err := h.conn.Write([]byte(msg))
+	return err
+}
+
+// format 按 RFC 3164 格式化日志，本机套接字省略主机名
+func (h *SyslogHook) format(r Record) string {
+	priority := syslogFacility | syslogSeverity(r.Level)
+	msg := strings.TrimSuffix(r.Message, "\n")
+	timestamp := r.Time.Format(time.Stamp)
+	if h.network == "" {
+		return fmt.Sprintf("<%d>%s %s[%d]: %s\n", priority, timestamp, h.tag, os.Getpid(), msg)
+	}
+	return fmt.Sprintf("<%d>%s %s %s[%d]: %s\n", priority, timestamp, h.hostname, h.tag, os.Getpid(), msg)
+}
+
+// Close 关闭连接
+func (h *SyslogHook) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.conn == nil {
+		return nil
+	}
+	err := h.conn.Close()
+	h.conn = nil
+	return err
+}
+
+// syslogSeverity 将日志级别转换为 syslog 严重程度
+func syslogSeverity(level LogLevel) int {
+	switch level {
+	case DEBUG:
+		return 7 // debug
+	case INFO:
+		return 6 // info
+	case WARN:
+		return 4 // warning
+	case ERROR:
+		return 3 // err
+	case FATAL:
+		return 2 // crit
+	}
+	return 5 // notice
+}
@@ -4,10 +4,13 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -40,10 +43,14 @@ const (
 // 支持多级日志、文件输出和并发安全
 type Logger struct {
 	*log.Logger
-	level     LogLevel    // 日志级别
-	logFile   *os.File    // 日志文件 (如果只输出到控制台或文件打开失败，则为 nil)
-	mu        sync.Mutex  // 互斥锁，保证并发安全
-	stdLogger *log.Logger // 标准日志记录器 (始终输出到 os.Stdout)
+	level     LogLevel       // 日志级别
+	logFile   io.WriteCloser // 日志文件 (如果只输出到控制台或文件打开失败，则为 nil)
+	logPath   string         // 日志文件路径
+	mu        sync.Mutex     // 互斥锁，保证并发安全
+	stdLogger *log.Logger    // 标准日志记录器 (始终输出到 os.Stdout)
+	async     *asyncWriter   // 异步写入器，同步模式下为 nil
+	dropped   atomic.Int64   // 异步模式下丢弃的日志条数
+	hooks     []*hookRunner  // 日志输出目标
 }
 
 var (
@@ -51,8 +58,21 @@ var (
 	infoLogger  *Logger
 	warnLogger  *Logger
 	errorLogger *Logger
+
+	initialized bool           // 是否已调用 InitWithOptions
+	logDir      string         // 包级别日志记录器的日志目录
+	minLevel    LogLevel       // 包级别日志记录器的最低级别
+	rotation    *RotateOptions // 包级别日志记录器的切割策略
+	async       *AsyncOptions  // 包级别日志记录器的异步配置
+	hooks       []hookSpec     // 包级别日志记录器的钩子
 )
 
+// hookSpec 是通过包级别 AddHook 添加的钩子
+type hookSpec struct {
+	hook  WriterHook
+	level LogLevel
+}
+
 // New 创建一个新的日志记录器
 // level: 日志级别
 // baseLogDir: 日志文件存储的根目录，例如 "logs"。如果为空，则只输出到控制台。
@@ -76,6 +96,7 @@ func New(level LogLevel, baseLogDir, logFileName string) *Logger {
 				l.stdLogger.Printf("无法打开日志文件 %s: %v", logFileName, err)
 			} else {
 				l.logFile = file
+				l.logPath = file.Name()
 				l.Logger = log.New(file, "", log.LstdFlags)
 			}
 		}
@@ -93,64 +114,69 @@ func (l *Logger) log(level LogLevel, format string, v ...interface{}) {
 	if level < l.level {
 		return
 	}
+	l.output(level, fmt.Sprintf(format, v...))
+}
 
-	// 加锁保证并发安全
-	l.mu.Lock()
-	defer l.mu.Unlock()
+// output 输出已格式化的日志消息，不检查日志级别
+// level: 日志级别
+// msg: 日志消息
+func (l *Logger) output(level LogLevel, msg string) {
+	now := time.Now()
+	console, file := formatEntry(level, now, msg)
+	// 异步模式下交给后台协程批量写入，异步写入已关闭时同步写入
+	if a := l.asyncWriter(); a == nil || !a.enqueue(console, file) {
+		l.write(console, file)
+	}
+	l.fireHooks(Record{Level: level, Time: now, Message: msg})
+
+	// 如果是致命错误，则退出程序
+	if level == FATAL {
+		l.Flush()
+		os.Exit(1)
+	}
+}
 
-	msg := fmt.Sprintf(format, v...)
-	now := time.Now().Format(time.DateTime) // 获取当前时间并格式化
+// formatEntry 返回控制台输出（带颜色码）和文件输出（不带颜色码）的日志行
+func formatEntry(level LogLevel, t time.Time, msg string) (console, file string) {
+	now := t.Format(time.DateTime) // 格式化当前时间
 
 	var color string
-	var levelStr string
-
 	switch level {
 	case DEBUG:
 		color = colorBlue
-		levelStr = "DEBUG"
 	case INFO:
 		color = colorGreen
-		levelStr = "INFO"
 	case WARN:
 		color = colorYellow
-		levelStr = "WARN"
-	case ERROR:
-		color = colorRed
-		levelStr = "ERROR"
-	case FATAL:
+	case ERROR, FATAL:
 		color = colorRed
-		levelStr = "FATAL"
 	case EASYGO:
 		color = colorMagenta
-		levelStr = "EASYGO"
 	default:
 		color = colorReset
-		levelStr = "UNKNOWN"
 	}
 
-	var logEntry string
+	// 文件不写入颜色码，避免文件内容被颜色码污染
 	if level == EASYGO {
-		logEntry = fmt.Sprintf("%s[EASYGO] %s %s%s", color, now, msg, colorReset)
+		file = fmt.Sprintf("[EASYGO] %s %s", now, msg)
 	} else {
-		logEntry = fmt.Sprintf("%s[EASYGO - %s] %s %s%s", color, levelStr, now, msg, colorReset)
+		file = fmt.Sprintf("[EASYGO - %s] %s %s", getLevelString(level), now, msg)
 	}
+	return color + file + colorReset, file
+}
+
+// write 同步输出一条日志到控制台和文件
+func (l *Logger) write(console, file string) {
+	// 加锁保证并发安全
+	l.mu.Lock()
+	defer l.mu.Unlock()
 
 	// 输出到控制台
-	l.stdLogger.Println(logEntry)
+	l.stdLogger.Println(console)
 
-	// 输出到文件 (文件不写入颜色码)
+	// 输出到文件
 	if l.logFile != nil {
-		// 移除颜色码再写入文件，避免文件内容被颜色码污染
-		fileLogEntry := fmt.Sprintf("[EASYGO - %s] %s %s", levelStr, now, msg)
-		if level == EASYGO {
-			fileLogEntry = fmt.Sprintf("[EASYGO] %s %s", now, msg)
-		}
-		fmt.Fprintln(l.logFile, fileLogEntry)
-	}
-
-	// 如果是致命错误，则退出程序
-	if level == FATAL {
-		os.Exit(1)
+		fmt.Fprintln(l.logFile, file)
 	}
 }
 
@@ -204,10 +230,48 @@ func (l *Logger) EasyGo(format string, v ...interface{}) {
 	l.log(EASYGO, format, v...)
 }
 
-// Close 关闭日志文件
+// SetRotation 为日志文件启用按大小和时间切割，只输出到控制台的记录器返回错误
+// options: 切割和保留策略
+func (l *Logger) SetRotation(options RotateOptions) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.logPath == "" {
+		return fmt.Errorf("logger: 记录器未配置日志文件")
+	}
+	if l.logFile != nil {
+		l.logFile.Close()
+		l.logFile = nil
+	}
+	w, err := NewRotateWriter(l.logPath, options)
+	if err != nil {
+		return err
+	}
+	l.logFile = w
+	l.Logger = log.New(w, "", log.LstdFlags)
+	return nil
+}
+
+// Close 写入异步缓冲区和钩子缓冲区中的日志，将日志文件刷新到磁盘并关闭
 func (l *Logger) Close() {
+	l.mu.Lock()
+	a, hooks := l.async, l.hooks
+	l.async, l.hooks = nil, nil
+	l.mu.Unlock()
+	if a != nil {
+		a.close()
+	}
+	for _, h := range hooks {
+		h.close()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	if l.logFile != nil {
+		if s, ok := l.logFile.(interface{ Sync() error }); ok {
+			s.Sync()
+		}
 		l.logFile.Close()
+		l.logFile = nil
 	}
 }
 
@@ -217,10 +281,138 @@ var defaultLogger = New(INFO, "logs", "app.log") // 修改默认日志器，使
 
 // Init 初始化日志记录器
 func Init() {
-	debugLogger = New(DEBUG, "logs", "debug.log")
-	infoLogger = New(INFO, "logs", "info.log")
-	warnLogger = New(WARN, "logs", "warn.log")
-	errorLogger = New(ERROR, "logs", "error.log")
+	InitWithOptions("logs", DEBUG)
+}
+
+// InitWithOptions 按目录和最低级别初始化日志记录器，低于最低级别的日志不会输出
+// dir: 日志文件目录
+// level: 最低日志级别
+func InitWithOptions(dir string, level LogLevel) {
+	// 关闭之前的日志记录器，写入其中尚未写入的异步日志
+	Close()
+	debugLogger, infoLogger, warnLogger, errorLogger = nil, nil, nil, nil
+	initialized, logDir, minLevel, rotation, async, hooks = true, dir, level, nil, nil, nil
+	ensureLoggers()
+}
+
+// ensureLoggers 按最低级别和模块级别覆盖创建缺少的包级别日志记录器
+// 模块级别低于全局级别时（例如只为 ratelimit 模块开启 debug），对应级别的日志文件也需要创建
+func ensureLoggers() {
+	if !initialized {
+		return
+	}
+	lowest := min(minLevel, lowestModuleLevel())
+	create := func(l **Logger, level LogLevel, name string) {
+		if *l != nil || level < lowest {
+			return
+		}
+		*l = New(level, logDir, name)
+		if rotation != nil && (*l).logPath != "" {
+			if err := (*l).SetRotation(*rotation); err != nil {
+				(*l).stdLogger.Printf("无法启用日志切割 %s: %v", name, err)
+			}
+		}
+		if async != nil {
+			(*l).SetAsync(*async)
+		}
+		for _, spec := range hooks {
+			// 包级别日志记录器只输出自身级别的日志
+			if level >= spec.level {
+				(*l).AddHook(spec.hook, spec.level)
+			}
+		}
+	}
+	create(&debugLogger, DEBUG, "debug.log")
+	create(&infoLogger, INFO, "info.log")
+	create(&warnLogger, WARN, "warn.log")
+	create(&errorLogger, ERROR, "error.log")
+}
+
+// packageLogger 返回输出该级别日志的包级别日志记录器，未创建时返回 nil
+func packageLogger(level LogLevel) *Logger {
+	switch level {
+	case DEBUG:
+		return debugLogger
+	case INFO:
+		return infoLogger
+	case WARN:
+		return warnLogger
+	case ERROR:
+		return errorLogger
+	}
+	return nil
+}
+
+// SetRotation 为包级别日志记录器的日志文件启用切割，应在 Init 或 InitWithOptions 之后调用
+// options: 切割和保留策略
+func SetRotation(options RotateOptions) error {
+	rotation = &options
+	for _, l := range []*Logger{debugLogger, infoLogger, warnLogger, errorLogger} {
+		if l == nil || l.logPath == "" {
+			continue
+		}
+		if err := l.SetRotation(options); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetAsync 为包级别日志记录器启用异步日志，应在 Init 或 InitWithOptions 之后调用
+// options: 异步日志配置
+func SetAsync(options AsyncOptions) {
+	async = &options
+	for _, l := range []*Logger{debugLogger, infoLogger, warnLogger, errorLogger} {
+		if l != nil {
+			l.SetAsync(options)
+		}
+	}
+}
+
+// AddHook 为包级别日志记录器添加日志输出目标，应在 Init 或 InitWithOptions 之后调用
+// hook: 输出目标，会被多个记录器并发调用
+// level: 钩子的最低日志级别
+func AddHook(hook WriterHook, level LogLevel) {
+	hooks = append(hooks, hookSpec{hook: hook, level: level})
+	for _, l := range []*Logger{debugLogger, infoLogger, warnLogger, errorLogger} {
+		// 包级别日志记录器只输出自身级别的日志，低于钩子级别的记录器无需添加
+		if l != nil && l.level >= level {
+			l.AddHook(hook, level)
+		}
+	}
+}
+
+// Flush 等待包级别日志记录器缓冲区中的日志写入完成
+func Flush() {
+	for _, l := range []*Logger{debugLogger, infoLogger, warnLogger, errorLogger} {
+		if l != nil {
+			l.Flush()
+		}
+	}
+}
+
+// ParseLevel 将 "debug"、"info"、"warn"、"error" 转换为日志级别
+func ParseLevel(s string) (LogLevel, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return DEBUG, nil
+	case "info":
+		return INFO, nil
+	case "warn", "warning":
+		return WARN, nil
+	case "error":
+		return ERROR, nil
+	}
+	return INFO, fmt.Errorf("logger: 未知的日志级别 %q", s)
+}
+
+// Close 刷新并关闭包级别日志记录器的日志文件，通常注册为引擎的关闭钩子
+func Close() {
+	for _, l := range []*Logger{debugLogger, infoLogger, warnLogger, errorLogger} {
+		if l != nil {
+			l.Close()
+		}
+	}
 }
 
 // 包级别日志函数
@@ -230,7 +422,17 @@ func Error(format string, v ...interface{}) {
 	}
 }
 func Info(format string, v ...interface{}) {
-	if infoLogger != nil {
+	if infoLogger != nil && INFO >= minLevel {
 		infoLogger.Info(format, v...)
 	}
 }
+func Debug(format string, v ...interface{}) {
+	if debugLogger != nil && DEBUG >= minLevel {
+		debugLogger.Debug(format, v...)
+	}
+}
+func Warn(format string, v ...interface{}) {
+	if warnLogger != nil && WARN >= minLevel {
+		warnLogger.Warn(format, v...)
+	}
+}
@@ -0,0 +1,50 @@
+package logger
+
+import "sync"
+
+// ModuleKey 是模块名字段，With(ModuleKey, name) 与 Module(name) 等价
+const ModuleKey = "module"
+
+var (
+	moduleMu     sync.RWMutex
+	moduleLevels = make(map[string]LogLevel)
+)
+
+// SetModuleLevel 覆盖模块的最低日志级别，模块内的日志按该级别而不是全局级别过滤
+// 例如全局为 info 时只为 ratelimit 模块开启 debug，或将嘈杂的模块提高到 warn
+// module: 模块名，与 Module(name) 或 With(ModuleKey, name) 中的名称一致
+// level: 最低日志级别
+func SetModuleLevel(module string, level LogLevel) {
+	moduleMu.Lock()
+	moduleLevels[module] = level
+	moduleMu.Unlock()
+	ensureLoggers()
+}
+
+// ResetModuleLevel 移除模块的日志级别覆盖，恢复使用全局级别
+// module: 模块名
+func ResetModuleLevel(module string) {
+	moduleMu.Lock()
+	defer moduleMu.Unlock()
+	delete(moduleLevels, module)
+}
+
+// ModuleLevel 返回模块的日志级别覆盖，未设置时 ok 为 false
+// module: 模块名
+func ModuleLevel(module string) (level LogLevel, ok bool) {
+	moduleMu.RLock()
+	defer moduleMu.RUnlock()
+	level, ok = moduleLevels[module]
+	return level, ok
+}
+
+// lowestModuleLevel 返回全部模块级别覆盖中的最低级别，没有覆盖时返回 EASYGO
+func lowestModuleLevel() LogLevel {
+	moduleMu.RLock()
+	defer moduleMu.RUnlock()
+	lowest := EASYGO
+	for _, level := range moduleLevels {
+		lowest = min(lowest, level)
+	}
+	return lowest
+}
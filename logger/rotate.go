@@ -0,0 +1,290 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat 是备份文件名中的时间格式，例如 app-2024-01-02T15-04-05.000.log
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// compressSuffix 是压缩后的备份文件后缀
+const compressSuffix = ".gz"
+
+// RotateOptions 定义了日志文件的切割和保留策略，各项为 0 时表示不启用
+type RotateOptions struct {
+	// MaxSize 单个日志文件的最大大小（MB），超过后切割
+	MaxSize int
+	// Interval 按时间切割的周期，例如 24 * time.Hour 表示每天零点切割，time.Hour 表示每小时切割
+	Interval time.Duration
+	// MaxAge 备份文件的最长保留时间，按备份文件名中的时间计算
+	MaxAge time.Duration
+	// MaxBackups 最多保留的备份文件数
+	MaxBackups int
+	// Compress 是否使用 gzip 压缩备份文件
+	Compress bool
+}
+
+// RotateWriter 是按大小和时间自动切割的日志文件
+// 切割时当前文件被重命名为 app-<时间>.log，再创建新的 app.log；
+// 压缩和清理旧文件在后台进行，不阻塞写入
+type RotateWriter struct {
+	filename string
+	options  RotateOptions
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	next   time.Time  // 下次按时间切割的时刻
+	millMu sync.Mutex // 串行执行压缩和清理
+}
+
+// NewRotateWriter 打开或创建日志文件
+// filename: 日志文件路径，例如 "logs/app.log"
+// options: 切割和保留策略
+func NewRotateWriter(filename string, options RotateOptions) (*RotateWriter, error) {
+	w := &RotateWriter{filename: filename, options: options}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.openExisting(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write 写入日志，写入前按需切割
+func (w *RotateWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		if err := w.openExisting(); err != nil {
+			return 0, err
+		}
+	}
+	now := time.Now()
+	if (w.options.Interval > 0 && !now.Before(w.next)) ||
+		(w.options.MaxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxSize()) {
+		if err := w.rotate(now); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Rotate 立即切割日志文件，可用于响应 SIGHUP 等外部信号
+func (w *RotateWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.rotate(time.Now())
+}
+
+// Sync 将日志文件刷新到磁盘
+func (w *RotateWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Sync()
+}
+
+// Close 关闭日志文件，之后的写入会重新打开文件
+func (w *RotateWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.close()
+}
+
+// close 关闭当前文件，调用方需持有锁
+func (w *RotateWriter) close() error {
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// maxSize 返回单个文件的最大字节数
+func (w *RotateWriter) maxSize() int64 {
+	return int64(w.options.MaxSize) * 1024 * 1024
+}
+
+// openExisting 以追加方式打开日志文件，文件属于上一个切割周期时先切割
+func (w *RotateWriter) openExisting() error {
+	if err := os.MkdirAll(filepath.Dir(w.filename), 0755); err != nil {
+		return fmt.Errorf("logger: 无法创建日志目录: %w", err)
+	}
+	now := time.Now()
+	info, err := os.Stat(w.filename)
+	if err == nil && w.options.Interval > 0 && info.ModTime().Before(w.periodStart(now)) {
+		// 进程重启前写入的文件已跨过切割时刻，以文件最后修改时间命名备份
+		if err := w.backup(info.ModTime()); err != nil {
+			return err
+		}
+		info, err = nil, os.ErrNotExist
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("logger: 无法读取日志文件: %w", err)
+	}
+
+	file, err := os.OpenFile(w.filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("logger: 无法打开日志文件: %w", err)
+	}
+	w.file = file
+	w.size = 0
+	if info != nil {
+		w.size = info.Size()
+	}
+	w.next = w.periodStart(now).Add(w.options.Interval)
+	return nil
+}
+
+// rotate 关闭当前文件、重命名为备份并创建新文件，调用方需持有锁
+func (w *RotateWriter) rotate(now time.Time) error {
+	if err := w.close(); err != nil {
+		return err
+	}
+	if err := w.backup(now); err != nil {
+		return err
+	}
+	return w.openExisting()
+}
+
+// backup 将当前日志文件重命名为带时间的备份文件，并在后台压缩和清理
+func (w *RotateWriter) backup(t time.Time) error {
+	if _, err := os.Stat(w.filename); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err := os.Rename(w.filename, w.backupName(t)); err != nil {
+		return fmt.Errorf("logger: 无法切割日志文件: %w", err)
+	}
+	go w.mill()
+	return nil
+}
+
+// periodStart 返回 t 所在切割周期的起始时刻，整天的周期按本地时间零点对齐
+func (w *RotateWriter) periodStart(t time.Time) time.Time {
+	interval := w.options.Interval
+	if interval <= 0 {
+		return time.Time{}
+	}
+	if interval%(24*time.Hour) == 0 {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	}
+	return t.Truncate(interval)
+}
+
+// backupName 返回备份文件名，例如 logs/app-2024-01-02T15-04-05.000.log
+func (w *RotateWriter) backupName(t time.Time) string {
+	dir, prefix, ext := w.nameParts()
+	return filepath.Join(dir, prefix+t.Format(backupTimeFormat)+ext)
+}
+
+// nameParts 返回日志目录、备份文件名前缀和扩展名
+func (w *RotateWriter) nameParts() (dir, prefix, ext string) {
+	dir = filepath.Dir(w.filename)
+	base := filepath.Base(w.filename)
+	ext = filepath.Ext(base)
+	return dir, strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// logBackup 是一个备份文件
+type logBackup struct {
+	path string
+	time time.Time
+}
+
+// backups 返回全部备份文件，按时间从新到旧排列
+func (w *RotateWriter) backups() ([]logBackup, error) {
+	dir, prefix, ext := w.nameParts()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var result []logBackup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimSuffix(name, compressSuffix), ext)
+		t, err := time.ParseInLocation(backupTimeFormat, strings.TrimPrefix(stamp, prefix), time.Local)
+		if err != nil {
+			continue
+		}
+		result = append(result, logBackup{path: filepath.Join(dir, name), time: t})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].time.After(result[j].time) })
+	return result, nil
+}
+
+// mill 压缩备份文件，并删除超出数量或保留时间的备份
+func (w *RotateWriter) mill() {
+	w.millMu.Lock()
+	defer w.millMu.Unlock()
+
+	backups, err := w.backups()
+	if err != nil {
+		return
+	}
+	cutoff := time.Now().Add(-w.options.MaxAge)
+	for i, b := range backups {
+		if (w.options.MaxBackups > 0 && i >= w.options.MaxBackups) ||
+			(w.options.MaxAge > 0 && b.time.Before(cutoff)) {
+			os.Remove(b.path)
+			continue
+		}
+		if w.options.Compress && !strings.HasSuffix(b.path, compressSuffix) {
+			if err := compressFile(b.path); err != nil {
+				fmt.Fprintf(os.Stderr, "logger: 压缩日志文件 %s 失败: %v\n", b.path, err)
+			}
+		}
+	}
+}
+
+// compressFile 将文件压缩为 .gz 并删除原文件
+func compressFile(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+compressSuffix, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(path + compressSuffix)
+		}
+	}()
+
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err = gz.Close(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	src.Close()
+	return os.Remove(path)
+}
@@ -3,18 +3,20 @@
 package main
 
 import (
-	"context"
-	"fmt"
+	"net/http"
 	"time"
 
+	"github.com/xzl-go/easygo/app"
+	"github.com/xzl-go/easygo/cache"
+	"github.com/xzl-go/easygo/captcha"
 	"github.com/xzl-go/easygo/core"
+	errs "github.com/xzl-go/easygo/errors"
 	"github.com/xzl-go/easygo/i18n"
 	"github.com/xzl-go/easygo/jwt"
 	"github.com/xzl-go/easygo/logger"
 	"github.com/xzl-go/easygo/middleware"
 	"github.com/xzl-go/easygo/rbac"
 	"github.com/xzl-go/easygo/tracing"
-	"github.com/xzl-go/easygo/validator"
 	"github.com/xzl-go/easygo/websocket"
 )
 
@@ -37,9 +39,11 @@ func main() {
 	// 初始化日志系统
 	logger.Init()
 
+	// 创建应用容器，管理各模块的启动与停止
+	application := app.New(app.Options{Report: true})
+
 	// 初始化链路追踪系统，用于分布式追踪
 	tracer := tracing.NewTracer("user-service")
-	defer tracer.Shutdown(context.Background())
 
 	// 初始化JWT管理器，设置密钥和token过期时间
 	jwtManager := jwt.NewJWTManager("your_secret_key", 24*time.Hour)
@@ -58,6 +62,9 @@ func main() {
 		return
 	}
 
+	// 初始化图形验证码，答案保存在内存缓存中
+	captchaManager := captcha.New(cache.NewMemory(time.Minute), captcha.DefaultConfig())
+
 	// 初始化定时任务
 	//cron.InitCron()
 	//defer cron.StopCron()
@@ -70,6 +77,16 @@ func main() {
 	// 创建Web应用引擎
 	app := core.New()
 
+	// 注册关闭钩子，优雅关闭时逆序执行：先关闭链路追踪，最后刷新日志
+	app.OnCloseFunc(logger.Close)
+	app.OnClose(tracer.Shutdown)
+
+	// 为每个请求创建追踪跨度，沿用上游服务的 traceparent
+	app.Use(middleware.Tracing(tracer))
+
+	// 生成或沿用请求ID，之后通过 ctx.Logger() 记录的日志都带有请求ID
+	app.Use(middleware.RequestID())
+
 	// 应用 Recovery 中间件，用于捕获 panic 并防止服务器崩溃
 	app.Use(middleware.Recovery())
 
@@ -79,32 +96,37 @@ func main() {
 	// 注册国际化中间件
 	app.Use(i18nManager.Middleware())
 
+	// 注册统一错误处理中间件，处理函数通过 ctx.Error(err) 返回错误
+	app.Use(middleware.ErrorHandler(i18nManager))
+	// 按请求语言翻译参数校验消息
+	app.SetTranslator(i18nManager)
+
+	// 获取图形验证码
+	app.GET("/captcha", captchaManager.Handler())
+
 	// 注册用户路由处理函数
 	app.POST("/register", func(ctx *core.Context) {
-		var user User
-		// 解析JSON请求体到User结构体
-		if err := ctx.BindJSON(&user); err != nil {
-			ctx.JSON(400, map[string]string{"error": err.Error()})
+		// 校验图形验证码
+		if !captchaManager.VerifyRequest(ctx) {
+			ctx.Fail(captcha.ErrInvalidCaptcha)
 			return
 		}
 
-		// 验证用户数据
-		if err := validator.Validate(user); err != nil {
-			ctx.JSON(400, map[string]string{"error": err.Error()})
+		var user User
+		// 解析请求体并验证用户数据，失败时已返回 400 或 422 响应
+		if err := ctx.BindAndValidate(&user); err != nil {
 			return
 		}
 
 		// 生成JWT令牌
 		token, err := jwtManager.GenerateToken(user.ID, user.Username)
 		if err != nil {
-			ctx.JSON(500, map[string]string{"error": "Token生成失败"})
+			ctx.Fail(errs.ErrInternal.Wrap(err))
 			return
 		}
 
-		lang := ctx.Get("lang").(string)
-		message := i18nManager.Translate("welcome.message", lang)
-		ctx.JSON(200, map[string]string{
-			"message": message,
+		ctx.Success(map[string]string{
+			"message": ctx.T("welcome.message"),
 			"token":   token,
 		})
 	})
@@ -116,54 +138,38 @@ func main() {
 			Password string `json:"password" validate:"required"`
 		}
 
+		// 校验图形验证码
+		if !captchaManager.VerifyRequest(ctx) {
+			ctx.Fail(captcha.ErrInvalidCaptcha)
+			return
+		}
+
 		// 解析登录请求
 		if err := ctx.BindJSON(&loginUser); err != nil {
-			ctx.JSON(400, map[string]string{"error": err.Error()})
+			ctx.Fail(errs.ErrBadRequest.Wrap(err))
 			return
 		}
 
 		// 模拟用户验证（实际应用中应该查询数据库）
 		if loginUser.Username == "admin" && loginUser.Password == "admin123" {
 			token, _ := jwtManager.GenerateToken("1", loginUser.Username)
-			lang := ctx.Get("lang").(string)
-			message := i18nManager.Translate("welcome.message", lang)
-			ctx.JSON(200, map[string]string{
-				"message": message,
+			ctx.Success(map[string]string{
+				"message": ctx.T("welcome.message"),
 				"token":   token,
 			})
 		} else {
-			ctx.JSON(401, map[string]string{"error": i18nManager.Translate("error.unauthorized", ctx.Get("lang").(string))})
+			ctx.JSONError(http.StatusUnauthorized, "error.unauthorized")
 		}
 	})
 
 	// 受保护的用户资料路由，需要认证和权限验证
-	app.GET("/profile", func(ctx *core.Context) {
-		// 获取认证头信息
-		authHeader := ctx.Header("Authorization")
-		if authHeader == "" {
-			ctx.JSON(401, map[string]string{"error": i18nManager.Translate("error.unauthorized", ctx.Get("lang").(string))})
-			return
-		}
-
-		// 验证JWT令牌
-		claims, err := jwtManager.VerifyToken(authHeader)
-		if err != nil {
-			ctx.JSON(401, map[string]string{"error": i18nManager.Translate("error.unauthorized", ctx.Get("lang").(string))})
-			return
-		}
-
-		// 检查用户权限
-		allowed, err := rbacManager.Enforce(claims.Username, "/profile", "GET")
-		if err != nil || !allowed {
-			ctx.JSON(403, map[string]string{"error": i18nManager.Translate("error.forbidden", ctx.Get("lang").(string))})
-			return
-		}
-
-		lang := ctx.Get("lang").(string)
-		message := fmt.Sprintf("欢迎，%s！", claims.Username)
-		translatedMessage := i18nManager.Translate(message, lang)
-		ctx.JSON(200, map[string]string{
-			"message": translatedMessage,
+	app.GET("/profile", middleware.JWT(jwtManager), middleware.RBACWithConfig(rbacManager, middleware.RBACConfig{
+		Subject:    middleware.RBACSubjectFromClaims,
+		Translator: i18nManager,
+	}), func(ctx *core.Context) {
+		claims := middleware.GetClaims(ctx)
+		ctx.Success(map[string]string{
+			"message": ctx.T("welcome.user", i18n.Params{"name": claims.Username}),
 		})
 	})
 
@@ -177,9 +183,10 @@ func main() {
 		websocket.HandleWebSocket(ctx)
 	})
 
-	// 启动Web服务器
-	if err := app.Run(":8080"); err != nil {
-		logger.Error("Failed to start server: %v", err)
+	// 启动Web服务器，收到退出信号后优雅关闭并执行关闭钩子
+	application.Serve(app, ":8080")
+	if err := application.Run(); err != nil {
+		logger.Error("Failed to run application: %v", err)
 		return
 	}
 }
@@ -0,0 +1,97 @@
+// Package metrics 提供了 Prometheus 指标采集和推送式指标导出
+// 常驻服务通过 Collector 采集 HTTP 请求指标并暴露 /metrics 端点供 Prometheus 抓取；
+// 定时任务、批处理和短生命周期的 worker 往往在 Prometheus 抓取前就已退出，
+// 因此提供 StatsD 和 Prometheus Pushgateway 两种主动推送方式
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/xzl-go/easygo/logger"
+)
+
+// Tags 是指标标签
+type Tags map[string]string
+
+// Sink 定义了推送式指标输出
+type Sink interface {
+	// Count 累加计数器
+	Count(name string, value int64, tags Tags)
+	// Gauge 设置仪表盘当前值
+	Gauge(name string, value float64, tags Tags)
+	// Timing 记录耗时
+	Timing(name string, d time.Duration, tags Tags)
+	// Flush 将缓存的指标推送出去
+	Flush(ctx context.Context) error
+	// Close 推送剩余指标并释放资源
+	Close() error
+}
+
+// Job 包装批处理任务，记录执行次数、失败次数、耗时和最后成功时间，并在任务结束后立即推送
+// 返回的函数可直接用于 cron.AddJob
+// sink: 指标输出
+// name: 任务名称，作为 task 标签（Pushgateway 保留了 job 标签）
+// fn: 任务函数
+func Job(sink Sink, name string, fn func(ctx context.Context) error) func() {
+	return func() {
+		tags := Tags{"task": name}
+		start := time.Now()
+		err := fn(context.Background())
+		sink.Timing("task_duration", time.Since(start), tags)
+		sink.Count("task_runs_total", 1, tags)
+		if err != nil {
+			sink.Count("task_failures_total", 1, tags)
+			logger.Error("任务 %s 执行失败：%v", name, err)
+		} else {
+			sink.Gauge("task_last_success_timestamp", float64(time.Now().Unix()), tags)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := sink.Flush(ctx); err != nil {
+			logger.Error("推送任务 %s 的指标失败：%v", name, err)
+		}
+	}
+}
+
+// Ticker 按固定间隔推送指标，适用于常驻的 worker
+type Ticker struct {
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// StartTicker 启动定期推送
+// sink: 指标输出
+// interval: 推送间隔
+func StartTicker(sink Sink, interval time.Duration) *Ticker {
+	t := &Ticker{stop: make(chan struct{}), done: make(chan struct{})}
+	go func() {
+		defer close(t.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				if err := sink.Flush(ctx); err != nil {
+					logger.Error("推送指标失败：%v", err)
+				}
+				cancel()
+			case <-t.stop:
+				return
+			}
+		}
+	}()
+	return t
+}
+
+// Stop 停止定期推送，不会关闭 sink
+func (t *Ticker) Stop() {
+	t.once.Do(func() {
+		close(t.stop)
+	})
+	<-t.done
+}
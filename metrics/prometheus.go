@@ -0,0 +1,212 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/xzl-go/easygo/core"
+)
+
+// CollectorOptions 定义了 HTTP 指标采集配置
+type CollectorOptions struct {
+	// Namespace 指标名前缀，例如 "myapp"，同时用于自定义指标
+	Namespace string
+	// Subsystem HTTP 指标的子系统名，默认 "http"
+	Subsystem string
+	// Buckets 请求耗时直方图的分桶（秒），默认为 prometheus.DefBuckets
+	Buckets []float64
+	// SizeBuckets 响应大小直方图的分桶（字节），默认 100B 到 100MB 按 10 倍递增
+	SizeBuckets []float64
+	// ConstLabels 附加到 HTTP 指标的固定标签，例如 {"service": "user"}
+	ConstLabels prometheus.Labels
+	// Registry 指标注册表，为 nil 时创建新的注册表并注册 Go 运行时和进程指标
+	Registry *prometheus.Registry
+}
+
+// Collector 采集 HTTP 请求指标并通过 /metrics 端点供 Prometheus 抓取
+// 记录的指标（以默认子系统为例）：
+//   - http_requests_total：请求数，标签 method、route、status
+//   - http_request_duration_seconds：请求耗时直方图，标签 method、route、status
+//   - http_response_size_bytes：响应大小直方图，标签 method、route、status
+//   - http_requests_in_flight：正在处理的请求数
+//
+// route 标签使用路由模式（如 /users/:id）而不是实际路径，避免标签数量无限增长；
+// 注册了 Tracing 中间件时，请求数和耗时带有 trace_id、span_id 的 exemplar
+type Collector struct {
+	options      CollectorOptions
+	registry     *prometheus.Registry
+	requests     *prometheus.CounterVec
+	duration     *prometheus.HistogramVec
+	responseSize *prometheus.HistogramVec
+	inFlight     prometheus.Gauge
+}
+
+// NewCollector 创建 HTTP 指标采集器，同一个注册表上只能创建一个
+// options: 采集配置
+func NewCollector(options CollectorOptions) *Collector {
+	if options.Subsystem == "" {
+		options.Subsystem = "http"
+	}
+	if options.Buckets == nil {
+		options.Buckets = prometheus.DefBuckets
+	}
+	if options.SizeBuckets == nil {
+		options.SizeBuckets = prometheus.ExponentialBuckets(100, 10, 7)
+	}
+	if options.Registry == nil {
+		options.Registry = prometheus.NewRegistry()
+		options.Registry.MustRegister(
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		)
+	}
+
+	labels := []string{"method", "route", "status"}
+	c := &Collector{
+		options:  options,
+		registry: options.Registry,
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   options.Namespace,
+			Subsystem:   options.Subsystem,
+			Name:        "requests_total",
+			Help:        "HTTP 请求总数",
+			ConstLabels: options.ConstLabels,
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   options.Namespace,
+			Subsystem:   options.Subsystem,
+			Name:        "request_duration_seconds",
+			Help:        "HTTP 请求耗时（秒）",
+			ConstLabels: options.ConstLabels,
+			Buckets:     options.Buckets,
+		}, labels),
+		responseSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   options.Namespace,
+			Subsystem:   options.Subsystem,
+			Name:        "response_size_bytes",
+			Help:        "HTTP 响应大小（字节）",
+			ConstLabels: options.ConstLabels,
+			Buckets:     options.SizeBuckets,
+		}, labels),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   options.Namespace,
+			Subsystem:   options.Subsystem,
+			Name:        "requests_in_flight",
+			Help:        "正在处理的 HTTP 请求数",
+			ConstLabels: options.ConstLabels,
+		}),
+	}
+	c.registry.MustRegister(c.requests, c.duration, c.responseSize, c.inFlight)
+	return c
+}
+
+// Registry 返回指标注册表，可传给 PushgatewayOptions.Registry 一并推送
+func (c *Collector) Registry() *prometheus.Registry {
+	return c.registry
+}
+
+// Middleware 返回记录 HTTP 请求指标的中间件，应注册在其他中间件之前以计入完整耗时
+// 未匹配任何路由的请求不经过中间件，不会被记录
+func (c *Collector) Middleware() core.HandlerFunc {
+	return func(ctx *core.Context) {
+		start := time.Now()
+		c.inFlight.Inc()
+		defer func() {
+			c.inFlight.Dec()
+			route := ctx.FullPath()
+			if route == "" {
+				route = "unmatched"
+			}
+			status := ctx.Writer.Status()
+			r := recover()
+			if r != nil {
+				// 处理函数 panic 且没有恢复中间件时按 500 记录，再继续向上传播
+				status = http.StatusInternalServerError
+			}
+			values := []string{ctx.Request.Method, route, strconv.Itoa(status)}
+			elapsed := time.Since(start).Seconds()
+			// 启用链路追踪且请求被采样时附带 exemplar，可从耗时直方图跳转到对应的追踪
+			if sc := trace.SpanContextFromContext(ctx.Request.Context()); sc.IsSampled() {
+				exemplar := prometheus.Labels{"trace_id": sc.TraceID().String(), "span_id": sc.SpanID().String()}
+				c.requests.WithLabelValues(values...).(prometheus.ExemplarAdder).AddWithExemplar(1, exemplar)
+				c.duration.WithLabelValues(values...).(prometheus.ExemplarObserver).ObserveWithExemplar(elapsed, exemplar)
+			} else {
+				c.requests.WithLabelValues(values...).Inc()
+				c.duration.WithLabelValues(values...).Observe(elapsed)
+			}
+			c.responseSize.WithLabelValues(values...).Observe(float64(max(ctx.Writer.Size(), 0)))
+			if r != nil {
+				panic(r)
+			}
+		}()
+		ctx.Next()
+	}
+}
+
+// Handler 返回以 Prometheus 文本格式输出指标的处理函数
+func (c *Collector) Handler() core.HandlerFunc {
+	// 启用 OpenMetrics 格式才会输出 exemplar，Prometheus 抓取时自动协商
+	h := promhttp.HandlerFor(c.registry, promhttp.HandlerOpts{Registry: c.registry, EnableOpenMetrics: true})
+	return func(ctx *core.Context) {
+		h.ServeHTTP(ctx.Writer, ctx.Request)
+	}
+}
+
+// Register 将采集中间件注册为全局中间件，并在 path 上暴露指标端点
+// 应在注册其他全局中间件之前调用，需要为指标端点加认证时分别使用 Middleware 和 Handler
+// engine: 引擎
+// path: 指标端点路径，为空时使用 "/metrics"
+func (c *Collector) Register(engine *core.Engine, path string) {
+	if path == "" {
+		path = "/metrics"
+	}
+	engine.Use(c.Middleware())
+	engine.GET(path, c.Handler())
+}
+
+// NewCounter 创建并注册自定义计数器，名称会加上 Namespace 前缀，重复注册时 panic
+// name: 指标名，例如 "orders_created_total"
+// help: 指标说明
+// labels: 标签名
+func (c *Collector) NewCounter(name, help string, labels ...string) *prometheus.CounterVec {
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{Namespace: c.options.Namespace, Name: name, Help: help}, labels)
+	c.registry.MustRegister(vec)
+	return vec
+}
+
+// NewGauge 创建并注册自定义仪表盘，名称会加上 Namespace 前缀，重复注册时 panic
+// name: 指标名，例如 "queue_length"
+// help: 指标说明
+// labels: 标签名
+func (c *Collector) NewGauge(name, help string, labels ...string) *prometheus.GaugeVec {
+	vec := prometheus.NewGaugeVec(prometheus.GaugeOpts{Namespace: c.options.Namespace, Name: name, Help: help}, labels)
+	c.registry.MustRegister(vec)
+	return vec
+}
+
+// NewHistogram 创建并注册自定义直方图，名称会加上 Namespace 前缀，重复注册时 panic
+// name: 指标名，例如 "payment_duration_seconds"
+// help: 指标说明
+// buckets: 分桶，为 nil 时使用 prometheus.DefBuckets
+// labels: 标签名
+func (c *Collector) NewHistogram(name, help string, buckets []float64, labels ...string) *prometheus.HistogramVec {
+	vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: c.options.Namespace,
+		Name:      name,
+		Help:      help,
+		Buckets:   buckets,
+	}, labels)
+	c.registry.MustRegister(vec)
+	return vec
+}
+
+// MustRegister 注册其他 Prometheus 采集器，例如 collectors.NewDBStatsCollector，重复注册时 panic
+func (c *Collector) MustRegister(cs ...prometheus.Collector) {
+	c.registry.MustRegister(cs...)
+}
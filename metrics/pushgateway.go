@@ -0,0 +1,184 @@
+package metrics
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/xzl-go/easygo/logger"
+)
+
+// PushgatewayOptions 定义了 Pushgateway 输出配置
+type PushgatewayOptions struct {
+	// Namespace 指标名前缀，例如 "myapp"
+	Namespace string
+	// Grouping 分组标签，例如 {"instance": "worker-1"}
+	Grouping Tags
+	// Registry 额外推送的已有注册表，例如业务自定义的 Prometheus 指标
+	Registry *prometheus.Registry
+	// Buckets 耗时直方图的分桶（秒），默认为 prometheus.DefBuckets
+	Buckets []float64
+	// Replace 为 true 时使用 PUT 替换分组下的全部指标，默认使用 POST 仅替换同名指标
+	Replace bool
+}
+
+// Pushgateway 是基于 Prometheus Pushgateway 的指标输出
+// 通过 Count、Gauge、Timing 记录的指标会自动创建对应的 Counter、Gauge、Histogram，
+// 指标名中的 "." 等非法字符会被替换为下划线
+type Pushgateway struct {
+	mu         sync.Mutex
+	options    PushgatewayOptions
+	registry   *prometheus.Registry
+	pusher     *push.Pusher
+	counters   map[string]*prometheus.CounterVec
+	gauges     map[string]*prometheus.GaugeVec
+	histograms map[string]*prometheus.HistogramVec
+}
+
+// NewPushgateway 创建 Pushgateway 输出
+// url: Pushgateway 地址，例如 "http://pushgateway:9091"
+// job: 作业名称
+// options: 输出配置
+func NewPushgateway(url, job string, options PushgatewayOptions) *Pushgateway {
+	if options.Buckets == nil {
+		options.Buckets = prometheus.DefBuckets
+	}
+	p := &Pushgateway{
+		options:    options,
+		registry:   prometheus.NewRegistry(),
+		counters:   make(map[string]*prometheus.CounterVec),
+		gauges:     make(map[string]*prometheus.GaugeVec),
+		histograms: make(map[string]*prometheus.HistogramVec),
+	}
+	p.pusher = push.New(url, job).Gatherer(p.registry)
+	if options.Registry != nil {
+		p.pusher = p.pusher.Gatherer(options.Registry)
+	}
+	for k, v := range options.Grouping {
+		p.pusher = p.pusher.Grouping(k, v)
+	}
+	return p
+}
+
+// Count 累加计数器
+func (p *Pushgateway) Count(name string, value int64, tags Tags) {
+	name = sanitize(name)
+	keys, values := splitTags(tags)
+	p.mu.Lock()
+	vec, ok := p.counters[name]
+	if !ok {
+		vec = prometheus.NewCounterVec(prometheus.CounterOpts{Namespace: p.options.Namespace, Name: name, Help: name}, keys)
+		if !p.register(name, vec) {
+			p.mu.Unlock()
+			return
+		}
+		p.counters[name] = vec
+	}
+	p.mu.Unlock()
+	if c, err := vec.GetMetricWithLabelValues(values...); err == nil {
+		c.Add(float64(value))
+	}
+}
+
+// Gauge 设置仪表盘当前值
+func (p *Pushgateway) Gauge(name string, value float64, tags Tags) {
+	name = sanitize(name)
+	keys, values := splitTags(tags)
+	p.mu.Lock()
+	vec, ok := p.gauges[name]
+	if !ok {
+		vec = prometheus.NewGaugeVec(prometheus.GaugeOpts{Namespace: p.options.Namespace, Name: name, Help: name}, keys)
+		if !p.register(name, vec) {
+			p.mu.Unlock()
+			return
+		}
+		p.gauges[name] = vec
+	}
+	p.mu.Unlock()
+	if g, err := vec.GetMetricWithLabelValues(values...); err == nil {
+		g.Set(value)
+	}
+}
+
+// Timing 记录耗时，单位为秒
+func (p *Pushgateway) Timing(name string, d time.Duration, tags Tags) {
+	name = sanitize(name)
+	keys, values := splitTags(tags)
+	p.mu.Lock()
+	vec, ok := p.histograms[name]
+	if !ok {
+		vec = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: p.options.Namespace,
+			Name:      name,
+			Help:      name,
+			Buckets:   p.options.Buckets,
+		}, keys)
+		if !p.register(name, vec) {
+			p.mu.Unlock()
+			return
+		}
+		p.histograms[name] = vec
+	}
+	p.mu.Unlock()
+	if h, err := vec.GetMetricWithLabelValues(values...); err == nil {
+		h.Observe(d.Seconds())
+	}
+}
+
+// Flush 将指标推送到 Pushgateway
+func (p *Pushgateway) Flush(ctx context.Context) error {
+	if p.options.Replace {
+		return p.pusher.PushContext(ctx)
+	}
+	return p.pusher.AddContext(ctx)
+}
+
+// Close 推送剩余指标
+func (p *Pushgateway) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return p.Flush(ctx)
+}
+
+// Delete 删除 Pushgateway 中该作业分组下的全部指标，用于任务下线
+func (p *Pushgateway) Delete() error {
+	return p.pusher.Delete()
+}
+
+// register 注册指标，调用方需持有锁
+// 同名指标的标签键必须一致，否则注册失败并丢弃该次记录
+func (p *Pushgateway) register(name string, c prometheus.Collector) bool {
+	if err := p.registry.Register(c); err != nil {
+		logger.Error("注册指标 %s 失败：%v", name, err)
+		return false
+	}
+	return true
+}
+
+// splitTags 将标签拆分为排序后的键和对应的值
+func splitTags(tags Tags) ([]string, []string) {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := make([]string, len(keys))
+	for i, k := range keys {
+		values[i] = tags[k]
+	}
+	return keys, values
+}
+
+// sanitize 将指标名中 Prometheus 不支持的字符替换为下划线
+func sanitize(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == ':' {
+			return r
+		}
+		return '_'
+	}, name)
+}
@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// StatsDOptions 定义了 StatsD 输出配置
+type StatsDOptions struct {
+	// Prefix 指标名前缀，例如 "myapp."
+	Prefix string
+	// Tags 附加到所有指标的公共标签，以 DogStatsD 格式（|#k:v）发送
+	Tags Tags
+	// MaxPacketSize 单个 UDP 包的最大字节数，默认 1432，超出时自动发送
+	MaxPacketSize int
+}
+
+// StatsD 是基于 UDP 的 StatsD 指标输出
+// 指标先写入缓冲区，缓冲区满或调用 Flush 时发送
+type StatsD struct {
+	mu      sync.Mutex
+	conn    net.Conn
+	options StatsDOptions
+	buf     bytes.Buffer
+}
+
+// NewStatsD 创建 StatsD 输出
+// addr: StatsD 服务地址，例如 "127.0.0.1:8125"
+// options: 输出配置
+func NewStatsD(addr string, options StatsDOptions) (*StatsD, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if options.MaxPacketSize <= 0 {
+		options.MaxPacketSize = 1432
+	}
+	return &StatsD{conn: conn, options: options}, nil
+}
+
+// Count 累加计数器
+func (s *StatsD) Count(name string, value int64, tags Tags) {
+	s.write(name, strconv.FormatInt(value, 10), "c", tags)
+}
+
+// Gauge 设置仪表盘当前值
+func (s *StatsD) Gauge(name string, value float64, tags Tags) {
+	s.write(name, strconv.FormatFloat(value, 'f', -1, 64), "g", tags)
+}
+
+// Timing 记录耗时，单位为毫秒
+func (s *StatsD) Timing(name string, d time.Duration, tags Tags) {
+	s.write(name, strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', -1, 64), "ms", tags)
+}
+
+// Flush 发送缓冲区中的指标
+func (s *StatsD) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.flushLocked()
+}
+
+// Close 发送剩余指标并关闭连接
+func (s *StatsD) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.flushLocked()
+	if cerr := s.conn.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// write 将一行指标写入缓冲区
+func (s *StatsD) write(name, value, kind string, tags Tags) {
+	var line bytes.Buffer
+	line.WriteString(s.options.Prefix)
+	line.WriteString(name)
+	line.WriteByte(':')
+	line.WriteString(value)
+	line.WriteByte('|')
+	line.WriteString(kind)
+	writeTags(&line, s.options.Tags, tags)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.buf.Len() > 0 && s.buf.Len()+1+line.Len() > s.options.MaxPacketSize {
+		_ = s.flushLocked()
+	}
+	if s.buf.Len() > 0 {
+		s.buf.WriteByte('\n')
+	}
+	s.buf.Write(line.Bytes())
+}
+
+// flushLocked 发送缓冲区，调用方需持有锁
+func (s *StatsD) flushLocked() error {
+	if s.buf.Len() == 0 {
+		return nil
+	}
+	_, err := s.conn.Write(s.buf.Bytes())
+	s.buf.Reset()
+	return err
+}
+
+// writeTags 以 DogStatsD 格式写入标签，按键排序保证输出稳定
+func writeTags(b *bytes.Buffer, common, tags Tags) {
+	if len(common) == 0 && len(tags) == 0 {
+		return
+	}
+	merged := make(Tags, len(common)+len(tags))
+	for k, v := range common {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	keys := make([]string, 0, len(merged))
+	for k := range merged {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b.WriteString("|#")
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(k)
+		b.WriteByte(':')
+		b.WriteString(merged[k])
+	}
+}
@@ -0,0 +1,322 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+
+	"github.com/xzl-go/easygo/core"
+)
+
+// 支持的压缩编码
+const (
+	EncodingGzip   = "gzip"
+	EncodingBrotli = "br"
+)
+
+// CompressConfig 定义了响应压缩中间件配置
+type CompressConfig struct {
+	// Encodings 启用的编码，客户端权重相同时按顺序优先，默认 {"br", "gzip"}
+	Encodings []string
+	// GzipLevel gzip 压缩级别，默认 gzip.DefaultCompression
+	GzipLevel int
+	// BrotliLevel brotli 压缩级别（0-11），默认 4，兼顾压缩率与速度
+	BrotliLevel int
+	// MinLength 最小压缩长度，响应体小于该字节数时不压缩，默认 1024
+	MinLength int
+	// ExcludedContentTypes 不压缩的内容类型，以 "*" 结尾表示前缀匹配，默认为图片、音视频、压缩包、字体等已压缩格式和 text/event-stream
+	ExcludedContentTypes []string
+	// ExcludedPaths 不压缩的路径，以 "*" 结尾表示前缀匹配
+	ExcludedPaths []string
+}
+
+// DefaultCompressExcludedContentTypes 返回默认不压缩的内容类型
+func DefaultCompressExcludedContentTypes() []string {
+	return []string{
+		"image/*", "video/*", "audio/*", "font/woff", "font/woff2",
+		"application/zip", "application/gzip", "application/x-gzip", "application/x-7z-compressed",
+		"application/x-rar-compressed", "application/x-bzip2", "application/zstd", "application/pdf",
+		"text/event-stream",
+	}
+}
+
+// encoder 是压缩编码器
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// Compress 返回使用默认配置的响应压缩中间件
+func Compress() core.HandlerFunc {
+	return CompressWithConfig(CompressConfig{})
+}
+
+// CompressWithConfig 按配置返回响应压缩中间件
+// 根据 Accept-Encoding 协商编码（支持 q 权重），响应体达到最小长度且内容类型未被排除时压缩，
+// 已设置 Content-Encoding、部分内容（206）和无响应体的响应保持原样；编码器通过对象池复用。
+// config: 中间件配置
+func CompressWithConfig(config CompressConfig) core.HandlerFunc {
+	if len(config.Encodings) == 0 {
+		config.Encodings = []string{EncodingBrotli, EncodingGzip}
+	}
+	if config.GzipLevel == 0 {
+		config.GzipLevel = gzip.DefaultCompression
+	}
+	if config.BrotliLevel == 0 {
+		config.BrotliLevel = 4
+	}
+	if config.MinLength <= 0 {
+		config.MinLength = 1024
+	}
+	if config.ExcludedContentTypes == nil {
+		config.ExcludedContentTypes = DefaultCompressExcludedContentTypes()
+	}
+
+	pools := make(map[string]*sync.Pool, len(config.Encodings))
+	for _, encoding := range config.Encodings {
+		switch encoding {
+		case EncodingGzip:
+			level := config.GzipLevel
+			if _, err := gzip.NewWriterLevel(io.Discard, level); err != nil {
+				panic("middleware: 无效的 gzip 压缩级别 " + strconv.Itoa(level))
+			}
+			pools[encoding] = &sync.Pool{New: func() interface{} {
+				w, _ := gzip.NewWriterLevel(io.Discard, level)
+				return w
+			}}
+		case EncodingBrotli:
+			level := config.BrotliLevel
+			pools[encoding] = &sync.Pool{New: func() interface{} {
+				return brotli.NewWriterLevel(io.Discard, level)
+			}}
+		default:
+			panic("middleware: 不支持的压缩编码 " + encoding)
+		}
+	}
+
+	return func(c *core.Context) {
+		if c.Request.Method == http.MethodHead || matchAnyPath(config.ExcludedPaths, c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"), config.Encodings)
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{
+			ResponseWriter: c.Writer,
+			config:         &config,
+			encoding:       encoding,
+			pool:           pools[encoding],
+			status:         http.StatusOK,
+		}
+		c.Writer = writer
+		completed := false
+		defer func() {
+			c.Writer = writer.ResponseWriter
+			// 处理函数 panic 时丢弃缓冲的数据，由恢复中间件写出错误响应
+			writer.close(completed)
+		}()
+		c.Next()
+		completed = true
+	}
+}
+
+// negotiateEncoding 根据 Accept-Encoding 选择编码，返回空字符串表示不压缩
+func negotiateEncoding(header string, encodings []string) string {
+	if header == "" {
+		return ""
+	}
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		weights[name] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, encoding := range encodings {
+		q, ok := weights[encoding]
+		if !ok {
+			q, ok = weights["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best
+}
+
+// compressWriter 在响应体达到最小长度后决定是否压缩，之前的数据暂存在缓冲区中
+type compressWriter struct {
+	core.ResponseWriter
+	config   *CompressConfig
+	encoding string
+	pool     *sync.Pool
+
+	status      int
+	wroteHeader bool // 处理函数是否已调用 WriteHeader 或 Write
+	decided     bool // 是否已决定压缩与否并写出响应头
+	encoder     encoder
+	buf         []byte
+	size        int
+}
+
+// WriteHeader 记录状态码，响应头在决定是否压缩后写出
+func (w *compressWriter) WriteHeader(code int) {
+	if code <= 0 || w.wroteHeader {
+		return
+	}
+	w.status = code
+	w.wroteHeader = true
+}
+
+// Write 写入响应体
+func (w *compressWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	w.size += len(b)
+	if !w.decided {
+		w.buf = append(w.buf, b...)
+		if len(w.buf) < w.config.MinLength {
+			return len(b), nil
+		}
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	if w.encoder != nil {
+		return w.encoder.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush 写出已缓冲的数据，流式响应在首次 Flush 时即决定是否压缩，不受最小长度限制
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide(true)
+	}
+	if w.encoder != nil {
+		w.encoder.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Hijack 接管连接，只能在写出响应之前调用
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if w.decided {
+		return nil, nil, errors.New("middleware: 响应已写出，无法接管连接")
+	}
+	w.decided = true
+	return w.ResponseWriter.Hijack()
+}
+
+// Status 返回响应状态码
+func (w *compressWriter) Status() int {
+	return w.status
+}
+
+// Size 返回处理函数写入的响应体字节数（压缩前），尚未写入时返回 -1
+func (w *compressWriter) Size() int {
+	if !w.wroteHeader {
+		return -1
+	}
+	return w.size
+}
+
+// Written 返回是否已写入响应
+func (w *compressWriter) Written() bool {
+	return w.wroteHeader
+}
+
+// close 在处理函数返回后写出剩余数据，并将编码器放回对象池
+// flush: 是否写出剩余数据
+func (w *compressWriter) close(flush bool) {
+	if flush && !w.decided && w.wroteHeader {
+		// 响应体不足最小长度，原样写出
+		w.decide(false)
+	}
+	if w.encoder != nil {
+		if flush {
+			w.encoder.Close()
+		}
+		w.encoder.Reset(io.Discard)
+		w.pool.Put(w.encoder)
+		w.encoder = nil
+	}
+}
+
+// decide 决定是否压缩，写出响应头和缓冲区
+// large: 响应体是否达到最小压缩长度（或为流式响应）
+func (w *compressWriter) decide(large bool) error {
+	w.decided = true
+	if w.compressible() && large {
+		header := w.Header()
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		header.Del("Accept-Ranges")
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			// 压缩后内容与原始表示不同，强 ETag 降级为弱 ETag
+			header.Set("ETag", "W/"+etag)
+		}
+		w.encoder = w.pool.Get().(encoder)
+		w.encoder.Reset(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if w.encoder != nil {
+		_, err = w.encoder.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// compressible 根据状态码和响应头判断响应是否适合压缩，适合时添加 Vary: Accept-Encoding
+func (w *compressWriter) compressible() bool {
+	if w.status < 200 || w.status == http.StatusNoContent || w.status == http.StatusPartialContent ||
+		w.status == http.StatusNotModified {
+		return false
+	}
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" {
+		return false
+	}
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		if len(w.buf) == 0 {
+			// 无法判断内容类型，且压缩后 net/http 无法再探测
+			return false
+		}
+		contentType = http.DetectContentType(w.buf)
+		header.Set("Content-Type", contentType)
+	}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	if matchAnyPath(w.config.ExcludedContentTypes, strings.ToLower(strings.TrimSpace(mediaType))) {
+		return false
+	}
+	header.Add("Vary", "Accept-Encoding")
+	return true
+}
@@ -0,0 +1,291 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"html"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/xzl-go/easygo/core"
+	errs "github.com/xzl-go/easygo/errors"
+	"github.com/xzl-go/easygo/session"
+)
+
+// CSRF 令牌写入上下文和模板数据的键
+const (
+	CSRFTokenKey = "csrf_token" // 令牌字符串，render 包会自动注入模板数据
+	CSRFFieldKey = "csrf_field" // 隐藏表单字段的 HTML，在模板中使用 {{ .csrf_field }}
+)
+
+// csrfSessionKey 是同步令牌模式下令牌在会话中的键
+const csrfSessionKey = "_csrf_token"
+
+// csrfTokenLength 是令牌的字节数
+const csrfTokenLength = 32
+
+// ErrCSRFInvalid CSRF 令牌缺失或不匹配
+var ErrCSRFInvalid = errs.New(40340, "error.csrf.invalid", http.StatusForbidden, "Invalid CSRF token")
+
+// CSRFMode 定义了令牌的保存方式
+type CSRFMode int
+
+const (
+	// CSRFDoubleSubmit 双重提交 Cookie：令牌保存在 Cookie 中，请求需在请求头或表单中提交相同的令牌，无需服务端存储
+	CSRFDoubleSubmit CSRFMode = iota
+	// CSRFSynchronizer 同步令牌：令牌保存在会话中，需在 session.Middleware 之后使用
+	CSRFSynchronizer
+)
+
+// CSRFConfig 定义了 CSRF 中间件配置
+type CSRFConfig struct {
+	// Mode 令牌保存方式，默认双重提交 Cookie
+	Mode CSRFMode
+	// Secret 双重提交模式下用于签名 Cookie 的密钥，防止子域名写入伪造的 Cookie，为空时不签名
+	Secret []byte
+	// TokenLookup 提交令牌的位置，按顺序查找，默认 "header:X-CSRF-Token,form:_csrf"
+	// 格式为逗号分隔的 "来源:名称"，来源可以是 header、form、query
+	TokenLookup string
+	// FieldName 模板中隐藏表单字段的名称，默认 "_csrf"
+	FieldName string
+	// CookieName 双重提交模式下的 Cookie 名称，默认 "_csrf"
+	CookieName string
+	// CookiePath Cookie 路径，默认 "/"
+	CookiePath string
+	// CookieDomain Cookie 域名
+	CookieDomain string
+	// CookieSecure 是否仅通过 HTTPS 发送 Cookie
+	CookieSecure bool
+	// CookieSameSite Cookie 的 SameSite 属性，默认 Lax
+	CookieSameSite http.SameSite
+	// CookieMaxAge Cookie 有效期，默认 12 小时
+	CookieMaxAge time.Duration
+	// Exempt 跳过校验的路径，以 "*" 结尾表示前缀匹配，例如使用令牌认证的 "/api/*"
+	Exempt []string
+	// Skipper 返回 true 时跳过校验
+	Skipper func(c *core.Context) bool
+	// Translator 按请求语言翻译错误消息，为 nil 时使用错误的默认消息
+	Translator Translator
+	// ErrorHandler 校验失败时的处理函数，默认返回 403 统一响应并中止请求
+	ErrorHandler func(c *core.Context, err error)
+}
+
+// CSRF 返回使用默认配置（双重提交 Cookie）的 CSRF 防护中间件
+func CSRF() core.HandlerFunc {
+	return CSRFWithConfig(CSRFConfig{})
+}
+
+// CSRFWithConfig 按配置返回 CSRF 防护中间件
+// 每个请求都会确保存在令牌，并写入上下文键 csrf_token 和模板数据 csrf_field；
+// GET、HEAD、OPTIONS、TRACE 之外的请求必须提交与保存的令牌一致的值。
+// 写入页面的令牌每次都经过随机掩码处理，防止 BREACH 类压缩侧信道攻击。
+// config: 中间件配置
+func CSRFWithConfig(config CSRFConfig) core.HandlerFunc {
+	if config.TokenLookup == "" {
+		config.TokenLookup = "header:X-CSRF-Token,form:_csrf"
+	}
+	if config.FieldName == "" {
+		config.FieldName = "_csrf"
+	}
+	if config.CookieName == "" {
+		config.CookieName = "_csrf"
+	}
+	if config.CookiePath == "" {
+		config.CookiePath = "/"
+	}
+	if config.CookieSameSite == 0 {
+		config.CookieSameSite = http.SameSiteLaxMode
+	}
+	if config.CookieMaxAge <= 0 {
+		config.CookieMaxAge = 12 * time.Hour
+	}
+	if config.ErrorHandler == nil {
+		config.ErrorHandler = func(c *core.Context, err error) {
+			e := errs.From(err)
+			c.Fail(e.WithMessage(translateMessage(c, config.Translator, e)))
+			c.Abort()
+		}
+	}
+	var sources []tokenSource
+	for _, part := range strings.Split(config.TokenLookup, ",") {
+		from, name, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok || name == "" {
+			panic("middleware: 无效的 TokenLookup " + config.TokenLookup)
+		}
+		switch from {
+		case "header", "form", "query":
+		default:
+			panic("middleware: TokenLookup 不支持的来源 " + from)
+		}
+		sources = append(sources, tokenSource{from: from, name: name})
+	}
+
+	return func(c *core.Context) {
+		if matchAnyPath(config.Exempt, c.Request.URL.Path) || (config.Skipper != nil && config.Skipper(c)) {
+			c.Next()
+			return
+		}
+
+		token := config.load(c)
+		if token == nil {
+			var err error
+			if token, err = newCSRFToken(); err != nil {
+				c.Fail(errs.ErrInternal.Wrap(err))
+				c.Abort()
+				return
+			}
+			config.save(c, token)
+		}
+
+		masked := maskCSRFToken(token)
+		c.Set(CSRFTokenKey, masked)
+		c.SetViewData(CSRFFieldKey, template.HTML(`<input type="hidden" name="`+html.EscapeString(config.FieldName)+`" value="`+masked+`">`))
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			c.Next()
+			return
+		}
+
+		submitted := unmaskCSRFToken(lookupCSRFToken(c, sources))
+		if submitted == nil || subtle.ConstantTimeCompare(submitted, token) != 1 {
+			config.ErrorHandler(c, ErrCSRFInvalid)
+			return
+		}
+		c.Next()
+	}
+}
+
+// CSRFToken 返回当前请求的 CSRF 令牌，用于在 JSON 响应或自定义模板中下发
+func CSRFToken(c *core.Context) string {
+	token, _ := c.Get(CSRFTokenKey).(string)
+	return token
+}
+
+// load 读取已保存的令牌，不存在或无效时返回 nil
+func (config *CSRFConfig) load(c *core.Context) []byte {
+	if config.Mode == CSRFSynchronizer {
+		s := session.Get(c)
+		if s == nil {
+			panic("middleware: 同步令牌模式的 CSRF 中间件需在 session.Middleware 之后使用")
+		}
+		return decodeCSRFToken(s.GetString(csrfSessionKey))
+	}
+
+	value, err := c.Cookie(config.CookieName)
+	if err != nil {
+		return nil
+	}
+	if len(config.Secret) > 0 {
+		raw, sig, ok := strings.Cut(value, ".")
+		if !ok || !hmac.Equal([]byte(sig), []byte(config.sign(raw))) {
+			return nil
+		}
+		value = raw
+	}
+	return decodeCSRFToken(value)
+}
+
+// save 保存新生成的令牌
+func (config *CSRFConfig) save(c *core.Context, token []byte) {
+	value := base64.RawURLEncoding.EncodeToString(token)
+	if config.Mode == CSRFSynchronizer {
+		session.Get(c).Set(csrfSessionKey, value)
+		return
+	}
+	if len(config.Secret) > 0 {
+		value += "." + config.sign(value)
+	}
+	// 不设置 HttpOnly，前端脚本需要读取 Cookie 并放入请求头
+	c.SetHTTPCookie(&http.Cookie{
+		Name:     config.CookieName,
+		Value:    value,
+		Path:     config.CookiePath,
+		Domain:   config.CookieDomain,
+		MaxAge:   int(config.CookieMaxAge / time.Second),
+		Secure:   config.CookieSecure,
+		SameSite: config.CookieSameSite,
+	})
+}
+
+// sign 计算 Cookie 值的签名
+func (config *CSRFConfig) sign(value string) string {
+	mac := hmac.New(sha256.New, config.Secret)
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// lookupCSRFToken 按来源顺序查找提交的令牌
+func lookupCSRFToken(c *core.Context, sources []tokenSource) string {
+	for _, s := range sources {
+		var token string
+		switch s.from {
+		case "header":
+			token = c.GetHeader(s.name)
+		case "form":
+			token = c.PostForm(s.name)
+		case "query":
+			token = c.Query(s.name)
+		}
+		if token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// newCSRFToken 生成随机令牌
+func newCSRFToken() ([]byte, error) {
+	token := make([]byte, csrfTokenLength)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// decodeCSRFToken 解码保存的令牌，长度不符时返回 nil
+func decodeCSRFToken(value string) []byte {
+	token, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(token) != csrfTokenLength {
+		return nil
+	}
+	return token
+}
+
+// maskCSRFToken 使用随机一次性密钥对令牌做异或掩码，输出随机密钥与掩码结果的拼接
+func maskCSRFToken(token []byte) string {
+	masked := make([]byte, 2*csrfTokenLength)
+	otp := masked[:csrfTokenLength]
+	if _, err := rand.Read(otp); err != nil {
+		return base64.RawURLEncoding.EncodeToString(token)
+	}
+	for i := range token {
+		masked[csrfTokenLength+i] = otp[i] ^ token[i]
+	}
+	return base64.RawURLEncoding.EncodeToString(masked)
+}
+
+// unmaskCSRFToken 还原提交的令牌，同时接受掩码后的令牌和直接从 Cookie 读取的原始令牌（含签名时忽略签名部分）
+func unmaskCSRFToken(value string) []byte {
+	value, _, _ = strings.Cut(value, ".")
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil
+	}
+	switch len(data) {
+	case csrfTokenLength:
+		return data
+	case 2 * csrfTokenLength:
+		token := make([]byte, csrfTokenLength)
+		for i := range token {
+			token[i] = data[i] ^ data[csrfTokenLength+i]
+		}
+		return token
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Package middleware 提供了EasyGo框架的常用中间件
+package middleware
+
+import (
+	"github.com/xzl-go/easygo/core"
+	errs "github.com/xzl-go/easygo/errors"
+)
+
+// Translator 定义了错误消息翻译器，*i18n.I18n 实现了该接口
+type Translator interface {
+	Translate(key, lang string) string
+}
+
+// ErrorHandler 返回统一错误处理中间件
+// 处理函数通过 ctx.Error(err) 记录错误后直接返回，由该中间件根据最后一个错误生成统一响应：
+// 业务错误映射为对应的状态码和错误码，消息按请求语言翻译，校验错误在 data 中附带字段错误列表；
+// 未知错误视为内部错误，5xx 错误会连同调用栈记录日志，发布模式下不向客户端暴露错误细节。
+// translator: 消息翻译器，可为 nil
+func ErrorHandler(translator Translator) core.HandlerFunc {
+	return func(c *core.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Written() {
+			return
+		}
+
+		err := c.Errors[len(c.Errors)-1]
+		e := errs.From(err)
+
+		if e.Status >= 500 {
+			c.Logger().Error("[%s] %s %s %v\n%s", c.TraceID(), c.Request.Method, c.Request.URL.Path, err, e.Stack())
+		}
+
+		message := translateMessage(c, translator, e)
+		// 调试模式下附带原始错误，便于排查问题
+		if core.IsDebugging() && e.Cause() != nil {
+			message = message + ": " + e.Cause().Error()
+		}
+
+		var data interface{}
+		if fieldErrors := core.FieldErrors(err); fieldErrors != nil {
+			data = fieldErrors
+		}
+		c.JSON(e.Status, core.Response{
+			Code:    e.Code,
+			Message: message,
+			Data:    data,
+			TraceID: c.TraceID(),
+		})
+	}
+}
+
+// translateMessage 按请求语言翻译错误消息，没有翻译器或译文时返回原消息
+func translateMessage(c *core.Context, translator Translator, e *errs.Error) string {
+	if translator != nil && e.Key != "" {
+		lang, _ := c.Get("lang").(string)
+		if translated := translator.Translate(e.Key, lang); translated != e.Key {
+			return translated
+		}
+	}
+	return e.Message
+}
@@ -0,0 +1,295 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/xzl-go/easygo/core"
+)
+
+// KeyCase 定义了 JSON 键名的转换方式
+type KeyCase int
+
+const (
+	KeepCase  KeyCase = iota // 保持原样
+	SnakeCase                // 转换为 snake_case
+	CamelCase                // 转换为 camelCase
+)
+
+// FieldFilterConfig 定义了响应字段过滤中间件配置
+type FieldFilterConfig struct {
+	// Param 指定返回字段的查询参数名，默认 "fields"，例如 ?fields=id,name,author.name
+	Param string
+	// Case 键名转换方式，默认保持原样
+	Case KeyCase
+	// DataKey 统一响应结构中数据字段的键名，默认 "data"
+	// 响应为包含该键的对象时只过滤该字段，code、message 等保持不变
+	DataKey string
+	// Paths 生效的路由，以 "*" 结尾表示前缀匹配，为空表示全部路由
+	Paths []string
+}
+
+// FieldFilter 返回响应字段过滤中间件
+// 对 2xx 的 JSON 响应按查询参数保留指定字段（支持以 "." 分隔的嵌套字段，数组逐项过滤），
+// 并可统一转换键名风格，用于减少移动端的响应体积
+// config: 中间件配置
+func FieldFilter(config FieldFilterConfig) core.HandlerFunc {
+	if config.Param == "" {
+		config.Param = "fields"
+	}
+	if config.DataKey == "" {
+		config.DataKey = "data"
+	}
+
+	return func(c *core.Context) {
+		if len(config.Paths) > 0 && !matchAnyPath(config.Paths, c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+		fields := parseFields(c.Query(config.Param))
+		if fields == nil && config.Case == KeepCase {
+			c.Next()
+			return
+		}
+
+		writer := &responseBuffer{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		body := writer.body.Bytes()
+		if writer.status >= 200 && writer.status < 300 &&
+			strings.HasPrefix(writer.Header().Get("Content-Type"), "application/json") {
+			if transformed, err := transformJSON(body, fields, config); err == nil {
+				body = transformed
+				writer.Header().Del("Content-Length")
+			}
+		}
+		writer.ResponseWriter.WriteHeader(writer.status)
+		writer.ResponseWriter.Write(body)
+	}
+}
+
+// fieldSet 是字段树，叶子节点为 nil 表示保留整个字段
+type fieldSet map[string]fieldSet
+
+// parseFields 解析 "id,name,author.name" 形式的字段列表，为空时返回 nil
+func parseFields(raw string) fieldSet {
+	var set fieldSet
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if set == nil {
+			set = make(fieldSet)
+		}
+		node := set
+		parts := strings.Split(field, ".")
+		for i, part := range parts {
+			child, exists := node[part]
+			if i == len(parts)-1 {
+				// 同时请求 author 和 author.name 时保留整个 author
+				node[part] = nil
+				break
+			}
+			if exists && child == nil {
+				break
+			}
+			if child == nil {
+				child = make(fieldSet)
+				node[part] = child
+			}
+			node = child
+		}
+	}
+	return set
+}
+
+// transformJSON 转换键名并过滤字段
+func transformJSON(body []byte, fields fieldSet, config FieldFilterConfig) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	if config.Case != KeepCase {
+		value = convertKeys(value, config.Case)
+	}
+	if fields != nil {
+		dataKey := convertKey(config.DataKey, config.Case)
+		if obj, ok := value.(map[string]interface{}); ok && obj[dataKey] != nil {
+			obj[dataKey] = filterFields(obj[dataKey], fields)
+		} else {
+			value = filterFields(value, fields)
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// filterFields 按字段树过滤对象，数组逐项过滤
+func filterFields(value interface{}, fields fieldSet) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		filtered := make(map[string]interface{}, len(fields))
+		for name, sub := range fields {
+			child, ok := v[name]
+			if !ok {
+				continue
+			}
+			if sub != nil {
+				child = filterFields(child, sub)
+			}
+			filtered[name] = child
+		}
+		return filtered
+	case []interface{}:
+		for i := range v {
+			v[i] = filterFields(v[i], fields)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// convertKeys 递归转换对象的键名
+func convertKeys(value interface{}, keyCase KeyCase) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			converted[convertKey(k, keyCase)] = convertKeys(child, keyCase)
+		}
+		return converted
+	case []interface{}:
+		for i := range v {
+			v[i] = convertKeys(v[i], keyCase)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// convertKey 转换单个键名
+func convertKey(key string, keyCase KeyCase) string {
+	switch keyCase {
+	case SnakeCase:
+		return toSnakeCase(key)
+	case CamelCase:
+		return toCamelCase(key)
+	default:
+		return key
+	}
+}
+
+// toSnakeCase 将 "userID"、"HTTPServer" 转换为 "user_id"、"http_server"
+func toSnakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && runes[i-1] != '_' &&
+				(unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+					(i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// toCamelCase 将 "user_id"、"UserName"、"HTTPServer" 转换为 "userId"、"userName"、"httpServer"
+func toCamelCase(s string) string {
+	parts := strings.Split(toSnakeCase(s), "_")
+	var b strings.Builder
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+		runes := []rune(part)
+		if b.Len() == 0 {
+			runes[0] = unicode.ToLower(runes[0])
+		} else {
+			runes[0] = unicode.ToUpper(runes[0])
+		}
+		b.WriteString(string(runes))
+	}
+	if b.Len() == 0 {
+		return s
+	}
+	return b.String()
+}
+
+// responseBuffer 缓存响应，待处理后再写出
+type responseBuffer struct {
+	core.ResponseWriter
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+// WriteHeader 记录状态码，只有第一次调用生效
+func (w *responseBuffer) WriteHeader(code int) {
+	if w.written {
+		return
+	}
+	w.status = code
+	w.written = true
+}
+
+// Write 缓存响应体
+func (w *responseBuffer) Write(b []byte) (int, error) {
+	w.written = true
+	return w.body.Write(b)
+}
+
+// Status 返回缓存的状态码
+func (w *responseBuffer) Status() int {
+	return w.status
+}
+
+// Size 返回缓存的响应体字节数
+func (w *responseBuffer) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+// Written 返回是否已写入响应
+func (w *responseBuffer) Written() bool {
+	return w.written
+}
+
+// Flush 缓存期间不向客户端发送数据
+func (w *responseBuffer) Flush() {}
+
+// matchAnyPath 判断路径是否匹配任一规则，规则以 "*" 结尾表示前缀匹配
+func matchAnyPath(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		} else if path == pattern {
+			return true
+		}
+	}
+	return false
+}
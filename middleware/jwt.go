@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+
+	"github.com/xzl-go/easygo/core"
+	errs "github.com/xzl-go/easygo/errors"
+	"github.com/xzl-go/easygo/jwt"
+)
+
+// JWT 中间件写入上下文的键
+const (
+	ClaimsKey          = "claims"           // 令牌载荷 *jwt.Claims
+	CurrentUserKey     = "current_user"     // 用户ID，RBAC 等中间件默认以其作为主体
+	CurrentUsernameKey = "current_username" // 用户名，仅用于展示，不唯一，不能用于鉴权
+)
+
+var (
+	// ErrTokenMissing 请求中没有令牌
+	ErrTokenMissing = errs.New(40101, "error.token.missing", http.StatusUnauthorized, "Missing authentication token")
+	// ErrTokenInvalid 令牌无效
+	ErrTokenInvalid = errs.New(40102, "error.token.invalid", http.StatusUnauthorized, "Invalid authentication token")
+	// ErrTokenExpired 令牌已过期
+	ErrTokenExpired = errs.New(40103, "error.token.expired", http.StatusUnauthorized, "Authentication token expired")
+	// ErrTokenRevoked 令牌已被吊销
+	ErrTokenRevoked = errs.New(40104, "error.token.revoked", http.StatusUnauthorized, "Authentication token revoked")
+)
+
+// JWTConfig 定义了 JWT 中间件配置
+type JWTConfig struct {
+	// TokenLookup 令牌的来源，按顺序查找，默认 "header:Authorization"
+	// 格式为逗号分隔的 "来源:名称"，来源可以是 header、query、cookie，例如 "header:Authorization,query:token,cookie:jwt"
+	TokenLookup string
+	// AuthScheme 从 Authorization 请求头读取令牌时的前缀，默认 "Bearer"
+	AuthScheme string
+	// Optional 为 true 时没有令牌的请求直接放行，令牌无效时仍然拒绝
+	Optional bool
+	// ErrorHandler 校验失败时的处理函数，默认返回 401 统一响应并中止请求
+	// err 为 ErrTokenMissing、ErrTokenInvalid、ErrTokenExpired 或 ErrTokenRevoked
+	ErrorHandler func(c *core.Context, err error)
+}
+
+// tokenSource 是令牌的一个来源
+type tokenSource struct {
+	from string
+	name string
+}
+
+// JWT 返回 JWT 认证中间件，从 "Authorization: Bearer <token>" 读取令牌
+// 校验通过后将 *jwt.Claims 写入上下文键 claims，将用户ID写入 current_user，将用户名写入 current_username；
+// JWT 管理器设置了令牌存储时拒绝已吊销的令牌
+// manager: JWT 管理器
+func JWT(manager *jwt.JWTManager) core.HandlerFunc {
+	return JWTWithConfig(manager, JWTConfig{})
+}
+
+// JWTWithConfig 按配置返回 JWT 认证中间件
+// manager: JWT 管理器
+// config: 中间件配置
+func JWTWithConfig(manager *jwt.JWTManager, config JWTConfig) core.HandlerFunc {
+	if config.TokenLookup == "" {
+		config.TokenLookup = "header:Authorization"
+	}
+	if config.AuthScheme == "" {
+		config.AuthScheme = "Bearer"
+	}
+	if config.ErrorHandler == nil {
+		config.ErrorHandler = func(c *core.Context, err error) {
+			c.Fail(err)
+			c.Abort()
+		}
+	}
+	var sources []tokenSource
+	for _, part := range strings.Split(config.TokenLookup, ",") {
+		from, name, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok || name == "" {
+			panic("middleware: 无效的 TokenLookup " + config.TokenLookup)
+		}
+		switch from {
+		case "header", "query", "cookie":
+		default:
+			panic("middleware: TokenLookup 不支持的来源 " + from)
+		}
+		sources = append(sources, tokenSource{from: from, name: name})
+	}
+
+	return func(c *core.Context) {
+		token := lookupToken(c, sources, config.AuthScheme)
+		if token == "" {
+			if config.Optional {
+				c.Next()
+				return
+			}
+			config.ErrorHandler(c, ErrTokenMissing)
+			return
+		}
+
+		claims, err := manager.VerifyTokenContext(c, token)
+		if err != nil {
+			switch {
+			case errors.Is(err, gojwt.ErrTokenExpired):
+				config.ErrorHandler(c, ErrTokenExpired.Wrap(err))
+			case errors.Is(err, jwt.ErrTokenRevoked):
+				config.ErrorHandler(c, ErrTokenRevoked.Wrap(err))
+			default:
+				config.ErrorHandler(c, ErrTokenInvalid.Wrap(err))
+			}
+			return
+		}
+
+		c.Set(ClaimsKey, claims)
+		c.Set(CurrentUserKey, claims.UserID)
+		c.Set(CurrentUsernameKey, claims.Username)
+		c.Next()
+	}
+}
+
+// GetClaims 获取 JWT 中间件写入上下文的载荷，未认证时返回 nil
+func GetClaims(c *core.Context) *jwt.Claims {
+	claims, _ := c.Get(ClaimsKey).(*jwt.Claims)
+	return claims
+}
+
+// lookupToken 按来源顺序查找令牌
+func lookupToken(c *core.Context, sources []tokenSource, scheme string) string {
+	for _, s := range sources {
+		var token string
+		switch s.from {
+		case "header":
+			token = c.GetHeader(s.name)
+			if strings.EqualFold(s.name, "Authorization") {
+				prefix := scheme + " "
+				if len(token) <= len(prefix) || !strings.EqualFold(token[:len(prefix)], prefix) {
+					token = ""
+				} else {
+					token = strings.TrimSpace(token[len(prefix):])
+				}
+			}
+		case "query":
+			token = c.Query(s.name)
+		case "cookie":
+			token, _ = c.Cookie(s.name)
+		}
+		if token != "" {
+			return token
+		}
+	}
+	return ""
+}
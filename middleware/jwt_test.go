@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/xzl-go/easygo/core"
+	"github.com/xzl-go/easygo/jwt"
+)
+
+func TestJWTCurrentUser(t *testing.T) {
+	manager := jwt.NewJWTManager("test-secret", time.Hour)
+	token, err := manager.GenerateToken("u-1001", "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var user, username interface{}
+	app := core.New()
+	app.GET("/", JWT(manager), func(c *core.Context) {
+		user = c.Get(CurrentUserKey)
+		username = c.Get(CurrentUsernameKey)
+	})
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	app.ServeHTTP(httptest.NewRecorder(), r)
+
+	// 用户名可以被用户设置为 admin，主体必须是用户ID
+	if user != "u-1001" {
+		t.Errorf("current_user = %v，期望用户ID u-1001", user)
+	}
+	if username != "admin" {
+		t.Errorf("current_username = %v，期望 admin", username)
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"time"
 
 	"github.com/xzl-go/easygo/core"
-	"github.com/xzl-go/easygo/logger"
 )
 
 // Logger 返回一个日志中间件
@@ -20,13 +19,13 @@ func Logger() core.HandlerFunc {
 		latency := time.Since(start)
 		clientIP := c.Request.RemoteAddr
 		method := c.Request.Method
-		statusCode := c.StatusCode
+		statusCode := c.Writer.Status()
 
 		if raw != "" {
 			path = path + "?" + raw
 		}
 
-		logger.Info("[%s] %s %s %d %v",
+		c.Logger().Info("[%s] %s %s %d %v",
 			clientIP,
 			method,
 			path,
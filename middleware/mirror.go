@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/xzl-go/easygo/core"
+	"github.com/xzl-go/easygo/logger"
+)
+
+// MirrorHeader 标记影子请求的请求头，影子服务可据此跳过扣费、发消息等副作用
+const MirrorHeader = "X-Shadow-Request"
+
+// MirrorConfig 定义了流量镜像中间件配置
+type MirrorConfig struct {
+	// Target 影子服务地址，例如 "http://api-canary:8080"，请求路径和查询参数保持不变
+	Target string
+	// Percent 镜像比例，取值 0~100
+	Percent float64
+	// Filter 返回 false 的请求不镜像，为空表示全部参与采样
+	Filter func(c *core.Context) bool
+	// Client 发送影子请求的客户端，默认超时 5 秒
+	Client *http.Client
+	// MaxBodySize 可镜像的最大请求体字节数，默认 1 MB，超出时该请求不镜像
+	MaxBodySize int64
+	// MaxConcurrent 同时进行的影子请求上限，默认 100，超出时丢弃
+	MaxConcurrent int
+	// OnError 影子请求失败时的回调，默认记录日志
+	OnError func(r *http.Request, err error)
+}
+
+// hopHeaders 是不应转发的逐跳请求头
+var hopHeaders = []string{
+	"Connection", "Keep-Alive", "Proxy-Authenticate", "Proxy-Authorization",
+	"Te", "Trailer", "Transfer-Encoding", "Upgrade",
+}
+
+// Mirror 返回流量镜像中间件
+// 按比例将请求异步复制到影子服务，影子服务的响应被丢弃，不影响主请求的处理和耗时
+// config: 中间件配置
+func Mirror(config MirrorConfig) core.HandlerFunc {
+	target, err := url.Parse(config.Target)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		panic("middleware: 无效的镜像地址 " + config.Target)
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 5 * time.Second}
+	}
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = 1 << 20
+	}
+	if config.MaxConcurrent <= 0 {
+		config.MaxConcurrent = 100
+	}
+	if config.OnError == nil {
+		config.OnError = func(r *http.Request, err error) {
+			logger.FromContext(r.Context()).Error("[Mirror] %s %s 镜像失败：%v", r.Method, r.URL.String(), err)
+		}
+	}
+	sem := make(chan struct{}, config.MaxConcurrent)
+
+	return func(c *core.Context) {
+		if config.Percent <= 0 || rand.Float64()*100 >= config.Percent ||
+			(config.Filter != nil && !config.Filter(c)) {
+			c.Next()
+			return
+		}
+
+		body, ok := copyBody(c.Request, config.MaxBodySize)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		select {
+		case sem <- struct{}{}:
+		default:
+			// 影子服务过慢时直接丢弃，避免堆积影响主服务
+			c.Next()
+			return
+		}
+
+		shadow := shadowRequest(c.Request, target, body, c.ClientIP())
+		go func() {
+			defer func() { <-sem }()
+			resp, err := config.Client.Do(shadow)
+			if err != nil {
+				config.OnError(shadow, err)
+				return
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}()
+
+		c.Next()
+	}
+}
+
+// copyBody 读取请求体并回填，请求体超过 limit 时返回 false
+func copyBody(r *http.Request, limit int64) ([]byte, bool) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, true
+	}
+	if r.ContentLength > limit {
+		return nil, false
+	}
+	head, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+	if err != nil {
+		return nil, false
+	}
+	if int64(len(head)) > limit {
+		r.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(head), r.Body), Closer: r.Body}
+		return nil, false
+	}
+	r.Body = io.NopCloser(bytes.NewReader(head))
+	return head, true
+}
+
+// shadowRequest 构造影子请求，使用独立的上下文，主请求结束不会取消影子请求
+func shadowRequest(r *http.Request, target *url.URL, body []byte, clientIP string) *http.Request {
+	u := *target
+	u.Path = strings.TrimSuffix(target.Path, "/") + r.URL.Path
+	u.RawPath = ""
+	u.RawQuery = r.URL.RawQuery
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	// 保留请求的 context 中的值（如带请求ID的日志记录器），但不随原请求结束而取消
+	shadow, _ := http.NewRequestWithContext(context.WithoutCancel(r.Context()), r.Method, u.String(), reader)
+	shadow.Header = r.Header.Clone()
+	for _, h := range hopHeaders {
+		shadow.Header.Del(h)
+	}
+	shadow.Header.Set("X-Forwarded-Host", r.Host)
+	if ip := net.ParseIP(clientIP); ip != nil {
+		if prior := shadow.Header.Get("X-Forwarded-For"); prior != "" {
+			clientIP = prior + ", " + clientIP
+		}
+		shadow.Header.Set("X-Forwarded-For", clientIP)
+	}
+	shadow.Header.Set(MirrorHeader, "true")
+	return shadow
+}
@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/xzl-go/easygo/core"
+	errs "github.com/xzl-go/easygo/errors"
+)
+
+// ErrBadGateway 上游服务不可用或返回了无效响应
+var ErrBadGateway = errs.New(50200, "error.bad_gateway", http.StatusBadGateway, "Bad gateway")
+
+// ProxyConfig 定义了反向代理配置
+type ProxyConfig struct {
+	// Target 上游服务地址，例如 "http://user-svc:8080"，可以包含路径，转发路径为 Target 的路径加请求路径
+	Target string
+	// StripPrefix 转发前去掉的请求路径前缀，例如 "/api/users"
+	StripPrefix string
+	// PreserveHost 是否保留客户端的 Host 请求头，默认改写为上游服务的 Host
+	PreserveHost bool
+	// SetHeaders 转发前设置的请求头，覆盖客户端发送的同名请求头，例如上游服务的认证信息
+	SetHeaders map[string]string
+	// RemoveHeaders 转发前删除的请求头，例如 "Cookie"
+	RemoveHeaders []string
+	// ModifyResponse 返回客户端前修改上游响应，返回错误时响应 502
+	ModifyResponse func(resp *http.Response) error
+	// Transport 转发请求使用的 RoundTripper，默认 http.DefaultTransport
+	Transport http.RoundTripper
+	// FlushInterval 响应刷新间隔，默认 0：SSE 和长度未知的流式响应立即刷新，其他响应在缓冲区满时刷新；
+	// 为负数时每次写入后立即刷新
+	FlushInterval time.Duration
+	// Translator 按请求语言翻译错误消息，为 nil 时使用错误的默认消息
+	Translator Translator
+}
+
+// proxyContextKey 是请求 context 中保存 *core.Context 的键，供错误处理函数使用
+type proxyContextKey struct{}
+
+// Proxy 返回转发到上游服务的反向代理处理函数
+// target: 上游服务地址
+func Proxy(target string) core.HandlerFunc {
+	return ProxyWithConfig(ProxyConfig{Target: target})
+}
+
+// ProxyWithConfig 按配置返回反向代理处理函数，作为路由的最后一个处理函数使用，例如：
+//
+//	app.Any("/api/users/*path", middleware.ProxyWithConfig(middleware.ProxyConfig{
+//		Target:      "http://user-svc:8080",
+//		StripPrefix: "/api/users",
+//	}))
+//
+// 请求体和响应体以流的方式转发，不在内存中缓冲；支持 WebSocket 等协议升级。
+// 转发时去掉逐跳请求头，追加 X-Forwarded-For，设置 X-Forwarded-Host 和 X-Forwarded-Proto。
+// 上游不可用时返回 502，上游超时返回 504，客户端断开时不返回响应。
+// config: 代理配置
+func ProxyWithConfig(config ProxyConfig) core.HandlerFunc {
+	target, err := url.Parse(config.Target)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		panic("middleware: 无效的代理地址 " + config.Target)
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			if config.StripPrefix != "" {
+				stripPrefix(pr.Out.URL, config.StripPrefix)
+			}
+			pr.SetURL(target)
+			if config.PreserveHost {
+				pr.Out.Host = pr.In.Host
+			}
+			// 保留客户端经过的代理链
+			pr.Out.Header["X-Forwarded-For"] = pr.In.Header["X-Forwarded-For"]
+			pr.SetXForwarded()
+			for _, name := range config.RemoveHeaders {
+				pr.Out.Header.Del(name)
+			}
+			for name, value := range config.SetHeaders {
+				pr.Out.Header.Set(name, value)
+			}
+		},
+		Transport:      config.Transport,
+		FlushInterval:  config.FlushInterval,
+		ModifyResponse: config.ModifyResponse,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			c, _ := r.Context().Value(proxyContextKey{}).(*core.Context)
+			proxyError(c, config.Translator, err)
+		},
+	}
+
+	return func(c *core.Context) {
+		ctx := context.WithValue(c.Request.Context(), proxyContextKey{}, c)
+		proxy.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
+	}
+}
+
+// proxyError 记录转发错误并返回 502 或 504
+func proxyError(c *core.Context, translator Translator, err error) {
+	if errors.Is(err, context.Canceled) {
+		// 客户端已断开
+		return
+	}
+	e := ErrBadGateway
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		e = ErrGatewayTimeout
+	}
+	c.Logger().Error("[Proxy] %s %s 转发失败：%v", c.Request.Method, c.Request.URL.Path, err)
+	if c.Written() {
+		return
+	}
+	body, _ := json.Marshal(core.Response{
+		Code:    e.Code,
+		Message: translateMessage(c, translator, e),
+		TraceID: c.TraceID(),
+	})
+	c.Data(e.Status, "application/json; charset=utf-8", body)
+}
+
+// stripPrefix 去掉 URL 路径的前缀，结果以 / 开头
+func stripPrefix(u *url.URL, prefix string) {
+	u.Path = "/" + strings.TrimLeft(strings.TrimPrefix(u.Path, prefix), "/")
+	if u.RawPath != "" {
+		u.RawPath = "/" + strings.TrimLeft(strings.TrimPrefix(u.RawPath, prefix), "/")
+	}
+}
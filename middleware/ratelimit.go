@@ -1,32 +1,267 @@
 package middleware
 
 import (
+	"container/list"
+	"context"
+	"math"
+	"strconv"
+	"sync"
 	"time"
 
-	"github.com/gin-gonic/gin"
-	"go.uber.org/ratelimit"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/xzl-go/easygo/core"
+	errs "github.com/xzl-go/easygo/errors"
 )
 
-// RateLimiter 限流中间件
-func RateLimiter(limit int) gin.HandlerFunc {
-	limiter := ratelimit.New(limit, ratelimit.Per(time.Second))
-	return func(c *gin.Context) {
-		limiter.Take()
+// DefaultRateLimitMaxKeys 是内存限流器默认保留的最大键数
+const DefaultRateLimitMaxKeys = 10000
+
+// RateLimitResult 是限流检查的结果
+type RateLimitResult struct {
+	Allowed    bool          // 是否允许本次请求
+	Limit      int           // 桶容量（突发上限）
+	Remaining  int           // 剩余令牌数
+	RetryAfter time.Duration // 被拒绝时距离下一个令牌可用的时间
+}
+
+// RateLimitStore 定义了令牌桶限流器的存储，可基于内存或 Redis 实现
+type RateLimitStore interface {
+	// Allow 从键对应的令牌桶中取出一个令牌
+	Allow(ctx context.Context, key string) (RateLimitResult, error)
+}
+
+// RateLimitConfig 定义了限流中间件配置
+type RateLimitConfig struct {
+	// Rate 每秒补充的令牌数，即平均每秒允许的请求数，Store 为 nil 时必须大于 0
+	Rate float64
+	// Burst 桶容量，即允许的突发请求数，默认与 Rate 相同（至少为 1）
+	Burst int
+	// MaxKeys 内存限流器保留的最大键数，超出时淘汰最久未使用的键，默认 10000
+	MaxKeys int
+	// Store 令牌桶存储，为 nil 时按 Rate、Burst 和 MaxKeys 创建内存存储；多实例部署时使用 NewRedisRateLimitStore
+	Store RateLimitStore
+	// KeyFunc 返回限流键，默认按 Context.ClientIP 限流；位于代理之后时需通过 Engine.SetTrustedProxies 设置可信代理，
+	// 不可信来源的 X-Forwarded-For 不会被采用，客户端无法通过伪造请求头获得新的令牌桶
+	KeyFunc func(c *core.Context) string
+	// Skipper 返回 true 时跳过限流
+	Skipper func(c *core.Context) bool
+	// Translator 按请求语言翻译错误消息，为 nil 时使用错误的默认消息
+	Translator Translator
+	// ErrorHandler 超出限制时的处理函数，默认返回 429 统一响应并中止请求
+	ErrorHandler func(c *core.Context, err error)
+}
+
+// RateLimiter 返回全局限流中间件，所有请求共享一个令牌桶
+// limit: 每秒允许的请求数，同时作为突发上限
+func RateLimiter(limit int) core.HandlerFunc {
+	return RateLimitWithConfig(RateLimitConfig{
+		Rate:    float64(limit),
+		KeyFunc: func(c *core.Context) string { return "" },
+	})
+}
+
+// IPRateLimiter 返回按客户端 IP 限流的中间件，客户端 IP 只采用可信代理设置的转发请求头
+// limit: 每个 IP 每秒允许的请求数，同时作为突发上限
+func IPRateLimiter(limit int) core.HandlerFunc {
+	return RateLimitWithConfig(RateLimitConfig{Rate: float64(limit)})
+}
+
+// RateLimitWithConfig 按配置返回令牌桶限流中间件
+// 响应头 X-RateLimit-Limit、X-RateLimit-Remaining 告知客户端限流状态，被拒绝时附带 Retry-After。
+// 存储出错（如 Redis 不可用）时记录日志并放行请求，避免限流器故障导致服务不可用。
+// config: 中间件配置
+func RateLimitWithConfig(config RateLimitConfig) core.HandlerFunc {
+	if config.Store == nil {
+		if config.Rate <= 0 {
+			panic("middleware: 限流速率必须大于 0")
+		}
+		config.Store = NewMemoryRateLimitStore(config.Rate, config.Burst, config.MaxKeys)
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = func(c *core.Context) string {
+			return c.ClientIP()
+		}
+	}
+	if config.ErrorHandler == nil {
+		config.ErrorHandler = func(c *core.Context, err error) {
+			e := errs.From(err)
+			c.Fail(e.WithMessage(translateMessage(c, config.Translator, e)))
+			c.Abort()
+		}
+	}
+
+	return func(c *core.Context) {
+		if config.Skipper != nil && config.Skipper(c) {
+			c.Next()
+			return
+		}
+
+		result, err := config.Store.Allow(c, config.KeyFunc(c))
+		if err != nil {
+			c.Logger().Error("[RateLimit] 限流检查失败：%v", err)
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		header.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		if !result.Allowed {
+			seconds := int(math.Ceil(result.RetryAfter.Seconds()))
+			header.Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+			config.ErrorHandler(c, errs.ErrTooManyRequests)
+			return
+		}
 		c.Next()
 	}
 }
 
-// IPRateLimiter IP限流中间件
-func IPRateLimiter(limit int) gin.HandlerFunc {
-	limiters := make(map[string]ratelimit.Limiter)
-	return func(c *gin.Context) {
-		ip := c.ClientIP()
-		limiter, exists := limiters[ip]
-		if !exists {
-			limiter = ratelimit.New(limit, ratelimit.Per(time.Second))
-			limiters[ip] = limiter
+// normalizeBurst 返回有效的桶容量
+func normalizeBurst(rate float64, burst int) int {
+	if burst <= 0 {
+		burst = int(math.Ceil(rate))
+	}
+	return max(burst, 1)
+}
+
+// tokenBucket 是单个键的令牌桶
+type tokenBucket struct {
+	key    string
+	tokens float64
+	last   time.Time
+}
+
+// MemoryRateLimitStore 是进程内的令牌桶存储，键数超出上限时按 LRU 淘汰，适用于单实例部署
+type MemoryRateLimitStore struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   int
+	maxKeys int
+	order   *list.List // 按最近使用排序，队首为最近使用
+	buckets map[string]*list.Element
+}
+
+// NewMemoryRateLimitStore 创建进程内的令牌桶存储
+// rate: 每秒补充的令牌数
+// burst: 桶容量，为 0 时与 rate 相同
+// maxKeys: 最大键数，为 0 时使用 DefaultRateLimitMaxKeys
+func NewMemoryRateLimitStore(rate float64, burst, maxKeys int) *MemoryRateLimitStore {
+	if maxKeys <= 0 {
+		maxKeys = DefaultRateLimitMaxKeys
+	}
+	return &MemoryRateLimitStore{
+		rate:    rate,
+		burst:   normalizeBurst(rate, burst),
+		maxKeys: maxKeys,
+		order:   list.New(),
+		buckets: make(map[string]*list.Element),
+	}
+}
+
+// Allow 从键对应的令牌桶中取出一个令牌
+func (s *MemoryRateLimitStore) Allow(ctx context.Context, key string) (RateLimitResult, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var b *tokenBucket
+	if elem, ok := s.buckets[key]; ok {
+		s.order.MoveToFront(elem)
+		b = elem.Value.(*tokenBucket)
+		elapsed := now.Sub(b.last).Seconds()
+		b.tokens = math.Min(float64(s.burst), b.tokens+elapsed*s.rate)
+		b.last = now
+	} else {
+		b = &tokenBucket{key: key, tokens: float64(s.burst), last: now}
+		s.buckets[key] = s.order.PushFront(b)
+		if s.order.Len() > s.maxKeys {
+			oldest := s.order.Back()
+			s.order.Remove(oldest)
+			delete(s.buckets, oldest.Value.(*tokenBucket).key)
 		}
-		limiter.Take()
-		c.Next()
 	}
+
+	result := RateLimitResult{Limit: s.burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) / s.rate * float64(time.Second))
+	}
+	result.Remaining = int(b.tokens)
+	return result, nil
+}
+
+// Len 返回当前保留的键数
+func (s *MemoryRateLimitStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+// tokenBucketScript 令牌桶限流，按上次访问以来经过的时间补充令牌，返回是否允许、剩余令牌数和需等待的毫秒数
+var tokenBucketScript = goredis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil then
+	tokens = burst
+	ts = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) * 1000 / rate)
+end
+redis.call("HMSET", KEYS[1], "tokens", tostring(tokens), "ts", tostring(math.max(now, ts)))
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+return {allowed, math.floor(tokens), wait}`)
+
+// RedisRateLimitStore 是基于 Redis 的令牌桶存储，多个实例共享同一个令牌桶
+// 令牌桶在补满后自动过期，不会无限占用内存
+type RedisRateLimitStore struct {
+	client goredis.UniversalClient
+	prefix string
+	rate   float64
+	burst  int
+}
+
+// NewRedisRateLimitStore 创建基于 Redis 的令牌桶存储
+// client: Redis 客户端
+// prefix: 键前缀，为空时使用 "ratelimit:"
+// rate: 每秒补充的令牌数
+// burst: 桶容量，为 0 时与 rate 相同
+func NewRedisRateLimitStore(client goredis.UniversalClient, prefix string, rate float64, burst int) *RedisRateLimitStore {
+	if rate <= 0 {
+		panic("middleware: 限流速率必须大于 0")
+	}
+	if prefix == "" {
+		prefix = "ratelimit:"
+	}
+	return &RedisRateLimitStore{client: client, prefix: prefix, rate: rate, burst: normalizeBurst(rate, burst)}
+}
+
+// Allow 从键对应的令牌桶中取出一个令牌
+// 使用应用服务器的时间计算补充的令牌，各实例的时钟应保持同步
+func (s *RedisRateLimitStore) Allow(ctx context.Context, key string) (RateLimitResult, error) {
+	now := time.Now().UnixMilli()
+	values, err := tokenBucketScript.Run(ctx, s.client, []string{s.prefix + key},
+		strconv.FormatFloat(s.rate, 'f', -1, 64), s.burst, now).Int64Slice()
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+	return RateLimitResult{
+		Allowed:    values[0] == 1,
+		Limit:      s.burst,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+	}, nil
 }
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/xzl-go/easygo/core"
+)
+
+// TestIPRateLimiterSpoofedForwardedFor 验证不可信来源的 X-Forwarded-For 不会得到新的令牌桶
+func TestIPRateLimiterSpoofedForwardedFor(t *testing.T) {
+	app := core.New()
+	app.Use(IPRateLimiter(1))
+	app.GET("/", func(c *core.Context) { c.String(http.StatusOK, "ok") })
+
+	codes := make([]int, 3)
+	for i := range codes {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = "203.0.113.7:1234"
+		r.Header.Set("X-Forwarded-For", "10.0.0."+strconv.Itoa(i+1))
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, r)
+		codes[i] = w.Code
+	}
+	if codes[0] != http.StatusOK || codes[1] != http.StatusTooManyRequests || codes[2] != http.StatusTooManyRequests {
+		t.Errorf("响应状态码 %v，期望第一次之后返回 429", codes)
+	}
+}
+
+// TestIPRateLimiterTrustedProxy 验证可信代理之后按转发的客户端地址分别限流
+func TestIPRateLimiterTrustedProxy(t *testing.T) {
+	app := core.New()
+	if err := app.SetTrustedProxies("10.0.0.0/8"); err != nil {
+		t.Fatal(err)
+	}
+	app.Use(IPRateLimiter(1))
+	app.GET("/", func(c *core.Context) { c.String(http.StatusOK, "ok") })
+
+	for _, client := range []string{"198.51.100.1", "198.51.100.2"} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = "10.0.0.5:1234"
+		r.Header.Set("X-Forwarded-For", client)
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Errorf("客户端 %s 返回 %d，期望 200", client, w.Code)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/xzl-go/easygo/core"
+	errs "github.com/xzl-go/easygo/errors"
+	"github.com/xzl-go/easygo/rbac"
+)
+
+// RBACConfig 定义了 RBAC 权限中间件配置
+type RBACConfig struct {
+	// Subject 从请求中提取主体（用户或角色），默认读取上下文键 current_user（由 JWT 或 API Key 中间件写入）
+	// 返回空字符串表示未认证，响应 401
+	Subject func(c *core.Context) string
+	// Object 将请求映射为权限对象，默认使用请求路径
+	// 策略中的通配对象（如 /api/*、/users/:id）需要模型的匹配器使用 keyMatch2(r.obj, p.obj)
+	Object func(c *core.Context) string
+	// Action 将请求映射为操作，默认使用请求方法
+	Action func(c *core.Context) string
+	// Domain 从请求中提取域（租户），设置后调用 EnforceWithDomain 按租户检查权限，
+	// 例如 RBACDomainFromClaims、RBACDomainFromSubdomain；返回空字符串表示无法确定租户，响应 403
+	Domain func(c *core.Context) string
+	// Translator 按请求语言翻译错误消息，为 nil 时使用错误的默认消息
+	Translator Translator
+	// ErrorHandler 未认证、无权限或权限检查失败时的处理函数，默认返回统一错误响应并中止请求
+	// err 为 errs.ErrUnauthorized、errs.ErrForbidden 或 errs.ErrInternal
+	ErrorHandler func(c *core.Context, err error)
+}
+
+// RBAC 返回 RBAC 权限中间件，以请求路径为对象、请求方法为操作调用 Enforce 检查权限
+// manager: RBAC 管理器
+// subject: 主体提取函数，为 nil 时读取上下文键 current_user
+func RBAC(manager *rbac.RBACManager, subject func(c *core.Context) string) core.HandlerFunc {
+	return RBACWithConfig(manager, RBACConfig{Subject: subject})
+}
+
+// RBACWithConfig 按配置返回 RBAC 权限中间件
+// manager: RBAC 管理器
+// config: 中间件配置
+func RBACWithConfig(manager *rbac.RBACManager, config RBACConfig) core.HandlerFunc {
+	if config.Subject == nil {
+		config.Subject = func(c *core.Context) string {
+			user, _ := c.Get(CurrentUserKey).(string)
+			return user
+		}
+	}
+	if config.Object == nil {
+		config.Object = func(c *core.Context) string {
+			return c.Request.URL.Path
+		}
+	}
+	if config.Action == nil {
+		config.Action = func(c *core.Context) string {
+			return c.Request.Method
+		}
+	}
+	if config.ErrorHandler == nil {
+		config.ErrorHandler = func(c *core.Context, err error) {
+			e := errs.From(err)
+			c.Fail(e.WithMessage(translateMessage(c, config.Translator, e)))
+			c.Abort()
+		}
+	}
+
+	return func(c *core.Context) {
+		sub := config.Subject(c)
+		if sub == "" {
+			config.ErrorHandler(c, errs.ErrUnauthorized)
+			return
+		}
+		var allowed bool
+		var err error
+		if config.Domain != nil {
+			domain := config.Domain(c)
+			if domain == "" {
+				config.ErrorHandler(c, errs.ErrForbidden)
+				return
+			}
+			allowed, err = manager.EnforceWithDomain(sub, domain, config.Object(c), config.Action(c))
+		} else {
+			allowed, err = manager.Enforce(sub, config.Object(c), config.Action(c))
+		}
+		if err != nil {
+			c.Logger().Error("[RBAC] 权限检查失败：%v", err)
+			config.ErrorHandler(c, errs.ErrInternal.Wrap(err))
+			return
+		}
+		if !allowed {
+			config.ErrorHandler(c, errs.ErrForbidden)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RBACWithDomain 返回按租户检查权限的 RBAC 中间件，模型需要带域的请求定义和角色定义
+// manager: RBAC 管理器
+// domain: 域提取函数，例如 RBACDomainFromClaims
+func RBACWithDomain(manager *rbac.RBACManager, domain func(c *core.Context) string) core.HandlerFunc {
+	return RBACWithConfig(manager, RBACConfig{Domain: domain})
+}
+
+// RBACSubjectFromClaims 以 JWT 载荷中的用户ID作为主体，可作为 RBAC 的主体提取函数，需在 JWT 中间件之后使用
+// 用户名不唯一且可能由用户自行设置，不应作为主体
+func RBACSubjectFromClaims(c *core.Context) string {
+	if claims := GetClaims(c); claims != nil {
+		return claims.UserID
+	}
+	return ""
+}
+
+// RBACDomainFromClaims 以 JWT 载荷中的自定义声明 tenant_id 作为域，需在 JWT 中间件之后使用
+func RBACDomainFromClaims(c *core.Context) string {
+	if claims := GetClaims(c); claims != nil {
+		if tenant, ok := claims.Extra["tenant_id"]; ok && tenant != nil {
+			return fmt.Sprint(tenant)
+		}
+	}
+	return ""
+}
+
+// RBACDomainFromSubdomain 返回以子域名作为域的提取函数，例如 acme.example.com 的域为 acme
+// 请求主机不属于 baseDomain 或没有子域名时返回空字符串
+// baseDomain: 主域名，例如 example.com
+func RBACDomainFromSubdomain(baseDomain string) func(c *core.Context) string {
+	suffix := "." + strings.ToLower(strings.TrimPrefix(baseDomain, "."))
+	return func(c *core.Context) string {
+		host := c.Request.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.ToLower(host)
+		if !strings.HasSuffix(host, suffix) {
+			return ""
+		}
+		sub := strings.TrimSuffix(host, suffix)
+		// 多级子域名取最靠近主域名的一级，例如 api.acme.example.com 的域为 acme
+		if i := strings.LastIndex(sub, "."); i >= 0 {
+			sub = sub[i+1:]
+		}
+		return sub
+	}
+}
@@ -2,16 +2,21 @@
 package middleware
 
 import (
+	"fmt"
+
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/xzl-go/easygo/core"
-	"github.com/xzl-go/easygo/logger"
 )
 
 // Recovery 返回一个恢复中间件
+// 使用 Tracing 中间件时 panic 同时记录到当前追踪跨度
 func Recovery() core.HandlerFunc {
 	return func(c *core.Context) {
 		defer func() {
 			if err := recover(); err != nil {
-				logger.Error("Panic recovered: %v", err)
+				c.Logger().Error("Panic recovered: %v", err)
+				trace.SpanFromContext(c).RecordError(fmt.Errorf("panic: %v", err), trace.WithStackTrace(true))
 				c.JSON(500, map[string]string{
 					"error": "Internal server error",
 				})
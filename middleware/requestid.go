@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/xzl-go/easygo/core"
+	"github.com/xzl-go/easygo/id"
+	"github.com/xzl-go/easygo/logger"
+)
+
+// RequestIDKey 是上下文中保存请求ID的键
+const RequestIDKey = "request_id"
+
+// maxRequestIDLength 是接受的请求ID最大长度
+const maxRequestIDLength = 128
+
+// RequestIDConfig 定义了请求ID中间件配置
+type RequestIDConfig struct {
+	// Header 读取和写出请求ID的请求头，默认 "X-Request-ID"
+	Header string
+	// Generator 生成请求ID，默认生成 UUID
+	Generator func() string
+	// IgnoreIncoming 为 true 时总是生成新的请求ID，不信任客户端传入的值
+	IgnoreIncoming bool
+}
+
+// RequestID 返回使用默认配置的请求ID中间件
+func RequestID() core.HandlerFunc {
+	return RequestIDWithConfig(RequestIDConfig{})
+}
+
+// RequestIDWithConfig 按配置返回请求ID中间件
+// 沿用请求头中的请求ID（上游网关或调用方传入），没有或格式不合法时生成新的请求ID，然后：
+// 写入上下文键 request_id（trace_id 未设置时同时写入，统一响应会带上该ID）、请求头和响应头，
+// 作为属性记录到当前追踪跨度，并为请求创建带 request_id 字段的日志记录器，通过 c.Logger() 记录的日志都会带上请求ID。
+// 应注册在其他中间件之前，使后续中间件的日志也能带上请求ID。
+// config: 中间件配置
+func RequestIDWithConfig(config RequestIDConfig) core.HandlerFunc {
+	if config.Header == "" {
+		config.Header = "X-Request-ID"
+	}
+	if config.Generator == nil {
+		config.Generator = func() string {
+			rid, err := id.NewUUID()
+			if err != nil {
+				rid, _ = id.NewULID()
+			}
+			return rid
+		}
+	}
+
+	return func(c *core.Context) {
+		rid := ""
+		if !config.IgnoreIncoming {
+			rid = c.GetHeader(config.Header)
+		}
+		if !validRequestID(rid) {
+			rid = config.Generator()
+			c.Request.Header.Set(config.Header, rid)
+		}
+
+		c.Set(RequestIDKey, rid)
+		if traceID, _ := c.Get(core.TraceIDKey).(string); traceID == "" {
+			c.Set(core.TraceIDKey, rid)
+		}
+		c.SetHeader(config.Header, rid)
+		trace.SpanFromContext(c).SetAttributes(attribute.String("http.request_id", rid))
+		c.Request = c.Request.WithContext(logger.NewContext(c.Request.Context(), c.Logger().With(RequestIDKey, rid)))
+
+		c.Next()
+	}
+}
+
+// GetRequestID 返回当前请求的请求ID，未使用请求ID中间件时返回空字符串
+func GetRequestID(c *core.Context) string {
+	rid, _ := c.Get(RequestIDKey).(string)
+	return rid
+}
+
+// validRequestID 检查传入的请求ID，只接受长度有限的可见 ASCII 字符，防止日志注入
+func validRequestID(rid string) bool {
+	if rid == "" || len(rid) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(rid); i++ {
+		if rid[i] <= ' ' || rid[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
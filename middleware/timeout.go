@@ -0,0 +1,227 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/xzl-go/easygo/core"
+	errs "github.com/xzl-go/easygo/errors"
+)
+
+// 超时错误
+var (
+	ErrTimeout        = errs.New(50300, "error.timeout", http.StatusServiceUnavailable, "Request timed out")
+	ErrGatewayTimeout = errs.New(50400, "error.gateway_timeout", http.StatusGatewayTimeout, "Gateway timeout")
+)
+
+// TimeoutConfig 定义了超时中间件配置
+type TimeoutConfig struct {
+	// Timeout 处理超时时间，必须大于 0
+	Timeout time.Duration
+	// Err 超时返回的错误，默认 ErrTimeout（503），作为网关转发上游请求时可使用 ErrGatewayTimeout（504）
+	Err *errs.Error
+	// ExcludedPaths 不设置超时的路径，以 "*" 结尾表示前缀匹配，SSE、WebSocket 等长连接需要排除
+	ExcludedPaths []string
+	// Translator 按请求语言翻译错误消息，为 nil 时使用错误的默认消息
+	Translator Translator
+}
+
+// Timeout 返回超时中间件，超时返回 503
+// timeout: 处理超时时间
+func Timeout(timeout time.Duration) core.HandlerFunc {
+	return TimeoutWithConfig(TimeoutConfig{Timeout: timeout})
+}
+
+// TimeoutWithConfig 按配置返回超时中间件
+// 后续处理函数在带截止时间的 Context 中运行，响应先写入缓冲区，按时完成后再写给客户端；
+// 超时后立即向客户端返回错误响应，之后处理函数的写入返回 http.ErrHandlerTimeout 并被丢弃。
+// 中间件会等待处理函数返回后才结束请求，保证 Context 不会在复用后仍被使用，
+// 因此处理函数应将 c 作为 context 传给数据库、Redis、HTTP 等调用，或检查 c.Done() 以便尽快退出。
+// 缓冲模式不支持流式响应和 Hijack，SSE、WebSocket 路由应通过 ExcludedPaths 排除。
+// config: 中间件配置
+func TimeoutWithConfig(config TimeoutConfig) core.HandlerFunc {
+	if config.Timeout <= 0 {
+		panic("middleware: 超时时间必须大于 0")
+	}
+	if config.Err == nil {
+		config.Err = ErrTimeout
+	}
+
+	return func(c *core.Context) {
+		if matchAnyPath(config.ExcludedPaths, c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		cancel := c.WithTimeout(config.Timeout)
+		defer cancel()
+		ctx, log, method, path := c.Request.Context(), c.Logger(), c.Request.Method, c.Request.URL.Path
+
+		// 超时响应在处理函数运行期间写出，需提前准备好，避免与处理函数并发访问 Context
+		body, _ := json.Marshal(core.Response{
+			Code:    config.Err.Code,
+			Message: translateMessage(c, config.Translator, config.Err),
+			TraceID: c.TraceID(),
+		})
+
+		original := c.Writer
+		tw := &timeoutWriter{
+			original: original,
+			header:   original.Header().Clone(),
+			status:   http.StatusOK,
+			size:     -1,
+		}
+		c.Writer = tw
+
+		done := make(chan struct{})
+		var panicked interface{}
+		go func() {
+			defer func() {
+				panicked = recover()
+				close(done)
+			}()
+			c.Next()
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			// 客户端断开时只丢弃响应，超时时返回错误响应
+			if tw.timeout() && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				log.Error("[Timeout] %s %s 处理超过 %s", method, path, config.Timeout)
+				header := original.Header()
+				header.Set("Content-Type", "application/json")
+				header.Set("Content-Length", strconv.Itoa(len(body)+1))
+				original.WriteHeader(config.Err.Status)
+				original.Write(append(body, '\n'))
+				original.Flush()
+			}
+			<-done
+		}
+
+		c.Writer = original
+		if panicked != nil {
+			// 交给恢复中间件处理
+			panic(panicked)
+		}
+		tw.commit()
+	}
+}
+
+// timeoutWriter 缓冲处理函数的响应，超时后丢弃之后的写入
+type timeoutWriter struct {
+	original core.ResponseWriter
+
+	mu       sync.Mutex
+	header   http.Header
+	buf      bytes.Buffer
+	status   int
+	size     int
+	timedOut bool
+}
+
+// timeout 将写入器标记为超时，处理函数已完成时返回 false
+func (w *timeoutWriter) timeout() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return false
+	}
+	w.timedOut = true
+	return true
+}
+
+// commit 在处理函数按时完成后写出缓冲的响应
+func (w *timeoutWriter) commit() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return
+	}
+	w.timedOut = true
+	header := w.original.Header()
+	for k := range header {
+		delete(header, k)
+	}
+	for k, v := range w.header {
+		header[k] = v
+	}
+	if w.size < 0 {
+		return
+	}
+	w.original.WriteHeader(w.status)
+	if w.buf.Len() > 0 {
+		w.original.Write(w.buf.Bytes())
+	}
+}
+
+// Header 返回响应头，超时前不会写给客户端
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader 记录状态码
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if code <= 0 || w.timedOut || w.size >= 0 {
+		return
+	}
+	w.status = code
+	w.size = 0
+}
+
+// Write 将响应体写入缓冲区，超时后返回 http.ErrHandlerTimeout
+func (w *timeoutWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if w.size < 0 {
+		w.size = 0
+	}
+	n, _ := w.buf.Write(b)
+	w.size += n
+	return n, nil
+}
+
+// Flush 在缓冲模式下不做任何事，响应在处理函数返回后一次写出
+func (w *timeoutWriter) Flush() {}
+
+// Hijack 不支持接管连接
+func (w *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, errors.New("middleware: 超时中间件不支持 Hijack")
+}
+
+// Status 返回响应状态码
+func (w *timeoutWriter) Status() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status
+}
+
+// Size 返回已写入的响应体字节数，尚未写入时返回 -1
+func (w *timeoutWriter) Size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.size
+}
+
+// Written 返回是否已写入响应
+func (w *timeoutWriter) Written() bool {
+	return w.Size() >= 0
+}
+
+// Unwrap 返回原始的 http.ResponseWriter，供 http.ResponseController 设置读写截止时间
+func (w *timeoutWriter) Unwrap() http.ResponseWriter {
+	return w.original.Unwrap()
+}
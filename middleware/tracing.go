@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/xzl-go/easygo/core"
+	"github.com/xzl-go/easygo/logger"
+	"github.com/xzl-go/easygo/tracing"
+)
+
+// tracerName 是未指定追踪器时使用的追踪器名称
+const tracerName = "github.com/xzl-go/easygo/middleware"
+
+// 日志字段名
+const (
+	TraceIDField = "trace_id" // 追踪ID
+	SpanIDField  = "span_id"  // 请求跨度ID
+)
+
+// TracingConfig 定义了链路追踪中间件配置
+type TracingConfig struct {
+	// Tracer 创建跨度的追踪器，为 nil 时使用全局追踪器提供者
+	Tracer *tracing.Tracer
+	// Propagator 提取上游追踪上下文的传播器，默认为 W3C traceparent 和 baggage
+	Propagator propagation.TextMapPropagator
+	// SpanName 返回跨度名称，默认为 "方法 路由模式"，例如 "GET /users/:id"
+	SpanName func(c *core.Context) string
+	// ExcludedPaths 不追踪的路径，以 "*" 结尾表示前缀匹配，例如 /healthz、/metrics
+	ExcludedPaths []string
+}
+
+// Tracing 返回链路追踪中间件
+// tracer: 追踪器，为 nil 时使用全局追踪器提供者
+func Tracing(tracer *tracing.Tracer) core.HandlerFunc {
+	return TracingWithConfig(TracingConfig{Tracer: tracer})
+}
+
+// TracingWithConfig 按配置返回链路追踪中间件
+// 从请求头提取上游服务的 traceparent 和 baggage，为每个请求创建服务端跨度，
+// 记录方法、路由模式、状态码等语义属性，状态码为 5xx 或处理函数 panic 时将跨度标记为错误。
+// 跨度放入请求的 context，后续处理函数通过 c.SpanContext() 读取，将 c 作为 context 传给数据库、Redis、
+// HTTP 客户端等调用即可创建子跨度；上下文键 trace_id 被设置为追踪ID，统一响应会带上该ID，
+// 通过 c.Logger() 记录的日志带上 trace_id 和 span_id 字段，metrics.Collector 记录的指标带上对应的 exemplar。
+// 应注册在 RequestID 和 Recovery 之前，使请求ID记录到跨度上、panic 能被记录。
+// config: 中间件配置
+func TracingWithConfig(config TracingConfig) core.HandlerFunc {
+	var tracer trace.Tracer
+	if config.Tracer != nil {
+		tracer = config.Tracer.Tracer()
+	} else {
+		tracer = otel.Tracer(tracerName)
+	}
+	if config.Propagator == nil {
+		config.Propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	}
+	if config.SpanName == nil {
+		config.SpanName = func(c *core.Context) string {
+			if route := c.FullPath(); route != "" {
+				return c.Request.Method + " " + route
+			}
+			return c.Request.Method
+		}
+	}
+
+	return func(c *core.Context) {
+		if matchAnyPath(config.ExcludedPaths, c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		r := c.Request
+		ctx := config.Propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		opts := []trace.SpanStartOption{
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.URLPath(r.URL.Path),
+				semconv.URLScheme(scheme),
+				semconv.ServerAddress(r.Host),
+				semconv.ClientAddress(c.ClientIP()),
+				semconv.UserAgentOriginal(r.UserAgent()),
+				semconv.NetworkProtocolVersion(fmt.Sprintf("%d.%d", r.ProtoMajor, r.ProtoMinor)),
+			),
+		}
+		if route := c.FullPath(); route != "" {
+			opts = append(opts, trace.WithAttributes(semconv.HTTPRoute(route)))
+		}
+		ctx, span := tracer.Start(ctx, config.SpanName(c), opts...)
+		if sc := span.SpanContext(); sc.IsValid() {
+			c.Set(core.TraceIDKey, sc.TraceID().String())
+			// 通过 c.Logger() 记录的日志带上 trace_id 和 span_id，便于从日志跳转到追踪
+			entry := logger.FromContext(ctx).With(TraceIDField, sc.TraceID().String()).With(SpanIDField, sc.SpanID().String())
+			ctx = logger.NewContext(ctx, entry)
+		}
+		c.Request = r.WithContext(ctx)
+
+		defer func() {
+			status := c.Writer.Status()
+			rec := recover()
+			if rec != nil {
+				// 没有恢复中间件时按 500 记录，再继续向上传播
+				status = http.StatusInternalServerError
+				span.RecordError(fmt.Errorf("panic: %v", rec), trace.WithStackTrace(true))
+			}
+			for _, err := range c.Errors {
+				span.RecordError(err)
+			}
+			span.SetAttributes(
+				semconv.HTTPResponseStatusCode(status),
+				semconv.HTTPResponseBodySize(max(c.Writer.Size(), 0)),
+			)
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+			span.End()
+			if rec != nil {
+				panic(rec)
+			}
+		}()
+		c.Next()
+	}
+}
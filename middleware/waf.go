@@ -0,0 +1,300 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/xzl-go/easygo/core"
+	errs "github.com/xzl-go/easygo/errors"
+)
+
+// ErrWAFBlocked 请求被安全策略拦截
+var ErrWAFBlocked = errs.New(40330, "error.waf.blocked", http.StatusForbidden, "Request blocked by security policy")
+
+// WAFMode 定义了命中规则后的处理方式
+type WAFMode int
+
+const (
+	WAFBlock   WAFMode = iota // 拦截请求并返回 403
+	WAFLogOnly                // 仅记录日志，用于上线前观察误报
+)
+
+// 内置检查的名称，可用于 WAFExemption.Rules
+const (
+	WAFCheckParamCount  = "param_count"
+	WAFCheckParamLength = "param_length"
+	WAFCheckHeader      = "header"
+)
+
+// WAFRule 是基于正则表达式的检测规则
+type WAFRule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// DefaultWAFRules 返回内置的 SQL 注入、XSS 和路径穿越检测规则
+func DefaultWAFRules() []WAFRule {
+	return []WAFRule{
+		{
+			Name: "sqli",
+			Pattern: regexp.MustCompile(`(?i)(\bunion\b[\s(]+(all\s+)?select\b|\bselect\b[\s\S]+?\bfrom\b[\s\S]+?(\bwhere\b|--|#|;)|` +
+				`\binsert\s+into\b|\bdelete\s+from\b|\bdrop\s+(table|database)\b|\bupdate\s+\w+\s+set\b|` +
+				`['"]\s*(or|and)\s+['"]?\w+['"]?\s*(=|like)\s*['"]?\w+|\b(sleep|benchmark|pg_sleep)\s*\(|` +
+				`\bwaitfor\s+delay\b|;\s*(shutdown|exec|xp_cmdshell)\b|/\*[\s\S]*?\*/)`),
+		},
+		{
+			Name: "xss",
+			Pattern: regexp.MustCompile(`(?i)(<\s*script\b|<\s*/\s*script\s*>|javascript\s*:|vbscript\s*:|` +
+				`<[^>]+\bon[a-z]+\s*=|<\s*(iframe|object|embed)\b|document\s*\.\s*(cookie|domain)|` +
+				`\bsrcdoc\s*=|expression\s*\()`),
+		},
+		{
+			Name:    "path_traversal",
+			Pattern: regexp.MustCompile(`(?i)(\.\.[/\\]|[/\\]\.\.$|%2e%2e|%252e|/etc/(passwd|shadow)|\bwin\.ini\b|\x00)`),
+		},
+	}
+}
+
+// WAFExemption 定义了路由豁免
+type WAFExemption struct {
+	// Path 路径，以 "*" 结尾表示前缀匹配，例如 "/api/articles/*"
+	Path string
+	// Rules 豁免的规则或检查名称，为空表示豁免全部
+	Rules []string
+}
+
+// WAFViolation 是一次规则命中
+type WAFViolation struct {
+	Rule     string // 命中的规则或检查
+	Location string // 命中位置，例如 "query.id"、"path"、"header.User-Agent"
+	Value    string // 命中的值（截断至 256 字节）
+}
+
+// WAFConfig 定义了 WAF 中间件配置
+type WAFConfig struct {
+	// Mode 命中规则后的处理方式，默认拦截
+	Mode WAFMode
+	// Rules 检测规则，默认为 DefaultWAFRules()
+	Rules []WAFRule
+	// MaxParams 查询参数与表单参数的最大数量，默认 100，小于 0 表示不限制
+	MaxParams int
+	// MaxParamLength 单个参数值的最大长度，默认 8192，小于 0 表示不限制
+	MaxParamLength int
+	// MaxHeaders 请求头的最大数量，默认 100
+	MaxHeaders int
+	// InspectBody 是否检查 JSON / 文本请求体，默认仅检查表单
+	InspectBody bool
+	// MaxBodySize 检查请求体的最大字节数，默认 64 KB，超出部分不检查
+	MaxBodySize int64
+	// Exemptions 路由豁免
+	Exemptions []WAFExemption
+	// OnViolation 命中规则时的回调，可用于上报安全事件
+	OnViolation func(c *core.Context, v WAFViolation)
+}
+
+// WAF 返回规则过滤中间件，作为处理函数之前的第一道防线
+// 依次检查请求头异常、参数数量与长度，以及路径、查询参数、表单（和可选的请求体）是否命中检测规则
+// config: 中间件配置
+func WAF(config WAFConfig) core.HandlerFunc {
+	if config.Rules == nil {
+		config.Rules = DefaultWAFRules()
+	}
+	if config.MaxParams == 0 {
+		config.MaxParams = 100
+	}
+	if config.MaxParamLength == 0 {
+		config.MaxParamLength = 8192
+	}
+	if config.MaxHeaders <= 0 {
+		config.MaxHeaders = 100
+	}
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = 64 << 10
+	}
+
+	return func(c *core.Context) {
+		skip := config.exempted(c.Request.URL.Path)
+		if skip["*"] {
+			c.Next()
+			return
+		}
+
+		v, found := config.inspect(c.Request, skip)
+		if !found {
+			c.Next()
+			return
+		}
+
+		c.Logger().Error("[WAF] %s %s %s 命中规则 %s，位置 %s，值 %q", c.ClientIP(), c.Request.Method, c.Request.URL.Path, v.Rule, v.Location, v.Value)
+		if config.OnViolation != nil {
+			config.OnViolation(c, v)
+		}
+		if config.Mode == WAFLogOnly {
+			c.Next()
+			return
+		}
+		c.Fail(ErrWAFBlocked)
+		c.Abort()
+	}
+}
+
+// exempted 返回当前路径豁免的规则集合，"*" 表示全部豁免
+func (config *WAFConfig) exempted(path string) map[string]bool {
+	var skip map[string]bool
+	for _, ex := range config.Exemptions {
+		prefix, isPrefix := strings.CutSuffix(ex.Path, "*")
+		if path != ex.Path && !(isPrefix && strings.HasPrefix(path, prefix)) {
+			continue
+		}
+		if skip == nil {
+			skip = make(map[string]bool)
+		}
+		if len(ex.Rules) == 0 {
+			skip["*"] = true
+		}
+		for _, r := range ex.Rules {
+			skip[r] = true
+		}
+	}
+	return skip
+}
+
+// inspect 检查请求，返回第一条命中记录
+func (config *WAFConfig) inspect(r *http.Request, skip map[string]bool) (WAFViolation, bool) {
+	if !skip[WAFCheckHeader] {
+		if v, ok := checkHeaders(r, config.MaxHeaders); ok {
+			return v, true
+		}
+	}
+
+	params := make(map[string][]string)
+	for k, vs := range r.URL.Query() {
+		params["query."+k] = vs
+	}
+	if form := formValues(r); form != nil {
+		for k, vs := range form {
+			params["form."+k] = vs
+		}
+	}
+
+	if config.MaxParams > 0 && !skip[WAFCheckParamCount] {
+		count := 0
+		for _, vs := range params {
+			count += len(vs)
+		}
+		if count > config.MaxParams {
+			return WAFViolation{Rule: WAFCheckParamCount, Location: "params"}, true
+		}
+	}
+
+	targets := map[string][]string{"path": {r.URL.Path, r.URL.RawPath}}
+	for k, vs := range params {
+		targets[k] = vs
+	}
+	if config.InspectBody {
+		if body := readBodyPrefix(r, config.MaxBodySize); body != "" {
+			targets["body"] = []string{body}
+		}
+	}
+
+	for location, values := range targets {
+		for _, value := range values {
+			if value == "" {
+				continue
+			}
+			if config.MaxParamLength > 0 && location != "body" && len(value) > config.MaxParamLength && !skip[WAFCheckParamLength] {
+				return WAFViolation{Rule: WAFCheckParamLength, Location: location, Value: truncate(value)}, true
+			}
+			// 额外解码一次，识别双重编码的攻击载荷
+			candidates := []string{value}
+			if decoded, err := url.QueryUnescape(value); err == nil && decoded != value {
+				candidates = append(candidates, decoded)
+			}
+			for _, rule := range config.Rules {
+				if skip[rule.Name] {
+					continue
+				}
+				for _, candidate := range candidates {
+					if rule.Pattern.MatchString(candidate) {
+						return WAFViolation{Rule: rule.Name, Location: location, Value: truncate(value)}, true
+					}
+				}
+			}
+		}
+	}
+	return WAFViolation{}, false
+}
+
+// checkHeaders 检查请求头异常：数量过多、包含控制字符、Host 非法
+func checkHeaders(r *http.Request, maxHeaders int) (WAFViolation, bool) {
+	if len(r.Header) > maxHeaders {
+		return WAFViolation{Rule: WAFCheckHeader, Location: "headers"}, true
+	}
+	if strings.ContainsAny(r.Host, " \t\r\n/\\@") {
+		return WAFViolation{Rule: WAFCheckHeader, Location: "header.Host", Value: truncate(r.Host)}, true
+	}
+	for name, values := range r.Header {
+		for _, value := range values {
+			for i := 0; i < len(value); i++ {
+				if b := value[i]; b < 0x20 && b != '\t' || b == 0x7f {
+					return WAFViolation{Rule: WAFCheckHeader, Location: "header." + name, Value: truncate(value)}, true
+				}
+			}
+		}
+	}
+	if len(r.Header.Values("Content-Length")) > 1 {
+		return WAFViolation{Rule: WAFCheckHeader, Location: "header.Content-Length"}, true
+	}
+	return WAFViolation{}, false
+}
+
+// formValues 解析 urlencoded 表单，multipart 表单仅检查普通字段
+func formValues(r *http.Request) url.Values {
+	ct := r.Header.Get("Content-Type")
+	switch {
+	case strings.HasPrefix(ct, "application/x-www-form-urlencoded"):
+		if err := r.ParseForm(); err != nil {
+			return nil
+		}
+		return r.PostForm
+	case strings.HasPrefix(ct, "multipart/form-data"):
+		if err := r.ParseMultipartForm(32 << 20); err != nil || r.MultipartForm == nil {
+			return nil
+		}
+		return r.MultipartForm.Value
+	}
+	return nil
+}
+
+// readBodyPrefix 读取请求体的前 limit 字节用于检查，并回填请求体
+func readBodyPrefix(r *http.Request, limit int64) string {
+	ct := r.Header.Get("Content-Type")
+	if r.Body == nil || r.Body == http.NoBody ||
+		strings.HasPrefix(ct, "multipart/") || strings.HasPrefix(ct, "application/x-www-form-urlencoded") {
+		return ""
+	}
+	head, err := io.ReadAll(io.LimitReader(r.Body, limit))
+	if err != nil {
+		return ""
+	}
+	r.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(head), r.Body), Closer: r.Body}
+	return string(head)
+}
+
+// readCloser 组合读取器与原始请求体的关闭方法
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// truncate 截断过长的值，避免日志膨胀
+func truncate(s string) string {
+	if len(s) > 256 {
+		return s[:256] + "..."
+	}
+	return s
+}
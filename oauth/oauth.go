@@ -0,0 +1,379 @@
+// Package oauth 提供了基于 OAuth2 / OIDC 授权码模式的第三方登录功能
+// 内置 Google、GitHub、微信等提供方，支持 state/PKCE 校验，并可与 jwt 包集成签发本地令牌
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/xzl-go/easygo/cache"
+	"github.com/xzl-go/easygo/core"
+	errs "github.com/xzl-go/easygo/errors"
+	"github.com/xzl-go/easygo/jwt"
+	"github.com/xzl-go/easygo/logger"
+)
+
+// 常见错误
+var (
+	ErrProviderNotFound = errors.New("oauth: 未注册的登录提供方")
+	ErrInvalidState     = errors.New("oauth: state 无效或已过期")
+	ErrMissingCode      = errors.New("oauth: 回调缺少授权码")
+)
+
+// Config 定义了 OAuth2 客户端配置
+type Config struct {
+	ClientID     string   // 客户端ID（微信为 AppID）
+	ClientSecret string   // 客户端密钥（微信为 AppSecret）
+	RedirectURL  string   // 回调地址
+	Scopes       []string // 授权范围
+}
+
+// Token 是授权服务器返回的令牌
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	IDToken      string `json:"id_token"` // OIDC 身份令牌
+	OpenID       string `json:"openid"`   // 微信用户标识
+	UnionID      string `json:"unionid"`  // 微信开放平台统一标识
+}
+
+// UserProfile 是归一化后的第三方用户信息
+type UserProfile struct {
+	Provider string                 `json:"provider"` // 提供方名称
+	ID       string                 `json:"id"`       // 用户在提供方的唯一标识
+	Name     string                 `json:"name"`     // 昵称或用户名
+	Email    string                 `json:"email"`    // 邮箱（可能为空）
+	Avatar   string                 `json:"avatar"`   // 头像地址
+	Raw      map[string]interface{} `json:"-"`        // 原始用户信息
+}
+
+// Provider 定义了第三方登录提供方的行为
+type Provider interface {
+	// Name 返回提供方名称，例如 "github"
+	Name() string
+	// AuthCodeURL 生成授权跳转地址
+	AuthCodeURL(state, codeChallenge string) string
+	// Exchange 使用授权码换取令牌
+	Exchange(ctx context.Context, code, codeVerifier string) (*Token, error)
+	// UserInfo 获取并归一化用户信息
+	UserInfo(ctx context.Context, token *Token) (*UserProfile, error)
+}
+
+// StateStore 定义了 state 与 PKCE 校验码的存储
+type StateStore interface {
+	// Save 保存 state 及其对应的 code_verifier
+	Save(state, verifier string, ttl time.Duration) error
+	// Take 取出并删除 state 对应的 code_verifier，state 不存在或过期时返回 false
+	Take(state string) (string, bool)
+}
+
+// memoryStateStore 是基于内存的 state 存储
+type memoryStateStore struct {
+	mu    sync.Mutex
+	items map[string]stateItem
+}
+
+type stateItem struct {
+	verifier string
+	expireAt time.Time
+}
+
+// NewMemoryStateStore 创建基于内存的 state 存储，适用于单实例部署
+func NewMemoryStateStore() StateStore {
+	return &memoryStateStore{items: make(map[string]stateItem)}
+}
+
+// Save 保存 state
+func (s *memoryStateStore) Save(state, verifier string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	// 顺带清理过期的 state，避免无限增长
+	for k, v := range s.items {
+		if now.After(v.expireAt) {
+			delete(s.items, k)
+		}
+	}
+	s.items[state] = stateItem{verifier: verifier, expireAt: now.Add(ttl)}
+	return nil
+}
+
+// Take 取出并删除 state
+func (s *memoryStateStore) Take(state string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item, ok := s.items[state]
+	if !ok {
+		return "", false
+	}
+	delete(s.items, state)
+	if time.Now().After(item.expireAt) {
+		return "", false
+	}
+	return item.verifier, true
+}
+
+// cacheStateStore 是基于 cache.Cache 的 state 存储
+type cacheStateStore struct {
+	cache  cache.Cache
+	prefix string
+}
+
+// NewCacheStateStore 创建基于缓存的 state 存储，配合 redis.NewCache 可用于多实例部署
+// c: 缓存
+// prefix: 键前缀，为空时使用 "oauth:state:"
+func NewCacheStateStore(c cache.Cache, prefix string) StateStore {
+	if prefix == "" {
+		prefix = "oauth:state:"
+	}
+	return &cacheStateStore{cache: c, prefix: prefix}
+}
+
+// Save 保存 state
+func (s *cacheStateStore) Save(state, verifier string, ttl time.Duration) error {
+	return s.cache.Set(s.prefix+state, verifier, ttl)
+}
+
+// Take 取出并删除 state，缓存实现了 cache.Taker 时为原子操作，同一个 state 只能使用一次
+func (s *cacheStateStore) Take(state string) (string, bool) {
+	return cache.Take(s.cache, s.prefix+state)
+}
+
+// LoginFunc 在第三方登录成功后调用，用于将第三方用户映射为本地用户
+// 返回本地用户ID和用户名，用于签发 JWT；第三方的昵称由用户自行设置，不能直接作为本地用户名，
+// 需要按 Provider 和 ID 查找或创建本地账号
+type LoginFunc func(c *core.Context, profile *UserProfile) (userID, username string, err error)
+
+// Manager 是第三方登录管理器
+// 负责注册提供方、生成授权跳转并处理回调
+type Manager struct {
+	providers  map[string]Provider
+	store      StateStore
+	jwtManager *jwt.JWTManager
+	stateTTL   time.Duration
+	onLogin    LoginFunc
+	onClaims   ClaimsFunc
+	tokenPair  bool
+}
+
+// ClaimsFunc 返回签发本地令牌时写入的自定义声明
+type ClaimsFunc func(c *core.Context, profile *UserProfile) map[string]interface{}
+
+// NewManager 创建一个新的第三方登录管理器
+// jwtManager: 用于签发本地令牌的 JWT 管理器，为 nil 时回调只返回用户信息
+func NewManager(jwtManager *jwt.JWTManager) *Manager {
+	return &Manager{
+		providers:  make(map[string]Provider),
+		store:      NewMemoryStateStore(),
+		jwtManager: jwtManager,
+		stateTTL:   10 * time.Minute,
+		onLogin:    defaultLogin,
+		onClaims:   defaultClaims,
+	}
+}
+
+// defaultClaims 默认在本地令牌中记录登录提供方
+func defaultClaims(c *core.Context, profile *UserProfile) map[string]interface{} {
+	return map[string]interface{}{"provider": profile.Provider}
+}
+
+// defaultLogin 默认以 "提供方:用户ID" 同时作为本地用户ID和用户名，
+// 不使用用户可以自行设置的昵称，避免昵称为 admin 等本地用户名的第三方账号被当作本地用户
+func defaultLogin(c *core.Context, profile *UserProfile) (string, string, error) {
+	id := profile.Provider + ":" + profile.ID
+	return id, id, nil
+}
+
+// Register 注册登录提供方
+func (m *Manager) Register(providers ...Provider) {
+	for _, p := range providers {
+		m.providers[p.Name()] = p
+	}
+}
+
+// Provider 获取已注册的提供方
+func (m *Manager) Provider(name string) (Provider, error) {
+	p, ok := m.providers[name]
+	if !ok {
+		return nil, ErrProviderNotFound
+	}
+	return p, nil
+}
+
+// SetStateStore 设置 state 存储（多实例部署时应使用共享存储）
+func (m *Manager) SetStateStore(store StateStore) {
+	m.store = store
+}
+
+// SetStateTTL 设置 state 有效期
+func (m *Manager) SetStateTTL(ttl time.Duration) {
+	m.stateTTL = ttl
+}
+
+// OnLogin 设置登录成功后的本地用户映射函数
+// 默认以 "提供方:用户ID" 作为本地用户ID和用户名，需要关联本地账号时必须在此完成映射
+func (m *Manager) OnLogin(fn LoginFunc) {
+	m.onLogin = fn
+}
+
+// OnClaims 设置本地令牌的自定义声明，如角色、租户ID，默认只记录登录提供方
+func (m *Manager) OnClaims(fn ClaimsFunc) {
+	m.onClaims = fn
+}
+
+// SetTokenPair 设置回调是否签发访问令牌和刷新令牌，默认只签发访问令牌
+func (m *Manager) SetTokenPair(enabled bool) {
+	m.tokenPair = enabled
+}
+
+// AuthURL 生成授权跳转地址，并保存 state 与 PKCE 校验码
+// name: 提供方名称
+// 返回授权地址和可能的错误
+func (m *Manager) AuthURL(name string) (string, error) {
+	p, err := m.Provider(name)
+	if err != nil {
+		return "", err
+	}
+	state, err := randomString(16)
+	if err != nil {
+		return "", err
+	}
+	verifier, err := randomString(32)
+	if err != nil {
+		return "", err
+	}
+	if err := m.store.Save(state, verifier, m.stateTTL); err != nil {
+		return "", err
+	}
+	return p.AuthCodeURL(state, CodeChallenge(verifier)), nil
+}
+
+// Complete 校验回调参数并获取归一化的用户信息
+// name: 提供方名称
+// state: 回调中的 state
+// code: 回调中的授权码
+func (m *Manager) Complete(ctx context.Context, name, state, code string) (*UserProfile, error) {
+	p, err := m.Provider(name)
+	if err != nil {
+		return nil, err
+	}
+	if code == "" {
+		return nil, ErrMissingCode
+	}
+	verifier, ok := m.store.Take(state)
+	if !ok {
+		return nil, ErrInvalidState
+	}
+	token, err := p.Exchange(ctx, code, verifier)
+	if err != nil {
+		return nil, fmt.Errorf("oauth: 换取令牌失败: %w", err)
+	}
+	profile, err := p.UserInfo(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("oauth: 获取用户信息失败: %w", err)
+	}
+	return profile, nil
+}
+
+// LoginHandler 返回跳转到第三方授权页的处理函数
+// name: 提供方名称
+func (m *Manager) LoginHandler(name string) core.HandlerFunc {
+	return func(c *core.Context) {
+		m.login(c, name)
+	}
+}
+
+// CallbackHandler 返回处理第三方回调的处理函数
+// 登录成功后返回本地 JWT 令牌和归一化的用户信息
+// name: 提供方名称
+func (m *Manager) CallbackHandler(name string) core.HandlerFunc {
+	return func(c *core.Context) {
+		m.callback(c, name)
+	}
+}
+
+// RegisterRoutes 为全部已注册的提供方注册登录和回调路由：
+// GET /:provider/login 跳转到授权页，GET /:provider/callback 处理回调，回调地址需与提供方配置一致
+// group: 路由组，例如 app.Group("/auth")
+func (m *Manager) RegisterRoutes(group *core.RouterGroup) {
+	group.GET("/:provider/login", func(c *core.Context) {
+		m.login(c, c.Param("provider"))
+	})
+	group.GET("/:provider/callback", func(c *core.Context) {
+		m.callback(c, c.Param("provider"))
+	})
+}
+
+// login 跳转到第三方授权页
+func (m *Manager) login(c *core.Context, name string) {
+	url, err := m.AuthURL(name)
+	if err != nil {
+		c.Fail(errs.ErrBadRequest.Wrap(err))
+		return
+	}
+	c.Redirect(http.StatusFound, url)
+}
+
+// callback 处理第三方回调，将第三方用户映射为本地用户并签发令牌
+func (m *Manager) callback(c *core.Context, name string) {
+	profile, err := m.Complete(c.Request.Context(), name, c.Query("state"), c.Query("code"))
+	if err != nil {
+		logger.Error("第三方登录失败：%v", err)
+		c.Fail(errs.ErrUnauthorized.Wrap(err))
+		return
+	}
+	c.Set("oauth_profile", profile)
+
+	userID, username, err := m.onLogin(c, profile)
+	if err != nil {
+		c.Fail(errs.ErrForbidden.Wrap(err))
+		return
+	}
+
+	resp := map[string]interface{}{"user": profile}
+	if m.jwtManager != nil {
+		claims := m.onClaims(c, profile)
+		if m.tokenPair {
+			pair, err := m.jwtManager.GenerateTokenPairWithClaims(userID, username, claims)
+			if err != nil {
+				c.Fail(errs.ErrInternal.Wrap(err))
+				return
+			}
+			resp["token"] = pair.AccessToken
+			resp["refresh_token"] = pair.RefreshToken
+			resp["expires_at"] = pair.ExpiresAt
+		} else {
+			token, err := m.jwtManager.GenerateTokenWithClaims(userID, username, claims)
+			if err != nil {
+				c.Fail(errs.ErrInternal.Wrap(err))
+				return
+			}
+			resp["token"] = token
+		}
+	}
+	c.Success(resp)
+}
+
+// CodeChallenge 根据 code_verifier 计算 S256 方式的 code_challenge
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// randomString 生成 URL 安全的随机字符串
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,314 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Endpoint 定义了 OAuth2 服务端点
+type Endpoint struct {
+	AuthURL     string // 授权地址
+	TokenURL    string // 令牌地址
+	UserInfoURL string // 用户信息地址
+}
+
+// ProfileMapper 将原始用户信息映射为归一化的用户信息
+type ProfileMapper func(raw map[string]interface{}) *UserProfile
+
+// httpClient 是提供方请求使用的默认 HTTP 客户端
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// standardProvider 是标准 OAuth2 / OIDC 提供方的通用实现
+type standardProvider struct {
+	name     string
+	config   Config
+	endpoint Endpoint
+	mapper   ProfileMapper
+}
+
+// NewProvider 创建一个标准 OAuth2 提供方
+// name: 提供方名称
+// cfg: 客户端配置
+// endpoint: 服务端点
+// mapper: 用户信息映射函数
+func NewProvider(name string, cfg Config, endpoint Endpoint, mapper ProfileMapper) Provider {
+	return &standardProvider{name: name, config: cfg, endpoint: endpoint, mapper: mapper}
+}
+
+// NewOIDCProvider 通过 OIDC Discovery 创建提供方
+// issuer: 签发者地址，例如 "https://accounts.google.com"
+func NewOIDCProvider(ctx context.Context, name, issuer string, cfg Config) (Provider, error) {
+	var doc struct {
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	}
+	discovery := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	if err := getJSON(ctx, discovery, "", &doc); err != nil {
+		return nil, fmt.Errorf("oauth: OIDC Discovery 失败: %w", err)
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "profile", "email"}
+	}
+	endpoint := Endpoint{
+		AuthURL:     doc.AuthorizationEndpoint,
+		TokenURL:    doc.TokenEndpoint,
+		UserInfoURL: doc.UserinfoEndpoint,
+	}
+	return NewProvider(name, cfg, endpoint, oidcMapper(name)), nil
+}
+
+// Google 创建 Google 登录提供方
+func Google(cfg Config) Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "profile", "email"}
+	}
+	return NewProvider("google", cfg, Endpoint{
+		AuthURL:     "https://accounts.google.com/o/oauth2/v2/auth",
+		TokenURL:    "https://oauth2.googleapis.com/token",
+		UserInfoURL: "https://openidconnect.googleapis.com/v1/userinfo",
+	}, oidcMapper("google"))
+}
+
+// GitHub 创建 GitHub 登录提供方
+func GitHub(cfg Config) Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"read:user", "user:email"}
+	}
+	return NewProvider("github", cfg, Endpoint{
+		AuthURL:     "https://github.com/login/oauth/authorize",
+		TokenURL:    "https://github.com/login/oauth/access_token",
+		UserInfoURL: "https://api.github.com/user",
+	}, func(raw map[string]interface{}) *UserProfile {
+		name := stringField(raw, "name")
+		if name == "" {
+			name = stringField(raw, "login")
+		}
+		return &UserProfile{
+			Provider: "github",
+			ID:       stringField(raw, "id"),
+			Name:     name,
+			Email:    stringField(raw, "email"),
+			Avatar:   stringField(raw, "avatar_url"),
+			Raw:      raw,
+		}
+	})
+}
+
+// oidcMapper 按 OIDC 标准声明映射用户信息
+func oidcMapper(name string) ProfileMapper {
+	return func(raw map[string]interface{}) *UserProfile {
+		return &UserProfile{
+			Provider: name,
+			ID:       stringField(raw, "sub"),
+			Name:     stringField(raw, "name"),
+			Email:    stringField(raw, "email"),
+			Avatar:   stringField(raw, "picture"),
+			Raw:      raw,
+		}
+	}
+}
+
+// Name 返回提供方名称
+func (p *standardProvider) Name() string {
+	return p.name
+}
+
+// AuthCodeURL 生成授权跳转地址
+func (p *standardProvider) AuthCodeURL(state, codeChallenge string) string {
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", p.config.ClientID)
+	v.Set("redirect_uri", p.config.RedirectURL)
+	v.Set("state", state)
+	if len(p.config.Scopes) > 0 {
+		v.Set("scope", strings.Join(p.config.Scopes, " "))
+	}
+	if codeChallenge != "" {
+		v.Set("code_challenge", codeChallenge)
+		v.Set("code_challenge_method", "S256")
+	}
+	return appendQuery(p.endpoint.AuthURL, v)
+}
+
+// Exchange 使用授权码换取令牌
+func (p *standardProvider) Exchange(ctx context.Context, code, codeVerifier string) (*Token, error) {
+	v := url.Values{}
+	v.Set("grant_type", "authorization_code")
+	v.Set("code", code)
+	v.Set("client_id", p.config.ClientID)
+	v.Set("client_secret", p.config.ClientSecret)
+	v.Set("redirect_uri", p.config.RedirectURL)
+	if codeVerifier != "" {
+		v.Set("code_verifier", codeVerifier)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint.TokenURL, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token Token
+	if err := doJSON(req, &token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("授权服务器未返回 access_token")
+	}
+	return &token, nil
+}
+
+// UserInfo 获取并归一化用户信息
+func (p *standardProvider) UserInfo(ctx context.Context, token *Token) (*UserProfile, error) {
+	raw := make(map[string]interface{})
+	if err := getJSON(ctx, p.endpoint.UserInfoURL, token.AccessToken, &raw); err != nil {
+		return nil, err
+	}
+	return p.mapper(raw), nil
+}
+
+// wechatProvider 是微信网站应用扫码登录提供方
+// 微信的参数命名与标准 OAuth2 不同，且不支持 PKCE
+type wechatProvider struct {
+	config Config
+}
+
+// WeChat 创建微信登录提供方
+func WeChat(cfg Config) Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"snsapi_login"}
+	}
+	return &wechatProvider{config: cfg}
+}
+
+// Name 返回提供方名称
+func (p *wechatProvider) Name() string {
+	return "wechat"
+}
+
+// AuthCodeURL 生成微信扫码授权地址
+func (p *wechatProvider) AuthCodeURL(state, codeChallenge string) string {
+	v := url.Values{}
+	v.Set("appid", p.config.ClientID)
+	v.Set("redirect_uri", p.config.RedirectURL)
+	v.Set("response_type", "code")
+	v.Set("scope", strings.Join(p.config.Scopes, ","))
+	v.Set("state", state)
+	return "https://open.weixin.qq.com/connect/qrconnect?" + v.Encode() + "#wechat_redirect"
+}
+
+// wechatError 是微信接口返回的错误
+type wechatError struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// Exchange 使用授权码换取令牌
+func (p *wechatProvider) Exchange(ctx context.Context, code, codeVerifier string) (*Token, error) {
+	v := url.Values{}
+	v.Set("appid", p.config.ClientID)
+	v.Set("secret", p.config.ClientSecret)
+	v.Set("code", code)
+	v.Set("grant_type", "authorization_code")
+
+	var resp struct {
+		Token
+		wechatError
+	}
+	if err := getJSON(ctx, "https://api.weixin.qq.com/sns/oauth2/access_token?"+v.Encode(), "", &resp); err != nil {
+		return nil, err
+	}
+	if resp.ErrCode != 0 {
+		return nil, fmt.Errorf("微信接口错误 %d: %s", resp.ErrCode, resp.ErrMsg)
+	}
+	return &resp.Token, nil
+}
+
+// UserInfo 获取并归一化微信用户信息
+func (p *wechatProvider) UserInfo(ctx context.Context, token *Token) (*UserProfile, error) {
+	v := url.Values{}
+	v.Set("access_token", token.AccessToken)
+	v.Set("openid", token.OpenID)
+
+	raw := make(map[string]interface{})
+	if err := getJSON(ctx, "https://api.weixin.qq.com/sns/userinfo?"+v.Encode(), "", &raw); err != nil {
+		return nil, err
+	}
+	if code := stringField(raw, "errcode"); code != "" && code != "0" {
+		return nil, fmt.Errorf("微信接口错误 %s: %s", code, stringField(raw, "errmsg"))
+	}
+
+	// 优先使用 unionid，便于同一开放平台下多应用共享用户
+	id := stringField(raw, "unionid")
+	if id == "" {
+		id = stringField(raw, "openid")
+	}
+	return &UserProfile{
+		Provider: "wechat",
+		ID:       id,
+		Name:     stringField(raw, "nickname"),
+		Avatar:   stringField(raw, "headimgurl"),
+		Raw:      raw,
+	}, nil
+}
+
+// getJSON 发送 GET 请求并解析 JSON 响应
+func getJSON(ctx context.Context, rawURL, accessToken string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	return doJSON(req, out)
+}
+
+// doJSON 执行请求并解析 JSON 响应
+func doJSON(req *http.Request, out interface{}) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("请求 %s 失败，状态码 %d: %s", req.URL.Host, resp.StatusCode, string(body))
+	}
+	return json.Unmarshal(body, out)
+}
+
+// appendQuery 在地址后追加查询参数
+func appendQuery(base string, v url.Values) string {
+	if strings.Contains(base, "?") {
+		return base + "&" + v.Encode()
+	}
+	return base + "?" + v.Encode()
+}
+
+// stringField 以字符串形式读取字段（数字ID会被格式化为整数）
+func stringField(raw map[string]interface{}, key string) string {
+	switch v := raw[key].(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
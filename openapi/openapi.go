@@ -0,0 +1,265 @@
+// Package openapi 提供了基于 OpenAPI 3 文档的请求与响应校验中间件
+// 适用于契约优先（contract-first）开发：请求不符合文档时返回 400 以及带 JSON Pointer 路径的错误明细，
+// 调试模式下还可校验响应，及早发现实现与文档不一致
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/xzl-go/easygo/core"
+	errs "github.com/xzl-go/easygo/errors"
+	"github.com/xzl-go/easygo/logger"
+)
+
+// ErrResponseMismatch 响应不符合文档，仅在调试模式下开启响应校验时返回
+var ErrResponseMismatch = errs.New(50010, "error.openapi.response", http.StatusInternalServerError, "Response does not match the API specification")
+
+// Violation 是一条校验错误
+type Violation struct {
+	Pointer string `json:"pointer"` // 出错位置，例如 /body/items/0/name、/query/limit
+	Message string `json:"message"`
+}
+
+// Options 定义了校验配置
+type Options struct {
+	// ValidateResponses 是否校验响应，仅在调试模式下生效，避免影响生产环境性能
+	ValidateResponses bool
+	// Authenticate 校验文档中声明的安全要求，默认跳过（认证由 JWT、API 密钥等中间件负责）
+	Authenticate openapi3filter.AuthenticationFunc
+	// SkipUnknownRoutes 文档中不存在的路由是否直接放行，默认 true
+	SkipUnknownRoutes *bool
+}
+
+// Validator 是 OpenAPI 校验器
+type Validator struct {
+	doc     *openapi3.T
+	router  routers.Router
+	options Options
+}
+
+// Load 从文件加载 OpenAPI 文档（JSON 或 YAML）并创建校验器
+// path: 文档路径
+// options: 校验配置
+func Load(path string, options Options) (*Validator, error) {
+	doc, err := openapi3.NewLoader().LoadFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(doc, options)
+}
+
+// LoadData 从内存数据加载 OpenAPI 文档并创建校验器，可配合 embed 使用
+// data: 文档内容（JSON 或 YAML）
+// options: 校验配置
+func LoadData(data []byte, options Options) (*Validator, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		return nil, err
+	}
+	return New(doc, options)
+}
+
+// New 根据已解析的文档创建校验器
+// 路由仅按路径匹配，忽略文档中 servers 声明的主机名
+// doc: OpenAPI 文档
+// options: 校验配置
+func New(doc *openapi3.T, options Options) (*Validator, error) {
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+	if options.Authenticate == nil {
+		options.Authenticate = openapi3filter.NoopAuthenticationFunc
+	}
+	if options.SkipUnknownRoutes == nil {
+		skip := true
+		options.SkipUnknownRoutes = &skip
+	}
+
+	routeDoc := *doc
+	routeDoc.Servers = nil
+	router, err := legacy.NewRouter(&routeDoc)
+	if err != nil {
+		return nil, err
+	}
+	return &Validator{doc: doc, router: router, options: options}, nil
+}
+
+// Middleware 返回校验中间件
+func (v *Validator) Middleware() core.HandlerFunc {
+	return func(c *core.Context) {
+		route, pathParams, err := v.router.FindRoute(c.Request)
+		if err != nil {
+			if *v.options.SkipUnknownRoutes {
+				c.Next()
+				return
+			}
+			c.Fail(errs.ErrNotFound)
+			c.Abort()
+			return
+		}
+
+		filterOptions := &openapi3filter.Options{
+			MultiError:         true,
+			AuthenticationFunc: v.options.Authenticate,
+		}
+		input := &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    filterOptions,
+		}
+		if err := openapi3filter.ValidateRequest(c.Request.Context(), input); err != nil {
+			v.reject(c, errs.ErrValidation, collect(err, ""))
+			c.Abort()
+			return
+		}
+
+		if !v.options.ValidateResponses || !core.IsDebugging() {
+			c.Next()
+			return
+		}
+
+		writer := &bufferedWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		respInput := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 writer.status,
+			Header:                 writer.Header(),
+			Options:                filterOptions,
+		}
+		respInput.SetBodyBytes(writer.body.Bytes())
+		if err := openapi3filter.ValidateResponse(c.Request.Context(), respInput); err != nil {
+			violations := collect(err, "/response")
+			logger.Error("响应不符合 OpenAPI 文档 %s %s：%v", c.Request.Method, c.Request.URL.Path, err)
+			v.reject(c, ErrResponseMismatch, violations)
+			return
+		}
+		writer.flush()
+	}
+}
+
+// reject 返回带校验明细的错误响应
+func (v *Validator) reject(c *core.Context, e *errs.Error, violations []Violation) {
+	c.JSON(e.Status, core.Response{
+		Code:    e.Code,
+		Message: e.Message,
+		Data:    violations,
+		TraceID: c.TraceID(),
+	})
+}
+
+// collect 将校验错误展开为带 JSON Pointer 的明细
+func collect(err error, prefix string) []Violation {
+	var violations []Violation
+	var walk func(err error, pointer string)
+	walk = func(err error, pointer string) {
+		switch e := err.(type) {
+		case openapi3.MultiError:
+			for _, inner := range e {
+				walk(inner, pointer)
+			}
+		case *openapi3filter.RequestError:
+			switch {
+			case e.Parameter != nil:
+				pointer = "/" + e.Parameter.In + "/" + escape(e.Parameter.Name)
+			case e.RequestBody != nil:
+				pointer = "/body"
+			}
+			if e.Err != nil {
+				walk(e.Err, pointer)
+				return
+			}
+			violations = append(violations, Violation{Pointer: pointer, Message: e.Error()})
+		case *openapi3filter.ResponseError:
+			if strings.Contains(e.Reason, "body") {
+				pointer += "/body"
+			}
+			if e.Err != nil {
+				walk(e.Err, pointer)
+				return
+			}
+			violations = append(violations, Violation{Pointer: pointer, Message: e.Error()})
+		case *openapi3.SchemaError:
+			for _, token := range e.JSONPointer() {
+				pointer += "/" + escape(token)
+			}
+			violations = append(violations, Violation{Pointer: pointer, Message: e.Reason})
+		case *openapi3filter.ParseError:
+			violations = append(violations, Violation{Pointer: pointer, Message: e.Error()})
+		default:
+			if inner := errors.Unwrap(err); inner != nil {
+				walk(inner, pointer)
+				return
+			}
+			violations = append(violations, Violation{Pointer: pointer, Message: err.Error()})
+		}
+	}
+	walk(err, prefix)
+	return violations
+}
+
+// escape 按 RFC 6901 转义 JSON Pointer 中的片段
+func escape(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// bufferedWriter 缓存响应，待校验通过后再写出
+type bufferedWriter struct {
+	core.ResponseWriter
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+// WriteHeader 记录状态码，只有第一次调用生效
+func (w *bufferedWriter) WriteHeader(code int) {
+	if w.written {
+		return
+	}
+	w.status = code
+	w.written = true
+}
+
+// Write 缓存响应体
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.body.Write(b)
+}
+
+// Status 返回缓存的状态码
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+// Size 返回缓存的响应体字节数
+func (w *bufferedWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+// Written 返回是否已写入响应
+func (w *bufferedWriter) Written() bool {
+	return w.written
+}
+
+// Flush 缓存期间不向客户端发送数据
+func (w *bufferedWriter) Flush() {}
+
+// flush 将缓存的响应写出
+func (w *bufferedWriter) flush() {
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.Write(w.body.Bytes())
+}
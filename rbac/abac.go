@@ -0,0 +1,19 @@
+package rbac
+
+// Attributes 是主体或资源的属性，匹配器中通过 r.sub.Attrs.dept、r.obj.Attrs.level 访问
+type Attributes map[string]interface{}
+
+// Subject 是基于属性的访问控制（ABAC）中的主体，作为 Enforce 的 sub 参数
+// 匹配器中使用 r.sub.Name 进行角色判断，例如 g(r.sub.Name, p.sub)
+type Subject struct {
+	Name  string     // 用户名
+	Attrs Attributes // 属性，例如部门、级别
+}
+
+// Resource 是基于属性的访问控制中的资源，作为 Enforce 的 obj 参数
+// 资源所有者检查的匹配器示例：r.sub.Name == r.obj.Owner
+type Resource struct {
+	Name  string     // 资源名称，例如请求路径或资源类型
+	Owner string     // 资源所有者
+	Attrs Attributes // 属性，例如所属部门、密级
+}
@@ -0,0 +1,212 @@
+package rbac
+
+import (
+	"net/http"
+
+	"github.com/xzl-go/easygo/core"
+	errs "github.com/xzl-go/easygo/errors"
+)
+
+var (
+	// ErrPolicyExists 策略或角色分配已存在
+	ErrPolicyExists = errs.New(40930, "error.rbac.policy_exists", http.StatusConflict, "Policy already exists")
+	// ErrPolicyNotFound 策略或角色分配不存在
+	ErrPolicyNotFound = errs.New(40430, "error.rbac.policy_not_found", http.StatusNotFound, "Policy not found")
+)
+
+// AdminOptions 定义了权限管理接口的选项
+type AdminOptions struct {
+	// Subject 从请求中提取操作者，默认读取上下文键 current_user（由 JWT 或 API Key 中间件写入）
+	Subject func(c *core.Context) string
+	// Object 管理接口自身的权限对象，默认 "rbac"
+	// 查询接口需要 (sub, Object, "read") 权限，修改接口需要 (sub, Object, "write") 权限
+	Object string
+}
+
+// PolicyRequest 是添加或删除策略的请求体
+type PolicyRequest struct {
+	PType string   `json:"ptype"`                          // 策略类型，默认 "p"
+	Rule  []string `json:"rule" validate:"required,min=1"` // 策略规则，例如 ["admin", "/api/*", "GET"]
+}
+
+// RoleRequest 是为用户分配角色的请求体
+type RoleRequest struct {
+	Role string `json:"role" validate:"required"`
+}
+
+// AdminRoutes 注册权限管理接口，运维人员无需重新部署即可调整权限，接口自身受 options.Object 权限保护：
+//
+//	GET    /policies                  列出策略，?ptype= 指定策略类型
+//	POST   /policies                  添加策略
+//	DELETE /policies                  删除策略
+//	POST   /policies/reload           从存储重新加载策略
+//	POST   /policies/save             将策略保存到存储（适配器不支持自动保存时使用，例如文件适配器）
+//	GET    /roles                     列出角色
+//	DELETE /roles/:role               删除角色及其分配和策略
+//	GET    /roles/:role/users         列出拥有角色的用户
+//	GET    /users/:user/roles         列出用户的角色
+//	POST   /users/:user/roles         为用户分配角色
+//	DELETE /users/:user/roles/:role   撤销用户的角色
+//	GET    /users/:user/permissions   列出用户的全部权限（包括通过角色继承的权限）
+//
+// group: 路由组，例如 app.Group("/admin/rbac")
+// options: 接口选项
+func (r *RBACManager) AdminRoutes(group *core.RouterGroup, options AdminOptions) {
+	if options.Subject == nil {
+		options.Subject = func(c *core.Context) string {
+			user, _ := c.Get("current_user").(string)
+			return user
+		}
+	}
+	if options.Object == "" {
+		options.Object = "rbac"
+	}
+	read, write := r.adminGuard(options, "read"), r.adminGuard(options, "write")
+
+	group.GET("/policies", read, func(c *core.Context) {
+		ptype := c.Query("ptype")
+		if ptype == "" {
+			ptype = "p"
+		}
+		policies, err := r.enforcer.GetNamedPolicy(ptype)
+		if err != nil {
+			c.Fail(errs.ErrBadRequest.Wrap(err))
+			return
+		}
+		c.Success(policies)
+	})
+	group.POST("/policies", write, func(c *core.Context) {
+		var req PolicyRequest
+		if err := c.BindAndValidate(&req); err != nil {
+			return
+		}
+		added(c, req.Rule)(r.AddPolicy("p", req.ptype(), req.Rule))
+	})
+	group.DELETE("/policies", write, func(c *core.Context) {
+		var req PolicyRequest
+		if err := c.BindAndValidate(&req); err != nil {
+			return
+		}
+		removed(c, req.Rule)(r.RemovePolicy("p", req.ptype(), req.Rule))
+	})
+	group.POST("/policies/reload", write, func(c *core.Context) {
+		if err := r.LoadPolicy(); err != nil {
+			c.Fail(errs.ErrInternal.Wrap(err))
+			return
+		}
+		c.Success(nil)
+	})
+	group.POST("/policies/save", write, func(c *core.Context) {
+		if err := r.SavePolicy(); err != nil {
+			c.Fail(errs.ErrInternal.Wrap(err))
+			return
+		}
+		c.Success(nil)
+	})
+
+	group.GET("/roles", read, func(c *core.Context) {
+		roles, err := r.enforcer.GetAllRoles()
+		list(c, roles, err)
+	})
+	group.DELETE("/roles/:role", write, func(c *core.Context) {
+		removed(c, c.Param("role"))(r.enforcer.DeleteRole(c.Param("role")))
+	})
+	group.GET("/roles/:role/users", read, func(c *core.Context) {
+		users, err := r.enforcer.GetUsersForRole(c.Param("role"))
+		list(c, users, err)
+	})
+
+	group.GET("/users/:user/roles", read, func(c *core.Context) {
+		roles, err := r.GetRolesForUser(c.Param("user"))
+		list(c, roles, err)
+	})
+	group.POST("/users/:user/roles", write, func(c *core.Context) {
+		var req RoleRequest
+		if err := c.BindAndValidate(&req); err != nil {
+			return
+		}
+		assignment := []string{c.Param("user"), req.Role}
+		added(c, assignment)(r.AddRoleForUser(c.Param("user"), req.Role))
+	})
+	group.DELETE("/users/:user/roles/:role", write, func(c *core.Context) {
+		assignment := []string{c.Param("user"), c.Param("role")}
+		removed(c, assignment)(r.DeleteRoleForUser(c.Param("user"), c.Param("role")))
+	})
+	group.GET("/users/:user/permissions", read, func(c *core.Context) {
+		permissions, err := r.enforcer.GetImplicitPermissionsForUser(c.Param("user"))
+		list(c, permissions, err)
+	})
+}
+
+// adminGuard 返回检查管理接口权限的中间件
+func (r *RBACManager) adminGuard(options AdminOptions, action string) core.HandlerFunc {
+	return func(c *core.Context) {
+		sub := options.Subject(c)
+		if sub == "" {
+			c.Fail(errs.ErrUnauthorized)
+			c.Abort()
+			return
+		}
+		allowed, err := r.Enforce(sub, options.Object, action)
+		if err != nil {
+			c.Logger().Error("[RBAC] 权限检查失败：%v", err)
+			c.Fail(errs.ErrInternal.Wrap(err))
+			c.Abort()
+			return
+		}
+		if !allowed {
+			c.Fail(errs.ErrForbidden)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// ptype 返回请求的策略类型，默认 "p"
+func (req *PolicyRequest) ptype() string {
+	if req.PType == "" {
+		return "p"
+	}
+	return req.PType
+}
+
+// added 返回写出添加结果的函数，未添加（已存在）时返回 409
+func added(c *core.Context, data interface{}) func(ok bool, err error) {
+	return func(ok bool, err error) {
+		switch {
+		case err != nil:
+			c.Fail(errs.ErrBadRequest.Wrap(err))
+		case !ok:
+			c.Fail(ErrPolicyExists)
+		default:
+			c.Success(data)
+		}
+	}
+}
+
+// removed 返回写出删除结果的函数，未删除（不存在）时返回 404
+func removed(c *core.Context, data interface{}) func(ok bool, err error) {
+	return func(ok bool, err error) {
+		switch {
+		case err != nil:
+			c.Fail(errs.ErrBadRequest.Wrap(err))
+		case !ok:
+			c.Fail(ErrPolicyNotFound)
+		default:
+			c.Success(data)
+		}
+	}
+}
+
+// list 写出查询结果
+func list[T any](c *core.Context, items []T, err error) {
+	if err != nil {
+		c.Fail(errs.ErrInternal.Wrap(err))
+		return
+	}
+	if items == nil {
+		items = []T{}
+	}
+	c.Success(items)
+}
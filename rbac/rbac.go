@@ -23,6 +23,7 @@ import (
 // 负责权限策略的管理和执行
 type RBACManager struct {
 	enforcer *casbin.Enforcer // Casbin执行器
+	watcher  persist.Watcher  // 策略变更监听器，未设置时为 nil
 }
 
 // NewRBACManager 创建一个新的RBAC权限管理器 (从文件加载模型和策略)
@@ -118,29 +119,42 @@ func NewRBACManagerWithAdapter(m model.Model, adapter persist.Adapter) (*RBACMan
 }
 
 // AddPolicy 添加权限策略
-// sec: 策略类型
-// ptype: 策略类型
+// sec: 策略所在的段，"p" 为权限策略，"g" 为角色继承
+// ptype: 策略类型，例如 "p"、"g"
 // rule: 策略规则
-// 返回操作结果和可能的错误
+// 返回操作结果（策略已存在时为 false）和可能的错误
 func (r *RBACManager) AddPolicy(sec, ptype string, rule []string) (bool, error) {
-	return r.enforcer.AddPolicy(sec, ptype, rule)
+	// Casbin 在策略已存在时同样返回 true，先检查是否存在
+	if sec == "g" {
+		if exists, err := r.enforcer.HasNamedGroupingPolicy(ptype, rule); exists || err != nil {
+			return false, err
+		}
+		return r.enforcer.AddNamedGroupingPolicy(ptype, rule)
+	}
+	if exists, err := r.enforcer.HasNamedPolicy(ptype, rule); exists || err != nil {
+		return false, err
+	}
+	return r.enforcer.AddNamedPolicy(ptype, rule)
 }
 
 // RemovePolicy 删除权限策略
-// sec: 策略类型
-// ptype: 策略类型
+// sec: 策略所在的段，"p" 为权限策略，"g" 为角色继承
+// ptype: 策略类型，例如 "p"、"g"
 // rule: 策略规则
-// 返回操作结果和可能的错误
+// 返回操作结果（策略不存在时为 false）和可能的错误
 func (r *RBACManager) RemovePolicy(sec, ptype string, rule []string) (bool, error) {
-	return r.enforcer.RemovePolicy(sec, ptype, rule)
+	if sec == "g" {
+		return r.enforcer.RemoveNamedGroupingPolicy(ptype, rule)
+	}
+	return r.enforcer.RemoveNamedPolicy(ptype, rule)
 }
 
 // AddRoleForUser 为用户添加角色
 // user: 用户名
 // role: 角色名
-// 返回操作结果和可能的错误
+// 返回操作结果（用户已拥有该角色时为 false）和可能的错误
 func (r *RBACManager) AddRoleForUser(user, role string) (bool, error) {
-	return r.enforcer.AddRoleForUser(user, role)
+	return r.AddPolicy("g", "g", []string{user, role})
 }
 
 // DeleteRoleForUser 删除用户的角色
@@ -152,14 +166,23 @@ func (r *RBACManager) DeleteRoleForUser(user, role string) (bool, error) {
 }
 
 // Enforce 执行权限检查
+// 主体和对象可以是字符串，也可以是结构体或 map，匹配器通过属性访问，例如 r.sub.Name == r.obj.Owner，参见 Subject 和 Resource
 // sub: 主体（用户）
 // obj: 对象（资源）
 // act: 操作（动作）
 // 返回是否允许访问和可能的错误
-func (r *RBACManager) Enforce(sub, obj, act string) (bool, error) {
+func (r *RBACManager) Enforce(sub, obj, act interface{}) (bool, error) {
 	return r.enforcer.Enforce(sub, obj, act)
 }
 
+// EnforceWithMatcher 使用指定的匹配器执行权限检查，替代模型中的匹配器，适合只在个别场景使用的属性规则
+// matcher: 匹配器表达式，例如 "r.sub.Name == r.obj.Owner && r.act == 'edit'"
+// rvals: 请求参数，顺序与模型的请求定义一致
+// 返回是否允许访问和可能的错误
+func (r *RBACManager) EnforceWithMatcher(matcher string, rvals ...interface{}) (bool, error) {
+	return r.enforcer.EnforceWithMatcher(matcher, rvals...)
+}
+
 // GetRolesForUser 获取用户的所有角色
 // user: 用户名
 // 返回角色列表和可能的错误
@@ -174,6 +197,54 @@ func (r *RBACManager) GetPermissionsForUser(user string) ([][]string, error) {
 	return r.enforcer.GetPermissionsForUser(user)
 }
 
+// AddRoleForUserInDomain 在域（租户）内为用户添加角色，模型需要带域的角色定义 g = _, _, _
+// user: 用户名
+// role: 角色名
+// domain: 域，例如租户ID
+// 返回操作结果（用户在该域内已拥有该角色时为 false）和可能的错误
+func (r *RBACManager) AddRoleForUserInDomain(user, role, domain string) (bool, error) {
+	return r.AddPolicy("g", "g", []string{user, role, domain})
+}
+
+// DeleteRoleForUserInDomain 删除用户在域内的角色
+// user: 用户名
+// role: 角色名
+// domain: 域
+// 返回操作结果和可能的错误
+func (r *RBACManager) DeleteRoleForUserInDomain(user, role, domain string) (bool, error) {
+	return r.enforcer.DeleteRoleForUserInDomain(user, role, domain)
+}
+
+// GetRolesForUserInDomain 获取用户在域内的角色
+// user: 用户名
+// domain: 域
+func (r *RBACManager) GetRolesForUserInDomain(user, domain string) []string {
+	return r.enforcer.GetRolesForUserInDomain(user, domain)
+}
+
+// GetPermissionsForUserInDomain 获取用户在域内直接拥有的权限
+// user: 用户名
+// domain: 域
+func (r *RBACManager) GetPermissionsForUserInDomain(user, domain string) [][]string {
+	return r.enforcer.GetPermissionsForUserInDomain(user, domain)
+}
+
+// GetDomains 获取所有域
+// 返回域列表和可能的错误
+func (r *RBACManager) GetDomains() ([]string, error) {
+	return r.enforcer.GetAllDomains()
+}
+
+// EnforceWithDomain 在域内执行权限检查，模型的请求定义需要为 r = sub, dom, obj, act
+// sub: 主体（用户）
+// domain: 域，例如租户ID
+// obj: 对象（资源）
+// act: 操作（动作）
+// 返回是否允许访问和可能的错误
+func (r *RBACManager) EnforceWithDomain(sub, domain, obj, act string) (bool, error) {
+	return r.enforcer.Enforce(sub, domain, obj, act)
+}
+
 // LoadPolicy 从存储加载权限策略
 // 返回可能的错误
 func (r *RBACManager) LoadPolicy() error {
@@ -0,0 +1,133 @@
+package rbac
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/casbin/casbin/v2/persist"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/xzl-go/easygo/id"
+	"github.com/xzl-go/easygo/logger"
+	"github.com/xzl-go/easygo/redis"
+)
+
+// SetWatcher 设置策略变更监听器，多实例部署时一个实例修改策略后通知其他实例重新加载
+// 可以使用 NewRedisWatcher，也可以使用实现了 persist.Watcher 的第三方监听器（如 etcd）；
+// 各实例需共享同一个策略存储（如数据库适配器），文件适配器的策略不会在实例之间同步
+// watcher: 策略变更监听器
+// 返回可能的错误
+func (r *RBACManager) SetWatcher(watcher persist.Watcher) error {
+	if err := r.enforcer.SetWatcher(watcher); err != nil {
+		return err
+	}
+	r.watcher = watcher
+	if _, ok := watcher.(persist.WatcherEx); ok {
+		return nil
+	}
+	return watcher.SetUpdateCallback(func(string) {
+		if err := r.LoadPolicy(); err != nil {
+			logger.Module("rbac").Error("[RBAC] 重新加载策略失败：%v", err)
+		}
+	})
+}
+
+// EnableAutoSave 设置修改策略时是否自动保存到存储，默认开启
+// 关闭后修改只在内存中生效，需调用 SavePolicy 保存，适合批量修改
+// autoSave: 是否自动保存
+func (r *RBACManager) EnableAutoSave(autoSave bool) {
+	r.enforcer.EnableAutoSave(autoSave)
+}
+
+// EnableAutoNotifyWatcher 设置修改策略时是否自动通知监听器，默认开启
+// 关闭后需在修改完成后调用 NotifyWatcher，避免批量修改时其他实例多次重新加载
+// notify: 是否自动通知
+func (r *RBACManager) EnableAutoNotifyWatcher(notify bool) {
+	r.enforcer.EnableAutoNotifyWatcher(notify)
+}
+
+// NotifyWatcher 通知其他实例重新加载策略，未设置监听器时不做任何事
+// 返回可能的错误
+func (r *RBACManager) NotifyWatcher() error {
+	if r.watcher != nil {
+		return r.watcher.Update()
+	}
+	return nil
+}
+
+// RedisWatcher 是基于 Redis pub/sub 的策略变更监听器，实现 persist.Watcher
+type RedisWatcher struct {
+	client  goredis.UniversalClient
+	channel string
+	node    string // 实例ID，用于忽略自身发布的通知
+	cancel  context.CancelFunc
+
+	mu       sync.Mutex
+	callback func(string)
+}
+
+// NewRedisWatcher 创建基于 Redis pub/sub 的策略变更监听器并开始订阅，订阅失败时每秒重试
+// client: Redis 客户端，全部实例需连接同一个 Redis
+// channel: 频道名称，同一服务的实例使用相同的频道，默认 "easygo:rbac"
+func NewRedisWatcher(client goredis.UniversalClient, channel string) *RedisWatcher {
+	if channel == "" {
+		channel = "easygo:rbac"
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &RedisWatcher{client: client, channel: channel, cancel: cancel}
+	w.node, _ = id.NewULID()
+	go w.subscribe(ctx)
+	return w
+}
+
+// SetUpdateCallback 设置收到其他实例的变更通知时调用的函数
+func (w *RedisWatcher) SetUpdateCallback(callback func(string)) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.callback = callback
+	return nil
+}
+
+// Update 通知其他实例策略已变更
+func (w *RedisWatcher) Update() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return w.client.Publish(ctx, w.channel, w.node).Err()
+}
+
+// Close 停止订阅
+func (w *RedisWatcher) Close() {
+	w.cancel()
+}
+
+// subscribe 订阅变更通知，直到 ctx 结束
+func (w *RedisWatcher) subscribe(ctx context.Context) {
+	for {
+		err := redis.Subscribe(ctx, w.client, func(_ string, payload []byte) {
+			w.receive(string(payload))
+		}, w.channel)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Module("rbac").Warn("[RBAC] 订阅策略变更失败，1 秒后重试：%v", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// receive 处理一条变更通知，忽略自身发布的通知
+func (w *RedisWatcher) receive(node string) {
+	if node == w.node {
+		return
+	}
+	w.mu.Lock()
+	callback := w.callback
+	w.mu.Unlock()
+	if callback != nil {
+		callback(node)
+	}
+}
@@ -0,0 +1,31 @@
+package redis
+
+import (
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/xzl-go/easygo/core"
+)
+
+// ContextKey 是连接管理器在上下文中的键
+const ContextKey = "redis"
+
+// Inject 返回将连接管理器注入上下文的中间件，处理函数通过 redis.From(c) 获取客户端
+func Inject(m *Manager) core.HandlerFunc {
+	return func(c *core.Context) {
+		c.Set(ContextKey, m)
+		c.Next()
+	}
+}
+
+// From 返回上下文中的客户端，未使用 Inject 中间件或连接不存在时返回 nil
+// name: 连接名称，省略时返回默认连接
+func From(c *core.Context, name ...string) goredis.UniversalClient {
+	m, ok := c.Get(ContextKey).(*Manager)
+	if !ok {
+		return nil
+	}
+	if len(name) > 0 {
+		return m.Client(name[0])
+	}
+	return m.Default()
+}
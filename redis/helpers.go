@@ -0,0 +1,135 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Cache 是基于 Redis 的缓存，实现了 cache.Cache，可用于会话、验证码等需要多实例共享的场景
+type Cache struct {
+	client goredis.UniversalClient
+	prefix string
+}
+
+// NewCache 创建基于 Redis 的缓存
+// client: Redis 客户端
+// prefix: 键前缀，例如 "cache:"
+func NewCache(client goredis.UniversalClient, prefix string) *Cache {
+	return &Cache{client: client, prefix: prefix}
+}
+
+// Get 获取缓存值，不存在或出错时返回 false
+func (c *Cache) Get(key string) (string, bool) {
+	v, err := c.client.Get(context.Background(), c.prefix+key).Result()
+	if err != nil {
+		return "", false
+	}
+	return v, true
+}
+
+// Set 设置缓存值，ttl 为 0 表示永不过期
+func (c *Cache) Set(key, value string, ttl time.Duration) error {
+	return c.client.Set(context.Background(), c.prefix+key, value, ttl).Err()
+}
+
+// SetNX 键不存在时设置缓存值，返回是否写入，实现了 cache.Adder
+func (c *Cache) SetNX(key, value string, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(context.Background(), c.prefix+key, value, ttl).Result()
+}
+
+// Take 使用 GETDEL 取出并删除缓存值，不存在或出错时返回 false，实现了 cache.Taker
+func (c *Cache) Take(key string) (string, bool) {
+	v, err := c.client.GetDel(context.Background(), c.prefix+key).Result()
+	if err != nil {
+		return "", false
+	}
+	return v, true
+}
+
+// Delete 删除缓存值
+func (c *Cache) Delete(key string) error {
+	return c.client.Del(context.Background(), c.prefix+key).Err()
+}
+
+// allowScript 固定窗口计数，首次计数时设置窗口过期时间，返回当前计数和窗口剩余毫秒数
+var allowScript = goredis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return {count, redis.call("PTTL", KEYS[1])}`)
+
+// RateResult 是限流检查的结果
+type RateResult struct {
+	Allowed   bool          // 是否允许本次请求
+	Remaining int64         // 窗口内剩余次数
+	ResetIn   time.Duration // 距离窗口重置的时间
+}
+
+// Allow 按固定窗口计数限流，多个实例共享同一个计数
+// key: 限流键，例如 "ratelimit:ip:1.2.3.4"
+// limit: 窗口内允许的次数
+// window: 窗口长度
+func Allow(ctx context.Context, client goredis.UniversalClient, key string, limit int64, window time.Duration) (RateResult, error) {
+	values, err := allowScript.Run(ctx, client, []string{key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return RateResult{}, err
+	}
+	count, ttl := values[0], values[1]
+	result := RateResult{
+		Allowed:   count <= limit,
+		Remaining: max(limit-count, 0),
+		ResetIn:   time.Duration(ttl) * time.Millisecond,
+	}
+	return result, nil
+}
+
+// Publish 将消息编码为 JSON 后发布到频道
+// channel: 频道名称
+// message: 消息，string 和 []byte 原样发布
+func Publish(ctx context.Context, client goredis.UniversalClient, channel string, message interface{}) error {
+	var payload interface{}
+	switch m := message.(type) {
+	case string, []byte:
+		payload = m
+	default:
+		b, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		payload = b
+	}
+	return client.Publish(ctx, channel, payload).Err()
+}
+
+// Subscribe 订阅频道，每收到一条消息调用一次 handler，直到 ctx 结束
+// 连接断开时 go-redis 会自动重连并重新订阅
+// channels: 频道名称
+// handler: 消息处理函数
+func Subscribe(ctx context.Context, client goredis.UniversalClient, handler func(channel string, payload []byte), channels ...string) error {
+	if len(channels) == 0 {
+		return errors.New("redis: 至少需要订阅一个频道")
+	}
+	pubsub := client.Subscribe(ctx, channels...)
+	defer pubsub.Close()
+	// 等待订阅确认，确保返回前订阅已生效
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			handler(msg.Channel, []byte(msg.Payload))
+		}
+	}
+}
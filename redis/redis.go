@@ -0,0 +1,162 @@
+// Package redis 提供了 Redis 连接管理
+// 根据配置创建单节点、哨兵或集群客户端，支持多个命名连接、健康检查，
+// 并提供缓存、限流、发布订阅等供其他模块使用的辅助方法
+package redis
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// DefaultName 是默认连接的名称
+const DefaultName = "default"
+
+// Config 定义了 Redis 连接配置
+type Config struct {
+	// Addrs 节点地址，单节点部署时只有一个地址；多个地址且未设置 MasterName 时为集群
+	Addrs []string `json:"addrs" yaml:"addrs"`
+	// MasterName 哨兵模式的主节点名称，此时 Addrs 为哨兵地址
+	MasterName string `json:"master_name" yaml:"master_name"`
+	Username   string `json:"username" yaml:"username"`
+	Password   string `json:"password" yaml:"password"`
+	// DB 数据库编号，集群模式下无效
+	DB int `json:"db" yaml:"db"`
+	// PoolSize 连接池大小，默认为 CPU 数的 10 倍
+	PoolSize int `json:"pool_size" yaml:"pool_size"`
+	// MinIdleConns 最小空闲连接数
+	MinIdleConns int           `json:"min_idle_conns" yaml:"min_idle_conns"`
+	DialTimeout  time.Duration `json:"dial_timeout" yaml:"dial_timeout"`
+	ReadTimeout  time.Duration `json:"read_timeout" yaml:"read_timeout"`
+	WriteTimeout time.Duration `json:"write_timeout" yaml:"write_timeout"`
+	// TLS 是否使用 TLS 连接
+	TLS bool `json:"tls" yaml:"tls"`
+}
+
+// NewClient 根据配置创建 Redis 客户端
+func NewClient(config Config) (goredis.UniversalClient, error) {
+	if len(config.Addrs) == 0 {
+		return nil, errors.New("redis: 未配置节点地址")
+	}
+	options := &goredis.UniversalOptions{
+		Addrs:        config.Addrs,
+		MasterName:   config.MasterName,
+		Username:     config.Username,
+		Password:     config.Password,
+		DB:           config.DB,
+		PoolSize:     config.PoolSize,
+		MinIdleConns: config.MinIdleConns,
+		DialTimeout:  config.DialTimeout,
+		ReadTimeout:  config.ReadTimeout,
+		WriteTimeout: config.WriteTimeout,
+	}
+	if config.TLS {
+		options.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return goredis.NewUniversalClient(options), nil
+}
+
+// Manager 管理多个命名的 Redis 连接
+// 实现了 app.Module，启动时检查全部连接，停止时关闭全部连接
+type Manager struct {
+	mu      sync.RWMutex
+	clients map[string]goredis.UniversalClient
+}
+
+// NewManager 根据配置创建连接管理器
+// configs: 连接名称到配置的映射，默认连接的名称为 "default"
+func NewManager(configs map[string]Config) (*Manager, error) {
+	m := &Manager{clients: make(map[string]goredis.UniversalClient)}
+	for name, config := range configs {
+		client, err := NewClient(config)
+		if err != nil {
+			m.Close()
+			return nil, fmt.Errorf("redis: 创建连接 %s 失败: %w", name, err)
+		}
+		m.clients[name] = client
+	}
+	return m, nil
+}
+
+// Add 添加已创建的客户端，例如测试中使用的 miniredis 客户端
+func (m *Manager) Add(name string, client goredis.UniversalClient) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clients[name] = client
+}
+
+// Client 返回指定名称的客户端，不存在时返回 nil
+func (m *Manager) Client(name string) goredis.UniversalClient {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.clients[name]
+}
+
+// Default 返回默认客户端
+func (m *Manager) Default() goredis.UniversalClient {
+	return m.Client(DefaultName)
+}
+
+// Ping 检查全部连接，返回第一个失败连接的错误，可用于健康检查
+func (m *Manager) Ping(ctx context.Context) error {
+	m.mu.RLock()
+	names := make([]string, 0, len(m.clients))
+	for name := range m.clients {
+		names = append(names, name)
+	}
+	m.mu.RUnlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := m.Client(name).Ping(ctx).Err(); err != nil {
+			return fmt.Errorf("redis: 连接 %s 不可用: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// PoolStats 返回各连接的连接池统计，可通过 expvar 或指标系统导出
+func (m *Manager) PoolStats() map[string]*goredis.PoolStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	stats := make(map[string]*goredis.PoolStats, len(m.clients))
+	for name, client := range m.clients {
+		stats[name] = client.PoolStats()
+	}
+	return stats
+}
+
+// Close 关闭全部连接
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var errs []error
+	for name, client := range m.clients {
+		if err := client.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("redis: 关闭连接 %s 失败: %w", name, err))
+		}
+	}
+	m.clients = make(map[string]goredis.UniversalClient)
+	return errors.Join(errs...)
+}
+
+// Name 返回模块名称
+func (m *Manager) Name() string {
+	return "redis"
+}
+
+// Start 检查全部连接是否可用
+func (m *Manager) Start(ctx context.Context) error {
+	return m.Ping(ctx)
+}
+
+// Stop 关闭全部连接
+func (m *Manager) Stop(context.Context) error {
+	return m.Close()
+}
@@ -0,0 +1,157 @@
+package render
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/xzl-go/easygo/core"
+)
+
+// HTMLOptions 定义了 html/template 渲染器的配置
+type HTMLOptions struct {
+	Dir           string // 模板根目录，FS 为空时从该目录读取
+	FS            fs.FS  // 模板文件系统，支持 embed.FS
+	Extension     string // 模板文件扩展名，默认 ".html"
+	LayoutDir     string // 布局目录（相对模板根目录），默认 "layouts"
+	PartialDir    string // 局部模板目录（相对模板根目录），默认 "partials"
+	DefaultLayout string // 默认布局名称，默认 "base"，对应 layouts/base.html
+}
+
+// HTMLRender 是基于 html/template 的渲染器
+//
+// 目录约定：
+//   - layouts/ 下为布局模板，布局中通过 {{template "content" .}} 引用页面内容
+//   - partials/ 下为局部模板，通过 {{template "partials/header" .}} 引用
+//   - 其余文件为页面模板，以去掉扩展名的相对路径命名，例如 "users/index"
+//
+// 页面中定义了 {{define "content"}} 时使用默认布局渲染，否则直接渲染页面本身。
+type HTMLRender struct {
+	base
+	options HTMLOptions
+	pages   map[string]*template.Template
+}
+
+// NewHTML 创建 html/template 渲染器
+// options: 渲染器配置
+func NewHTML(options HTMLOptions) *HTMLRender {
+	if options.Extension == "" {
+		options.Extension = ".html"
+	}
+	if options.LayoutDir == "" {
+		options.LayoutDir = "layouts"
+	}
+	if options.PartialDir == "" {
+		options.PartialDir = "partials"
+	}
+	if options.DefaultLayout == "" {
+		options.DefaultLayout = "base"
+	}
+	r := &HTMLRender{
+		base:    newBase(),
+		options: options,
+		pages:   make(map[string]*template.Template),
+	}
+	r.AddFunc("safe", func(s string) template.HTML { return template.HTML(s) })
+	return r
+}
+
+// Load 加载（或重新加载）全部模板
+func (r *HTMLRender) Load() error {
+	fsys := r.options.FS
+	if fsys == nil {
+		fsys = os.DirFS(r.options.Dir)
+	}
+
+	shared := make(map[string]string) // 布局和局部模板
+	pages := make(map[string]string)
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(p, r.options.Extension) {
+			return nil
+		}
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(p, r.options.Extension)
+		if isUnder(p, r.options.LayoutDir) || isUnder(p, r.options.PartialDir) {
+			shared[name] = string(content)
+		} else {
+			pages[name] = string(content)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("render: 读取模板失败: %w", err)
+	}
+
+	funcs := template.FuncMap(r.funcMap())
+	compiled := make(map[string]*template.Template, len(pages))
+	for name, content := range pages {
+		t, err := template.New(name).Funcs(funcs).Parse(content)
+		if err != nil {
+			return fmt.Errorf("render: 解析模板 %s 失败: %w", name, err)
+		}
+		for sharedName, sharedContent := range shared {
+			if _, err := t.New(sharedName).Parse(sharedContent); err != nil {
+				return fmt.Errorf("render: 解析模板 %s 失败: %w", sharedName, err)
+			}
+		}
+		compiled[name] = t
+	}
+
+	r.mu.Lock()
+	r.pages = compiled
+	r.mu.Unlock()
+	return nil
+}
+
+// Render 实现 core.Renderer 接口
+// 调试模式下每次渲染前重新加载模板，发布模式下使用已编译的模板
+// name: 页面名称，例如 "users/index" 或 "users/index.html"
+func (r *HTMLRender) Render(w http.ResponseWriter, name string, data interface{}) error {
+	if core.IsDebugging() {
+		if err := r.Load(); err != nil {
+			return err
+		}
+	}
+	return r.RenderLayout(w, r.options.DefaultLayout, name, data)
+}
+
+// RenderLayout 使用指定布局渲染页面
+// layout: 布局名称，为空时不使用布局
+// name: 页面名称
+func (r *HTMLRender) RenderLayout(w io.Writer, layout, name string, data interface{}) error {
+	name = strings.TrimSuffix(name, r.options.Extension)
+	r.mu.RLock()
+	t, ok := r.pages[name]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("render: 模板 %s 不存在", name)
+	}
+
+	if rw, ok := w.(http.ResponseWriter); ok && rw.Header().Get("Content-Type") == "" {
+		rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	}
+
+	if layout != "" && t.Lookup("content") != nil {
+		layoutName := path.Join(r.options.LayoutDir, layout)
+		if t.Lookup(layoutName) != nil {
+			return t.ExecuteTemplate(w, layoutName, data)
+		}
+	}
+	return t.ExecuteTemplate(w, name, data)
+}
+
+// isUnder 判断路径是否位于指定目录下
+func isUnder(p, dir string) bool {
+	return dir != "" && strings.HasPrefix(p, strings.TrimSuffix(dir, "/")+"/")
+}
@@ -0,0 +1,122 @@
+package render
+
+import (
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/CloudyKit/jet/v6"
+	"github.com/xzl-go/easygo/core"
+)
+
+// JetOptions 定义了 Jet 渲染器的配置
+type JetOptions struct {
+	Dir         string // 模板根目录，FS 为空时从该目录读取
+	FS          fs.FS  // 模板文件系统，支持 embed.FS
+	Extension   string // 模板文件扩展名，默认 ".jet"
+	Development bool   // 开发模式，每次渲染都重新读取模板；引擎处于调试模式时自动开启
+}
+
+// JetRender 是基于 Jet 的渲染器
+// Jet 原生支持 {{extends "layouts/base.jet"}} 布局继承和 {{include}} / {{import}} 局部模板。
+// 注入的上下文值（csrf_token、current_user、T 等）以模板变量形式提供，例如 {{ csrf_token }}。
+type JetRender struct {
+	base
+	options JetOptions
+	set     *jet.Set
+}
+
+// NewJet 创建 Jet 渲染器
+// options: 渲染器配置
+func NewJet(options JetOptions) *JetRender {
+	if options.Extension == "" {
+		options.Extension = ".jet"
+	}
+	return &JetRender{base: newBase(), options: options}
+}
+
+// Load 创建模板集并注册模板函数，需在注册全部函数后调用
+func (r *JetRender) Load() error {
+	var loader jet.Loader
+	if r.options.FS != nil {
+		loader = &fsLoader{fsys: r.options.FS}
+	} else {
+		loader = jet.NewOSFileSystemLoader(r.options.Dir)
+	}
+
+	opts := []jet.Option{jet.WithTemplateNameExtensions([]string{"", r.options.Extension})}
+	if r.options.Development || core.IsDebugging() {
+		opts = append(opts, jet.InDevelopmentMode())
+	}
+	set := jet.NewSet(loader, opts...)
+	for name, fn := range r.funcMap() {
+		set.AddGlobal(name, fn)
+	}
+
+	r.mu.Lock()
+	r.set = set
+	r.mu.Unlock()
+	return nil
+}
+
+// Render 实现 core.Renderer 接口
+// name: 模板名称，例如 "users/index" 或 "users/index.jet"
+// data: 为 map[string]interface{} 时每个键都会作为模板变量，同时整体作为模板上下文 {{ . }}
+func (r *JetRender) Render(w http.ResponseWriter, name string, data interface{}) error {
+	r.mu.RLock()
+	set := r.set
+	r.mu.RUnlock()
+	if set == nil {
+		if err := r.Load(); err != nil {
+			return err
+		}
+		r.mu.RLock()
+		set = r.set
+		r.mu.RUnlock()
+	}
+
+	t, err := set.GetTemplate(name)
+	if err != nil {
+		return err
+	}
+
+	vars := make(jet.VarMap)
+	if m, ok := data.(map[string]interface{}); ok {
+		for k, v := range m {
+			vars[k] = reflect.ValueOf(v)
+		}
+	}
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	}
+	return t.Execute(w, vars, data)
+}
+
+// fsLoader 是基于 fs.FS 的 Jet 模板加载器
+type fsLoader struct {
+	fsys fs.FS
+}
+
+// Exists 判断模板是否存在
+func (l *fsLoader) Exists(templatePath string) bool {
+	_, err := fs.Stat(l.fsys, l.clean(templatePath))
+	return err == nil
+}
+
+// Open 打开模板
+func (l *fsLoader) Open(templatePath string) (io.ReadCloser, error) {
+	f, err := l.fsys.Open(l.clean(templatePath))
+	if err != nil {
+		return nil, os.ErrNotExist
+	}
+	return f, nil
+}
+
+// clean 将 Jet 的绝对模板路径转换为 fs.FS 路径
+func (l *fsLoader) clean(templatePath string) string {
+	return strings.TrimPrefix(path.Clean("/"+templatePath), "/")
+}
@@ -0,0 +1,120 @@
+// Package render 提供了 core.Renderer 的模板引擎适配器
+// 内置 html/template（支持布局与局部模板约定）和 Jet 两种实现，
+// 支持注册模板函数，并可将请求上下文中的 CSRF 令牌、当前用户、翻译函数注入模板数据
+package render
+
+import (
+	"sync"
+
+	"github.com/xzl-go/easygo/core"
+)
+
+// 注入模板数据时读取的上下文键
+const (
+	CSRFTokenKey       = "csrf_token"       // CSRF 令牌
+	CurrentUserKey     = "current_user"     // 当前登录用户的ID
+	CurrentUsernameKey = "current_username" // 当前登录用户的用户名
+	LangKey            = "lang"             // 当前语言，由 i18n 中间件设置
+)
+
+// Translator 定义了模板中使用的翻译器，*i18n.I18n 实现了该接口
+type Translator interface {
+	Translate(key, lang string) string
+}
+
+// Injector 在渲染前向模板数据中注入上下文相关的值
+type Injector func(c *core.Context, data map[string]interface{})
+
+// base 是各模板引擎适配器共享的函数注册与数据注入逻辑
+type base struct {
+	mu         sync.RWMutex
+	funcs      map[string]interface{}
+	injectors  []Injector
+	translator Translator
+}
+
+func newBase() base {
+	return base{funcs: make(map[string]interface{})}
+}
+
+// AddFunc 注册模板函数，需在加载模板前调用
+// name: 函数名
+// fn: 函数实现
+func (b *base) AddFunc(name string, fn interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.funcs[name] = fn
+}
+
+// Inject 注册自定义数据注入函数
+func (b *base) Inject(fn Injector) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.injectors = append(b.injectors, fn)
+}
+
+// SetTranslator 设置翻译器，设置后模板数据中会注入翻译函数 T
+func (b *base) SetTranslator(t Translator) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.translator = t
+}
+
+// ViewData 合并模板数据与上下文中的注入值
+// 注入的键包括 csrf_token、current_user、current_username、lang 以及翻译函数 T（在模板中使用 {{call .T "key"}}），
+// 模板数据中已有的同名键不会被覆盖。
+// data 为 map[string]interface{} 或 nil 时才会注入，其他类型原样返回。
+func (b *base) ViewData(c *core.Context, data interface{}) interface{} {
+	var view map[string]interface{}
+	switch d := data.(type) {
+	case nil:
+		view = make(map[string]interface{})
+	case map[string]interface{}:
+		view = make(map[string]interface{}, len(d)+4)
+		for k, v := range d {
+			view[k] = v
+		}
+	default:
+		return data
+	}
+
+	injected := make(map[string]interface{})
+	for _, key := range []string{CSRFTokenKey, CurrentUserKey, CurrentUsernameKey, LangKey} {
+		if v := c.Get(key); v != nil {
+			injected[key] = v
+		}
+	}
+
+	b.mu.RLock()
+	translator := b.translator
+	injectors := b.injectors
+	b.mu.RUnlock()
+
+	if translator != nil {
+		lang, _ := c.Get(LangKey).(string)
+		injected["T"] = func(key string) string {
+			return translator.Translate(key, lang)
+		}
+	}
+	for _, fn := range injectors {
+		fn(c, injected)
+	}
+
+	for k, v := range injected {
+		if _, exists := view[k]; !exists {
+			view[k] = v
+		}
+	}
+	return view
+}
+
+// funcMap 返回已注册函数的副本
+func (b *base) funcMap() map[string]interface{} {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	m := make(map[string]interface{}, len(b.funcs))
+	for k, v := range b.funcs {
+		m[k] = v
+	}
+	return m
+}
@@ -0,0 +1,92 @@
+package session
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// maxCookieSize 是浏览器允许的单个 Cookie 最大长度，超出时浏览器会丢弃
+const maxCookieSize = 4000
+
+// ErrTooLarge 会话数据过大，无法保存到 Cookie
+var ErrTooLarge = errors.New("session: 会话数据超过 Cookie 大小限制")
+
+// CookieStore 将会话数据使用 AES-GCM 加密后保存在 Cookie 中，无需服务端存储
+// 数据无法被客户端读取或篡改，但 Destroy 只能清除当前浏览器的 Cookie，已泄露的 Cookie 在过期前仍然有效
+type CookieStore struct {
+	aead cipher.AEAD
+}
+
+// cookiePayload 是加密前的 Cookie 内容
+type cookiePayload struct {
+	ID       string `json:"i"`
+	Data     []byte `json:"d"`
+	ExpireAt int64  `json:"e"`
+}
+
+// NewCookieStore 创建基于加密 Cookie 的会话存储
+// key: AES 密钥，长度为 16、24 或 32 字节；更换密钥后已有会话全部失效
+func NewCookieStore(key []byte) (*CookieStore, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &CookieStore{aead: aead}, nil
+}
+
+// Load 解密 Cookie 中的会话
+func (s *CookieStore) Load(_ context.Context, token string) (string, []byte, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) < s.aead.NonceSize() {
+		return "", nil, ErrNotFound
+	}
+	nonce, ciphertext := raw[:s.aead.NonceSize()], raw[s.aead.NonceSize():]
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", nil, ErrNotFound
+	}
+	var payload cookiePayload
+	if err := json.Unmarshal(plaintext, &payload); err != nil {
+		return "", nil, ErrNotFound
+	}
+	if payload.ExpireAt > 0 && time.Now().Unix() > payload.ExpireAt {
+		return "", nil, ErrNotFound
+	}
+	return payload.ID, payload.Data, nil
+}
+
+// Save 加密会话数据，返回写入 Cookie 的值
+func (s *CookieStore) Save(_ context.Context, id string, data []byte, ttl time.Duration) (string, error) {
+	payload := cookiePayload{ID: id, Data: data}
+	if ttl > 0 {
+		payload.ExpireAt = time.Now().Add(ttl).Unix()
+	}
+	plaintext, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(s.aead.Seal(nonce, nonce, plaintext, nil))
+	if len(token) > maxCookieSize {
+		return "", ErrTooLarge
+	}
+	return token, nil
+}
+
+// Delete 无需处理，Cookie 由会话中间件清除
+func (s *CookieStore) Delete(context.Context, string) error {
+	return nil
+}
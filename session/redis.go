@@ -0,0 +1,47 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore 是基于 Redis 的会话存储，适用于多实例部署
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisStore 创建基于 Redis 的会话存储
+// client: Redis 客户端
+// prefix: 键前缀，为空时使用 "session:"
+func NewRedisStore(client redis.UniversalClient, prefix string) *RedisStore {
+	if prefix == "" {
+		prefix = "session:"
+	}
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+// Load 加载会话
+func (s *RedisStore) Load(ctx context.Context, token string) (string, []byte, error) {
+	data, err := s.client.Get(ctx, s.prefix+token).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return "", nil, ErrNotFound
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return token, data, nil
+}
+
+// Save 保存会话
+func (s *RedisStore) Save(ctx context.Context, id string, data []byte, ttl time.Duration) (string, error) {
+	return id, s.client.Set(ctx, s.prefix+id, data, ttl).Err()
+}
+
+// Delete 删除会话
+func (s *RedisStore) Delete(ctx context.Context, id string) error {
+	return s.client.Del(ctx, s.prefix+id).Err()
+}
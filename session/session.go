@@ -0,0 +1,332 @@
+// Package session 提供了基于 Cookie 的会话管理
+// 支持内存、Redis 和加密 Cookie 存储，会话在响应写出前自动保存，空闲超时自动续期
+package session
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/xzl-go/easygo/core"
+	"github.com/xzl-go/easygo/logger"
+)
+
+// ContextKey 是会话在上下文中的键
+const ContextKey = "session"
+
+// Options 定义了会话选项
+type Options struct {
+	// CookieName 保存会话的 Cookie 名称，默认 "easygo_session"
+	CookieName string
+	// MaxAge 空闲超时，期间有访问时自动续期，默认 24 小时
+	MaxAge time.Duration
+	// Lifetime 绝对有效期，从会话创建开始计算，到期后必须重新登录，为 0 时不限制
+	Lifetime time.Duration
+	// Path Cookie 生效路径，默认 "/"
+	Path string
+	// Domain Cookie 生效域名
+	Domain string
+	// Secure 是否仅通过 HTTPS 发送 Cookie，生产环境应开启
+	Secure bool
+	// SameSite Cookie 的 SameSite 属性，默认 Lax
+	SameSite http.SameSite
+}
+
+// Session 是一次请求中的会话
+// 会话数据以 JSON 保存，读取时数字为 json.Number，可使用 GetInt 读取
+type Session struct {
+	mu        sync.RWMutex
+	id        string
+	oldID     string // Regenerate 前的会话ID，保存时从存储中删除
+	values    map[string]interface{}
+	createdAt time.Time
+	updatedAt time.Time
+	isNew     bool
+	modified  bool
+	destroyed bool
+}
+
+// record 是会话在存储中的格式
+type record struct {
+	Values    map[string]interface{} `json:"values"`
+	CreatedAt int64                  `json:"created_at"`
+	UpdatedAt int64                  `json:"updated_at"`
+}
+
+// Get 返回当前请求的会话，未使用会话中间件时返回 nil
+func Get(c *core.Context) *Session {
+	s, _ := c.Get(ContextKey).(*Session)
+	return s
+}
+
+// ID 返回会话ID
+func (s *Session) ID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.id
+}
+
+// IsNew 返回会话是否为本次请求新建
+func (s *Session) IsNew() bool {
+	return s.isNew
+}
+
+// Get 获取会话值，不存在时返回 nil
+func (s *Session) Get(key string) interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.values[key]
+}
+
+// GetString 获取字符串类型的会话值
+func (s *Session) GetString(key string) string {
+	v, _ := s.Get(key).(string)
+	return v
+}
+
+// GetInt 获取整数类型的会话值，不存在或类型不符时返回 0
+func (s *Session) GetInt(key string) int64 {
+	switch v := s.Get(key).(type) {
+	case json.Number:
+		n, _ := v.Int64()
+		return n
+	case int:
+		return int64(v)
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	}
+	return 0
+}
+
+// Set 设置会话值，值需要能够编码为 JSON
+func (s *Session) Set(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+	s.modified = true
+}
+
+// Delete 删除会话值
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+	s.modified = true
+}
+
+// Clear 清空会话值
+func (s *Session) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values = make(map[string]interface{})
+	s.modified = true
+}
+
+// Regenerate 更换会话ID并保留会话值，登录、提权后应调用以防止会话固定攻击
+func (s *Session) Regenerate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.isNew && s.oldID == "" {
+		s.oldID = s.id
+	}
+	s.id = newID()
+	s.createdAt = time.Now()
+	s.modified = true
+}
+
+// Destroy 销毁会话，从存储中删除并清除 Cookie，通常在退出登录时调用
+func (s *Session) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values = make(map[string]interface{})
+	s.destroyed = true
+}
+
+// Middleware 返回会话中间件
+// 处理函数通过 session.Get(c) 获取会话，会话在响应写出前自动保存
+// store: 会话存储
+// options: 会话选项
+func Middleware(store Store, options Options) core.HandlerFunc {
+	if options.CookieName == "" {
+		options.CookieName = "easygo_session"
+	}
+	if options.MaxAge <= 0 {
+		options.MaxAge = 24 * time.Hour
+	}
+	if options.Path == "" {
+		options.Path = "/"
+	}
+	if options.SameSite == 0 {
+		options.SameSite = http.SameSiteLaxMode
+	}
+	m := &manager{store: store, options: options}
+
+	return func(c *core.Context) {
+		s := m.load(c)
+		c.Set(ContextKey, s)
+
+		writer := &sessionWriter{ResponseWriter: c.Writer}
+		writer.commit = func() { m.save(c, s) }
+		c.Writer = writer
+		c.Next()
+		writer.commitOnce()
+		c.Writer = writer.ResponseWriter
+	}
+}
+
+// manager 负责会话的加载和保存
+type manager struct {
+	store   Store
+	options Options
+}
+
+// load 从 Cookie 加载会话，不存在、已过期或无效时创建新会话
+func (m *manager) load(c *core.Context) *Session {
+	now := time.Now()
+	cookie, err := c.Request.Cookie(m.options.CookieName)
+	if err == nil && cookie.Value != "" {
+		id, data, err := m.store.Load(c.Request.Context(), cookie.Value)
+		if err == nil {
+			var r record
+			decoder := json.NewDecoder(bytes.NewReader(data))
+			decoder.UseNumber()
+			if err := decoder.Decode(&r); err == nil {
+				created := time.Unix(r.CreatedAt, 0)
+				if m.options.Lifetime <= 0 || now.Sub(created) < m.options.Lifetime {
+					if r.Values == nil {
+						r.Values = make(map[string]interface{})
+					}
+					return &Session{
+						id:        id,
+						values:    r.Values,
+						createdAt: created,
+						updatedAt: time.Unix(r.UpdatedAt, 0),
+					}
+				}
+				// 超过绝对有效期，删除旧会话
+				_ = m.store.Delete(c.Request.Context(), id)
+			}
+		} else if !errors.Is(err, ErrNotFound) {
+			logger.Error("[Session] 加载会话失败：%v", err)
+		}
+	}
+	return &Session{
+		id:        newID(),
+		values:    make(map[string]interface{}),
+		createdAt: now,
+		isNew:     true,
+	}
+}
+
+// save 保存会话并写入 Cookie，需在响应头写出前调用
+func (m *manager) save(c *core.Context, s *Session) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ctx := c.Request.Context()
+
+	if s.oldID != "" {
+		if err := m.store.Delete(ctx, s.oldID); err != nil {
+			logger.Error("[Session] 删除旧会话失败：%v", err)
+		}
+	}
+	if s.destroyed {
+		if !s.isNew || s.oldID != "" {
+			if err := m.store.Delete(ctx, s.id); err != nil {
+				logger.Error("[Session] 删除会话失败：%v", err)
+			}
+		}
+		m.setCookie(c, "", -1)
+		return
+	}
+
+	now := time.Now()
+	// 未修改的会话在空闲超时过去四分之一后续期，避免每个请求都写入存储
+	if !s.modified && (s.isNew || now.Sub(s.updatedAt) < m.options.MaxAge/4) {
+		return
+	}
+
+	ttl := m.options.MaxAge
+	if m.options.Lifetime > 0 {
+		if remaining := s.createdAt.Add(m.options.Lifetime).Sub(now); remaining < ttl {
+			ttl = remaining
+		}
+	}
+	data, err := json.Marshal(record{Values: s.values, CreatedAt: s.createdAt.Unix(), UpdatedAt: now.Unix()})
+	if err != nil {
+		logger.Error("[Session] 编码会话失败：%v", err)
+		return
+	}
+	token, err := m.store.Save(ctx, s.id, data, ttl)
+	if err != nil {
+		logger.Error("[Session] 保存会话失败：%v", err)
+		return
+	}
+	m.setCookie(c, token, int(ttl.Seconds()))
+}
+
+// setCookie 写入会话 Cookie，maxAge 为负数时删除
+func (m *manager) setCookie(c *core.Context, value string, maxAge int) {
+	c.SetHTTPCookie(&http.Cookie{
+		Name:     m.options.CookieName,
+		Value:    value,
+		Path:     m.options.Path,
+		Domain:   m.options.Domain,
+		MaxAge:   maxAge,
+		Secure:   m.options.Secure,
+		HttpOnly: true,
+		SameSite: m.options.SameSite,
+	})
+}
+
+// sessionWriter 在响应头写出前保存会话，保证 Set-Cookie 能够随响应发送
+type sessionWriter struct {
+	core.ResponseWriter
+	commit    func()
+	committed bool
+}
+
+// commitOnce 保存会话，只执行一次
+func (w *sessionWriter) commitOnce() {
+	if !w.committed {
+		w.committed = true
+		w.commit()
+	}
+}
+
+// WriteHeader 写出状态码前保存会话
+func (w *sessionWriter) WriteHeader(code int) {
+	w.commitOnce()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write 写出响应体前保存会话
+func (w *sessionWriter) Write(b []byte) (int, error) {
+	w.commitOnce()
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush 刷新前保存会话
+func (w *sessionWriter) Flush() {
+	w.commitOnce()
+	w.ResponseWriter.Flush()
+}
+
+// newID 生成随机会话ID
+func newID() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
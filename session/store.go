@@ -0,0 +1,63 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/xzl-go/easygo/cache"
+)
+
+// ErrNotFound 会话不存在、已过期或 Cookie 无效
+var ErrNotFound = errors.New("session: 会话不存在")
+
+// Store 定义了会话存储
+// 服务端存储（内存、Redis）只在 Cookie 中保存会话ID；CookieStore 将加密后的会话数据直接保存在 Cookie 中
+type Store interface {
+	// Load 根据 Cookie 中的值加载会话，返回会话ID和数据，不存在或已过期时返回 ErrNotFound
+	Load(ctx context.Context, token string) (id string, data []byte, err error)
+	// Save 保存会话数据，返回写入 Cookie 的值
+	Save(ctx context.Context, id string, data []byte, ttl time.Duration) (token string, err error)
+	// Delete 删除会话
+	Delete(ctx context.Context, id string) error
+}
+
+// CacheStore 是基于 cache.Cache 的会话存储
+type CacheStore struct {
+	cache  cache.Cache
+	prefix string
+}
+
+// NewCacheStore 创建基于缓存的会话存储
+// c: 缓存
+// prefix: 键前缀，为空时使用 "session:"
+func NewCacheStore(c cache.Cache, prefix string) *CacheStore {
+	if prefix == "" {
+		prefix = "session:"
+	}
+	return &CacheStore{cache: c, prefix: prefix}
+}
+
+// NewMemoryStore 创建基于内存的会话存储，适用于单实例和开发环境
+func NewMemoryStore() *CacheStore {
+	return NewCacheStore(cache.NewMemory(time.Minute), "")
+}
+
+// Load 加载会话
+func (s *CacheStore) Load(_ context.Context, token string) (string, []byte, error) {
+	data, ok := s.cache.Get(s.prefix + token)
+	if !ok {
+		return "", nil, ErrNotFound
+	}
+	return token, []byte(data), nil
+}
+
+// Save 保存会话
+func (s *CacheStore) Save(_ context.Context, id string, data []byte, ttl time.Duration) (string, error) {
+	return id, s.cache.Set(s.prefix+id, string(data), ttl)
+}
+
+// Delete 删除会话
+func (s *CacheStore) Delete(_ context.Context, id string) error {
+	return s.cache.Delete(s.prefix + id)
+}
@@ -0,0 +1,87 @@
+package signature
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Signer 是客户端签名工具，用于调用开放平台接口
+type Signer struct {
+	appID  string
+	secret string
+}
+
+// NewSigner 创建签名工具
+// appID: 应用ID
+// secret: 应用密钥
+func NewSigner(appID, secret string) *Signer {
+	return &Signer{appID: appID, secret: secret}
+}
+
+// Sign 为请求设置应用ID、时间戳、随机串和签名头
+// 需在请求的方法、URL 和请求体确定后调用
+func (s *Signer) Sign(r *http.Request) error {
+	body, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	nonce, err := newNonce()
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	r.Header.Set(HeaderAppID, s.appID)
+	r.Header.Set(HeaderTimestamp, timestamp)
+	r.Header.Set(HeaderNonce, nonce)
+	r.Header.Set(HeaderSignature, Compute(s.secret, StringToSign(r, s.appID, timestamp, nonce, body)))
+	return nil
+}
+
+// Transport 返回自动签名的 http.RoundTripper
+// base: 底层传输，为 nil 时使用 http.DefaultTransport
+func (s *Signer) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{signer: s, base: base}
+}
+
+// Client 返回自动签名的 HTTP 客户端
+// timeout: 请求超时时间
+func (s *Signer) Client(timeout time.Duration) *http.Client {
+	return &http.Client{Transport: s.Transport(nil), Timeout: timeout}
+}
+
+// transport 在发送前为请求签名
+type transport struct {
+	signer *Signer
+	base   http.RoundTripper
+}
+
+// RoundTrip 签名并发送请求，按 http.RoundTripper 约定不修改原请求
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	req := r.Clone(r.Context())
+	if r.Body != nil && r.GetBody != nil {
+		body, err := r.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = body
+	}
+	if err := t.signer.Sign(req); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// newNonce 生成随机串
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
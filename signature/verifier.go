@@ -0,0 +1,155 @@
+package signature
+
+import (
+	"crypto/hmac"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/xzl-go/easygo/cache"
+	"github.com/xzl-go/easygo/core"
+	errs "github.com/xzl-go/easygo/errors"
+)
+
+// AppIDKey 是校验通过后上下文中保存应用ID的键
+const AppIDKey = "app_id"
+
+// 预定义错误
+var (
+	ErrInvalidSignature = errs.New(40113, "error.signature.invalid", http.StatusUnauthorized, "Invalid request signature")
+	ErrExpired          = errs.New(40114, "error.signature.expired", http.StatusUnauthorized, "Request timestamp expired")
+	ErrReplayed         = errs.New(40115, "error.signature.replayed", http.StatusUnauthorized, "Duplicate request nonce")
+	ErrBodyTooLarge     = errs.New(41300, "error.signature.body_too_large", http.StatusRequestEntityTooLarge, "Request body too large")
+)
+
+// NonceStore 记录已使用的随机串，用于防重放
+type NonceStore interface {
+	// Use 标记随机串已使用，随机串在 ttl 内已被使用过时返回 false
+	Use(key string, ttl time.Duration) (bool, error)
+}
+
+// cacheNonceStore 是基于 cache.Cache 的随机串存储
+type cacheNonceStore struct {
+	mu    sync.Mutex
+	cache cache.Cache
+}
+
+// NewCacheNonceStore 基于缓存创建随机串存储
+// 单实例部署可使用内存缓存，多实例部署需使用共享缓存（如 redis.NewCache）；
+// 缓存实现了 cache.Adder 时通过 SetNX 原子地标记随机串，多个实例并发收到同一个随机串时只有一个通过，
+// 否则只能在进程内串行化检查，不能防止多实例间的重放
+func NewCacheNonceStore(c cache.Cache) NonceStore {
+	return &cacheNonceStore{cache: c}
+}
+
+// Use 标记随机串已使用
+func (s *cacheNonceStore) Use(key string, ttl time.Duration) (bool, error) {
+	if _, ok := s.cache.(cache.Adder); !ok {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+	return cache.SetNX(s.cache, key, "1", ttl)
+}
+
+// VerifierOptions 定义了签名校验配置
+type VerifierOptions struct {
+	// Tolerance 允许的时间戳误差，默认 5 分钟
+	Tolerance time.Duration
+	// Nonces 随机串存储，默认使用内存缓存
+	Nonces NonceStore
+	// MaxBodySize 计算签名时读取的请求体最大字节数，默认 10 MB，超出时返回 ErrBodyTooLarge；
+	// 请求体在签名校验通过前读取，限制大小避免未认证的请求耗尽内存
+	MaxBodySize int64
+}
+
+// Verifier 是服务端签名校验器
+type Verifier struct {
+	secrets SecretProvider
+	options VerifierOptions
+}
+
+// NewVerifier 创建签名校验器
+// secrets: 应用密钥查询
+// options: 校验配置
+func NewVerifier(secrets SecretProvider, options VerifierOptions) *Verifier {
+	if options.Tolerance <= 0 {
+		options.Tolerance = 5 * time.Minute
+	}
+	if options.Nonces == nil {
+		options.Nonces = NewCacheNonceStore(cache.NewMemory(time.Minute))
+	}
+	if options.MaxBodySize <= 0 {
+		options.MaxBodySize = 10 << 20
+	}
+	return &Verifier{secrets: secrets, options: options}
+}
+
+// Verify 校验请求签名，返回应用ID
+// 校验顺序：必填头 -> 时间戳 -> 签名 -> 随机串，签名无效的请求不会占用随机串
+func (v *Verifier) Verify(r *http.Request) (string, error) {
+	appID := r.Header.Get(HeaderAppID)
+	timestamp := r.Header.Get(HeaderTimestamp)
+	nonce := r.Header.Get(HeaderNonce)
+	signature := r.Header.Get(HeaderSignature)
+	if appID == "" || timestamp == "" || nonce == "" || signature == "" {
+		return "", ErrInvalidSignature
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "", ErrInvalidSignature
+	}
+	diff := time.Since(time.Unix(ts, 0))
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > v.options.Tolerance {
+		return "", ErrExpired
+	}
+
+	secret, err := v.secrets.Secret(appID)
+	if err != nil {
+		return "", ErrInvalidSignature
+	}
+	if r.Body != nil && r.Body != http.NoBody {
+		r.Body = http.MaxBytesReader(nil, r.Body, v.options.MaxBodySize)
+	}
+	body, err := readBody(r)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return "", ErrBodyTooLarge.Wrap(err)
+	}
+	if err != nil {
+		return "", ErrInvalidSignature.Wrap(err)
+	}
+	expected := Compute(secret, StringToSign(r, appID, timestamp, nonce, body))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return "", ErrInvalidSignature
+	}
+
+	// 随机串只需在时间戳有效期内保持唯一
+	ok, err := v.options.Nonces.Use("signature:nonce:"+appID+":"+nonce, 2*v.options.Tolerance)
+	if err != nil {
+		return "", errs.ErrInternal.Wrap(err)
+	}
+	if !ok {
+		return "", ErrReplayed
+	}
+	return appID, nil
+}
+
+// Middleware 返回签名校验中间件，校验通过后将应用ID写入上下文键 app_id
+func (v *Verifier) Middleware() core.HandlerFunc {
+	return func(c *core.Context) {
+		appID, err := v.Verify(c.Request)
+		if err != nil {
+			c.Fail(err)
+			c.Abort()
+			return
+		}
+		c.Set(AppIDKey, appID)
+		c.Next()
+	}
+}
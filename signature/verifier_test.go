@@ -0,0 +1,62 @@
+package signature
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xzl-go/easygo/cache"
+)
+
+func TestNonceStoreConcurrentUse(t *testing.T) {
+	store := NewCacheNonceStore(cache.NewMemory(0))
+	const n = 50
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	accepted := 0
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := store.Use("nonce", time.Minute)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if ok {
+				mu.Lock()
+				accepted++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if accepted != 1 {
+		t.Errorf("同一个随机串通过 %d 次，期望 1 次", accepted)
+	}
+}
+
+func TestVerifyBodyTooLarge(t *testing.T) {
+	verifier := NewVerifier(StaticSecrets{"app": "secret"}, VerifierOptions{MaxBodySize: 16})
+	signer := NewSigner("app", "secret")
+
+	r := httptest.NewRequest(http.MethodPost, "/open", strings.NewReader(strings.Repeat("x", 1024)))
+	if err := signer.Sign(r); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifier.Verify(r); !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("请求体过大时返回 %v，期望 ErrBodyTooLarge", err)
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/open", strings.NewReader("small"))
+	if err := signer.Sign(r); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifier.Verify(r); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+}
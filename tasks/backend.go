@@ -0,0 +1,129 @@
+package tasks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Backend 是任务队列的存储后端
+type Backend interface {
+	// Push 保存新任务，RunAt 晚于当前时间的任务到期后才能取出
+	Push(ctx context.Context, queue string, t *Task) error
+	// Pop 取出一个到期的任务，队列为空时最多等待 wait，超时返回 nil, nil
+	// 取出的任务需通过 Ack、Retry 或 Bury 写回结果
+	Pop(ctx context.Context, queue string, wait time.Duration) (*Task, error)
+	// Ack 确认任务执行成功并删除
+	Ack(ctx context.Context, queue string, t *Task) error
+	// Retry 将任务按 RunAt 重新放回队列
+	Retry(ctx context.Context, queue string, t *Task) error
+	// Bury 将任务移入死信队列
+	Bury(ctx context.Context, queue string, t *Task) error
+	// Dead 返回死信队列中的任务
+	Dead(ctx context.Context, queue string) ([]*Task, error)
+}
+
+// MemoryBackend 是基于内存的后端，任务随进程退出丢失，适用于单实例和开发环境
+type MemoryBackend struct {
+	mu     sync.Mutex
+	queues map[string]*memoryQueue
+	notify chan struct{} // 有新任务时关闭并替换，唤醒等待的 Pop
+}
+
+// memoryQueue 是一个队列的任务，按 RunAt 排序
+type memoryQueue struct {
+	pending []*Task
+	dead    []*Task
+}
+
+// NewMemoryBackend 创建内存后端
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{queues: make(map[string]*memoryQueue), notify: make(chan struct{})}
+}
+
+// queue 返回队列，不存在时创建，调用方需持有锁
+func (b *MemoryBackend) queue(name string) *memoryQueue {
+	q, ok := b.queues[name]
+	if !ok {
+		q = &memoryQueue{}
+		b.queues[name] = q
+	}
+	return q
+}
+
+// Push 保存新任务
+func (b *MemoryBackend) Push(ctx context.Context, queue string, t *Task) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	q := b.queue(queue)
+	// 按 RunAt 插入，RunAt 相同时先提交的先执行
+	i := sort.Search(len(q.pending), func(i int) bool { return q.pending[i].RunAt.After(t.RunAt) })
+	q.pending = append(q.pending, nil)
+	copy(q.pending[i+1:], q.pending[i:])
+	q.pending[i] = t
+	close(b.notify)
+	b.notify = make(chan struct{})
+	return nil
+}
+
+// Pop 取出一个到期的任务
+func (b *MemoryBackend) Pop(ctx context.Context, queue string, wait time.Duration) (*Task, error) {
+	deadline := time.Now().Add(wait)
+	for {
+		b.mu.Lock()
+		q := b.queue(queue)
+		now := time.Now()
+		if len(q.pending) > 0 && !q.pending[0].RunAt.After(now) {
+			t := q.pending[0]
+			q.pending = q.pending[1:]
+			b.mu.Unlock()
+			return t, nil
+		}
+		// 等到最早的任务到期、有新任务或超时
+		d := deadline.Sub(now)
+		if len(q.pending) > 0 {
+			d = min(d, q.pending[0].RunAt.Sub(now))
+		}
+		notify := b.notify
+		b.mu.Unlock()
+		if !now.Before(deadline) {
+			return nil, nil
+		}
+		timer := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-notify:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// Ack 确认任务执行成功，内存后端取出时已删除任务
+func (b *MemoryBackend) Ack(ctx context.Context, queue string, t *Task) error {
+	return nil
+}
+
+// Retry 将任务重新放回队列
+func (b *MemoryBackend) Retry(ctx context.Context, queue string, t *Task) error {
+	return b.Push(ctx, queue, t)
+}
+
+// Bury 将任务移入死信队列
+func (b *MemoryBackend) Bury(ctx context.Context, queue string, t *Task) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	q := b.queue(queue)
+	q.dead = append(q.dead, t)
+	return nil
+}
+
+// Dead 返回死信队列中的任务
+func (b *MemoryBackend) Dead(ctx context.Context, queue string) ([]*Task, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*Task(nil), b.queue(queue).dead...), nil
+}
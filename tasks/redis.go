@@ -0,0 +1,221 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// promoteScript 将到期的延迟任务移入待执行列表
+var promoteScript = goredis.NewScript(`
+local items = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, 100)
+for _, item in ipairs(items) do
+	redis.call('ZREM', KEYS[1], item)
+	redis.call('LPUSH', KEYS[2], item)
+end
+return #items`)
+
+// requeueScript 将租约到期的执行中任务放回待执行列表
+// 没有租约的任务（取出后未来得及写入租约）从本次开始计时，避免误回收刚被其他实例取出的任务
+var requeueScript = goredis.NewScript(`
+local items = redis.call('LRANGE', KEYS[1], 0, -1)
+local count = 0
+for _, item in ipairs(items) do
+	local deadline = redis.call('ZSCORE', KEYS[2], item)
+	if not deadline then
+		redis.call('ZADD', KEYS[2], ARGV[2], item)
+	elseif tonumber(deadline) <= tonumber(ARGV[1]) then
+		redis.call('LREM', KEYS[1], 1, item)
+		redis.call('ZREM', KEYS[2], item)
+		redis.call('RPUSH', KEYS[3], item)
+		count = count + 1
+	end
+end
+return count`)
+
+// RedisBackend 是基于 Redis 的后端，多个实例共享同一个队列
+// 每个队列使用五个键：<prefix>{<queue>}:ready 待执行列表、:delayed 延迟任务有序集合、
+// :processing 执行中列表、:leases 执行租约有序集合和 :dead 死信列表；
+// 队列名作为哈希标签，保证同一队列的键在 Redis Cluster 中位于同一个槽，事务和脚本可以跨键执行
+// 取出任务时写入租约，进程在执行中崩溃时任务保留在 :processing 列表中，租约到期后由 Pop 或 Requeue 放回待执行列表
+type RedisBackend struct {
+	client     goredis.UniversalClient
+	prefix     string
+	visibility time.Duration
+
+	mu         sync.Mutex
+	requeuedAt map[string]time.Time // 每个队列上次自动回收的时间，用于限制回收频率
+}
+
+// NewRedisBackend 创建 Redis 后端
+// client: Redis 客户端
+// prefix: 键前缀，默认 "easygo:tasks:"
+func NewRedisBackend(client goredis.UniversalClient, prefix string) *RedisBackend {
+	if prefix == "" {
+		prefix = "easygo:tasks:"
+	}
+	return &RedisBackend{client: client, prefix: prefix, visibility: 30 * time.Minute, requeuedAt: make(map[string]time.Time)}
+}
+
+// SetVisibilityTimeout 设置执行租约的时长，默认 30 分钟
+// 任务取出后超过该时长仍未确认、重试或移入死信列表时视为执行者已崩溃，任务被放回待执行列表重新执行；
+// 应大于任务的最长执行时间，否则任务可能被重复执行；为 0 时不自动回收，只能通过 Requeue 手动回收
+// d: 租约时长
+func (b *RedisBackend) SetVisibilityTimeout(d time.Duration) *RedisBackend {
+	b.visibility = max(d, 0)
+	return b
+}
+
+// key 返回队列的键，队列名作为哈希标签
+func (b *RedisBackend) key(queue, kind string) string {
+	return b.prefix + "{" + queue + "}:" + kind
+}
+
+// Push 保存新任务，未到期的任务写入延迟集合
+func (b *RedisBackend) Push(ctx context.Context, queue string, t *Task) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return b.push(ctx, b.client, queue, t, data)
+}
+
+// push 按 RunAt 将任务写入待执行列表或延迟集合
+func (b *RedisBackend) push(ctx context.Context, cmd goredis.Cmdable, queue string, t *Task, data []byte) error {
+	if t.RunAt.After(time.Now()) {
+		return cmd.ZAdd(ctx, b.key(queue, "delayed"), goredis.Z{Score: float64(t.RunAt.UnixMilli()), Member: data}).Err()
+	}
+	return cmd.LPush(ctx, b.key(queue, "ready"), data).Err()
+}
+
+// Requeue 将租约到期的执行中任务放回待执行列表，返回放回的任务数
+// 租约时长大于 0 时 Pop 会定期自动调用；为 0 时不自动回收，可在启动或运维脚本中手动调用
+// queue: 队列名称
+// timeout: 租约时长，为 0 时使用 SetVisibilityTimeout 设置的值
+func (b *RedisBackend) Requeue(ctx context.Context, queue string, timeout time.Duration) (int, error) {
+	if timeout <= 0 {
+		timeout = b.visibility
+	}
+	now := time.Now()
+	keys := []string{b.key(queue, "processing"), b.key(queue, "leases"), b.key(queue, "ready")}
+	return requeueScript.Run(ctx, b.client, keys, now.UnixMilli(), now.Add(timeout).UnixMilli()).Int()
+}
+
+// requeue 每秒最多执行一次自动回收，多个 worker 共享同一个后端时避免重复扫描
+func (b *RedisBackend) requeue(ctx context.Context, queue string) error {
+	if b.visibility <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	now := time.Now()
+	if now.Sub(b.requeuedAt[queue]) < time.Second {
+		b.mu.Unlock()
+		return nil
+	}
+	b.requeuedAt[queue] = now
+	b.mu.Unlock()
+	_, err := b.Requeue(ctx, queue, b.visibility)
+	return err
+}
+
+// Pop 回收租约到期的任务并将到期的延迟任务移入待执行列表，再取出一个任务放入执行中列表并写入租约
+func (b *RedisBackend) Pop(ctx context.Context, queue string, wait time.Duration) (*Task, error) {
+	if err := b.requeue(ctx, queue); err != nil {
+		return nil, err
+	}
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	keys := []string{b.key(queue, "delayed"), b.key(queue, "ready")}
+	if err := promoteScript.Run(ctx, b.client, keys, now).Err(); err != nil {
+		return nil, err
+	}
+	// BLMOVE 的超时以秒为单位，不足 1 秒时按 1 秒等待
+	wait = max(wait, time.Second)
+	raw, err := b.client.BLMove(ctx, b.key(queue, "ready"), b.key(queue, "processing"), "RIGHT", "LEFT", wait).Result()
+	if errors.Is(err, goredis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	t := &Task{}
+	if err := json.Unmarshal([]byte(raw), t); err != nil {
+		// 无法解析的数据直接移入死信列表，避免反复取出
+		b.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			pipe.LPush(ctx, b.key(queue, "dead"), raw)
+			b.release(ctx, pipe, queue, raw)
+			return nil
+		})
+		return nil, err
+	}
+	t.raw = raw
+	// 写入失败时由 Requeue 在下次扫描时补写租约
+	if b.visibility > 0 {
+		b.client.ZAdd(ctx, b.key(queue, "leases"), goredis.Z{Score: float64(time.Now().Add(b.visibility).UnixMilli()), Member: raw})
+	}
+	return t, nil
+}
+
+// Ack 在一个事务中从执行中列表删除任务并释放租约
+func (b *RedisBackend) Ack(ctx context.Context, queue string, t *Task) error {
+	_, err := b.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		b.release(ctx, pipe, queue, t.raw)
+		return nil
+	})
+	return err
+}
+
+// release 从执行中列表删除任务并释放租约
+func (b *RedisBackend) release(ctx context.Context, pipe goredis.Pipeliner, queue, raw string) {
+	pipe.LRem(ctx, b.key(queue, "processing"), 1, raw)
+	pipe.ZRem(ctx, b.key(queue, "leases"), raw)
+}
+
+// Retry 在一个事务中将更新后的任务放回队列、从执行中列表删除并释放租约
+func (b *RedisBackend) Retry(ctx context.Context, queue string, t *Task) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	_, err = b.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		b.push(ctx, pipe, queue, t, data)
+		b.release(ctx, pipe, queue, t.raw)
+		return nil
+	})
+	return err
+}
+
+// Bury 在一个事务中将任务移入死信列表、从执行中列表删除并释放租约
+func (b *RedisBackend) Bury(ctx context.Context, queue string, t *Task) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	_, err = b.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.LPush(ctx, b.key(queue, "dead"), data)
+		b.release(ctx, pipe, queue, t.raw)
+		return nil
+	})
+	return err
+}
+
+// Dead 返回死信列表中的任务，最近移入的在前
+func (b *RedisBackend) Dead(ctx context.Context, queue string) ([]*Task, error) {
+	items, err := b.client.LRange(ctx, b.key(queue, "dead"), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*Task, 0, len(items))
+	for _, item := range items {
+		t := &Task{}
+		if err := json.Unmarshal([]byte(item), t); err != nil {
+			continue
+		}
+		list = append(list, t)
+	}
+	return list, nil
+}
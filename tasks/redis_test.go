@@ -0,0 +1,23 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRedisBackendKeyHashTag(t *testing.T) {
+	b := NewRedisBackend(nil, "")
+	if got := b.key("default", "ready"); got != "easygo:tasks:{default}:ready" {
+		t.Fatalf("key = %q", got)
+	}
+}
+
+func TestRedisBackendVisibilityTimeout(t *testing.T) {
+	b := NewRedisBackend(nil, "app:")
+	if b.visibility != 30*time.Minute {
+		t.Fatalf("default visibility = %v", b.visibility)
+	}
+	if b.SetVisibilityTimeout(-time.Second).visibility != 0 {
+		t.Fatal("negative visibility should disable recovery")
+	}
+}
@@ -0,0 +1,300 @@
+// Package tasks 提供了异步任务队列：提交带载荷的任务，由 worker 池按并发限制执行，
+// 失败后按退避策略重试，重试用尽后进入死信队列
+// 与按时间触发的 cron 互补，适用于发送邮件、生成报表、处理回调等由事件触发的后台工作
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/xzl-go/easygo/id"
+	"github.com/xzl-go/easygo/logger"
+)
+
+// ErrNoHandler 任务类型没有注册处理函数，此类任务直接进入死信队列
+var ErrNoHandler = errors.New("tasks: 任务类型没有注册处理函数")
+
+// Task 是一个异步任务
+type Task struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`                 // 任务类型，用于选择处理函数
+	Payload    json.RawMessage `json:"payload,omitempty"`    // 任务载荷
+	Attempts   int             `json:"attempts"`             // 已执行次数
+	MaxRetries int             `json:"max_retries"`          // 失败后的最大重试次数
+	CreatedAt  time.Time       `json:"created_at"`           // 提交时间
+	RunAt      time.Time       `json:"run_at"`               // 最早执行时间
+	LastError  string          `json:"last_error,omitempty"` // 最近一次执行的错误
+
+	raw string // 后端保存的原始数据，用于确认和删除
+}
+
+// Bind 将任务载荷解码到 v
+func (t *Task) Bind(v interface{}) error {
+	return json.Unmarshal(t.Payload, v)
+}
+
+// Handler 处理一种类型的任务，返回错误时按退避策略重试
+type Handler func(ctx context.Context, t *Task) error
+
+// Options 定义了任务队列配置
+type Options struct {
+	// Backend 存储后端，默认 NewMemoryBackend()；多实例部署时使用 NewRedisBackend
+	Backend Backend
+	// Queue 队列名称，默认 "default"
+	Queue string
+	// Concurrency 同时执行的任务数，默认 10
+	Concurrency int
+	// MaxRetries 失败后的最大重试次数，默认 3，为负数时不重试
+	MaxRetries int
+	// Backoff 第 retry 次重试前的等待时间，默认从 1 秒开始指数增长，最长 10 分钟
+	Backoff func(retry int) time.Duration
+	// Timeout 单个任务的执行超时，超时后取消 ctx；为 0 时不限制
+	Timeout time.Duration
+	// PollInterval 队列为空时的最长等待时间，默认 1 秒
+	PollInterval time.Duration
+}
+
+// EnqueueOptions 定义了提交任务的选项
+type EnqueueOptions struct {
+	// Delay 延迟执行的时间
+	Delay time.Duration
+	// MaxRetries 最大重试次数，为 0 时使用队列配置，为负数时不重试
+	MaxRetries int
+}
+
+// Queue 是异步任务队列，既可以提交任务，也可以启动 worker 执行任务
+// 实现了应用容器的模块接口，可通过 app.Register(queue) 随应用启动和停止 worker
+type Queue struct {
+	options Options
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// New 创建任务队列
+// options: 队列配置
+func New(options Options) *Queue {
+	if options.Backend == nil {
+		options.Backend = NewMemoryBackend()
+	}
+	if options.Queue == "" {
+		options.Queue = "default"
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = 10
+	}
+	if options.MaxRetries == 0 {
+		options.MaxRetries = 3
+	}
+	if options.MaxRetries < 0 {
+		options.MaxRetries = 0
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = time.Second
+	}
+	return &Queue{options: options, handlers: make(map[string]Handler)}
+}
+
+// Handle 注册任务类型的处理函数，需在 Start 之前注册
+// taskType: 任务类型，例如 "email.send"
+// handler: 处理函数
+func (q *Queue) Handle(taskType string, handler Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[taskType] = handler
+}
+
+// Enqueue 提交任务
+// taskType: 任务类型
+// payload: 任务载荷，编码为 JSON；[]byte 和 json.RawMessage 原样保存
+func (q *Queue) Enqueue(ctx context.Context, taskType string, payload interface{}) (*Task, error) {
+	return q.EnqueueWithOptions(ctx, taskType, payload, EnqueueOptions{})
+}
+
+// EnqueueWithOptions 按选项提交任务
+// taskType: 任务类型
+// payload: 任务载荷
+// options: 提交选项
+func (q *Queue) EnqueueWithOptions(ctx context.Context, taskType string, payload interface{}, options EnqueueOptions) (*Task, error) {
+	var data []byte
+	switch p := payload.(type) {
+	case json.RawMessage:
+		data = p
+	case []byte:
+		data = p
+	default:
+		var err error
+		if data, err = json.Marshal(payload); err != nil {
+			return nil, fmt.Errorf("tasks: 无法编码任务载荷: %w", err)
+		}
+	}
+	taskID, err := id.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+	maxRetries := q.options.MaxRetries
+	if options.MaxRetries > 0 {
+		maxRetries = options.MaxRetries
+	} else if options.MaxRetries < 0 {
+		maxRetries = 0
+	}
+	now := time.Now()
+	t := &Task{
+		ID:         taskID,
+		Type:       taskType,
+		Payload:    data,
+		MaxRetries: maxRetries,
+		CreatedAt:  now,
+		RunAt:      now.Add(options.Delay),
+	}
+	if err := q.options.Backend.Push(ctx, q.options.Queue, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// DeadTasks 返回死信队列中的任务
+func (q *Queue) DeadTasks(ctx context.Context) ([]*Task, error) {
+	return q.options.Backend.Dead(ctx, q.options.Queue)
+}
+
+// Name 返回模块名称
+func (q *Queue) Name() string {
+	return "tasks"
+}
+
+// Start 启动 worker，按 Concurrency 并发执行任务
+func (q *Queue) Start(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.cancel != nil {
+		return errors.New("tasks: 队列已启动")
+	}
+	// worker 的生命周期由 Stop 控制，不随启动时的 ctx 结束
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	q.cancel = cancel
+	for i := 0; i < q.options.Concurrency; i++ {
+		q.wg.Add(1)
+		go q.work(runCtx)
+	}
+	return nil
+}
+
+// Stop 停止获取新任务并等待正在执行的任务结束
+// ctx: 控制等待时间，超时后返回错误，正在执行的任务继续在后台完成
+func (q *Queue) Stop(ctx context.Context) error {
+	q.mu.Lock()
+	cancel := q.cancel
+	q.cancel = nil
+	q.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// work 循环获取并执行任务，直到 ctx 结束
+func (q *Queue) work(ctx context.Context) {
+	defer q.wg.Done()
+	for ctx.Err() == nil {
+		t, err := q.options.Backend.Pop(ctx, q.options.Queue, q.options.PollInterval)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Module("tasks").Warn("[Tasks] 获取任务失败：%v", err)
+				sleep(ctx, q.options.PollInterval)
+			}
+			continue
+		}
+		if t != nil {
+			q.process(context.WithoutCancel(ctx), t)
+		}
+	}
+}
+
+// process 执行一个任务，根据结果确认、重试或移入死信队列
+// 已取出的任务不受 Stop 取消，保证执行结果能够写回后端
+func (q *Queue) process(ctx context.Context, t *Task) {
+	t.Attempts++
+	err := q.call(ctx, t)
+	backend, queue := q.options.Backend, q.options.Queue
+	log := logger.Module("tasks").With("task_id", t.ID).With("task_type", t.Type)
+	switch {
+	case err == nil:
+		err = backend.Ack(ctx, queue, t)
+	case errors.Is(err, ErrNoHandler) || t.Attempts > t.MaxRetries:
+		t.LastError = err.Error()
+		log.Error("[Tasks] 任务执行 %d 次后仍失败，移入死信队列：%v", t.Attempts, err)
+		err = backend.Bury(ctx, queue, t)
+	default:
+		t.LastError = err.Error()
+		wait := q.backoff(t.Attempts)
+		t.RunAt = time.Now().Add(wait)
+		log.Warn("[Tasks] 任务执行失败，%v 后进行第 %d 次重试：%v", wait, t.Attempts, err)
+		err = backend.Retry(ctx, queue, t)
+	}
+	if err != nil {
+		log.Error("[Tasks] 写回任务状态失败：%v", err)
+	}
+}
+
+// call 调用处理函数，将 panic 恢复为错误
+func (q *Queue) call(ctx context.Context, t *Task) (err error) {
+	q.mu.RLock()
+	handler := q.handlers[t.Type]
+	q.mu.RUnlock()
+	if handler == nil {
+		return ErrNoHandler
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+			logger.Module("tasks").Error("[Tasks] 任务 %s panic：%v\n%s", t.ID, r, debug.Stack())
+		}
+	}()
+	if q.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, q.options.Timeout)
+		defer cancel()
+	}
+	return handler(ctx, t)
+}
+
+// backoff 返回第 retry 次重试前的等待时间
+func (q *Queue) backoff(retry int) time.Duration {
+	if q.options.Backoff != nil {
+		return q.options.Backoff(retry)
+	}
+	d := time.Second
+	for i := 1; i < retry && d < 10*time.Minute; i++ {
+		d *= 2
+	}
+	return min(d, 10*time.Minute)
+}
+
+// sleep 等待 d 或直到 ctx 结束
+func sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,242 @@
+// Package testutil 提供了路由级集成测试辅助工具
+// 通过 httptest.Server 在进程内启动引擎，并提供便捷的请求构造和响应断言方法
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/xzl-go/easygo/core"
+)
+
+// Server 是进程内运行的测试服务器
+type Server struct {
+	*httptest.Server
+	t      testing.TB
+	client *http.Client
+	header http.Header // 每个请求默认携带的请求头
+}
+
+// NewServer 启动测试服务器，测试结束时自动关闭
+// t: 测试对象
+// handler: 被测引擎，通常为 *core.Engine
+func NewServer(t testing.TB, handler http.Handler) *Server {
+	t.Helper()
+	ts := httptest.NewServer(handler)
+	jar, _ := cookiejar.New(nil)
+	client := ts.Client()
+	client.Jar = jar
+	// 不自动跟随重定向，便于断言 3xx 响应
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	s := &Server{Server: ts, t: t, client: client, header: make(http.Header)}
+	t.Cleanup(s.Close)
+	return s
+}
+
+// SetHeader 设置每个请求默认携带的请求头，例如认证令牌
+func (s *Server) SetHeader(key, value string) *Server {
+	s.header.Set(key, value)
+	return s
+}
+
+// Request 是待发送的测试请求
+type Request struct {
+	server *Server
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   io.Reader
+}
+
+// NewRequest 构造测试请求
+// method: 请求方法
+// path: 请求路径，例如 "/users/1"
+func (s *Server) NewRequest(method, path string) *Request {
+	return &Request{
+		server: s,
+		method: method,
+		path:   path,
+		query:  make(url.Values),
+		header: s.header.Clone(),
+	}
+}
+
+// GET 发送 GET 请求
+func (s *Server) GET(path string) *Response {
+	return s.NewRequest(http.MethodGet, path).Do()
+}
+
+// DELETE 发送 DELETE 请求
+func (s *Server) DELETE(path string) *Response {
+	return s.NewRequest(http.MethodDelete, path).Do()
+}
+
+// POST 以 JSON 格式发送 POST 请求
+func (s *Server) POST(path string, body interface{}) *Response {
+	return s.NewRequest(http.MethodPost, path).JSON(body).Do()
+}
+
+// PUT 以 JSON 格式发送 PUT 请求
+func (s *Server) PUT(path string, body interface{}) *Response {
+	return s.NewRequest(http.MethodPut, path).JSON(body).Do()
+}
+
+// Header 设置请求头
+func (r *Request) Header(key, value string) *Request {
+	r.header.Set(key, value)
+	return r
+}
+
+// Query 设置查询参数
+func (r *Request) Query(key, value string) *Request {
+	r.query.Add(key, value)
+	return r
+}
+
+// JSON 设置 JSON 请求体
+func (r *Request) JSON(body interface{}) *Request {
+	data, err := json.Marshal(body)
+	if err != nil {
+		r.server.t.Fatalf("testutil: 序列化请求体失败: %v", err)
+	}
+	r.body = bytes.NewReader(data)
+	r.header.Set("Content-Type", "application/json")
+	return r
+}
+
+// Form 设置表单请求体
+func (r *Request) Form(values url.Values) *Request {
+	r.body = strings.NewReader(values.Encode())
+	r.header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+// Body 设置原始请求体
+func (r *Request) Body(contentType string, body io.Reader) *Request {
+	r.body = body
+	r.header.Set("Content-Type", contentType)
+	return r
+}
+
+// Do 发送请求并读取完整响应
+func (r *Request) Do() *Response {
+	t := r.server.t
+	t.Helper()
+
+	target := r.server.URL + r.path
+	if len(r.query) > 0 {
+		sep := "?"
+		if strings.Contains(r.path, "?") {
+			sep = "&"
+		}
+		target += sep + r.query.Encode()
+	}
+
+	req, err := http.NewRequest(r.method, target, r.body)
+	if err != nil {
+		t.Fatalf("testutil: 构造请求失败: %v", err)
+	}
+	req.Header = r.header
+
+	resp, err := r.server.client.Do(req)
+	if err != nil {
+		t.Fatalf("testutil: 请求 %s %s 失败: %v", r.method, r.path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("testutil: 读取响应失败: %v", err)
+	}
+	return &Response{t: t, StatusCode: resp.StatusCode, Header: resp.Header, Body: body}
+}
+
+// Response 是测试响应
+type Response struct {
+	t          testing.TB
+	StatusCode int         // 响应状态码
+	Header     http.Header // 响应头
+	Body       []byte      // 响应体
+}
+
+// String 返回响应体字符串
+func (r *Response) String() string {
+	return string(r.Body)
+}
+
+// Decode 将响应体解析为 JSON
+func (r *Response) Decode(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// Envelope 将响应体解析为统一响应结构
+// data: 用于接收 data 字段的目标对象，可为 nil
+func (r *Response) Envelope(data interface{}) core.Response {
+	r.t.Helper()
+	var env struct {
+		core.Response
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(r.Body, &env); err != nil {
+		r.t.Fatalf("testutil: 解析统一响应失败: %v, body=%s", err, r.Body)
+	}
+	if data != nil && len(env.Data) > 0 {
+		if err := json.Unmarshal(env.Data, data); err != nil {
+			r.t.Fatalf("testutil: 解析 data 字段失败: %v", err)
+		}
+	}
+	env.Response.Data = data
+	return env.Response
+}
+
+// ExpectStatus 断言响应状态码
+func (r *Response) ExpectStatus(code int) *Response {
+	r.t.Helper()
+	if r.StatusCode != code {
+		r.t.Errorf("testutil: 期望状态码 %d，实际为 %d, body=%s", code, r.StatusCode, r.Body)
+	}
+	return r
+}
+
+// ExpectHeader 断言响应头
+func (r *Response) ExpectHeader(key, value string) *Response {
+	r.t.Helper()
+	if got := r.Header.Get(key); got != value {
+		r.t.Errorf("testutil: 期望响应头 %s=%q，实际为 %q", key, value, got)
+	}
+	return r
+}
+
+// ExpectCode 断言统一响应中的业务错误码
+func (r *Response) ExpectCode(code int) *Response {
+	r.t.Helper()
+	if env := r.Envelope(nil); env.Code != code {
+		r.t.Errorf("testutil: 期望业务错误码 %d，实际为 %d（%s）", code, env.Code, env.Message)
+	}
+	return r
+}
+
+// ExpectBodyContains 断言响应体包含指定内容
+func (r *Response) ExpectBodyContains(substr string) *Response {
+	r.t.Helper()
+	if !strings.Contains(string(r.Body), substr) {
+		r.t.Errorf("testutil: 响应体不包含 %q, body=%s", substr, r.Body)
+	}
+	return r
+}
+
+// Dump 返回便于调试输出的响应描述
+func (r *Response) Dump() string {
+	return fmt.Sprintf("%d %v\n%s", r.StatusCode, r.Header, r.Body)
+}
@@ -0,0 +1,221 @@
+// Package totp 提供了基于 RFC 6238 的 TOTP 两步验证功能
+// 支持密钥生成、otpauth:// 配置地址、带时间漂移窗口的校验以及恢复码
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Algorithm 定义了 HMAC 哈希算法
+type Algorithm string
+
+// 支持的哈希算法
+const (
+	SHA1   Algorithm = "SHA1"
+	SHA256 Algorithm = "SHA256"
+	SHA512 Algorithm = "SHA512"
+)
+
+// ErrInvalidSecret 表示密钥不是合法的 Base32 字符串
+var ErrInvalidSecret = errors.New("totp: 无效的密钥")
+
+// b32 是不带填充的 Base32 编码
+var b32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Config 定义了 TOTP 配置
+type Config struct {
+	Issuer    string    // 签发方名称，显示在验证器 App 中
+	Digits    int       // 验证码位数，默认 6
+	Period    uint      // 时间步长（秒），默认 30
+	Skew      uint      // 允许前后漂移的时间步数，建议设置为 1
+	Algorithm Algorithm // 哈希算法，默认 SHA1（多数验证器 App 仅支持 SHA1）
+}
+
+// TOTP 是两步验证管理器
+type TOTP struct {
+	config Config
+}
+
+// New 创建一个 TOTP 管理器
+// config: TOTP 配置，未设置的字段使用默认值
+func New(config Config) *TOTP {
+	if config.Digits <= 0 {
+		config.Digits = 6
+	}
+	if config.Period == 0 {
+		config.Period = 30
+	}
+	if config.Algorithm == "" {
+		config.Algorithm = SHA1
+	}
+	return &TOTP{config: config}
+}
+
+// GenerateSecret 生成随机密钥
+// 返回 Base32 编码（无填充）的 20 字节密钥
+func GenerateSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return b32.EncodeToString(b), nil
+}
+
+// ProvisioningURI 生成 otpauth:// 配置地址，可直接作为二维码内容供验证器 App 扫描
+// account: 用户账号（如邮箱或用户名）
+// secret: Base32 密钥
+func (t *TOTP) ProvisioningURI(account, secret string) string {
+	label := url.PathEscape(account)
+	if t.config.Issuer != "" {
+		label = url.PathEscape(t.config.Issuer) + ":" + label
+	}
+	v := url.Values{}
+	v.Set("secret", secret)
+	if t.config.Issuer != "" {
+		v.Set("issuer", t.config.Issuer)
+	}
+	v.Set("algorithm", string(t.config.Algorithm))
+	v.Set("digits", fmt.Sprint(t.config.Digits))
+	v.Set("period", fmt.Sprint(t.config.Period))
+	return "otpauth://totp/" + label + "?" + v.Encode()
+}
+
+// GenerateCode 生成指定时间的验证码
+// secret: Base32 密钥
+// at: 时间点
+func (t *TOTP) GenerateCode(secret string, at time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return t.hotp(key, t.counter(at)), nil
+}
+
+// Validate 校验当前时间的验证码，允许前后 Skew 个时间步的漂移
+// secret: Base32 密钥
+// code: 用户输入的验证码
+func (t *TOTP) Validate(secret, code string) bool {
+	_, ok := t.ValidateAt(secret, code, time.Now())
+	return ok
+}
+
+// ValidateAt 校验指定时间的验证码
+// 返回匹配的时间步计数器，调用方可记录该值以拒绝同一验证码的重复使用
+func (t *TOTP) ValidateAt(secret, code string, at time.Time) (uint64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != t.config.Digits {
+		return 0, false
+	}
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return 0, false
+	}
+	current := t.counter(at)
+	skew := uint64(t.config.Skew)
+	for c := current - min(skew, current); c <= current+skew; c++ {
+		if subtle.ConstantTimeCompare([]byte(t.hotp(key, c)), []byte(code)) == 1 {
+			return c, true
+		}
+	}
+	return 0, false
+}
+
+// counter 计算时间步计数器
+func (t *TOTP) counter(at time.Time) uint64 {
+	return uint64(at.Unix()) / uint64(t.config.Period)
+}
+
+// hotp 按 RFC 4226 计算一次性密码
+func (t *TOTP) hotp(key []byte, counter uint64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	mac := hmac.New(t.hashFunc(), key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < t.config.Digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", t.config.Digits, value%mod)
+}
+
+// hashFunc 返回配置的哈希函数
+func (t *TOTP) hashFunc() func() hash.Hash {
+	switch t.config.Algorithm {
+	case SHA256:
+		return sha256.New
+	case SHA512:
+		return sha512.New
+	default:
+		return sha1.New
+	}
+}
+
+// decodeSecret 解码 Base32 密钥，兼容小写、空格和填充字符
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	secret = strings.TrimRight(secret, "=")
+	key, err := b32.DecodeString(secret)
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return key, nil
+}
+
+// GenerateRecoveryCodes 生成一组恢复码
+// n: 恢复码数量
+// 返回明文恢复码（仅展示给用户一次）和用于持久化的哈希值
+func GenerateRecoveryCodes(n int) (codes, hashes []string, err error) {
+	codes = make([]string, n)
+	hashes = make([]string, n)
+	for i := 0; i < n; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(b32.EncodeToString(b))
+		codes[i] = raw[:4] + "-" + raw[4:]
+		hashes[i] = HashRecoveryCode(codes[i])
+	}
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode 计算恢复码的哈希值（忽略大小写和连字符）
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyRecoveryCode 校验恢复码
+// code: 用户输入的恢复码
+// hashes: 已保存的恢复码哈希列表
+// 返回匹配的下标，调用方应在使用后将其删除；未匹配时返回 -1
+func VerifyRecoveryCode(code string, hashes []string) int {
+	h := []byte(HashRecoveryCode(code))
+	for i, stored := range hashes {
+		if subtle.ConstantTimeCompare(h, []byte(stored)) == 1 {
+			return i
+		}
+	}
+	return -1
+}
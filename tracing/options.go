@@ -0,0 +1,184 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// 导出器类型
+const (
+	ExporterStdout   = "stdout"    // 输出到标准输出，用于调试
+	ExporterOTLPGRPC = "otlp-grpc" // OTLP gRPC，默认地址 localhost:4317
+	ExporterOTLPHTTP = "otlp-http" // OTLP HTTP，默认地址 localhost:4318
+	ExporterJaeger   = "jaeger"    // Jaeger（1.35 及以上版本原生接收 OTLP），使用 OTLP gRPC 发送到 Jaeger 的 4317 端口
+	ExporterNone     = "none"      // 不导出，只在进程内传播追踪上下文
+)
+
+// 采样策略
+const (
+	SamplerAlways = "always" // 全部采样
+	SamplerNever  = "never"  // 全部不采样
+	SamplerRatio  = "ratio"  // 按 SampleRatio 比例采样
+)
+
+// TracerOptions 定义了追踪器配置
+type TracerOptions struct {
+	// ServiceName 服务名称，用于标识追踪来源
+	ServiceName string
+	// ServiceVersion 服务版本，写入资源属性 service.version
+	ServiceVersion string
+	// Environment 部署环境，例如 "production"，写入资源属性 deployment.environment
+	Environment string
+	// Attributes 附加的资源属性，例如 {"team": "payment"}
+	Attributes map[string]string
+
+	// Exporter 导出器类型，默认 ExporterStdout
+	Exporter string
+	// Endpoint 导出地址，例如 "otel-collector:4317" 或 "https://collector.example.com/v1/traces"，为空时使用导出器的默认地址
+	Endpoint string
+	// Insecure 是否使用不加密的连接，Endpoint 带 http:// 前缀时自动启用
+	Insecure bool
+	// Headers 导出请求附加的头，例如认证令牌
+	Headers map[string]string
+
+	// Sampler 采样策略，默认 SamplerAlways
+	Sampler string
+	// SampleRatio SamplerRatio 的采样比例，取值 0~1
+	SampleRatio float64
+	// ParentBased 是否优先遵循上游服务的采样决定，只对没有父跨度的请求应用 Sampler
+	ParentBased bool
+}
+
+// NewTracerWithOptions 按配置创建追踪器，并设置为全局追踪器提供者和 W3C traceparent/baggage 传播器
+// options: 追踪器配置
+func NewTracerWithOptions(options TracerOptions) (*Tracer, error) {
+	if options.ServiceName == "" {
+		options.ServiceName = "easygo"
+	}
+	exporter, err := newExporter(options)
+	if err != nil {
+		return nil, err
+	}
+	sampler, err := newSampler(options)
+	if err != nil {
+		return nil, err
+	}
+	res, err := newResource(options)
+	if err != nil {
+		return nil, err
+	}
+
+	tpOptions := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sampler),
+		sdktrace.WithResource(res),
+	}
+	if exporter != nil {
+		tpOptions = append(tpOptions, sdktrace.WithBatcher(exporter)) // 使用批处理器导出追踪数据
+	}
+	tp := sdktrace.NewTracerProvider(tpOptions...)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return &Tracer{
+		tracer:     tp,
+		tracerName: options.ServiceName,
+	}, nil
+}
+
+// newExporter 按导出器类型创建导出器，ExporterNone 时返回 nil
+func newExporter(options TracerOptions) (sdktrace.SpanExporter, error) {
+	ctx := context.Background()
+	endpoint := options.Endpoint
+	insecure := options.Insecure || strings.HasPrefix(endpoint, "http://")
+
+	switch options.Exporter {
+	case "", ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterOTLPGRPC, ExporterJaeger:
+		var opts []otlptracegrpc.Option
+		if strings.Contains(endpoint, "://") {
+			opts = append(opts, otlptracegrpc.WithEndpointURL(endpoint))
+		} else if endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(endpoint))
+		}
+		if insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		if len(options.Headers) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(options.Headers))
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case ExporterOTLPHTTP:
+		var opts []otlptracehttp.Option
+		if strings.Contains(endpoint, "://") {
+			opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+		} else if endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(endpoint))
+		}
+		if insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if len(options.Headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(options.Headers))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	case ExporterNone:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("tracing: 未知的导出器 %q", options.Exporter)
+}
+
+// newSampler 按采样策略创建采样器
+func newSampler(options TracerOptions) (sdktrace.Sampler, error) {
+	var sampler sdktrace.Sampler
+	switch options.Sampler {
+	case "", SamplerAlways:
+		sampler = sdktrace.AlwaysSample()
+	case SamplerNever:
+		sampler = sdktrace.NeverSample()
+	case SamplerRatio:
+		if options.SampleRatio < 0 || options.SampleRatio > 1 {
+			return nil, fmt.Errorf("tracing: 采样比例 %v 超出范围 0~1", options.SampleRatio)
+		}
+		sampler = sdktrace.TraceIDRatioBased(options.SampleRatio)
+	default:
+		return nil, fmt.Errorf("tracing: 未知的采样策略 %q", options.Sampler)
+	}
+	if options.ParentBased {
+		sampler = sdktrace.ParentBased(sampler)
+	}
+	return sampler, nil
+}
+
+// newResource 创建描述服务的资源，环境变量 OTEL_RESOURCE_ATTRIBUTES 中的属性也会被合并
+func newResource(options TracerOptions) (*resource.Resource, error) {
+	attrs := []attribute.KeyValue{semconv.ServiceName(options.ServiceName)}
+	if options.ServiceVersion != "" {
+		attrs = append(attrs, semconv.ServiceVersion(options.ServiceVersion))
+	}
+	if options.Environment != "" {
+		attrs = append(attrs, semconv.DeploymentEnvironment(options.Environment))
+	}
+	for k, v := range options.Attributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	return resource.New(context.Background(),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithAttributes(attrs...),
+	)
+}
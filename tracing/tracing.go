@@ -5,8 +5,6 @@ package tracing
 import (
 	"context"
 
-	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -18,36 +16,33 @@ type Tracer struct {
 	tracerName string               // 服务名称
 }
 
-// NewTracer 创建一个新的追踪器
+// NewTracer 创建一个新的追踪器，追踪数据输出到标准输出，用于调试
+// 导出到 OTLP 收集器或 Jaeger 时使用 NewTracerWithOptions
 // serviceName: 服务名称，用于标识追踪来源
 func NewTracer(serviceName string) *Tracer {
-	// 创建标准输出导出器，用于调试
-	exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+	t, err := NewTracerWithOptions(TracerOptions{
+		ServiceName: serviceName,
+		Exporter:    ExporterStdout,
+		ParentBased: true,
+	})
 	if err != nil {
 		panic(err)
 	}
-
-	// 创建追踪器提供者
-	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter), // 使用批处理器导出追踪数据
-	)
-
-	// 设置全局追踪器提供者
-	otel.SetTracerProvider(tp)
-
-	return &Tracer{
-		tracer:     tp,
-		tracerName: serviceName,
-	}
+	return t
 }
 
 // StartSpan 开始一个新的追踪跨度
 // ctx: 上下文
 // spanName: 跨度名称
+// opts: 跨度选项，例如 trace.WithSpanKind、trace.WithAttributes
 // 返回新的上下文和追踪跨度
-func (t *Tracer) StartSpan(ctx context.Context, spanName string) (context.Context, trace.Span) {
-	tracer := t.tracer.Tracer(t.tracerName)
-	return tracer.Start(ctx, spanName)
+func (t *Tracer) StartSpan(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return t.Tracer().Start(ctx, spanName, opts...)
+}
+
+// Tracer 返回以服务名称命名的 OpenTelemetry 追踪器
+func (t *Tracer) Tracer() trace.Tracer {
+	return t.tracer.Tracer(t.tracerName)
 }
 
 // EndSpan 结束追踪跨度
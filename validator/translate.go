@@ -0,0 +1,167 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Translator 定义了校验消息翻译器，*i18n.I18n 实现了该接口
+type Translator interface {
+	Translate(key, lang string) string
+}
+
+// FieldError 是单个字段的校验错误，可直接作为 JSON 响应返回
+type FieldError struct {
+	Field   string `json:"field"`           // 字段名，嵌套字段为 "Address.City"
+	Tag     string `json:"tag"`             // 未通过的规则，例如 required、min
+	Param   string `json:"param,omitempty"` // 规则参数，例如 min=3 中的 3
+	Message string `json:"message"`         // 翻译后的错误消息
+}
+
+// ValidationErrors 是一组字段校验错误
+type ValidationErrors []FieldError
+
+// Error 实现 error 接口，返回以分号分隔的错误消息
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Translate 将 Validate 返回的错误转换为按语言翻译的字段错误
+// 消息模板按顺序查找：翻译器中的 validation.<规则>、内置的中英文模板、通用模板；
+// 长度类规则（min、max、len 等）作用于字符串、切片和 map 时优先查找 validation.<规则>.length；
+// 模板中的 {field} 和 {param} 替换为字段名和规则参数
+// err: Validate 返回的错误，不是校验错误时返回 nil
+// translator: 翻译器，可为 nil
+// lang: 语言，例如 "zh"、"en-US"，也可以直接传入 Accept-Language 请求头
+func Translate(err error, translator Translator, lang string) ValidationErrors {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return nil
+	}
+	lang = normalizeLang(lang)
+	result := make(ValidationErrors, 0, len(errs))
+	for _, fe := range errs {
+		field := fieldPath(fe)
+		tmpl := lookupTemplate(translator, lang, fe.Tag(), isLengthKind(fe.Kind()))
+		message := strings.NewReplacer("{field}", field, "{param}", fe.Param(), "{tag}", fe.Tag()).Replace(tmpl)
+		result = append(result, FieldError{Field: field, Tag: fe.Tag(), Param: fe.Param(), Message: message})
+	}
+	return result
+}
+
+// fieldPath 返回去掉顶层结构体名的字段路径，例如 User.Address.City 返回 Address.City
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.IndexByte(ns, '.'); i >= 0 {
+		return ns[i+1:]
+	}
+	return fe.Field()
+}
+
+// isLengthKind 判断长度类规则是否按长度校验
+func isLengthKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
+
+// lookupTemplate 查找规则的消息模板
+func lookupTemplate(translator Translator, lang, tag string, length bool) string {
+	keys := []string{"validation." + tag}
+	if length {
+		keys = append([]string{"validation." + tag + ".length"}, keys...)
+	}
+	if translator != nil {
+		for _, key := range keys {
+			if tmpl := translator.Translate(key, lang); tmpl != key && tmpl != "" {
+				return tmpl
+			}
+		}
+	}
+	base, _, _ := strings.Cut(lang, "-")
+	for _, l := range []string{lang, base, "en"} {
+		if templates, ok := defaultTemplates[l]; ok {
+			for _, key := range keys {
+				if tmpl, ok := templates[key]; ok {
+					return tmpl
+				}
+			}
+			if tmpl, ok := templates["validation.default"]; ok {
+				return tmpl
+			}
+		}
+	}
+	return "{field} failed on the '{tag}' rule"
+}
+
+// normalizeLang 从 Accept-Language 等形式中取出首选语言，例如 "zh-CN,zh;q=0.9" 返回 "zh-cn"
+func normalizeLang(lang string) string {
+	lang, _, _ = strings.Cut(lang, ",")
+	lang, _, _ = strings.Cut(lang, ";")
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
+
+// defaultTemplates 是内置的消息模板，翻译器中没有对应键时使用
+var defaultTemplates = map[string]map[string]string{
+	"en": {
+		"validation.default":    "{field} failed on the '{tag}' rule",
+		"validation.required":   "{field} is required",
+		"validation.email":      "{field} must be a valid email address",
+		"validation.url":        "{field} must be a valid URL",
+		"validation.uuid":       "{field} must be a valid UUID",
+		"validation.numeric":    "{field} must be numeric",
+		"validation.alphanum":   "{field} must contain only letters and numbers",
+		"validation.oneof":      "{field} must be one of [{param}]",
+		"validation.eqfield":    "{field} must be equal to {param}",
+		"validation.nefield":    "{field} must not be equal to {param}",
+		"validation.gtfield":    "{field} must be greater than {param}",
+		"validation.gtefield":   "{field} must be greater than or equal to {param}",
+		"validation.ltfield":    "{field} must be less than {param}",
+		"validation.ltefield":   "{field} must be less than or equal to {param}",
+		"validation.len":        "{field} must be {param}",
+		"validation.len.length": "{field} must be {param} characters long",
+		"validation.min":        "{field} must be at least {param}",
+		"validation.min.length": "{field} must be at least {param} characters long",
+		"validation.max":        "{field} must be at most {param}",
+		"validation.max.length": "{field} must be at most {param} characters long",
+		"validation.gt":         "{field} must be greater than {param}",
+		"validation.gte":        "{field} must be greater than or equal to {param}",
+		"validation.lt":         "{field} must be less than {param}",
+		"validation.lte":        "{field} must be less than or equal to {param}",
+	},
+	"zh": {
+		"validation.default":    "{field} 未通过 {tag} 校验",
+		"validation.required":   "{field} 为必填字段",
+		"validation.email":      "{field} 必须是有效的邮箱地址",
+		"validation.url":        "{field} 必须是有效的 URL",
+		"validation.uuid":       "{field} 必须是有效的 UUID",
+		"validation.numeric":    "{field} 必须是数字",
+		"validation.alphanum":   "{field} 只能包含字母和数字",
+		"validation.oneof":      "{field} 必须是 [{param}] 中的一个",
+		"validation.eqfield":    "{field} 必须与 {param} 相同",
+		"validation.nefield":    "{field} 不能与 {param} 相同",
+		"validation.gtfield":    "{field} 必须大于 {param}",
+		"validation.gtefield":   "{field} 必须大于或等于 {param}",
+		"validation.ltfield":    "{field} 必须小于 {param}",
+		"validation.ltefield":   "{field} 必须小于或等于 {param}",
+		"validation.len":        "{field} 必须等于 {param}",
+		"validation.len.length": "{field} 的长度必须为 {param}",
+		"validation.min":        "{field} 不能小于 {param}",
+		"validation.min.length": "{field} 的长度不能少于 {param}",
+		"validation.max":        "{field} 不能大于 {param}",
+		"validation.max.length": "{field} 的长度不能超过 {param}",
+		"validation.gt":         "{field} 必须大于 {param}",
+		"validation.gte":        "{field} 必须大于或等于 {param}",
+		"validation.lt":         "{field} 必须小于 {param}",
+		"validation.lte":        "{field} 必须小于或等于 {param}",
+	},
+}
@@ -3,15 +3,104 @@
 package validator
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+
 	"github.com/go-playground/validator/v10"
 )
 
-// validate 是全局验证器实例
+// Validator 是独立的验证器实例，自定义规则只对该实例生效，可通过 Engine.SetValidator 挂到引擎上
+type Validator struct {
+	validate *validator.Validate
+}
+
+// validate 是全局验证器实例，错误中的字段名为 Go 字段名
 var validate *validator.Validate
 
 // init 初始化验证器
 func init() {
-	validate = validator.New()
+	validate = newValidate()
+}
+
+// newValidate 创建验证器并注册 database/sql 空值类型
+func newValidate() *validator.Validate {
+	v := validator.New()
+	v.RegisterCustomTypeFunc(valuerType,
+		sql.NullString{}, sql.NullInt64{}, sql.NullInt32{}, sql.NullInt16{}, sql.NullByte{},
+		sql.NullFloat64{}, sql.NullBool{}, sql.NullTime{})
+	return v
+}
+
+// valuerType 按 driver.Valuer 的值校验，例如 sql.NullString 无效时视为空值
+func valuerType(field reflect.Value) interface{} {
+	if valuer, ok := field.Interface().(driver.Valuer); ok {
+		if value, err := valuer.Value(); err == nil {
+			return value
+		}
+	}
+	return nil
+}
+
+// New 创建验证器实例，错误中的字段名取自 json 标签，例如 Addr.City 的 json 标签为 address.city 时字段名为 address.city
+// 已注册 sql.NullString 等 database/sql 空值类型：Valid 为 false 时视为空值
+func New() *Validator {
+	v := &Validator{validate: newValidate()}
+	v.SetFieldNameTag("json")
+	return v
+}
+
+// Validate 验证结构体
+// obj: 要验证的结构体实例
+func (v *Validator) Validate(obj interface{}) error {
+	return v.validate.Struct(obj)
+}
+
+// Var 按规则验证单个值，例如 v.Var(email, "required,email")
+// field: 要验证的值
+// tag: 验证规则
+func (v *Validator) Var(field interface{}, tag string) error {
+	return v.validate.Var(field, tag)
+}
+
+// RegisterValidation 注册自定义验证规则
+// tag: 验证标签名
+// fn: 验证函数
+func (v *Validator) RegisterValidation(tag string, fn validator.Func) error {
+	return v.validate.RegisterValidation(tag, fn)
+}
+
+// RegisterStructValidation 注册结构体级别的验证，用于跨字段规则，例如结束时间必须晚于开始时间
+// 验证失败时在函数中调用 sl.ReportError(field, fieldName, structFieldName, tag, param)
+// fn: 验证函数
+// types: 适用的结构体类型的示例值，例如 DateRange{}
+func (v *Validator) RegisterStructValidation(fn validator.StructLevelFunc, types ...interface{}) {
+	v.validate.RegisterStructValidation(fn, types...)
+}
+
+// RegisterCustomTypeFunc 注册自定义类型的取值函数，校验时按返回的值应用规则
+// 例如金额类型返回其数值，使 gte=0 等规则生效
+// fn: 取值函数
+// types: 适用的类型的示例值
+func (v *Validator) RegisterCustomTypeFunc(fn validator.CustomTypeFunc, types ...interface{}) {
+	v.validate.RegisterCustomTypeFunc(fn, types...)
+}
+
+// SetFieldNameTag 设置错误中字段名的来源标签，例如 "json"、"form"；为空时使用 Go 字段名
+// 标签值为 "-" 或未设置标签的字段使用 Go 字段名
+// tag: 标签名
+func (v *Validator) SetFieldNameTag(tag string) {
+	v.validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		if tag == "" {
+			return field.Name
+		}
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "" || name == "-" {
+			return field.Name
+		}
+		return name
+	})
 }
 
 // Validate 验证结构体
@@ -36,3 +125,10 @@ func RegisterValidation(tag string, fn validator.Func) error {
 func RegisterCustomValidation(tag string, fn validator.Func) error {
 	return validate.RegisterValidation(tag, fn)
 }
+
+// RegisterStructValidation 为全局验证器注册结构体级别的验证
+// fn: 验证函数
+// types: 适用的结构体类型的示例值
+func RegisterStructValidation(fn validator.StructLevelFunc, types ...interface{}) {
+	validate.RegisterStructValidation(fn, types...)
+}
@@ -0,0 +1,93 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 投递请求携带的头部
+const (
+	HeaderID        = "X-Webhook-Id"        // 投递ID，接收方可用于去重
+	HeaderEvent     = "X-Webhook-Event"     // 事件类型
+	HeaderTimestamp = "X-Webhook-Timestamp" // 签名时间戳（Unix 秒）
+	HeaderSignature = "X-Webhook-Signature" // 签名，格式为 v1=<hex>
+)
+
+// signatureVersion 签名版本前缀
+const signatureVersion = "v1="
+
+var (
+	// ErrMissingSignature 请求缺少签名头
+	ErrMissingSignature = errors.New("webhook: 缺少签名")
+	// ErrInvalidSignature 签名不匹配
+	ErrInvalidSignature = errors.New("webhook: 签名无效")
+	// ErrTimestampExpired 时间戳超出允许的误差范围，可能是重放请求
+	ErrTimestampExpired = errors.New("webhook: 时间戳已过期")
+)
+
+// Sign 计算负载签名
+// 签名内容为 "时间戳.请求体"，使用 HMAC-SHA256 计算，返回 "v1=<hex>"
+// secret: 端点密钥
+// timestamp: Unix 秒级时间戳
+// body: 请求体
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signatureVersion + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验签名，供接收方使用
+// secret: 端点密钥
+// timestamp: 请求头中的时间戳
+// signature: 请求头中的签名
+// body: 请求体
+// tolerance: 允许的时间误差，为 0 时不校验时间戳
+func Verify(secret, timestamp, signature string, body []byte, tolerance time.Duration) error {
+	if timestamp == "" || signature == "" {
+		return ErrMissingSignature
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if tolerance > 0 {
+		diff := time.Since(time.Unix(ts, 0))
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > tolerance {
+			return ErrTimestampExpired
+		}
+	}
+	if !strings.HasPrefix(signature, signatureVersion) {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, ts, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyRequest 读取请求体并校验签名，返回请求体
+// r: 接收到的 webhook 请求
+// secret: 端点密钥
+// tolerance: 允许的时间误差，建议设置为 5 分钟
+func VerifyRequest(r *http.Request, secret string, tolerance time.Duration) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := Verify(secret, r.Header.Get(HeaderTimestamp), r.Header.Get(HeaderSignature), body, tolerance); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrNotFound 端点或投递记录不存在
+var ErrNotFound = errors.New("webhook: 记录不存在")
+
+// Store 定义了端点与投递记录的存储，可基于数据库或 Redis 实现
+type Store interface {
+	// SaveEndpoint 新增或更新端点
+	SaveEndpoint(endpoint *Endpoint) error
+	// DeleteEndpoint 删除端点
+	DeleteEndpoint(id string) error
+	// GetEndpoint 获取端点
+	GetEndpoint(id string) (*Endpoint, error)
+	// ListEndpoints 获取全部端点
+	ListEndpoints() ([]*Endpoint, error)
+	// SaveDelivery 新增或更新投递记录
+	SaveDelivery(delivery *Delivery) error
+	// GetDelivery 获取投递记录
+	GetDelivery(id string) (*Delivery, error)
+	// ListDeliveries 按条件查询投递记录，按创建时间倒序
+	ListDeliveries(query DeliveryQuery) ([]*Delivery, error)
+}
+
+// DeliveryQuery 定义了投递记录查询条件，零值字段不参与过滤
+type DeliveryQuery struct {
+	EndpointID string
+	EventID    string
+	Status     Status
+	Limit      int
+}
+
+// match 判断投递记录是否满足查询条件
+func (q DeliveryQuery) match(d *Delivery) bool {
+	return (q.EndpointID == "" || d.EndpointID == q.EndpointID) &&
+		(q.EventID == "" || d.EventID == q.EventID) &&
+		(q.Status == "" || d.Status == q.Status)
+}
+
+// MemoryStore 是基于内存的存储，适用于单实例和测试
+type MemoryStore struct {
+	mu         sync.RWMutex
+	endpoints  map[string]*Endpoint
+	deliveries map[string]*Delivery
+}
+
+// NewMemoryStore 创建内存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		endpoints:  make(map[string]*Endpoint),
+		deliveries: make(map[string]*Delivery),
+	}
+}
+
+// SaveEndpoint 新增或更新端点
+func (s *MemoryStore) SaveEndpoint(endpoint *Endpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := *endpoint
+	s.endpoints[e.ID] = &e
+	return nil
+}
+
+// DeleteEndpoint 删除端点
+func (s *MemoryStore) DeleteEndpoint(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.endpoints[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.endpoints, id)
+	return nil
+}
+
+// GetEndpoint 获取端点
+func (s *MemoryStore) GetEndpoint(id string) (*Endpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e, ok := s.endpoints[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *e
+	return &copied, nil
+}
+
+// ListEndpoints 获取全部端点
+func (s *MemoryStore) ListEndpoints() ([]*Endpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]*Endpoint, 0, len(s.endpoints))
+	for _, e := range s.endpoints {
+		copied := *e
+		list = append(list, &copied)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+	return list, nil
+}
+
+// SaveDelivery 新增或更新投递记录
+func (s *MemoryStore) SaveDelivery(delivery *Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := *delivery
+	s.deliveries[d.ID] = &d
+	return nil
+}
+
+// GetDelivery 获取投递记录
+func (s *MemoryStore) GetDelivery(id string) (*Delivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	d, ok := s.deliveries[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *d
+	return &copied, nil
+}
+
+// ListDeliveries 按条件查询投递记录
+func (s *MemoryStore) ListDeliveries(query DeliveryQuery) ([]*Delivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]*Delivery, 0)
+	for _, d := range s.deliveries {
+		if query.match(d) {
+			copied := *d
+			list = append(list, &copied)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.After(list[j].CreatedAt) })
+	if query.Limit > 0 && len(list) > query.Limit {
+		list = list[:query.Limit]
+	}
+	return list, nil
+}
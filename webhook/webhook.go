@@ -0,0 +1,503 @@
+// Package webhook 提供了出站 Webhook 投递功能
+// 支持注册端点、HMAC 签名（包含时间戳防重放）、异步投递、指数退避重试、死信记录以及投递状态查询
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/xzl-go/easygo/id"
+	"github.com/xzl-go/easygo/logger"
+)
+
+// Status 是投递状态
+type Status string
+
+const (
+	StatusPending   Status = "pending"   // 等待投递或等待重试
+	StatusSucceeded Status = "succeeded" // 投递成功
+	StatusDead      Status = "dead"      // 重试耗尽，进入死信
+)
+
+// ErrClosed 投递器已关闭
+var ErrClosed = errors.New("webhook: 投递器已关闭")
+
+// Endpoint 是订阅事件的接收端点
+type Endpoint struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"-"`      // 签名密钥，为空时注册时自动生成
+	Events    []string  `json:"events"` // 订阅的事件类型，为空或包含 "*" 表示订阅全部
+	Disabled  bool      `json:"disabled"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// subscribes 判断端点是否订阅了指定事件
+func (e *Endpoint) subscribes(eventType string) bool {
+	if e.Disabled {
+		return false
+	}
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, t := range e.Events {
+		if t == "*" || t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event 是一次发布的事件
+type Event struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// Delivery 是事件到某个端点的一次投递
+type Delivery struct {
+	ID           string    `json:"id"`
+	EndpointID   string    `json:"endpoint_id"`
+	EventID      string    `json:"event_id"`
+	EventType    string    `json:"event_type"`
+	URL          string    `json:"url"`
+	Payload      []byte    `json:"-"`
+	Status       Status    `json:"status"`
+	Attempts     int       `json:"attempts"`
+	ResponseCode int       `json:"response_code,omitempty"`
+	LastError    string    `json:"last_error,omitempty"`
+	NextRetryAt  time.Time `json:"next_retry_at,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// BackoffFunc 根据已尝试次数计算下次重试的等待时间
+type BackoffFunc func(attempt int) time.Duration
+
+// ExponentialBackoff 返回指数退避函数，等待时间为 base * 2^(attempt-1)，不超过 max
+func ExponentialBackoff(base, max time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		return d
+	}
+}
+
+// Options 定义了投递器配置
+type Options struct {
+	// Client 发送请求使用的 HTTP 客户端，默认超时 10 秒
+	Client *http.Client
+	// Workers 并发投递协程数，默认 4
+	Workers int
+	// QueueSize 投递队列长度，默认 1024
+	QueueSize int
+	// MaxAttempts 最大尝试次数（包含首次投递），默认 5，超过后进入死信
+	MaxAttempts int
+	// Backoff 重试退避策略，默认从 10 秒开始指数退避，最长 1 小时
+	Backoff BackoffFunc
+	// OnDead 投递进入死信时的回调，可用于告警
+	OnDead func(delivery *Delivery)
+	// SweepInterval 扫描存储中已到重试时间的 pending 投递并重新入队的间隔，默认 30 秒
+	// 用于补发队列已满时未能入队的投递，以及进程重启前尚未完成的投递
+	SweepInterval time.Duration
+}
+
+// Dispatcher 是 Webhook 投递器
+type Dispatcher struct {
+	store   Store
+	options Options
+
+	queue  chan string
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	queued map[string]bool        // 已入队或正在投递的记录，扫描时跳过
+	timers map[string]*time.Timer // 等待重试的记录
+	closed bool
+}
+
+// New 创建投递器并启动投递协程
+// store: 端点与投递记录存储，为 nil 时使用内存存储
+// options: 投递器配置
+func New(store Store, options Options) *Dispatcher {
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	if options.Client == nil {
+		options.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if options.Workers <= 0 {
+		options.Workers = 4
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = 1024
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = 5
+	}
+	if options.Backoff == nil {
+		options.Backoff = ExponentialBackoff(10*time.Second, time.Hour)
+	}
+	if options.SweepInterval <= 0 {
+		options.SweepInterval = 30 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Dispatcher{
+		store:   store,
+		options: options,
+		queue:   make(chan string, options.QueueSize),
+		ctx:     ctx,
+		cancel:  cancel,
+		queued:  make(map[string]bool),
+		timers:  make(map[string]*time.Timer),
+	}
+	for i := 0; i < options.Workers; i++ {
+		d.wg.Add(1)
+		go d.worker()
+	}
+	d.wg.Add(1)
+	go d.sweeper()
+	return d
+}
+
+// Register 注册端点，返回保存后的端点（包含生成的 ID 和密钥）
+// endpoint: 端点配置，URL 必填
+func (d *Dispatcher) Register(endpoint Endpoint) (*Endpoint, error) {
+	if endpoint.URL == "" {
+		return nil, errors.New("webhook: 端点 URL 不能为空")
+	}
+	if endpoint.ID == "" {
+		endpointID, err := id.NewULID()
+		if err != nil {
+			return nil, err
+		}
+		endpoint.ID = endpointID
+	}
+	if endpoint.Secret == "" {
+		secret, err := GenerateSecret()
+		if err != nil {
+			return nil, err
+		}
+		endpoint.Secret = secret
+	}
+	if endpoint.CreatedAt.IsZero() {
+		endpoint.CreatedAt = time.Now()
+	}
+	if err := d.store.SaveEndpoint(&endpoint); err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+// Unregister 删除端点，已创建的投递记录仍会保留
+func (d *Dispatcher) Unregister(endpointID string) error {
+	return d.store.DeleteEndpoint(endpointID)
+}
+
+// Endpoints 返回全部端点
+func (d *Dispatcher) Endpoints() ([]*Endpoint, error) {
+	return d.store.ListEndpoints()
+}
+
+// Publish 发布事件，先为每个订阅该事件的端点保存投递记录，再加入队列异步发送
+// 队列已满时投递记录保持 pending 状态，由后台扫描重新入队
+// eventType: 事件类型，例如 "order.paid"
+// data: 事件数据，会被序列化为 JSON
+// 返回事件和创建的投递记录
+func (d *Dispatcher) Publish(eventType string, data interface{}) (*Event, []*Delivery, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	eventID, err := id.NewULID()
+	if err != nil {
+		return nil, nil, err
+	}
+	event := &Event{ID: eventID, Type: eventType, Data: raw, CreatedAt: time.Now()}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	endpoints, err := d.store.ListEndpoints()
+	if err != nil {
+		return nil, nil, err
+	}
+	var deliveries []*Delivery
+	for _, ep := range endpoints {
+		if !ep.subscribes(eventType) {
+			continue
+		}
+		deliveryID, err := id.NewULID()
+		if err != nil {
+			return event, deliveries, err
+		}
+		delivery := &Delivery{
+			ID:         deliveryID,
+			EndpointID: ep.ID,
+			EventID:    event.ID,
+			EventType:  eventType,
+			URL:        ep.URL,
+			Payload:    payload,
+			Status:     StatusPending,
+			CreatedAt:  event.CreatedAt,
+			UpdatedAt:  event.CreatedAt,
+		}
+		if err := d.store.SaveDelivery(delivery); err != nil {
+			return event, deliveries, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	for _, delivery := range deliveries {
+		if err := d.enqueue(delivery.ID); err != nil {
+			if errors.Is(err, ErrClosed) {
+				return event, deliveries, err
+			}
+			logger.Warn("%v", err)
+		}
+	}
+	return event, deliveries, nil
+}
+
+// Redeliver 重新投递一条记录（通常是死信），尝试次数会被清零
+func (d *Dispatcher) Redeliver(deliveryID string) error {
+	delivery, err := d.store.GetDelivery(deliveryID)
+	if err != nil {
+		return err
+	}
+	delivery.Status = StatusPending
+	delivery.Attempts = 0
+	delivery.NextRetryAt = time.Time{}
+	delivery.UpdatedAt = time.Now()
+	if err := d.store.SaveDelivery(delivery); err != nil {
+		return err
+	}
+	return d.enqueue(deliveryID)
+}
+
+// Delivery 查询投递状态
+func (d *Dispatcher) Delivery(deliveryID string) (*Delivery, error) {
+	return d.store.GetDelivery(deliveryID)
+}
+
+// Deliveries 按条件查询投递记录
+func (d *Dispatcher) Deliveries(query DeliveryQuery) ([]*Delivery, error) {
+	return d.store.ListDeliveries(query)
+}
+
+// DeadLetters 返回死信记录
+// limit: 返回数量上限，为 0 时不限制
+func (d *Dispatcher) DeadLetters(limit int) ([]*Delivery, error) {
+	return d.store.ListDeliveries(DeliveryQuery{Status: StatusDead, Limit: limit})
+}
+
+// Close 停止投递器，取消等待中的重试并等待进行中的投递结束
+// 未完成和被取消的投递保持 pending 状态，不计入尝试次数，重启后由后台扫描补发
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return
+	}
+	d.closed = true
+	for deliveryID, t := range d.timers {
+		t.Stop()
+		delete(d.timers, deliveryID)
+	}
+	close(d.queue)
+	d.mu.Unlock()
+
+	d.cancel()
+	d.wg.Wait()
+}
+
+// enqueue 将投递加入队列
+func (d *Dispatcher) enqueue(deliveryID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return ErrClosed
+	}
+	if d.queued[deliveryID] {
+		return nil
+	}
+	select {
+	case d.queue <- deliveryID:
+		d.queued[deliveryID] = true
+		return nil
+	default:
+		return fmt.Errorf("webhook: 投递队列已满，投递 %s 等待补发", deliveryID)
+	}
+}
+
+// sweeper 定期将已到重试时间但不在队列中的 pending 投递重新入队
+func (d *Dispatcher) sweeper() {
+	defer d.wg.Done()
+	ticker := time.NewTicker(d.options.SweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			d.sweep()
+		case <-d.ctx.Done():
+			return
+		}
+	}
+}
+
+// sweep 扫描一次 pending 投递，队列已满时停止，等待下次扫描
+func (d *Dispatcher) sweep() {
+	deliveries, err := d.store.ListDeliveries(DeliveryQuery{Status: StatusPending})
+	if err != nil {
+		logger.Error("扫描 webhook 待投递记录失败：%v", err)
+		return
+	}
+	now := time.Now()
+	for _, delivery := range deliveries {
+		if delivery.NextRetryAt.After(now) {
+			continue
+		}
+		d.mu.Lock()
+		_, waiting := d.timers[delivery.ID]
+		d.mu.Unlock()
+		if waiting {
+			continue
+		}
+		if err := d.enqueue(delivery.ID); err != nil {
+			return
+		}
+	}
+}
+
+// scheduleRetry 在退避时间后重新加入队列
+func (d *Dispatcher) scheduleRetry(deliveryID string, delay time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return
+	}
+	d.timers[deliveryID] = time.AfterFunc(delay, func() {
+		d.mu.Lock()
+		delete(d.timers, deliveryID)
+		d.mu.Unlock()
+		if err := d.enqueue(deliveryID); err != nil && !errors.Is(err, ErrClosed) {
+			logger.Error("%v", err)
+		}
+	})
+}
+
+// worker 投递协程
+func (d *Dispatcher) worker() {
+	defer d.wg.Done()
+	for deliveryID := range d.queue {
+		d.attempt(deliveryID)
+		d.mu.Lock()
+		delete(d.queued, deliveryID)
+		d.mu.Unlock()
+	}
+}
+
+// attempt 执行一次投递并更新状态
+func (d *Dispatcher) attempt(deliveryID string) {
+	delivery, err := d.store.GetDelivery(deliveryID)
+	if err != nil {
+		logger.Error("读取 webhook 投递记录 %s 失败：%v", deliveryID, err)
+		return
+	}
+	if delivery.Status != StatusPending {
+		return
+	}
+
+	endpoint, err := d.store.GetEndpoint(delivery.EndpointID)
+	if err == nil {
+		delivery.ResponseCode, err = d.send(endpoint, delivery)
+	}
+	if err != nil && d.ctx.Err() != nil {
+		// Close 取消的投递不计入尝试次数，保持 pending 等待补发
+		return
+	}
+	delivery.Attempts++
+	delivery.UpdatedAt = time.Now()
+	delivery.NextRetryAt = time.Time{}
+
+	switch {
+	case err == nil:
+		delivery.Status = StatusSucceeded
+		delivery.LastError = ""
+	case errors.Is(err, ErrNotFound) || delivery.Attempts >= d.options.MaxAttempts:
+		delivery.Status = StatusDead
+		delivery.LastError = err.Error()
+	default:
+		delivery.LastError = err.Error()
+		delivery.NextRetryAt = delivery.UpdatedAt.Add(d.options.Backoff(delivery.Attempts))
+	}
+
+	if err := d.store.SaveDelivery(delivery); err != nil {
+		logger.Error("保存 webhook 投递记录 %s 失败：%v", deliveryID, err)
+	}
+
+	switch delivery.Status {
+	case StatusDead:
+		logger.Error("webhook 投递 %s 到 %s 失败 %d 次，进入死信：%s", delivery.ID, delivery.URL, delivery.Attempts, delivery.LastError)
+		if d.options.OnDead != nil {
+			d.options.OnDead(delivery)
+		}
+	case StatusPending:
+		d.scheduleRetry(delivery.ID, time.Until(delivery.NextRetryAt))
+	}
+}
+
+// send 发送签名后的请求，2xx 视为成功
+func (d *Dispatcher) send(endpoint *Endpoint, delivery *Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "EasyGo-Webhook/1.0")
+	req.Header.Set(HeaderID, delivery.ID)
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(endpoint.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.options.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook: 端点返回状态码 %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// GenerateSecret 生成随机端点密钥
+func GenerateSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
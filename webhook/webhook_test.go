@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// waitStatus 等待投递记录进入指定状态
+func waitStatus(t *testing.T, d *Dispatcher, deliveryID string, status Status) *Delivery {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		delivery, err := d.Delivery(deliveryID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if delivery.Status == status {
+			return delivery
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("投递 %s 的状态为 %s，期望 %s", deliveryID, delivery.Status, status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestPublishQueueFull(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	d := New(nil, Options{Workers: 1, QueueSize: 1, SweepInterval: 20 * time.Millisecond})
+	defer d.Close()
+	for i := 0; i < 4; i++ {
+		if _, err := d.Register(Endpoint{URL: srv.URL}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 队列已满时仍为每个端点创建投递记录，由后台扫描补发
+	_, deliveries, err := d.Publish("order.paid", map[string]int{"id": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 4 {
+		t.Fatalf("创建了 %d 条投递记录，期望 4 条", len(deliveries))
+	}
+	for _, delivery := range deliveries {
+		if got := waitStatus(t, d, delivery.ID, StatusSucceeded); got.Attempts != 1 {
+			t.Errorf("投递 %s 尝试了 %d 次，期望 1 次", delivery.ID, got.Attempts)
+		}
+	}
+}
+
+func TestCloseCancelsWithoutFailing(t *testing.T) {
+	received := make(chan struct{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 读完请求体后服务端才能感知客户端断开
+		io.Copy(io.Discard, r.Body)
+		received <- struct{}{}
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	d := New(nil, Options{MaxAttempts: 1})
+	if _, err := d.Register(Endpoint{URL: srv.URL}); err != nil {
+		t.Fatal(err)
+	}
+	_, deliveries, err := d.Publish("order.paid", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-received:
+	case <-time.After(3 * time.Second):
+		t.Fatal("端点没有收到投递")
+	}
+	d.Close()
+
+	delivery, err := d.Delivery(deliveries[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delivery.Status != StatusPending || delivery.Attempts != 0 {
+		t.Errorf("Close 取消的投递状态为 %s、尝试 %d 次，期望 pending、0 次", delivery.Status, delivery.Attempts)
+	}
+}
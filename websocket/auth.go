@@ -0,0 +1,80 @@
+package websocket
+
+import (
+	"errors"
+	"strings"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+
+	"github.com/xzl-go/easygo/core"
+	"github.com/xzl-go/easygo/jwt"
+	"github.com/xzl-go/easygo/middleware"
+)
+
+// AuthFunc 在升级前校验请求，返回的载荷保存在连接上；返回错误时拒绝升级并按统一响应返回错误
+type AuthFunc func(c *core.Context) (*jwt.Claims, error)
+
+// JWTAuth 返回从请求中读取并校验 JWT 的认证函数
+// 浏览器的 WebSocket API 不能设置请求头，因此默认先从查询参数 token 读取，再从 "Authorization: Bearer <token>" 读取；
+// 错误与 JWT 中间件一致：ErrTokenMissing、ErrTokenInvalid、ErrTokenExpired 或 ErrTokenRevoked
+// manager: JWT 管理器
+// tokenLookup: 令牌的来源，格式同 middleware.JWTConfig.TokenLookup，默认 "query:token,header:Authorization"
+func JWTAuth(manager *jwt.JWTManager, tokenLookup ...string) AuthFunc {
+	lookup := "query:token,header:Authorization"
+	if len(tokenLookup) > 0 && tokenLookup[0] != "" {
+		lookup = tokenLookup[0]
+	}
+	type source struct{ from, name string }
+	var sources []source
+	for _, part := range strings.Split(lookup, ",") {
+		from, name, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok || name == "" {
+			panic("websocket: 无效的 tokenLookup " + lookup)
+		}
+		switch from {
+		case "header", "query", "cookie":
+		default:
+			panic("websocket: tokenLookup 不支持的来源 " + from)
+		}
+		sources = append(sources, source{from: from, name: name})
+	}
+
+	return func(c *core.Context) (*jwt.Claims, error) {
+		var token string
+		for _, s := range sources {
+			switch s.from {
+			case "header":
+				token = c.GetHeader(s.name)
+				if strings.EqualFold(s.name, "Authorization") {
+					if len(token) > 7 && strings.EqualFold(token[:7], "Bearer ") {
+						token = strings.TrimSpace(token[7:])
+					} else {
+						token = ""
+					}
+				}
+			case "query":
+				token = c.Query(s.name)
+			case "cookie":
+				token, _ = c.Cookie(s.name)
+			}
+			if token != "" {
+				break
+			}
+		}
+		if token == "" {
+			return nil, middleware.ErrTokenMissing
+		}
+
+		claims, err := manager.VerifyTokenContext(c, token)
+		switch {
+		case err == nil:
+			return claims, nil
+		case errors.Is(err, gojwt.ErrTokenExpired):
+			return nil, middleware.ErrTokenExpired.Wrap(err)
+		case errors.Is(err, jwt.ErrTokenRevoked):
+			return nil, middleware.ErrTokenRevoked.Wrap(err)
+		default:
+			return nil, middleware.ErrTokenInvalid.Wrap(err)
+		}
+	}
+}
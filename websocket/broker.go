@@ -0,0 +1,109 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/xzl-go/easygo/logger"
+	"github.com/xzl-go/easygo/redis"
+)
+
+// Broker 在多个实例之间转发广播，使连接分布在不同实例上时广播和定向发送仍能送达
+// 可以基于 Redis pub/sub、NATS 等实现
+type Broker interface {
+	// Publish 向全部实例（包括自身）发布消息
+	Publish(ctx context.Context, data []byte) error
+	// Subscribe 订阅消息，每收到一条调用一次 handler，阻塞直到 ctx 结束或订阅失败
+	Subscribe(ctx context.Context, handler func(data []byte)) error
+}
+
+// relay 是实例之间转发的消息
+type relay struct {
+	Node        string   `json:"node"`              // 发布消息的实例
+	Room        string   `json:"room,omitempty"`    // 房间，为空时发送给全部连接
+	ConnID      string   `json:"conn,omitempty"`    // 定向发送的连接ID
+	Exclude     []string `json:"exclude,omitempty"` // 不发送的连接ID
+	MessageType int      `json:"type"`              // TextMessage 或 BinaryMessage
+	Data        []byte   `json:"data"`              // 消息内容
+}
+
+// publish 将消息发布给其他实例，未设置 Broker 时不做任何事
+func (h *Hub) publish(msg relay) error {
+	if h.options.Broker == nil {
+		return nil
+	}
+	msg.Node = h.node
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := h.options.Broker.Publish(ctx, data); err != nil {
+		logger.Module("websocket").Warn("[WebSocket] 发布广播失败：%v", err)
+		return err
+	}
+	return nil
+}
+
+// subscribe 接收其他实例的消息并发送给本实例的连接，订阅失败时每秒重试，直到 ctx 结束
+func (h *Hub) subscribe(ctx context.Context) {
+	for {
+		err := h.options.Broker.Subscribe(ctx, h.receive)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Module("websocket").Warn("[WebSocket] 订阅广播失败，1 秒后重试：%v", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// receive 处理一条来自其他实例的消息，忽略自身发布的消息
+func (h *Hub) receive(data []byte) {
+	var msg relay
+	if err := json.Unmarshal(data, &msg); err != nil || msg.Node == h.node {
+		return
+	}
+	if msg.ConnID != "" {
+		if c, ok := h.Conn(msg.ConnID); ok {
+			c.SendMessage(msg.MessageType, msg.Data)
+		}
+		return
+	}
+	h.deliver(msg.Room, msg.MessageType, msg.Data, msg.Exclude)
+}
+
+// RedisBroker 是基于 Redis pub/sub 的 Broker
+type RedisBroker struct {
+	client  goredis.UniversalClient
+	channel string
+}
+
+// NewRedisBroker 创建基于 Redis pub/sub 的 Broker
+// client: Redis 客户端，全部实例需连接同一个 Redis
+// channel: 频道名称，同一服务的实例使用相同的频道，默认 "easygo:websocket"
+func NewRedisBroker(client goredis.UniversalClient, channel string) *RedisBroker {
+	if channel == "" {
+		channel = "easygo:websocket"
+	}
+	return &RedisBroker{client: client, channel: channel}
+}
+
+// Publish 发布消息到频道
+func (b *RedisBroker) Publish(ctx context.Context, data []byte) error {
+	return b.client.Publish(ctx, b.channel, data).Err()
+}
+
+// Subscribe 订阅频道，连接断开时 go-redis 会自动重连并重新订阅
+func (b *RedisBroker) Subscribe(ctx context.Context, handler func(data []byte)) error {
+	return redis.Subscribe(ctx, b.client, func(_ string, payload []byte) {
+		handler(payload)
+	}, b.channel)
+}
@@ -0,0 +1,606 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/xzl-go/easygo/core"
+	"github.com/xzl-go/easygo/id"
+	"github.com/xzl-go/easygo/jwt"
+	"github.com/xzl-go/easygo/logger"
+	"github.com/xzl-go/easygo/middleware"
+)
+
+// 连接和中心的错误
+var (
+	ErrConnNotFound = errors.New("websocket: 连接不存在")
+	ErrConnClosed   = errors.New("websocket: 连接已关闭")
+	ErrSendBufFull  = errors.New("websocket: 发送缓冲区已满")
+)
+
+// 消息类型，与 gorilla/websocket 一致
+const (
+	TextMessage   = websocket.TextMessage
+	BinaryMessage = websocket.BinaryMessage
+)
+
+// HubOptions 定义了连接中心配置
+type HubOptions struct {
+	// Upgrader 升级器，用于配置允许的来源、子协议等，默认只允许同源请求，参见 NewUpgrader
+	Upgrader *websocket.Upgrader
+	// Auth 升级前的认证，例如 JWTAuth(manager)；认证失败时返回错误响应且不升级
+	// 未设置时使用路由上 JWT 中间件写入的载荷（如果有）
+	Auth AuthFunc
+	// Broker 多实例部署时在实例之间转发广播和定向发送，例如 NewRedisBroker(client, "")
+	// 未设置时只发送给本实例的连接；Count、Conns 等统计始终只包含本实例的连接
+	Broker Broker
+	// SendBufferSize 每个连接待发送的消息数，默认 256；缓冲区已满说明客户端过慢，连接会被关闭
+	SendBufferSize int
+	// OnConnect 连接建立后调用，可在此设置元数据、加入房间
+	OnConnect func(c *Conn)
+	// OnDisconnect 连接断开后调用，此时连接已离开全部房间
+	OnDisconnect func(c *Conn)
+	// OnJoin 连接加入房间后调用
+	OnJoin func(c *Conn, room string)
+	// OnLeave 连接离开房间后调用，连接断开时离开房间也会调用
+	OnLeave func(c *Conn, room string)
+	// OnMessage 收到客户端消息时调用，在连接的读协程中执行，同一连接的消息按顺序处理
+	// 使用 On 注册了消息处理函数时，只有无法分发的消息交给 OnMessage
+	OnMessage func(c *Conn, messageType int, data []byte)
+
+	// PongWait 读超时：超过该时间没有收到 pong 或消息时认为客户端已断开并清理连接，默认 60 秒
+	PongWait time.Duration
+	// PingInterval 发送 ping 的间隔，必须小于 PongWait，默认为 PongWait 的 9/10
+	PingInterval time.Duration
+	// WriteWait 单条消息的写超时，默认 10 秒
+	WriteWait time.Duration
+	// IdleTimeout 超过该时间没有收到客户端消息（不含 pong）时关闭连接，为 0 时不限制
+	IdleTimeout time.Duration
+	// MaxMessageSize 客户端消息的最大字节数，超过时关闭连接，为 0 时不限制
+	MaxMessageSize int64
+}
+
+// Hub 是 WebSocket 连接中心，管理连接和房间，提供广播和定向发送
+// 每个连接有独立的写协程和发送缓冲区，广播不会因个别慢速客户端阻塞
+type Hub struct {
+	options HubOptions
+
+	mu     sync.RWMutex
+	conns  map[string]*Conn
+	rooms  map[string]map[string]*Conn
+	closed bool
+	wg     sync.WaitGroup // 正在处理的连接
+
+	handlers map[string]MessageHandler // 按消息类型注册的处理函数，参见 On
+
+	node   string             // 实例ID，用于忽略自身通过 Broker 发布的消息
+	cancel context.CancelFunc // 停止订阅
+}
+
+// NewHub 创建连接中心
+// options: 中心配置
+func NewHub(options HubOptions) *Hub {
+	if options.SendBufferSize <= 0 {
+		options.SendBufferSize = 256
+	}
+	if options.PongWait <= 0 {
+		options.PongWait = 60 * time.Second
+	}
+	if options.PingInterval <= 0 || options.PingInterval >= options.PongWait {
+		options.PingInterval = options.PongWait * 9 / 10
+	}
+	if options.WriteWait <= 0 {
+		options.WriteWait = 10 * time.Second
+	}
+	h := &Hub{
+		options: options,
+		conns:   make(map[string]*Conn),
+		rooms:   make(map[string]map[string]*Conn),
+		cancel:  func() {},
+	}
+	if options.Broker != nil {
+		h.node, _ = id.NewULID()
+		var ctx context.Context
+		ctx, h.cancel = context.WithCancel(context.Background())
+		go h.subscribe(ctx)
+	}
+	return h
+}
+
+// Handler 返回升级 WebSocket 连接并交给中心管理的处理函数
+func (h *Hub) Handler() core.HandlerFunc {
+	return func(c *core.Context) {
+		h.Handle(c)
+	}
+}
+
+// Handle 升级 WebSocket 连接并处理到连接断开
+// c: 请求上下文
+func (h *Hub) Handle(c *core.Context) {
+	h.mu.RLock()
+	closed := h.closed
+	h.mu.RUnlock()
+	if closed {
+		c.Writer.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	claims := middleware.GetClaims(c)
+	if h.options.Auth != nil {
+		var err error
+		if claims, err = h.options.Auth(c); err != nil {
+			c.Fail(err)
+			c.Abort()
+			return
+		}
+	}
+
+	u := h.options.Upgrader
+	if u == nil {
+		u = upgrader
+	}
+	ws, err := u.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		c.Logger().Error("Failed to upgrade connection: %v", err)
+		return
+	}
+	conn := newConn(h, ws, c.Request)
+	conn.claims = claims
+	if !h.register(conn) {
+		ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(time.Second))
+		ws.Close()
+		return
+	}
+	defer h.unregister(conn)
+
+	go conn.writeLoop()
+	if h.options.OnConnect != nil {
+		h.options.OnConnect(conn)
+	}
+	conn.readLoop()
+}
+
+// register 登记连接，中心已关闭时返回 false
+func (h *Hub) register(c *Conn) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	h.conns[c.ID] = c
+	h.wg.Add(1)
+	return true
+}
+
+// unregister 关闭连接、离开全部房间并注销
+func (h *Hub) unregister(c *Conn) {
+	defer h.wg.Done()
+	c.Close()
+	for _, room := range c.Rooms() {
+		h.leave(c, room)
+	}
+	h.mu.Lock()
+	delete(h.conns, c.ID)
+	h.mu.Unlock()
+	if h.options.OnDisconnect != nil {
+		h.options.OnDisconnect(c)
+	}
+}
+
+// join 将连接加入房间
+func (h *Hub) join(c *Conn, room string) {
+	h.mu.Lock()
+	members, ok := h.rooms[room]
+	if !ok {
+		members = make(map[string]*Conn)
+		h.rooms[room] = members
+	}
+	_, joined := members[c.ID]
+	members[c.ID] = c
+	h.mu.Unlock()
+
+	c.mu.Lock()
+	c.rooms[room] = struct{}{}
+	c.mu.Unlock()
+	if !joined && h.options.OnJoin != nil {
+		h.options.OnJoin(c, room)
+	}
+}
+
+// leave 将连接移出房间，房间为空时删除
+func (h *Hub) leave(c *Conn, room string) {
+	h.mu.Lock()
+	members := h.rooms[room]
+	_, joined := members[c.ID]
+	delete(members, c.ID)
+	if len(members) == 0 {
+		delete(h.rooms, room)
+	}
+	h.mu.Unlock()
+
+	c.mu.Lock()
+	delete(c.rooms, room)
+	c.mu.Unlock()
+	if joined && h.options.OnLeave != nil {
+		h.options.OnLeave(c, room)
+	}
+}
+
+// Broadcast 向全部连接发送文本消息
+// data: 消息内容
+func (h *Hub) Broadcast(data []byte) {
+	h.BroadcastMessage(TextMessage, data)
+}
+
+// BroadcastMessage 向全部连接发送指定类型的消息
+// messageType: TextMessage 或 BinaryMessage
+// data: 消息内容
+func (h *Hub) BroadcastMessage(messageType int, data []byte) {
+	h.deliver("", messageType, data, nil)
+	h.publish(relay{MessageType: messageType, Data: data})
+}
+
+// BroadcastJSON 将 v 编码为 JSON 后向全部连接发送
+func (h *Hub) BroadcastJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	h.Broadcast(data)
+	return nil
+}
+
+// BroadcastToRoom 向房间内的连接发送文本消息
+// room: 房间名
+// data: 消息内容
+// exclude: 不发送的连接ID，例如消息的发送者
+func (h *Hub) BroadcastToRoom(room string, data []byte, exclude ...string) {
+	h.deliver(room, TextMessage, data, exclude)
+	h.publish(relay{Room: room, Exclude: exclude, MessageType: TextMessage, Data: data})
+}
+
+// deliver 向本实例房间内的连接发送消息，room 为空时发送给全部连接
+func (h *Hub) deliver(room string, messageType int, data []byte, exclude []string) {
+	for _, c := range h.snapshot(room) {
+		if !contains(exclude, c.ID) {
+			c.SendMessage(messageType, data)
+		}
+	}
+}
+
+// SendTo 向指定连接发送文本消息
+// 设置了 Broker 时，连接不在本实例则转发给其他实例，此时不会返回 ErrConnNotFound
+// id: 连接ID
+// data: 消息内容
+func (h *Hub) SendTo(id string, data []byte) error {
+	c, ok := h.Conn(id)
+	if ok {
+		return c.Send(data)
+	}
+	if h.options.Broker == nil {
+		return ErrConnNotFound
+	}
+	// 连接可能在其他实例上
+	return h.publish(relay{ConnID: id, MessageType: TextMessage, Data: data})
+}
+
+// Conn 返回指定ID的连接
+func (h *Hub) Conn(id string) (*Conn, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	c, ok := h.conns[id]
+	return c, ok
+}
+
+// Conns 返回全部连接，room 不为空时只返回房间内的连接
+func (h *Hub) Conns(room string) []*Conn {
+	return h.snapshot(room)
+}
+
+// Count 返回连接数
+func (h *Hub) Count() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.conns)
+}
+
+// RoomCount 返回房间内的连接数
+func (h *Hub) RoomCount(room string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.rooms[room])
+}
+
+// Rooms 返回全部非空房间名，按名称排序
+func (h *Hub) Rooms() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	rooms := make([]string, 0, len(h.rooms))
+	for room := range h.rooms {
+		rooms = append(rooms, room)
+	}
+	sort.Strings(rooms)
+	return rooms
+}
+
+// snapshot 复制连接列表，发送时不持有中心的锁
+func (h *Hub) snapshot(room string) []*Conn {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	source := h.conns
+	if room != "" {
+		source = h.rooms[room]
+	}
+	conns := make([]*Conn, 0, len(source))
+	for _, c := range source {
+		conns = append(conns, c)
+	}
+	return conns
+}
+
+// Shutdown 停止接受新连接，向全部连接发送关闭帧（1001 Going Away）并等待连接处理结束
+// 通常注册为引擎的关闭钩子：app.OnClose(hub.Shutdown)，http.Server 的优雅关闭不会关闭已升级的连接
+// ctx: 控制等待时间，超时后强制关闭剩余连接
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closed = true
+	h.mu.Unlock()
+	h.cancel()
+
+	for _, c := range h.snapshot("") {
+		c.CloseWithReason(websocket.CloseGoingAway, "server shutdown")
+	}
+	done := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		for _, c := range h.snapshot("") {
+			c.ws.Close()
+		}
+		return ctx.Err()
+	}
+}
+
+// Name 返回模块名称，用于注册到应用容器
+func (h *Hub) Name() string {
+	return "websocket"
+}
+
+// Start 实现应用容器的模块接口，连接中心无需启动
+func (h *Hub) Start(context.Context) error {
+	return nil
+}
+
+// Stop 关闭全部连接
+func (h *Hub) Stop(ctx context.Context) error {
+	return h.Shutdown(ctx)
+}
+
+// outbound 是待发送的消息
+type outbound struct {
+	messageType int
+	data        []byte
+}
+
+// Conn 是由中心管理的 WebSocket 连接
+type Conn struct {
+	// ID 连接ID
+	ID string
+	// Request 升级前的 HTTP 请求，可读取请求头、查询参数和远程地址
+	Request *http.Request
+
+	hub  *Hub
+	ws   *websocket.Conn
+	send chan outbound
+	done chan struct{}
+
+	mu        sync.RWMutex
+	meta      map[string]interface{}
+	rooms     map[string]struct{}
+	closeOnce sync.Once
+	closeMsg  []byte       // 写协程退出前发送的关闭帧
+	active    atomic.Int64 // 最后收到客户端消息的时间（UnixNano）
+	claims    *jwt.Claims  // 认证通过的载荷
+}
+
+// newConn 创建连接
+func newConn(h *Hub, ws *websocket.Conn, r *http.Request) *Conn {
+	connID, err := id.NewUUID()
+	if err != nil {
+		connID, _ = id.NewULID()
+	}
+	c := &Conn{
+		ID:      connID,
+		Request: r,
+		hub:     h,
+		ws:      ws,
+		send:    make(chan outbound, h.options.SendBufferSize),
+		done:    make(chan struct{}),
+		meta:    make(map[string]interface{}),
+		rooms:   make(map[string]struct{}),
+	}
+	c.active.Store(time.Now().UnixNano())
+	return c
+}
+
+// Set 设置连接元数据，例如用户ID
+func (c *Conn) Set(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.meta[key] = value
+}
+
+// Get 返回连接元数据
+func (c *Conn) Get(key string) (interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.meta[key]
+	return v, ok
+}
+
+// Join 加入房间
+// room: 房间名
+func (c *Conn) Join(room string) {
+	c.hub.join(c, room)
+}
+
+// Leave 离开房间
+// room: 房间名
+func (c *Conn) Leave(room string) {
+	c.hub.leave(c, room)
+}
+
+// Rooms 返回连接所在的房间，按名称排序
+func (c *Conn) Rooms() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	rooms := make([]string, 0, len(c.rooms))
+	for room := range c.rooms {
+		rooms = append(rooms, room)
+	}
+	sort.Strings(rooms)
+	return rooms
+}
+
+// Send 发送文本消息，消息进入发送缓冲区后立即返回
+func (c *Conn) Send(data []byte) error {
+	return c.SendMessage(TextMessage, data)
+}
+
+// SendJSON 将 v 编码为 JSON 后发送
+func (c *Conn) SendJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.Send(data)
+}
+
+// SendMessage 发送指定类型的消息，发送缓冲区已满时关闭连接，避免慢速客户端占用内存
+// messageType: TextMessage 或 BinaryMessage
+// data: 消息内容
+func (c *Conn) SendMessage(messageType int, data []byte) error {
+	select {
+	case <-c.done:
+		return ErrConnClosed
+	default:
+	}
+	select {
+	case c.send <- outbound{messageType: messageType, data: data}:
+		return nil
+	case <-c.done:
+		return ErrConnClosed
+	default:
+		c.CloseWithReason(websocket.ClosePolicyViolation, "send buffer full")
+		return ErrSendBufFull
+	}
+}
+
+// Close 关闭连接
+func (c *Conn) Close() {
+	c.CloseWithReason(websocket.CloseNormalClosure, "")
+}
+
+// CloseWithReason 发送关闭帧后关闭连接
+// code: 关闭码，例如 websocket.CloseGoingAway
+// reason: 关闭原因
+func (c *Conn) CloseWithReason(code int, reason string) {
+	c.closeOnce.Do(func() {
+		c.closeMsg = websocket.FormatCloseMessage(code, reason)
+		close(c.done)
+	})
+}
+
+// Done 返回连接关闭时关闭的通道
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+// Claims 返回升级前认证通过的载荷，未认证时返回 nil
+func (c *Conn) Claims() *jwt.Claims {
+	return c.claims
+}
+
+// LastActive 返回最后收到客户端消息的时间，不含 pong
+func (c *Conn) LastActive() time.Time {
+	return time.Unix(0, c.active.Load())
+}
+
+// readLoop 读取客户端消息直到连接断开
+// 每次收到 pong 或消息都会延长读超时，客户端消失（断网、休眠）时读取在 PongWait 后超时返回，连接随之清理
+func (c *Conn) readLoop() {
+	// 主动关闭时写协程关闭底层连接，阻塞的读取随之返回
+	defer c.Close()
+	options := c.hub.options
+	if options.MaxMessageSize > 0 {
+		c.ws.SetReadLimit(options.MaxMessageSize)
+	}
+	c.ws.SetReadDeadline(time.Now().Add(options.PongWait))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(options.PongWait))
+	})
+	for {
+		messageType, data, err := c.ws.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseNoStatusReceived) {
+				logger.FromContext(c.Request.Context()).Debug("[WebSocket] 连接 %s 读取失败：%v", c.ID, err)
+			}
+			return
+		}
+		c.active.Store(time.Now().UnixNano())
+		c.ws.SetReadDeadline(time.Now().Add(options.PongWait))
+		c.hub.dispatch(c, messageType, data)
+	}
+}
+
+// writeLoop 是连接唯一的写协程，依次发送缓冲区中的消息并定时发送 ping，连接关闭时发送关闭帧
+func (c *Conn) writeLoop() {
+	defer c.ws.Close()
+	options := c.hub.options
+	ticker := time.NewTicker(options.PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case msg := <-c.send:
+			c.ws.SetWriteDeadline(time.Now().Add(options.WriteWait))
+			if err := c.ws.WriteMessage(msg.messageType, msg.data); err != nil {
+				c.Close()
+				return
+			}
+		case <-ticker.C:
+			if options.IdleTimeout > 0 && time.Since(c.LastActive()) > options.IdleTimeout {
+				c.CloseWithReason(websocket.CloseNormalClosure, "idle timeout")
+				continue
+			}
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(options.WriteWait)); err != nil {
+				c.Close()
+				return
+			}
+		case <-c.done:
+			c.ws.WriteControl(websocket.CloseMessage, c.closeMsg, time.Now().Add(time.Second))
+			return
+		}
+	}
+}
+
+// contains 判断切片是否包含 s
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
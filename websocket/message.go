@@ -0,0 +1,137 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	errs "github.com/xzl-go/easygo/errors"
+	"github.com/xzl-go/easygo/logger"
+	"github.com/xzl-go/easygo/validator"
+)
+
+// ErrorType 是处理消息失败时回复给客户端的消息类型
+const ErrorType = "error"
+
+// ErrUnknownType 消息类型没有注册处理函数
+var ErrUnknownType = errs.New(40410, "error.websocket.unknown_type", http.StatusNotFound, "Unknown message type")
+
+// Message 是 JSON 消息信封，例如 {"type": "chat.send", "payload": {"room": "lobby", "text": "hi"}}
+type Message struct {
+	Type    string          `json:"type"`              // 消息类型
+	Payload json.RawMessage `json:"payload,omitempty"` // 消息内容，由处理函数按类型解码
+}
+
+// ErrorPayload 是错误消息的内容，与 HTTP 统一响应的错误码一致
+type ErrorPayload struct {
+	Type    string `json:"type"`    // 处理失败的消息类型
+	Code    int    `json:"code"`    // 业务错误码
+	Message string `json:"message"` // 错误消息
+}
+
+// MessageHandler 处理一种类型的消息，返回的错误以 error 消息回复给客户端
+type MessageHandler func(c *Conn, msg *Message) error
+
+// Bind 将消息内容解码到 obj，obj 为结构体时按 validate 标签校验
+// 解码失败返回 errors.ErrBadRequest，校验失败返回 errors.ErrValidation
+// obj: 目标对象指针
+func (m *Message) Bind(obj interface{}) error {
+	if len(m.Payload) > 0 {
+		if err := json.Unmarshal(m.Payload, obj); err != nil {
+			return errs.ErrBadRequest.Wrap(err)
+		}
+	}
+	if reflect.Indirect(reflect.ValueOf(obj)).Kind() == reflect.Struct {
+		if err := validator.Validate(obj); err != nil {
+			return errs.ErrValidation.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// On 注册消息类型的处理函数，注册后文本消息按信封解析并分发
+// 没有对应处理函数或不是信封格式的消息交给 OnMessage，未设置 OnMessage 时回复 error 消息
+// msgType: 消息类型，例如 "chat.send"
+// handler: 处理函数
+func (h *Hub) On(msgType string, handler MessageHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.handlers == nil {
+		h.handlers = make(map[string]MessageHandler)
+	}
+	h.handlers[msgType] = handler
+}
+
+// On 注册带类型的处理函数，消息内容解码到 T 并校验后传入
+// hub: 连接中心
+// msgType: 消息类型
+// handler: 处理函数
+func On[T any](hub *Hub, msgType string, handler func(c *Conn, payload *T) error) {
+	hub.On(msgType, func(c *Conn, msg *Message) error {
+		payload := new(T)
+		if err := msg.Bind(payload); err != nil {
+			return err
+		}
+		return handler(c, payload)
+	})
+}
+
+// Emit 向连接发送信封消息
+// msgType: 消息类型
+// payload: 消息内容，编码为 JSON
+func (c *Conn) Emit(msgType string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return c.SendJSON(Message{Type: msgType, Payload: data})
+}
+
+// dispatch 分发一条客户端消息
+func (h *Hub) dispatch(c *Conn, messageType int, data []byte) {
+	h.mu.RLock()
+	handlers := h.handlers
+	h.mu.RUnlock()
+	if len(handlers) == 0 || messageType != TextMessage {
+		if h.options.OnMessage != nil {
+			h.options.OnMessage(c, messageType, data)
+		}
+		return
+	}
+
+	var msg Message
+	err := json.Unmarshal(data, &msg)
+	handler := handlers[msg.Type]
+	if err != nil || handler == nil {
+		if h.options.OnMessage != nil {
+			h.options.OnMessage(c, messageType, data)
+			return
+		}
+		if err != nil {
+			err = errs.ErrBadRequest.Wrap(err)
+		} else {
+			err = ErrUnknownType
+		}
+		c.replyError(msg.Type, err)
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			c.replyError(msg.Type, fmt.Errorf("panic: %v", r))
+		}
+	}()
+	if err := handler(c, &msg); err != nil {
+		c.replyError(msg.Type, err)
+	}
+}
+
+// replyError 以 error 消息回复处理失败的原因，内部错误同时记录日志
+func (c *Conn) replyError(msgType string, err error) {
+	e := errs.From(err)
+	if e.Status >= http.StatusInternalServerError {
+		logger.FromContext(c.Request.Context()).Error("[WebSocket] 连接 %s 处理消息 %s 失败：%v", c.ID, msgType, err)
+	}
+	c.Emit(ErrorType, ErrorPayload{Type: msgType, Code: e.Code, Message: e.Message})
+}
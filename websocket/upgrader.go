@@ -0,0 +1,90 @@
+package websocket
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// UpgraderOptions 定义了 WebSocket 升级配置
+type UpgraderOptions struct {
+	// ReadBufferSize 读缓冲区大小，默认 1024
+	ReadBufferSize int
+	// WriteBufferSize 写缓冲区大小，默认 1024
+	WriteBufferSize int
+	// AllowedOrigins 允许的来源，例如 "https://example.com"、"https://*.example.com"，"*" 表示允许全部来源
+	// 为空且未设置 CheckOrigin 时只允许同源请求；没有 Origin 请求头的非浏览器客户端始终允许
+	AllowedOrigins []string
+	// CheckOrigin 自定义来源校验，设置后忽略 AllowedOrigins
+	CheckOrigin func(r *http.Request) bool
+	// Subprotocols 服务端支持的子协议，按优先级排列
+	Subprotocols []string
+	// EnableCompression 是否协商 permessage-deflate 压缩
+	EnableCompression bool
+}
+
+// upgrader 是 HandleWebSocket 和未设置 Upgrader 的连接中心使用的默认升级器，只允许同源请求
+var upgrader = NewUpgrader(UpgraderOptions{})
+
+// NewUpgrader 按配置创建升级器
+// options: 升级配置
+func NewUpgrader(options UpgraderOptions) *websocket.Upgrader {
+	if options.ReadBufferSize <= 0 {
+		options.ReadBufferSize = 1024
+	}
+	if options.WriteBufferSize <= 0 {
+		options.WriteBufferSize = 1024
+	}
+	checkOrigin := options.CheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = originChecker(options.AllowedOrigins)
+	}
+	return &websocket.Upgrader{
+		ReadBufferSize:    options.ReadBufferSize,
+		WriteBufferSize:   options.WriteBufferSize,
+		CheckOrigin:       checkOrigin,
+		Subprotocols:      options.Subprotocols,
+		EnableCompression: options.EnableCompression,
+	}
+}
+
+// originChecker 返回按允许列表校验来源的函数，列表为空时只允许同源请求
+func originChecker(allowed []string) func(r *http.Request) bool {
+	patterns := make([]string, 0, len(allowed))
+	for _, origin := range allowed {
+		patterns = append(patterns, strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/")))
+	}
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		origin = strings.ToLower(origin)
+		if len(patterns) == 0 {
+			u, err := url.Parse(origin)
+			return err == nil && strings.EqualFold(u.Host, r.Host)
+		}
+		for _, pattern := range patterns {
+			if matchOrigin(pattern, origin) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// matchOrigin 判断来源是否匹配，模式中的 "*." 匹配任意一级或多级子域名
+func matchOrigin(pattern, origin string) bool {
+	if pattern == "*" || pattern == origin {
+		return true
+	}
+	prefix, suffix, ok := strings.Cut(pattern, "*.")
+	if !ok {
+		return false
+	}
+	// "https://*.example.com" 匹配 "https://a.example.com"，不匹配 "https://example.com"
+	return strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, "."+suffix) &&
+		len(origin) > len(prefix)+len(suffix)+1
+}
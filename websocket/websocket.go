@@ -1,21 +1,10 @@
 package websocket
 
 import (
-	"net/http"
-
-	"github.com/gorilla/websocket"
 	"github.com/xzl-go/easygo/core"
 	"github.com/xzl-go/easygo/logger"
 )
 
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		return true
-	},
-}
-
 // HandleWebSocket 处理WebSocket连接
 func HandleWebSocket(c *core.Context) {
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)